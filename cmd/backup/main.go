@@ -0,0 +1,140 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"cryptorg/internal/domain"
+	"cryptorg/internal/service"
+	"cryptorg/internal/storage"
+)
+
+// Использование:
+//
+//	backup create  -journal data/journal.jsonl -precision data/precision.json -bots data/bots.json -out data/backups [-since N]
+//	backup restore -journal data/journal.jsonl -precision data/precision.json -bots data/bots.json -trades data/trades.json [-force] full.json [incr1.json ...]
+//	backup restore ... -trades-db-driver postgres -trades-db-dsn postgres://... full.json [incr1.json ...]
+//
+// Восстановление выполняется при остановленном боте: журнал, переопределения точности и боты
+// перезаписываются содержимым цепочки копий (полная, затем инкрементальные по порядку),
+// файл или таблица открытых сделок собирается заново из восстановленного журнала.
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatalf("Usage: backup create|restore [flags]")
+	}
+
+	flags := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	journalPath := flags.String("journal", "data/journal.jsonl", "path to the event journal")
+	precisionPath := flags.String("precision", "", "path to the precision overrides file")
+	botsPath := flags.String("bots", "", "path to the bots file")
+	tradesPath := flags.String("trades", "", "path to the open trades file, rebuilt from the journal on restore")
+	tradesDBDriver := flags.String("trades-db-driver", "", "trades database driver (postgres or sqlite), rebuilt from the journal on restore")
+	tradesDBDSN := flags.String("trades-db-dsn", "", "trades database connection string")
+	outDir := flags.String("out", "data/backups", "directory for new backups")
+	sinceSeq := flags.Uint64("since", 0, "create an incremental backup of events after this sequence number")
+	force := flags.Bool("force", false, "overwrite an existing journal on restore")
+	flags.Parse(os.Args[2:])
+
+	switch os.Args[1] {
+	case "create":
+		journal, err := storage.NewFileJournal(*journalPath)
+		if err != nil {
+			log.Fatalf("Failed to open journal: %v", err)
+		}
+		defer journal.Close()
+
+		var precisionStore domain.PrecisionStore = storage.NewMemoryPrecisionStore()
+		if *precisionPath != "" {
+			precisionStore, err = storage.NewFilePrecisionStore(*precisionPath)
+			if err != nil {
+				log.Fatalf("Failed to open precision store: %v", err)
+			}
+		}
+
+		var botStore domain.BotStore = storage.NewMemoryBotStore()
+		if *botsPath != "" {
+			botStore, err = storage.NewFileBotStore(*botsPath)
+			if err != nil {
+				log.Fatalf("Failed to open bot store: %v", err)
+			}
+		}
+
+		path, backup, err := service.NewBackupManager(journal, precisionStore, botStore, *outDir).Create(*sinceSeq)
+		if err != nil {
+			log.Fatalf("Failed to create backup: %v", err)
+		}
+		fmt.Printf("%s: %d events after seq %d, last seq %d\n", path, len(backup.Events), backup.FromSeq, backup.LastSeq)
+
+	case "restore":
+		if flags.NArg() == 0 {
+			log.Fatalf("No backup files given")
+		}
+		if _, err := os.Stat(*journalPath); err == nil && !*force {
+			log.Fatalf("Journal %s already exists, use -force to overwrite", *journalPath)
+		}
+
+		backups := make([]*domain.StorageBackup, 0, flags.NArg())
+		for _, path := range flags.Args() {
+			backup, err := storage.ReadBackupFile(path)
+			if err != nil {
+				log.Fatalf("Failed to read backup: %v", err)
+			}
+			backups = append(backups, backup)
+		}
+
+		if err := storage.RestoreBackups(backups, *journalPath, *precisionPath, *botsPath); err != nil {
+			log.Fatalf("Failed to restore: %v", err)
+		}
+
+		// Проверяем, что восстановленный журнал читается и проигрывается
+		events, err := storage.ReadJournalFile(*journalPath)
+		if err != nil {
+			log.Fatalf("Restored journal is unreadable: %v", err)
+		}
+		trades, err := service.ReplayEvents(events, 0)
+		if err != nil {
+			log.Fatalf("Restored journal does not replay: %v", err)
+		}
+		fmt.Printf("Restored %d events into %d trades\n", len(events), len(trades))
+
+		if *tradesPath != "" {
+			// Снимки сделок со старого сервера неактуальны: файл собирается только из журнала
+			if err := os.Remove(*tradesPath); err != nil && !os.IsNotExist(err) {
+				log.Fatalf("Failed to replace trades file: %v", err)
+			}
+			repository, err := storage.NewFileTradeRepository(*tradesPath)
+			if err != nil {
+				log.Fatalf("Failed to open trades file: %v", err)
+			}
+			open, err := service.RestoreTrades(events, repository)
+			if err != nil {
+				log.Fatalf("Failed to restore open trades: %v", err)
+			}
+			fmt.Printf("Restored %d open trades into %s\n", open, *tradesPath)
+		}
+
+		if *tradesDBDriver != "" {
+			db, err := storage.OpenTradeDatabase(*tradesDBDriver, *tradesDBDSN)
+			if err != nil {
+				log.Fatalf("Failed to open trades database: %v", err)
+			}
+			defer db.Close()
+
+			// Как и файл, таблица сделок собирается только из восстановленного журнала
+			repository := storage.NewSQLTradeRepository(db, *tradesDBDriver)
+			if err := repository.Reset(); err != nil {
+				log.Fatalf("Failed to replace trades: %v", err)
+			}
+			open, err := service.RestoreTrades(events, repository)
+			if err != nil {
+				log.Fatalf("Failed to restore open trades: %v", err)
+			}
+			fmt.Printf("Restored %d open trades into the %s database\n", open, *tradesDBDriver)
+		}
+
+	default:
+		log.Fatalf("Unknown command %q, expected create or restore", os.Args[1])
+	}
+}
@@ -2,12 +2,31 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"log"
 
 	"cryptorg/internal/app"
+	"cryptorg/pkg/config"
 )
 
 func main() {
+	printConfig := flag.Bool("print-config", false, "print the effective configuration (CONFIG_FILE, .env and environment merged) and exit")
+	flag.Parse()
+
+	if *printConfig {
+		cfg, err := config.Load()
+		if err != nil {
+			log.Fatalf("Failed to load config: %v", err)
+		}
+		effective, err := cfg.Effective()
+		if err != nil {
+			log.Fatalf("Failed to render config: %v", err)
+		}
+		fmt.Print(string(effective))
+		return
+	}
+
 	application, err := app.NewApplication()
 	if err != nil {
 		log.Fatalf("Failed to create application: %v", err)
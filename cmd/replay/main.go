@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"sort"
+
+	"cryptorg/internal/domain"
+	"cryptorg/internal/service"
+	"cryptorg/internal/storage"
+
+	"github.com/google/uuid"
+)
+
+func main() {
+	journalPath := flag.String("journal", "data/journal.jsonl", "path to the event journal")
+	tradeID := flag.String("trade", "", "replay a single trade by ID")
+	untilSeq := flag.Uint64("until", 0, "stop after the event with this sequence number (0 - whole journal)")
+	flag.Parse()
+
+	events, err := storage.ReadJournalFile(*journalPath)
+	if err != nil {
+		log.Fatalf("Failed to read journal: %v", err)
+	}
+
+	trades, err := service.ReplayEvents(events, *untilSeq)
+	if err != nil {
+		log.Fatalf("Failed to replay journal: %v", err)
+	}
+
+	result := make([]*domain.Trade, 0, len(trades))
+	if *tradeID != "" {
+		id, err := uuid.Parse(*tradeID)
+		if err != nil {
+			log.Fatalf("Invalid trade ID: %v", err)
+		}
+		trade, exists := trades[id]
+		if !exists {
+			log.Fatalf("Trade %s not found in journal", id)
+		}
+		result = append(result, trade)
+	} else {
+		for _, trade := range trades {
+			result = append(result, trade)
+		}
+		sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt) })
+	}
+
+	log.Printf("Replayed %d events into %d trades", len(events), len(trades))
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		log.Fatalf("Failed to encode trades: %v", err)
+	}
+}
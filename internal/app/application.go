@@ -2,6 +2,8 @@ package app
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
@@ -10,24 +12,51 @@ import (
 	"time"
 
 	"cryptorg/internal/bybit"
+	"cryptorg/internal/domain"
+	"cryptorg/internal/export"
+	"cryptorg/internal/feature"
 	"cryptorg/internal/handler"
+	"cryptorg/internal/i18n"
+	"cryptorg/internal/metrics"
+	"cryptorg/internal/notify"
+	"cryptorg/internal/okx"
 	"cryptorg/internal/router"
 	"cryptorg/internal/service"
+	"cryptorg/internal/storage"
+	"cryptorg/internal/tracing"
 	"cryptorg/pkg/config"
+	"cryptorg/pkg/version"
 
 	"github.com/joho/godotenv"
 	"github.com/valyala/fasthttp"
 )
 
 type App struct {
-	config          *config.Config
-	exchangeClient  *bybit.Client
-	orderManager    *service.OrderService
-	tradeManager    *service.TradeService
-	orderController *handler.OrderHandler
-	tradeController *handler.TradeHandler
-	router          *router.Router
-	server          *fasthttp.Server
+	config               *config.Config
+	exchangeClient       service.ExchangeClient
+	orderManager         *service.OrderService
+	tradeManager         *service.TradeService
+	orderController      *handler.OrderHandler
+	tradeController      *handler.TradeHandler
+	statusController     *handler.StatusHandler
+	adminController      *handler.AdminHandler
+	signalController     *handler.SignalHandler
+	rebalancerController *handler.RebalancerHandler
+	botController        *handler.BotHandler
+	toolsController      *handler.ToolsHandler
+	reportController     *handler.ReportHandler
+	router               *router.Router
+	server               *fasthttp.Server
+	scheduler            *service.Scheduler
+	shutdownTracing      func(context.Context) error
+	notificationQueue    *notify.Queue
+	exportQueue          *export.Queue // nil - выгрузка сделок выключена
+	fillPool             *service.FillPool
+	consistencyManager   *service.ConsistencyService
+	orderPoller          *service.OrderPollService // nil - исполнения приходят только вебхуком
+	orderStream          *okx.OrderStream          // nil - OKX не подключен
+	features             *feature.Flags
+	tradeDB              *sql.DB // nil - сделки хранятся не в базе
 }
 
 func init() {
@@ -42,19 +71,225 @@ func NewApplication() (*App, error) {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
-	exchangeClient := bybit.NewExchangeClient(
-		cfg.Bybit.APIKey,
-		cfg.Bybit.SecretKey,
-		cfg.Bybit.Testnet,
+	// Версия из ldflags точнее переменной окружения VERSION
+	if version.Version != "dev" {
+		cfg.Base.Version = version.Version
+	}
+	log.SetPrefix("[" + version.Short() + "] ")
+
+	if cfg.Server.NumberFormat != router.NumberFormatString && cfg.Server.NumberFormat != router.NumberFormatNumber {
+		return nil, fmt.Errorf("unsupported JSON_NUMBER_FORMAT: %s", cfg.Server.NumberFormat)
+	}
+
+	language, err := i18n.Parse(cfg.Base.Language)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported LANGUAGE: %w", err)
+	}
+	i18n.SetDefault(language)
+
+	exchangeClient, err := newExchangeClient(cfg, cfg.Exchange.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	recorder, err := metrics.New(cfg.Metrics.Backend, cfg.Metrics.Prefix, cfg.Metrics.StatsDAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init metrics: %w", err)
+	}
+
+	shutdownTracing := func(context.Context) error { return nil }
+	if cfg.Tracing.Enabled {
+		shutdownTracing, err = tracing.Setup(context.Background(), tracing.Options{
+			ServiceName: cfg.Base.ServiceID,
+			Version:     cfg.Base.Version,
+			Endpoint:    cfg.Tracing.Endpoint,
+			Insecure:    cfg.Tracing.Insecure,
+			SampleRatio: cfg.Tracing.SampleRatio,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to init tracing: %w", err)
+		}
+	}
+
+	orderCache := service.NewOrderStateCache(time.Duration(cfg.Exchange.OrderCacheTTL) * time.Second)
+	var precisionStore domain.PrecisionStore = storage.NewMemoryPrecisionStore()
+	if cfg.Storage.PrecisionPath != "" {
+		precisionStore, err = storage.NewFilePrecisionStore(cfg.Storage.PrecisionPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open precision store: %w", err)
+		}
+	}
+
+	precision, err := service.NewPrecisionOverrides(precisionStore)
+	if err != nil {
+		return nil, err
+	}
+
+	orderManager := service.NewOrderManager(exchangeClient, orderCache, recorder, precision, cfg.Exchange.RawPayloads)
+
+	// Биржи, которые можно выбрать у бота; у каждой свой клиент и кэш ордеров
+	exchangeClients := map[string]service.ExchangeClient{cfg.Exchange.Name: exchangeClient}
+	orderManagers := map[string]*service.OrderService{cfg.Exchange.Name: orderManager}
+	for _, name := range cfg.Exchange.Enabled()[1:] {
+		client, err := newExchangeClient(cfg, name)
+		if err != nil {
+			return nil, err
+		}
+		exchangeClients[name] = client
+		orderManagers[name] = service.NewOrderManager(client, service.NewOrderStateCache(time.Duration(cfg.Exchange.OrderCacheTTL)*time.Second), recorder, precision, cfg.Exchange.RawPayloads)
+	}
+	var journal domain.EventJournal = storage.NewMemoryJournal()
+	if cfg.Storage.JournalPath != "" {
+		fileJournal, err := storage.NewFileJournal(cfg.Storage.JournalPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open event journal: %w", err)
+		}
+		journal = fileJournal
+	}
+
+	notifier, notificationQueue := newNotifier(cfg)
+
+	features, err := feature.New(cfg.Feature.File, cfg.Base.Environment, cfg.Feature.Overrides)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load feature flags: %w", err)
+	}
+
+	symbolLists, err := service.NewSymbolLists(cfg.Exchange.SymbolAllowlist, cfg.Exchange.SymbolDenylist)
+	if err != nil {
+		return nil, err
+	}
+	var cooldownStore domain.CooldownStore = storage.NewMemoryCooldownStore()
+	if cfg.Storage.CooldownsPath != "" {
+		cooldownStore, err = storage.NewFileCooldownStore(cfg.Storage.CooldownsPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open cooldown store: %w", err)
+		}
+	}
+	cooldowns, err := service.NewSymbolCooldowns(cooldownStore, time.Duration(cfg.Exchange.StopLossCooldown)*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	riskManager := service.NewRiskManager(exchangeClient, cfg.Exchange.QuoteBudgets, symbolLists, cooldowns, cfg.Exchange.DailyLossLimits, cfg.Exchange.ApprovalLimits)
+	for name, client := range exchangeClients {
+		riskManager.AddExchange(name, client)
+	}
+	exposureGuard := service.NewExposureGuard()
+	tradeDB, tradeRepository, err := newTradeRepository(cfg.Storage)
+	if err != nil {
+		return nil, err
+	}
+
+	var executionStore domain.ExecutionStore = storage.NewMemoryExecutionStore(domain.DefaultExecutionDedupTTL)
+	if cfg.Storage.ExecutionsPath != "" {
+		executionStore, err = storage.NewFileExecutionStore(cfg.Storage.ExecutionsPath, domain.DefaultExecutionDedupTTL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open execution store: %w", err)
+		}
+	}
+
+	exporter, exportQueue, err := newTradeExporter(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var tradeLocker domain.TradeLocker = storage.NewMemoryTradeLocker()
+	if cfg.Storage.TradesDBDriver == storage.DriverPostgres {
+		// Реплики на общей базе блокируют сделки в ней же
+		tradeLocker = storage.NewPostgresTradeLocker(tradeDB)
+	}
+
+	tradeManager := service.NewTradeManager(orderManager, riskManager, journal, tradeRepository, executionStore, recorder, notifier, tradeLocker, exposureGuard, exporter)
+	if loader, ok := tradeRepository.(domain.TradeLoader); ok && cfg.Storage.TradesDBDriver == storage.DriverPostgres {
+		tradeManager.UseSharedStorage(loader)
+	}
+	for name, orders := range orderManagers {
+		tradeManager.AddExchange(name, orders)
+	}
+	restored, err := tradeManager.LoadTrades()
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore trades: %w", err)
+	}
+	if restored > 0 {
+		log.Printf("Restored %d open trades from %s storage", restored, tradeRepository.Driver())
+	}
+
+	orderController := handler.NewOrderController(orderManager, tradeManager)
+	tradeDefaults, err := loadTradeDefaults(cfg.Trade)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load trade defaults: %w", err)
+	}
+
+	fillPool := service.NewFillPool(tradeManager, recorder, cfg.Worker.FillWorkers, cfg.Worker.FillQueueSize)
+	var messageCapture domain.MessageCapture
+	if cfg.Storage.MessageCapturePath != "" {
+		messageCapture, err = storage.NewFileMessageCapture(cfg.Storage.MessageCapturePath, cfg.Storage.MessageCaptureMaxBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open message capture: %w", err)
+		}
+	}
+
+	// Bybit присылает исполнения вебхуком, OKX - в приватный WebSocket
+	var orderStream *okx.OrderStream
+	if client, ok := exchangeClients["okx"].(*okx.Client); ok {
+		orderStream = okx.NewOrderStream(client, fillPool.Dispatch, messageCapture)
+	}
+
+	tradeController := handler.NewTradeController(tradeManager, fillPool, tradeDefaults, cfg.Server.HasAdminKey(), cfg.Server.OrderWebhookSecret, messageCapture)
+
+	statusController := handler.NewStatusController(cfg, exchangeClient, tradeManager, notificationQueue, fillPool, orderStream)
+
+	var botStore domain.BotStore = storage.NewMemoryBotStore()
+	if cfg.Storage.BotsPath != "" {
+		botStore, err = storage.NewFileBotStore(cfg.Storage.BotsPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open bot store: %w", err)
+		}
+	}
+	backupManager := service.NewBackupManager(journal, precisionStore, botStore, cfg.Storage.BackupDir)
+	consistencyManager := service.NewConsistencyManager(tradeManager, notifier, cfg.Worker.ConsistencyAutoRepair)
+	var pnlRevisions domain.PnLRevisionStore = storage.NewMemoryPnLRevisionStore()
+	if cfg.Storage.PnLRevisionsPath != "" {
+		pnlRevisions, err = storage.NewFilePnLRevisionStore(cfg.Storage.PnLRevisionsPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open pnl revisions: %w", err)
+		}
+	}
+	pnlRecomputeManager := service.NewPnLRecomputeManager(journal, pnlRevisions)
+	adminController := handler.NewAdminController(cfg, tradeManager, notificationQueue, features, backupManager, consistencyManager, pnlRecomputeManager, symbolLists, exchangeClient, messageCapture)
+
+	symbolCooldowns := make(map[string]time.Duration, len(cfg.Signal.SymbolCooldowns))
+	for symbol, seconds := range cfg.Signal.SymbolCooldowns {
+		symbolCooldowns[symbol] = time.Duration(seconds) * time.Second
+	}
+	signalGate := service.NewSignalGate(
+		time.Duration(cfg.Signal.Cooldown)*time.Second,
+		time.Duration(cfg.Signal.DedupWindow)*time.Second,
+		symbolCooldowns,
 	)
+	signalController := handler.NewSignalController(tradeManager, signalGate, tradeDefaults)
 
-	orderManager := service.NewOrderManager(exchangeClient)
-	tradeManager := service.NewTradeManager(orderManager)
+	rebalancerManager := service.NewRebalancerManager(orderManager, tradeManager, exposureGuard)
+	rebalancerController := handler.NewRebalancerController(rebalancerManager)
 
-	orderController := handler.NewOrderController(orderManager)
-	tradeController := handler.NewTradeController(tradeManager)
+	botManager, err := service.NewBotManager(tradeManager, botStore)
+	if err != nil {
+		return nil, err
+	}
+	botController := handler.NewBotController(botManager, tradeDefaults)
+	telegramController := handler.NewTelegramController(cfg, tradeManager)
+
+	backtestManager := service.NewBacktestManager(orderManager)
+	toolsController := handler.NewToolsController(riskManager, backtestManager)
 
-	appRouter := router.NewRouter(orderController, tradeController)
+	reportLocation, err := time.LoadLocation(cfg.Report.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid report timezone %q: %w", cfg.Report.Timezone, err)
+	}
+	reportManager := service.NewReportManager(tradeManager, notifier, reportLocation, cfg.Report.DeliveryHour)
+	reportController := handler.NewReportController(reportManager)
+	marketController := handler.NewMarketController(orderManager)
+
+	appRouter := router.NewRouter(orderController, tradeController, statusController, adminController, signalController, rebalancerController, botController, telegramController, toolsController, reportController, marketController, recorder, cfg.Server.AccessLog, cfg.Server.NumberFormat, cfg.Server.PublicStats, cfg.Server.APIKeys)
 
 	server := &fasthttp.Server{
 		Handler:      appRouter.Handler,
@@ -63,25 +298,225 @@ func NewApplication() (*App, error) {
 		IdleTimeout:  time.Duration(cfg.Server.IdleTimeout) * time.Second,
 	}
 
+	var orderPoller *service.OrderPollService
+	if cfg.Worker.OrderPollInterval > 0 {
+		orderPoller = service.NewOrderPollManager(tradeManager, time.Duration(cfg.Worker.OrderPollInterval)*time.Second, cfg.Worker.OrderPollPerSymbol)
+	}
+
+	scheduler := service.NewScheduler(time.Duration(cfg.Worker.SchedulerInterval) * time.Second)
+	scheduler.Register("start_conditions", tradeManager.TriggerWaitingTrades)
+	scheduler.Register("scheduled_buys", tradeManager.ExecuteScheduledBuys)
+	scheduler.Register("grid_refresh", tradeManager.RefreshStaleGrids)
+	scheduler.Register("dca_expiry", tradeManager.ExpireDCAOrders)
+	scheduler.Register("trades_snapshot", tradeManager.RefreshSnapshot)
+	scheduler.Register("balance_check", tradeManager.CheckFunding)
+	scheduler.Register("exit_assistant", tradeManager.RunExitAssistant)
+	scheduler.Register("tp_deferred", tradeManager.RetryDeferredTakeProfits)
+	scheduler.Register("tp_fallback", tradeManager.RunTPFallback)
+	scheduler.Register("cancel_retry", tradeManager.RetryPendingCancels)
+	scheduler.Register("tp_credit_check", tradeManager.VerifyTPCredits)
+	scheduler.Register("loss_limit", tradeManager.CheckLossLimit)
+	scheduler.Register("consistency_check", consistencyManager.Run)
+	scheduler.Register("portfolio_rebalance", rebalancerManager.RebalanceAll)
+	scheduler.Register("bots", botManager.RunBots)
+	scheduler.Register("feature_flags_reload", func(ctx context.Context) error { return features.Reload() })
+	if cfg.Report.DailyEnabled {
+		scheduler.Register("daily_report", reportManager.DeliverDailyReport)
+	}
+
 	app := &App{
-		config:          cfg,
-		exchangeClient:  exchangeClient,
-		orderManager:    orderManager,
-		tradeManager:    tradeManager,
-		orderController: orderController,
-		tradeController: tradeController,
-		router:          appRouter,
-		server:          server,
+		config:               cfg,
+		exchangeClient:       exchangeClient,
+		orderManager:         orderManager,
+		tradeManager:         tradeManager,
+		orderController:      orderController,
+		tradeController:      tradeController,
+		statusController:     statusController,
+		adminController:      adminController,
+		signalController:     signalController,
+		rebalancerController: rebalancerController,
+		botController:        botController,
+		toolsController:      toolsController,
+		reportController:     reportController,
+		router:               appRouter,
+		server:               server,
+		scheduler:            scheduler,
+		notificationQueue:    notificationQueue,
+		exportQueue:          exportQueue,
+		fillPool:             fillPool,
+		consistencyManager:   consistencyManager,
+		orderPoller:          orderPoller,
+		orderStream:          orderStream,
+		features:             features,
+		tradeDB:              tradeDB,
+		shutdownTracing:      shutdownTracing,
 	}
 
 	return app, nil
 }
 
+func newExchangeClient(cfg *config.Config, name string) (service.ExchangeClient, error) {
+	switch name {
+	case "bybit":
+		client := bybit.NewExchangeClient(
+			cfg.Bybit.APIKey,
+			cfg.Bybit.SecretKey,
+			cfg.Bybit.Testnet,
+		)
+		switch cfg.Bybit.FixturesMode {
+		case "":
+		case bybit.FixtureModeRecord:
+			client.SetTransport(bybit.NewRecordingTransport(cfg.Bybit.FixturesDir, nil))
+		case bybit.FixtureModeReplay:
+			client.SetTransport(bybit.NewReplayTransport(cfg.Bybit.FixturesDir))
+		default:
+			return nil, fmt.Errorf("unsupported fixtures mode: %s", cfg.Bybit.FixturesMode)
+		}
+		return client, nil
+	case "okx":
+		return okx.NewExchangeClient(
+			cfg.OKX.APIKey,
+			cfg.OKX.SecretKey,
+			cfg.OKX.Passphrase,
+			cfg.OKX.Demo,
+		), nil
+	default:
+		return nil, fmt.Errorf("unsupported exchange: %s", name)
+	}
+}
+
+// newNotifier пишет уведомления в лог сразу, а во внешние каналы - через очередь с повторами.
+func newNotifier(cfg *config.Config) (notify.Notifier, *notify.Queue) {
+	channels := notify.MultiNotifier{}
+	if cfg.Notify.TelegramToken != "" && cfg.Notify.TelegramChatID != "" {
+		channels = append(channels, notify.NewTelegramNotifier(cfg.Notify.TelegramToken, cfg.Notify.TelegramChatID))
+	}
+
+	queue := notify.NewQueue(
+		channels,
+		time.Duration(cfg.Notify.RetryBase)*time.Second,
+		time.Duration(cfg.Notify.RetryMax)*time.Second,
+		time.Duration(cfg.Notify.MaxAge)*time.Second,
+	)
+	return notify.MultiNotifier{notify.LogNotifier{}, queue}, queue
+}
+
+// newTradeRepository выбирает хранилище сделок: база из TRADES_DB_DRIVER, файл TRADES_PATH
+// или память. Для базы возвращается и ее подключение, чтобы закрыть его при остановке.
+func newTradeRepository(cfg config.StorageConfig) (*sql.DB, domain.TradeRepository, error) {
+	switch {
+	case cfg.TradesDBDriver != "":
+		db, err := storage.OpenTradeDatabase(cfg.TradesDBDriver, cfg.TradesDBDSN)
+		if err != nil {
+			return nil, nil, err
+		}
+		return db, storage.NewSQLTradeRepository(db, cfg.TradesDBDriver), nil
+	case cfg.TradesPath != "":
+		repository, err := storage.NewFileTradeRepository(cfg.TradesPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open trade repository: %w", err)
+		}
+		return nil, repository, nil
+	}
+	return nil, storage.NewMemoryTradeRepository(), nil
+}
+
+// newTradeExporter включает выгрузку закрытых сделок в Google таблицу, если задана таблица.
+func newTradeExporter(cfg *config.Config) (domain.TradeExporter, *export.Queue, error) {
+	if cfg.Export.SheetsSpreadsheetID == "" {
+		return nil, nil, nil
+	}
+
+	credentials, err := os.ReadFile(cfg.Export.SheetsCredentialsFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read google sheets credentials: %w", err)
+	}
+	sheets, err := export.NewSheetsExporter(credentials, cfg.Export.SheetsSpreadsheetID, cfg.Export.SheetsRange)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to init google sheets export: %w", err)
+	}
+
+	queue := export.NewQueue(sheets, cfg.Export.QueueSize, time.Duration(cfg.Notify.RetryBase)*time.Second)
+	return queue, queue, nil
+}
+
+func loadTradeDefaults(cfg config.TradeDefaultsConfig) (domain.TradeConfig, error) {
+	var defaults domain.TradeConfig
+
+	if cfg.File != "" {
+		data, err := os.ReadFile(cfg.File)
+		if err != nil {
+			return defaults, err
+		}
+		if err := json.Unmarshal(data, &defaults); err != nil {
+			return defaults, fmt.Errorf("invalid defaults file %s: %w", cfg.File, err)
+		}
+	}
+
+	if cfg.EntryVolume != "" {
+		defaults.EntryVolume = cfg.EntryVolume
+	}
+	if cfg.DCAVolume != "" {
+		defaults.DCAVolume = cfg.DCAVolume
+	}
+	if cfg.DCACount != 0 {
+		defaults.DCACount = cfg.DCACount
+	}
+	if cfg.DCAStepPercent != 0 {
+		defaults.DCAStepPercent = cfg.DCAStepPercent
+	}
+	if cfg.TakeProfitPercent != 0 {
+		defaults.TakeProfitPercent = cfg.TakeProfitPercent
+	}
+	if cfg.Martingale != 0 {
+		defaults.Martingale = cfg.Martingale
+	}
+	if cfg.StopLossPercent != 0 {
+		defaults.StopLossPercent = cfg.StopLossPercent
+	}
+
+	// Обход защиты от ручной торговли должен быть явным в каждом запросе
+	defaults.Force = false
+
+	return defaults, nil
+}
+
 func (a *App) Run(ctx context.Context) error {
-	log.Printf("Starting Cryptorg Bot on port %s", a.config.Server.Port)
+	log.Printf("Starting Cryptorg Bot %s on port %s", version.String(), a.config.Server.Port)
 	log.Printf("Environment: %s", a.config.Base.Environment)
+	log.Printf("Exchange: %s", a.config.Exchange.Name)
+	if len(a.config.Exchange.Extra) > 0 {
+		log.Printf("Bot exchanges: %v", a.config.Exchange.Enabled())
+	}
+	log.Printf("Metrics backend: %s", a.config.Metrics.Backend)
+	log.Printf("Tracing enabled: %v", a.config.Tracing.Enabled)
 	log.Printf("Bybit Testnet: %v", a.config.Bybit.Testnet)
 	log.Printf("Symbol: %s", a.config.Bybit.Symbol)
+	if len(a.config.Server.APIKeys) == 0 {
+		log.Printf("Warning: API_KEYS is empty, /api routes are served without authentication")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go a.notificationQueue.Run(ctx)
+	if a.config.Worker.ReconcileOnStartup {
+		// Биржа могла исполнить или отменить ордера, пока бот не работал
+		if _, err := a.consistencyManager.Reconcile(ctx); err != nil {
+			log.Printf("Startup reconciliation finished with errors: %v", err)
+		}
+	}
+	go a.scheduler.Run(ctx)
+	if a.orderPoller != nil {
+		go a.orderPoller.Run(ctx)
+	}
+	if a.exportQueue != nil {
+		go a.exportQueue.Run(ctx)
+	}
+	go a.fillPool.Run(ctx)
+	if a.orderStream != nil {
+		go a.orderStream.Run(ctx)
+	}
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -117,6 +552,15 @@ func (a *App) shutdown() error {
 		return err
 	}
 
+	if err := a.shutdownTracing(ctx); err != nil {
+		log.Printf("Failed to flush traces: %v", err)
+	}
+	if a.tradeDB != nil {
+		if err := a.tradeDB.Close(); err != nil {
+			log.Printf("Failed to close trades database: %v", err)
+		}
+	}
+
 	log.Println("Cryptorg Bot shut down successfully")
 	return nil
 }
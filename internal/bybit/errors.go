@@ -0,0 +1,124 @@
+package bybit
+
+import (
+	"errors"
+	"fmt"
+
+	apperrors "cryptorg/pkg/errors"
+)
+
+// ErrOrderNotFound - биржа не знает ордер с таким идентификатором.
+var ErrOrderNotFound = errors.New("order not found")
+
+// APIError - отказ биржи с ненулевым retCode при HTTP 200.
+type APIError struct {
+	RetCode int
+	RetMsg  string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("bybit API error: retCode %d, retMsg: %s", e.RetCode, e.RetMsg)
+}
+
+// Unwrap отдает отказ как AppError, чтобы обработчики выбрали HTTP статус через errors.As.
+func (e *APIError) Unwrap() error {
+	return e.AppError()
+}
+
+// AppError переводит retCode в категорию ошибки приложения.
+func (e *APIError) AppError() *apperrors.AppError {
+	var appErr *apperrors.AppError
+	switch retCodeCategory(e.RetCode) {
+	case categoryInsufficientBalance:
+		appErr = apperrors.DomainError(e.RetMsg, "INSUFFICIENT_BALANCE")
+	case categoryInvalidOrder:
+		appErr = &apperrors.AppError{Type: apperrors.ErrorTypeValidation, Code: "INVALID_ORDER", Message: e.RetMsg}
+	case categoryRateLimit:
+		appErr = apperrors.RateLimitedError("bybit", e.RetMsg)
+	case categoryAuth:
+		appErr = apperrors.ExternalError("bybit", e.RetMsg)
+		appErr.Code = "EXCHANGE_AUTH_FAILED"
+	case categoryNotFound:
+		appErr = &apperrors.AppError{Type: apperrors.ErrorTypeNotFound, Code: "ORDER_NOT_FOUND", Message: e.RetMsg}
+	default:
+		appErr = apperrors.ExternalError("bybit", e.RetMsg)
+	}
+	if appErr.Details == nil {
+		appErr.Details = make(map[string]interface{})
+	}
+	appErr.Details["retCode"] = e.RetCode
+	return appErr
+}
+
+// HTTPError - ответ биржи с HTTP статусом, отличным от 200.
+type HTTPError struct {
+	Status int
+	Body   string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("bybit API error: status %d, body: %s", e.Status, e.Body)
+}
+
+// Unwrap отдает отказ как AppError: 401/403 - ошибка ключей, 429 - лимит, прочее - сбой биржи.
+func (e *HTTPError) Unwrap() error {
+	switch e.Status {
+	case 401, 403:
+		appErr := apperrors.ExternalError("bybit", fmt.Sprintf("status %d", e.Status))
+		appErr.Code = "EXCHANGE_AUTH_FAILED"
+		return appErr
+	case 429:
+		return apperrors.RateLimitedError("bybit", fmt.Sprintf("status %d", e.Status))
+	}
+	return apperrors.ExternalError("bybit", fmt.Sprintf("status %d", e.Status))
+}
+
+type errorCategory int
+
+const (
+	categoryOther errorCategory = iota
+	categoryInsufficientBalance
+	categoryInvalidOrder
+	categoryRateLimit
+	categoryAuth
+	categoryNotFound
+)
+
+// Коды отказов v5 API по категориям; неизвестные коды считаются сбоем биржи.
+var retCodeCategories = map[int]errorCategory{
+	110004: categoryInsufficientBalance,
+	110007: categoryInsufficientBalance,
+	110012: categoryInsufficientBalance,
+	170131: categoryInsufficientBalance,
+
+	10001:                       categoryInvalidOrder,
+	170124:                      categoryInvalidOrder,
+	170130:                      categoryInvalidOrder,
+	170132:                      categoryInvalidOrder,
+	170133:                      categoryInvalidOrder,
+	RetCodePriceDecimalTooLong:  categoryInvalidOrder,
+	170135:                      categoryInvalidOrder,
+	170136:                      categoryInvalidOrder,
+	RetCodeQtyDecimalTooLong:    categoryInvalidOrder,
+	170140:                      categoryInvalidOrder,
+	RetCodeAmountDecimalTooLong: categoryInvalidOrder,
+
+	retCodeTooManyVisits:  categoryRateLimit,
+	10018:                 categoryRateLimit,
+	retCodeFreqProtection: categoryRateLimit,
+
+	10003: categoryAuth,
+	10004: categoryAuth,
+	10005: categoryAuth,
+	10007: categoryAuth,
+	10009: categoryAuth,
+	10010: categoryAuth,
+	33004: categoryAuth,
+
+	110001: categoryNotFound,
+	170213: categoryNotFound,
+}
+
+func retCodeCategory(code int) errorCategory {
+	return retCodeCategories[code]
+}
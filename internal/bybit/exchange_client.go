@@ -12,7 +12,13 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
+
+	"cryptorg/internal/chaos"
+	"cryptorg/internal/tracing"
+	"cryptorg/pkg/latency"
+	"cryptorg/pkg/ratelimit"
 )
 
 type Client struct {
@@ -20,6 +26,10 @@ type Client struct {
 	secretKey  string
 	testnet    bool
 	httpClient *http.Client
+	latency    *latency.Tracker
+	rateLimits *ratelimit.Tracker
+	buckets    map[string]*ratelimit.Bucket // Категория эндпоинта -> лимит частоты
+	clock      *latency.ClockOffset
 }
 
 func NewExchangeClient(apiKey, secretKey string, testnet bool) *Client {
@@ -28,7 +38,83 @@ func NewExchangeClient(apiKey, secretKey string, testnet bool) *Client {
 		secretKey:  secretKey,
 		testnet:    testnet,
 		httpClient: &http.Client{Timeout: 30 * time.Second},
+		latency:    latency.NewTracker(latency.DefaultWindow),
+		rateLimits: ratelimit.NewTracker(ratelimit.DefaultWindow, ratelimit.DefaultReserve),
+		buckets:    newCategoryBuckets(),
+		clock:      latency.NewClockOffset(),
+	}
+}
+
+func (c *Client) Latency() *latency.Tracker {
+	return c.latency
+}
+
+func (c *Client) RateLimits() *ratelimit.Tracker {
+	return c.rateLimits
+}
+
+// Clock - смещение часов биржи, оценивается по заголовку Timenow каждого ответа.
+func (c *Client) Clock() *latency.ClockOffset {
+	return c.clock
+}
+
+// SetTransport подменяет HTTP транспорт, например на запись или воспроизведение фикстур.
+func (c *Client) SetTransport(transport http.RoundTripper) {
+	c.httpClient.Transport = transport
+}
+
+// send выполняет одну попытку запроса.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	ctx, span := tracing.Start(req.Context(), "bybit "+req.Method+" "+req.URL.Path)
+	req = req.WithContext(ctx)
+
+	if err := c.rateLimits.Wait(ctx, req.URL.Path); err != nil {
+		tracing.End(span, err)
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	received := time.Now()
+	c.latency.Record(received.Sub(start))
+	tracing.End(span, err)
+
+	if err == nil {
+		c.observeRateLimit(req.URL.Path, resp)
+		if serverMs, err := strconv.ParseInt(resp.Header.Get("Timenow"), 10, 64); err == nil {
+			c.clock.Observe(time.UnixMilli(serverMs), start, received)
+		}
+		if chaosErr := chaos.Inject(chaos.PointExchangeResponse); chaosErr != nil {
+			resp.Body.Close()
+			return nil, chaosErr
+		}
+	}
+	return resp, err
+}
+
+const recvWindow = "5000"
+
+// observeRateLimit передает трекеру квоту из заголовков X-Bapi-Limit*. Заголовки приходят
+// только на приватные эндпоинты; 403 и 429 означают, что IP или ключ упираются в лимит.
+func (c *Client) observeRateLimit(endpoint string, resp *http.Response) {
+	if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests {
+		c.rateLimits.Reject(endpoint)
+		return
+	}
+
+	limit, err := strconv.Atoi(resp.Header.Get("X-Bapi-Limit"))
+	if err != nil {
+		return
+	}
+	remaining, err := strconv.Atoi(resp.Header.Get("X-Bapi-Limit-Status"))
+	if err != nil {
+		return
+	}
+	resetMs, err := strconv.ParseInt(resp.Header.Get("X-Bapi-Limit-Reset-Timestamp"), 10, 64)
+	if err != nil {
+		return
 	}
+	c.rateLimits.Observe(endpoint, limit, remaining, time.UnixMilli(resetMs))
 }
 
 func (c *Client) getBaseURL() string {
@@ -39,207 +125,675 @@ func (c *Client) getBaseURL() string {
 }
 
 type ExchangeOrderRequest struct {
-	Symbol      string `json:"symbol"`
-	Side        string `json:"side"`
-	OrderType   string `json:"orderType"`
-	Qty         string `json:"qty,omitempty"`
-	Price       string `json:"price,omitempty"`
-	TimeInForce string `json:"timeInForce,omitempty"`
-	Timestamp   int64  `json:"timestamp"`
+	Category     string `json:"category"` // Заполняет клиент
+	Symbol       string `json:"symbol"`
+	Side         string `json:"side"`
+	OrderType    string `json:"orderType"`
+	Qty          string `json:"qty,omitempty"`
+	Price        string `json:"price,omitempty"`
+	TimeInForce  string `json:"timeInForce,omitempty"`
+	TriggerPrice string `json:"triggerPrice,omitempty"`
+	OrderFilter  string `json:"orderFilter,omitempty"`
+	MarketUnit   string `json:"marketUnit,omitempty"` // Единица qty рыночного ордера: baseCoin или quoteCoin
+	OrderLinkID  string `json:"orderLinkId,omitempty"`
+	Timestamp    int64  `json:"timestamp"`
 }
 
 type ExchangeOrderResponse struct {
-	Symbol      string `json:"symbol"`
-	OrderID     string `json:"orderId"`
-	OrderLinkID string `json:"orderLinkId"`
-	Price       string `json:"price"`
-	Qty         string `json:"qty"`
-	ExecutedQty string `json:"executedQty"`
-	Status      string `json:"orderStatus"`
-	TimeInForce string `json:"timeInForce"`
-	OrderType   string `json:"orderType"`
-	Side        string `json:"side"`
-	CreatedTime string `json:"createdTime"`
+	Symbol        string `json:"symbol"`
+	OrderID       string `json:"orderId"`
+	OrderLinkID   string `json:"orderLinkId"`
+	Price         string `json:"price"`
+	Qty           string `json:"qty"`
+	ExecutedQty   string `json:"cumExecQty"`
+	Status        string `json:"orderStatus"`
+	TimeInForce   string `json:"timeInForce"`
+	OrderType     string `json:"orderType"`
+	Side          string `json:"side"`
+	CreatedTime   string `json:"createdTime"`
+	UpdatedTime   string `json:"updatedTime"` // Последнее изменение на бирже, для исполненного ордера - время исполнения
+	ExecutedValue string `json:"cumExecValue"`
+	ExecutedFee   string `json:"cumExecFee"`
+
+	Raw json.RawMessage `json:"-"` // Исходный JSON ордера из ответа биржи
 }
 
-type ExchangeCancelRequest struct {
+const (
+	OrderFilterOrder     = "Order"
+	OrderFilterStopOrder = "StopOrder"
+
+	MarketUnitQuoteCoin = "quoteCoin"
+)
+
+// Коды отказов v5 API, связанные с точностью символа
+const (
+	RetCodePriceDecimalTooLong  = 170134
+	RetCodeQtyDecimalTooLong    = 170137
+	RetCodeAmountDecimalTooLong = 170148
+)
+
+// ExchangeAmendRequest меняет цену и/или количество (в базовой монете) активного ордера.
+type ExchangeAmendRequest struct {
+	Category  string `json:"category"`
 	Symbol    string `json:"symbol"`
-	OrderID   string `json:"orderId,omitempty"`
+	OrderID   string `json:"orderId"`
+	Qty       string `json:"qty,omitempty"`
+	Price     string `json:"price,omitempty"`
 	Timestamp int64  `json:"timestamp"`
 }
 
+type ExchangeCancelRequest struct {
+	Category    string `json:"category"`
+	Symbol      string `json:"symbol"`
+	OrderID     string `json:"orderId,omitempty"`
+	OrderFilter string `json:"orderFilter,omitempty"`
+	Timestamp   int64  `json:"timestamp"`
+}
+
 func (c *Client) ExecuteOrder(ctx context.Context, req ExchangeOrderRequest) (*ExchangeOrderResponse, error) {
+	req.Category = "spot"
 	req.Timestamp = time.Now().UnixMilli()
 
-	endpoint := "/v5/order/create"
-
-	resp, err := c.makeAuthenticatedRequest(ctx, "POST", endpoint, req)
+	raw, err := c.postPrivate(ctx, "/v5/order/create", req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create order: %w", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("bybit API error: status %d, body: %s", resp.StatusCode, string(body))
+	var result ExchangeOrderResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode order response: %w", err)
 	}
-
-	var apiResp struct {
-		Result ExchangeOrderResponse `json:"result"`
+	if result.OrderID == "" {
+		return nil, fmt.Errorf("bybit accepted order without orderId: %s", string(raw))
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
-		return nil, fmt.Errorf("failed to decode order response: %w", err)
+	// Ответ на создание содержит только идентификаторы: принятый биржей ордер считается New
+	if result.Status == "" {
+		result.Status = "New"
 	}
-	return &apiResp.Result, nil
+	result.Raw = raw
+	return &result, nil
 }
 
 func (c *Client) TerminateOrder(ctx context.Context, req ExchangeCancelRequest) error {
+	req.Category = "spot"
 	req.Timestamp = time.Now().UnixMilli()
 
-	endpoint := "/v5/order/cancel"
-
-	resp, err := c.makeAuthenticatedRequest(ctx, "POST", endpoint, req)
-	if err != nil {
+	if _, err := c.postPrivate(ctx, "/v5/order/cancel", req); err != nil {
 		return fmt.Errorf("failed to cancel order: %w", err)
 	}
-	defer resp.Body.Close()
+	return nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("bybit API error: status %d, body: %s", resp.StatusCode, string(body))
+func (c *Client) AmendOrder(ctx context.Context, req ExchangeAmendRequest) error {
+	req.Category = "spot"
+	req.Timestamp = time.Now().UnixMilli()
+
+	if _, err := c.postPrivate(ctx, "/v5/order/amend", req); err != nil {
+		return fmt.Errorf("failed to amend order: %w", err)
 	}
 	return nil
 }
 
 func (c *Client) FetchOrderInfo(ctx context.Context, symbol string, orderID string) (*ExchangeOrderResponse, error) {
-	timestamp := time.Now().UnixMilli()
-
 	params := url.Values{}
+	params.Set("category", "spot")
 	params.Set("symbol", symbol)
 	params.Set("orderId", orderID)
-	params.Set("timestamp", strconv.FormatInt(timestamp, 10))
 
-	endpoint := "/v5/order/realtime?" + params.Encode()
-	signature := c.createSignature(params.Encode())
-	endpoint += "&signature=" + signature
+	var result struct {
+		List []json.RawMessage `json:"list"`
+	}
+	if err := c.getPrivate(ctx, "/v5/order/realtime", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to fetch order: %w", err)
+	}
+	if len(result.List) == 0 {
+		return nil, ErrOrderNotFound
+	}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", c.getBaseURL()+endpoint, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	var order ExchangeOrderResponse
+	if err := json.Unmarshal(result.List[0], &order); err != nil {
+		return nil, fmt.Errorf("failed to decode order response: %w", err)
 	}
+	order.Raw = result.List[0]
+	return &order, nil
+}
 
-	req.Header.Set("X-BAPI-API-KEY", c.apiKey)
-	req.Header.Set("X-BAPI-SIGN", signature)
-	req.Header.Set("X-BAPI-TIMESTAMP", strconv.FormatInt(timestamp, 10))
+// postPrivate отправляет подписанный POST с JSON телом и возвращает поле result ответа.
+func (c *Client) postPrivate(ctx context.Context, endpoint string, payload interface{}) (json.RawMessage, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(ctx, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", c.getBaseURL()+endpoint, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		c.sign(req, string(body))
+		return req, nil
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("bybit API error: status %d, body: %s", resp.StatusCode, string(body))
-	}
+	return readEnvelope(resp)
+}
+
+// sign подписывает приватный запрос по схеме v5: HMAC от timestamp, ключа, recv_window
+// и строки запроса (GET) или тела (POST).
+func (c *Client) sign(req *http.Request, payload string) {
+	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+
+	req.Header.Set("X-BAPI-API-KEY", c.apiKey)
+	req.Header.Set("X-BAPI-SIGN", c.createSignature(timestamp+c.apiKey+recvWindow+payload))
+	req.Header.Set("X-BAPI-TIMESTAMP", timestamp)
+	req.Header.Set("X-BAPI-RECV-WINDOW", recvWindow)
+}
+
+func (c *Client) createSignature(queryString string) string {
+	h := hmac.New(sha256.New, []byte(c.secretKey))
+	h.Write([]byte(queryString))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+type Ticker struct {
+	Symbol    string `json:"symbol"`
+	LastPrice string `json:"lastPrice"`
+	Bid1Price string `json:"bid1Price"`
+	Ask1Price string `json:"ask1Price"`
+	HighPrice string `json:"highPrice24h"`
+	LowPrice  string `json:"lowPrice24h"`
+	Volume24h string `json:"volume24h"`
+	Turnover  string `json:"turnover24h"`
+}
+
+// OrderBook - снимок стакана: Bids по убыванию цены, Asks по возрастанию.
+type OrderBook struct {
+	Symbol string
+	Bids   []BookLevel
+	Asks   []BookLevel
+	Time   time.Time
+}
+
+type BookLevel struct {
+	Price float64
+	Size  float64
+}
+
+type Kline struct {
+	StartTime int64
+	Open      float64
+	High      float64
+	Low       float64
+	Close     float64
+	Volume    float64
+}
 
-	var apiResp struct {
-		Result struct {
-			List []ExchangeOrderResponse `json:"list"`
-		} `json:"result"`
+func (c *Client) GetTicker(ctx context.Context, symbol string) (*Ticker, error) {
+	params := url.Values{}
+	params.Set("category", "spot")
+	params.Set("symbol", symbol)
+
+	var result struct {
+		List []Ticker `json:"list"`
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
-		return nil, fmt.Errorf("failed to decode order response: %w", err)
+	if err := c.getPublic(ctx, "/v5/market/tickers", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to get ticker: %w", err)
 	}
 
-	if len(apiResp.Result.List) == 0 {
-		return nil, fmt.Errorf("order not found")
+	if len(result.List) == 0 {
+		return nil, fmt.Errorf("ticker not found for %s", symbol)
+	}
+
+	return &result.List[0], nil
+}
+
+// ListTickers возвращает тикеры всех спотовых символов.
+func (c *Client) ListTickers(ctx context.Context) ([]Ticker, error) {
+	params := url.Values{}
+	params.Set("category", "spot")
+
+	var result struct {
+		List []Ticker `json:"list"`
+	}
+	if err := c.getPublic(ctx, "/v5/market/tickers", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to list tickers: %w", err)
 	}
 
-	return &apiResp.Result.List[0], nil
+	return result.List, nil
 }
 
-func (c *Client) makeAuthenticatedRequest(ctx context.Context, method, endpoint string, payload interface{}) (*http.Response, error) {
-	timestamp := time.Now().UnixMilli()
+// OrderBookMaxDepth - максимум уровней на сторону в /v5/market/orderbook для спота.
+const OrderBookMaxDepth = 200
+
+// GetOrderBook возвращает до limit уровней стакана на каждую сторону.
+func (c *Client) GetOrderBook(ctx context.Context, symbol string, limit int) (*OrderBook, error) {
+	params := url.Values{}
+	params.Set("category", "spot")
+	params.Set("symbol", symbol)
+	params.Set("limit", strconv.Itoa(limit))
 
-	var body io.Reader
-	var queryString string
+	var result struct {
+		Bids [][]string `json:"b"`
+		Asks [][]string `json:"a"`
+		Ts   int64      `json:"ts"`
+	}
+	if err := c.getPublic(ctx, "/v5/market/orderbook", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to get order book: %w", err)
+	}
 
-	if method == "GET" || method == "DELETE" {
-		params, err := structToURLValues(payload)
+	return &OrderBook{
+		Symbol: symbol,
+		Bids:   ParseBookLevels(result.Bids),
+		Asks:   ParseBookLevels(result.Asks),
+		Time:   time.UnixMilli(result.Ts),
+	}, nil
+}
+
+// ParseBookLevels разбирает уровни вида [цена, объем, ...], пропуская некорректные.
+func ParseBookLevels(rows [][]string) []BookLevel {
+	levels := make([]BookLevel, 0, len(rows))
+	for _, row := range rows {
+		if len(row) < 2 {
+			continue
+		}
+		price, err := strconv.ParseFloat(row[0], 64)
 		if err != nil {
-			return nil, fmt.Errorf("failed to convert payload to query params: %w", err)
+			continue
 		}
-		queryString = params.Encode()
-	} else {
-		jsonData, err := json.Marshal(payload)
+		size, err := strconv.ParseFloat(row[1], 64)
 		if err != nil {
-			return nil, fmt.Errorf("failed to marshal payload: %w", err)
+			continue
+		}
+		levels = append(levels, BookLevel{Price: price, Size: size})
+	}
+	return levels
+}
+
+// GetKlines возвращает свечи в хронологическом порядке (Bybit отдает от новых к старым).
+func (c *Client) GetKlines(ctx context.Context, symbol, interval string, limit int) ([]Kline, error) {
+	params := url.Values{}
+	params.Set("category", "spot")
+	params.Set("symbol", symbol)
+	params.Set("interval", interval)
+	params.Set("limit", strconv.Itoa(limit))
+
+	var result struct {
+		List [][]string `json:"list"`
+	}
+	if err := c.getPublic(ctx, "/v5/market/kline", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to get klines: %w", err)
+	}
+
+	return parseKlines(result.List), nil
+}
+
+// klinesPageLimit - максимум свечей в одном ответе /v5/market/kline.
+const klinesPageLimit = 1000
+
+// GetKlinesRange возвращает свечи с start по end в хронологическом порядке,
+// запрашивая историю страницами от end к start.
+func (c *Client) GetKlinesRange(ctx context.Context, symbol, interval string, start, end time.Time) ([]Kline, error) {
+	startMs := start.UnixMilli()
+	cursor := end.UnixMilli()
+
+	var klines []Kline
+	for cursor >= startMs {
+		params := url.Values{}
+		params.Set("category", "spot")
+		params.Set("symbol", symbol)
+		params.Set("interval", interval)
+		params.Set("start", strconv.FormatInt(startMs, 10))
+		params.Set("end", strconv.FormatInt(cursor, 10))
+		params.Set("limit", strconv.Itoa(klinesPageLimit))
+
+		var result struct {
+			List [][]string `json:"list"`
+		}
+		if err := c.getPublic(ctx, "/v5/market/kline", params, &result); err != nil {
+			return nil, fmt.Errorf("failed to get klines: %w", err)
+		}
+
+		page := parseKlines(result.List)
+		if len(page) == 0 {
+			break
+		}
+		klines = append(page, klines...)
+		if len(result.List) < klinesPageLimit {
+			break
+		}
+		cursor = page[0].StartTime - 1
+	}
+
+	return klines, nil
+}
+
+// parseKlines разбирает строки свечей Bybit (от новых к старым) в хронологическом порядке.
+func parseKlines(rows [][]string) []Kline {
+	klines := make([]Kline, 0, len(rows))
+	for i := len(rows) - 1; i >= 0; i-- {
+		row := rows[i]
+		if len(row) < 6 {
+			continue
 		}
-		body = bytes.NewBuffer(jsonData)
-		queryString = string(jsonData)
+		var k Kline
+		k.StartTime, _ = strconv.ParseInt(row[0], 10, 64)
+		k.Open, _ = strconv.ParseFloat(row[1], 64)
+		k.High, _ = strconv.ParseFloat(row[2], 64)
+		k.Low, _ = strconv.ParseFloat(row[3], 64)
+		k.Close, _ = strconv.ParseFloat(row[4], 64)
+		k.Volume, _ = strconv.ParseFloat(row[5], 64)
+		klines = append(klines, k)
 	}
+	return klines
+}
 
-	signature := c.createSignature(strconv.FormatInt(timestamp, 10) + c.apiKey + queryString)
+func (c *Client) getPublic(ctx context.Context, endpoint string, params url.Values, result interface{}) error {
+	return c.decodeResponse(ctx, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", c.getBaseURL()+endpoint+"?"+params.Encode(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		return req, nil
+	}, result)
+}
 
-	var requestURL string
-	if method == "GET" || method == "DELETE" {
-		requestURL = c.getBaseURL() + endpoint + "?" + queryString
-	} else {
-		requestURL = c.getBaseURL() + endpoint
+// decodeResponse выполняет запрос и разбирает стандартный конверт v5 API.
+func (c *Client) decodeResponse(ctx context.Context, build requestBuilder, result interface{}) error {
+	resp, err := c.do(ctx, build)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
 	}
+	defer resp.Body.Close()
 
-	req, err := http.NewRequestWithContext(ctx, method, requestURL, body)
+	raw, err := readEnvelope(resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return err
 	}
+	return json.Unmarshal(raw, result)
+}
 
-	req.Header.Set("X-BAPI-API-KEY", c.apiKey)
-	req.Header.Set("X-BAPI-SIGN", signature)
-	req.Header.Set("X-BAPI-TIMESTAMP", strconv.FormatInt(timestamp, 10))
-	req.Header.Set("X-BAPI-RECV-WINDOW", "5000")
+// readEnvelope проверяет HTTP статус и конверт v5 API и возвращает поле result.
+// Биржа отвечает 200 и на большинство отказов, поэтому ненулевой retCode - ошибка *APIError.
+func readEnvelope(resp *http.Response) (json.RawMessage, error) {
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &HTTPError{Status: resp.StatusCode, Body: string(body)}
+	}
 
-	if method == "POST" {
-		req.Header.Set("Content-Type", "application/json")
+	var envelope struct {
+		RetCode int             `json:"retCode"`
+		RetMsg  string          `json:"retMsg"`
+		Result  json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
+	if envelope.RetCode != 0 {
+		return nil, &APIError{RetCode: envelope.RetCode, RetMsg: envelope.RetMsg}
+	}
+	return envelope.Result, nil
+}
 
-	return c.httpClient.Do(req)
+type CoinBalance struct {
+	Coin          string `json:"coin"`
+	WalletBalance string `json:"walletBalance"`
+	Locked        string `json:"locked"`
 }
 
-func (c *Client) createSignature(queryString string) string {
-	h := hmac.New(sha256.New, []byte(c.secretKey))
-	h.Write([]byte(queryString))
-	return hex.EncodeToString(h.Sum(nil))
+var quoteAssets = []string{"USDT", "USDC", "BTC", "ETH", "EUR"}
+
+// BaseAsset возвращает базовую монету спотовой пары: SOLUSDT -> SOL.
+func BaseAsset(symbol string) string {
+	for _, quote := range quoteAssets {
+		if strings.HasSuffix(symbol, quote) && len(symbol) > len(quote) {
+			return strings.TrimSuffix(symbol, quote)
+		}
+	}
+	return symbol
 }
 
-func structToURLValues(v interface{}) (url.Values, error) {
-	data, err := json.Marshal(v)
-	if err != nil {
-		return nil, err
+// QuoteAsset возвращает котируемую монету спотовой пары: SOLUSDT -> USDT.
+func QuoteAsset(symbol string) string {
+	for _, quote := range quoteAssets {
+		if strings.HasSuffix(symbol, quote) && len(symbol) > len(quote) {
+			return quote
+		}
 	}
+	return ""
+}
 
-	var m map[string]interface{}
-	if err := json.Unmarshal(data, &m); err != nil {
-		return nil, err
+// ListOpenOrders возвращает все активные спотовые ордера по символу, включая выставленные вручную.
+func (c *Client) ListOpenOrders(ctx context.Context, symbol string) ([]ExchangeOrderResponse, error) {
+	params := url.Values{}
+	params.Set("category", "spot")
+	// Без символа биржа отдает открытые ордера всех спотовых пар
+	if symbol != "" {
+		params.Set("symbol", symbol)
+	}
+	params.Set("openOnly", "0")
+
+	var result struct {
+		List []ExchangeOrderResponse `json:"list"`
+	}
+	if err := c.getPrivate(ctx, "/v5/order/realtime", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to list open orders: %w", err)
+	}
+
+	return result.List, nil
+}
+
+// FetchOrderByLinkID ищет ордер по orderLinkId, в том числе недавно исполненный.
+func (c *Client) FetchOrderByLinkID(ctx context.Context, symbol string, linkID string) (*ExchangeOrderResponse, error) {
+	params := url.Values{}
+	params.Set("category", "spot")
+	params.Set("symbol", symbol)
+	params.Set("orderLinkId", linkID)
+
+	var result struct {
+		List []json.RawMessage `json:"list"`
+	}
+	if err := c.getPrivate(ctx, "/v5/order/realtime", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to fetch order by link id: %w", err)
+	}
+	if len(result.List) == 0 {
+		return nil, ErrOrderNotFound
+	}
+
+	var order ExchangeOrderResponse
+	if err := json.Unmarshal(result.List[0], &order); err != nil {
+		return nil, fmt.Errorf("failed to decode order response: %w", err)
+	}
+	order.Raw = result.List[0]
+	return &order, nil
+}
+
+// FetchOrderHistory ищет закрытый ордер в истории: /v5/order/realtime отдает только
+// последние закрытые ордера, более старые есть лишь в /v5/order/history.
+func (c *Client) FetchOrderHistory(ctx context.Context, symbol string, orderID string) (*ExchangeOrderResponse, error) {
+	params := url.Values{}
+	params.Set("category", "spot")
+	params.Set("symbol", symbol)
+	params.Set("orderId", orderID)
+
+	var result struct {
+		List []json.RawMessage `json:"list"`
+	}
+	if err := c.getPrivate(ctx, "/v5/order/history", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to fetch order history: %w", err)
+	}
+	if len(result.List) == 0 {
+		return nil, ErrOrderNotFound
+	}
+
+	var order ExchangeOrderResponse
+	if err := json.Unmarshal(result.List[0], &order); err != nil {
+		return nil, fmt.Errorf("failed to decode order response: %w", err)
+	}
+	order.Raw = result.List[0]
+	return &order, nil
+}
+
+// Execution - одна сделка по ордеру: точные цена, объем, комиссия и время исполнения.
+type Execution struct {
+	ExecID  string `json:"execId"`
+	OrderID string `json:"orderId"`
+	Price   string `json:"execPrice"`
+	Qty     string `json:"execQty"`
+	Value   string `json:"execValue"`
+	Fee     string `json:"execFee"`
+	Time    string `json:"execTime"` // Время исполнения на бирже, мс
+}
+
+// ListExecutions возвращает исполнения ордера.
+func (c *Client) ListExecutions(ctx context.Context, symbol string, orderID string) ([]Execution, error) {
+	params := url.Values{}
+	params.Set("category", "spot")
+	params.Set("symbol", symbol)
+	params.Set("orderId", orderID)
+	params.Set("limit", "100")
+
+	var result struct {
+		List []Execution `json:"list"`
+	}
+	if err := c.getPrivate(ctx, "/v5/execution/list", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to list executions: %w", err)
+	}
+	return result.List, nil
+}
+
+// GetWalletBalance возвращает балансы всех монет единого торгового счета.
+func (c *Client) GetWalletBalance(ctx context.Context) ([]CoinBalance, error) {
+	params := url.Values{}
+	params.Set("accountType", "UNIFIED")
+
+	var result struct {
+		List []struct {
+			Coin []CoinBalance `json:"coin"`
+		} `json:"list"`
+	}
+	if err := c.getPrivate(ctx, "/v5/account/wallet-balance", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to get wallet balance: %w", err)
+	}
+
+	balances := make([]CoinBalance, 0)
+	for _, account := range result.List {
+		balances = append(balances, account.Coin...)
+	}
+	return balances, nil
+}
+
+func (c *Client) GetBalance(ctx context.Context, coin string) (*CoinBalance, error) {
+	params := url.Values{}
+	params.Set("accountType", "UNIFIED")
+	params.Set("coin", coin)
+
+	var result struct {
+		List []struct {
+			Coin []CoinBalance `json:"coin"`
+		} `json:"list"`
+	}
+	if err := c.getPrivate(ctx, "/v5/account/wallet-balance", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
 	}
 
-	values := url.Values{}
-	for key, val := range m {
-		if val != nil {
-			switch v := val.(type) {
-			case string:
-				if v != "" {
-					values.Set(key, v)
-				}
-			case float64:
-				values.Set(key, strconv.FormatFloat(v, 'f', -1, 64))
-			case bool:
-				values.Set(key, strconv.FormatBool(v))
-			default:
-				values.Set(key, fmt.Sprintf("%v", v))
+	for _, account := range result.List {
+		for _, balance := range account.Coin {
+			if balance.Coin == coin {
+				return &balance, nil
 			}
 		}
 	}
 
-	return values, nil
+	return &CoinBalance{Coin: coin, WalletBalance: "0", Locked: "0"}, nil
+}
+
+// WalletChange - изменение баланса монеты от исполнения ордера.
+type WalletChange struct {
+	Coin    string `json:"currency"`
+	OrderID string `json:"orderId"`
+	Change  string `json:"change"` // С учетом комиссии: у продажи приход котируемой валюты
+}
+
+// ListWalletChanges возвращает изменения баланса монеты от спотовых сделок начиная с since.
+func (c *Client) ListWalletChanges(ctx context.Context, coin string, since time.Time) ([]WalletChange, error) {
+	params := url.Values{}
+	params.Set("accountType", "UNIFIED")
+	params.Set("category", "spot")
+	params.Set("currency", coin)
+	params.Set("type", "TRADE")
+	params.Set("startTime", strconv.FormatInt(since.UnixMilli(), 10))
+	params.Set("limit", "50")
+
+	var result struct {
+		List []WalletChange `json:"list"`
+	}
+	if err := c.getPrivate(ctx, "/v5/account/transaction-log", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to list wallet changes: %w", err)
+	}
+	return result.List, nil
+}
+
+func (c *Client) getPrivate(ctx context.Context, endpoint string, params url.Values, result interface{}) error {
+	queryString := params.Encode()
+
+	return c.decodeResponse(ctx, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", c.getBaseURL()+endpoint+"?"+queryString, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		c.sign(req, queryString)
+		return req, nil
+	}, result)
+}
+
+// InstrumentInfo - торговые фильтры спотового символа.
+type InstrumentInfo struct {
+	Symbol         string `json:"symbol"`
+	TickSize       string `json:"tick_size"`       // Шаг цены
+	QtyStep        string `json:"qty_step"`        // Шаг количества базовой монеты
+	QuotePrecision string `json:"quote_precision"` // Шаг суммы в котируемой монете
+	MinOrderQty    string `json:"min_order_qty"`
+	MinOrderAmt    string `json:"min_order_amt"`
+}
+
+func (c *Client) GetInstrumentInfo(ctx context.Context, symbol string) (*InstrumentInfo, error) {
+	params := url.Values{}
+	params.Set("category", "spot")
+	params.Set("symbol", symbol)
+
+	var result struct {
+		List []struct {
+			Symbol        string `json:"symbol"`
+			LotSizeFilter struct {
+				BasePrecision  string `json:"basePrecision"`
+				QuotePrecision string `json:"quotePrecision"`
+				MinOrderQty    string `json:"minOrderQty"`
+				MinOrderAmt    string `json:"minOrderAmt"`
+			} `json:"lotSizeFilter"`
+			PriceFilter struct {
+				TickSize string `json:"tickSize"`
+			} `json:"priceFilter"`
+		} `json:"list"`
+	}
+	if err := c.getPublic(ctx, "/v5/market/instruments-info", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to get instrument info: %w", err)
+	}
+
+	if len(result.List) == 0 {
+		return nil, fmt.Errorf("instrument not found for %s", symbol)
+	}
+
+	item := result.List[0]
+	return &InstrumentInfo{
+		Symbol:         item.Symbol,
+		TickSize:       item.PriceFilter.TickSize,
+		QtyStep:        item.LotSizeFilter.BasePrecision,
+		QuotePrecision: item.LotSizeFilter.QuotePrecision,
+		MinOrderQty:    item.LotSizeFilter.MinOrderQty,
+		MinOrderAmt:    item.LotSizeFilter.MinOrderAmt,
+	}, nil
 }
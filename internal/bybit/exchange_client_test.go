@@ -0,0 +1,83 @@
+package bybit
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// capturingClient запоминает отправленные запросы и отвечает одним и тем же result.
+func capturingClient(result string) (*Client, *[]*http.Request, *[][]byte) {
+	var requests []*http.Request
+	var bodies [][]byte
+	client := NewExchangeClient("key", "secret", false)
+	client.SetTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		var body []byte
+		if req.Body != nil {
+			body, _ = io.ReadAll(req.Body)
+		}
+		requests = append(requests, req)
+		bodies = append(bodies, body)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(bytes.NewReader([]byte(`{"retCode":0,"retMsg":"OK","result":` + result + `}`))),
+			Request:    req,
+		}, nil
+	}))
+	return client, &requests, &bodies
+}
+
+func assertV5Signature(t *testing.T, req *http.Request, payload string) {
+	t.Helper()
+
+	assert.Equal(t, "key", req.Header.Get("X-BAPI-API-KEY"))
+	assert.Equal(t, recvWindow, req.Header.Get("X-BAPI-RECV-WINDOW"))
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte(req.Header.Get("X-BAPI-TIMESTAMP") + "key" + recvWindow + payload))
+	assert.Equal(t, hex.EncodeToString(mac.Sum(nil)), req.Header.Get("X-BAPI-SIGN"))
+	assert.Empty(t, req.URL.Query().Get("signature"))
+}
+
+func TestPrivateRequestsUseV5Signature(t *testing.T) {
+	client, requests, bodies := capturingClient(`{"orderId":"1","orderLinkId":"link","list":[{"orderId":"1"}]}`)
+	ctx := context.Background()
+
+	_, err := client.ExecuteOrder(ctx, ExchangeOrderRequest{Symbol: "BTCUSDT", Side: "Buy", OrderType: "Limit", Qty: "1", Price: "100"})
+	require.NoError(t, err)
+	require.NoError(t, client.TerminateOrder(ctx, ExchangeCancelRequest{Symbol: "BTCUSDT", OrderID: "1"}))
+	require.NoError(t, client.AmendOrder(ctx, ExchangeAmendRequest{Symbol: "BTCUSDT", OrderID: "1", Price: "101"}))
+	_, err = client.FetchOrderInfo(ctx, "BTCUSDT", "1")
+	require.NoError(t, err)
+	require.Len(t, *requests, 4)
+
+	// POST подписывается телом, и в каждом теле ордера указана категория
+	for i, req := range (*requests)[:3] {
+		body := (*bodies)[i]
+		assertV5Signature(t, req, string(body))
+		var fields map[string]interface{}
+		require.NoError(t, json.Unmarshal(body, &fields))
+		assert.Equal(t, "spot", fields["category"], req.URL.Path)
+	}
+
+	// GET подписывается строкой запроса
+	info := (*requests)[3]
+	assert.Equal(t, "/v5/order/realtime", info.URL.Path)
+	assert.Equal(t, "spot", info.URL.Query().Get("category"))
+	assertV5Signature(t, info, info.URL.RawQuery)
+}
@@ -0,0 +1,187 @@
+package bybit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// replayClient отвечает записанными ответами из testdata/replay без обращения к API.
+func replayClient() *Client {
+	client := NewExchangeClient("key", "secret", false)
+	client.SetTransport(NewReplayTransport("testdata/replay"))
+	return client
+}
+
+func TestFixtureKeyIgnoresVolatileFields(t *testing.T) {
+	first, err := http.NewRequest("GET", "https://api.bybit.com/v5/order/realtime?symbol=BTCUSDT&orderId=1&timestamp=1&signature=aa&recv_window=5000", nil)
+	require.NoError(t, err)
+	second, err := http.NewRequest("GET", "https://api.bybit.com/v5/order/realtime?orderId=1&symbol=BTCUSDT&timestamp=2&signature=bb", nil)
+	require.NoError(t, err)
+
+	firstKey, err := fixtureKey(first)
+	require.NoError(t, err)
+	secondKey, err := fixtureKey(second)
+	require.NoError(t, err)
+	assert.Equal(t, "GET_v5_order_realtime_orderId=1_symbol=BTCUSDT.json", firstKey)
+	assert.Equal(t, firstKey, secondKey)
+}
+
+func TestFixtureKeyHashesPostBody(t *testing.T) {
+	post := func(body string) string {
+		req, err := http.NewRequest("POST", "https://api.bybit.com/v5/order/create", bytes.NewReader([]byte(body)))
+		require.NoError(t, err)
+		key, err := fixtureKey(req)
+		require.NoError(t, err)
+		return key
+	}
+
+	// Метка времени и порядок полей на ключ не влияют, параметры ордера - влияют
+	buy := post(`{"symbol":"BTCUSDT","side":"Buy","price":"100","timestamp":1}`)
+	assert.Equal(t, buy, post(`{"timestamp":2,"price":"100","side":"Buy","symbol":"BTCUSDT"}`))
+	assert.NotEqual(t, buy, post(`{"symbol":"BTCUSDT","side":"Buy","price":"101","timestamp":1}`))
+}
+
+func TestFixtureKeyKeepsBodyForTransport(t *testing.T) {
+	body := `{"symbol":"BTCUSDT"}`
+	req, err := http.NewRequest("POST", "https://api.bybit.com/v5/order/cancel", bytes.NewReader([]byte(body)))
+	require.NoError(t, err)
+	req.GetBody = nil
+
+	_, err = fixtureKey(req)
+	require.NoError(t, err)
+
+	var sent bytes.Buffer
+	_, err = sent.ReadFrom(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, body, sent.String())
+}
+
+func TestReplayParsesTicker(t *testing.T) {
+	ticker, err := replayClient().GetTicker(context.Background(), "BTCUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, "63000", ticker.LastPrice)
+	assert.Equal(t, "62999.9", ticker.Bid1Price)
+	assert.Equal(t, "63000", ticker.Ask1Price)
+	assert.Equal(t, "12987.45", ticker.Volume24h)
+}
+
+func TestReplayParsesInstrumentInfo(t *testing.T) {
+	info, err := replayClient().GetInstrumentInfo(context.Background(), "BTCUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, &InstrumentInfo{
+		Symbol:         "BTCUSDT",
+		TickSize:       "0.01",
+		QtyStep:        "0.000001",
+		QuotePrecision: "0.00000001",
+		MinOrderQty:    "0.000048",
+		MinOrderAmt:    "1",
+	}, info)
+}
+
+func TestReplayParsesCreateOrder(t *testing.T) {
+	order, err := replayClient().ExecuteOrder(context.Background(), ExchangeOrderRequest{
+		Symbol:      "BTCUSDT",
+		Side:        "Buy",
+		OrderType:   "Limit",
+		Qty:         "0.001619",
+		Price:       "61740",
+		TimeInForce: "GTC",
+		OrderLinkID: "dca-1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "1868412334150428928", order.OrderID)
+	assert.Equal(t, "dca-1", order.OrderLinkID)
+	// Ответ на создание содержит только идентификаторы
+	assert.Equal(t, "New", order.Status)
+	assert.Empty(t, order.Price)
+}
+
+func TestReplayParsesCancelOrder(t *testing.T) {
+	client := replayClient()
+
+	err := client.TerminateOrder(context.Background(), ExchangeCancelRequest{Symbol: "BTCUSDT", OrderID: "1868412334150428928"})
+	require.NoError(t, err)
+
+	// Отмена несуществующего ордера - отказ API с кодом, по которому сервис понимает, что ордера нет
+	err = client.TerminateOrder(context.Background(), ExchangeCancelRequest{Symbol: "BTCUSDT", OrderID: "1868412334150429999"})
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, 170213, apiErr.RetCode)
+}
+
+func TestReplayParsesOrderInfo(t *testing.T) {
+	order, err := replayClient().FetchOrderInfo(context.Background(), "BTCUSDT", "1868412334150428928")
+	require.NoError(t, err)
+	assert.Equal(t, "dca-1", order.OrderLinkID)
+	assert.Equal(t, "PartiallyFilled", order.Status)
+	assert.Equal(t, "61740", order.Price)
+	assert.Equal(t, "0.001619", order.Qty)
+	assert.Equal(t, "0.001", order.ExecutedQty)
+	assert.NotEmpty(t, order.Raw)
+}
+
+func TestReplayParsesOrderByLinkID(t *testing.T) {
+	client := replayClient()
+
+	order, err := client.FetchOrderByLinkID(context.Background(), "BTCUSDT", "dca-1")
+	require.NoError(t, err)
+	assert.Equal(t, "PartiallyFilled", order.Status)
+	assert.Equal(t, "0.001", order.ExecutedQty)
+	assert.Equal(t, "61.74", order.ExecutedValue)
+	assert.Equal(t, "0.000001", order.ExecutedFee)
+	assert.Equal(t, "1760000060000", order.UpdatedTime)
+	assert.NotEmpty(t, order.Raw)
+
+	_, err = client.FetchOrderByLinkID(context.Background(), "BTCUSDT", "missing")
+	assert.True(t, errors.Is(err, ErrOrderNotFound))
+}
+
+func TestReplayParsesOrderHistory(t *testing.T) {
+	order, err := replayClient().FetchOrderHistory(context.Background(), "BTCUSDT", "1868412334150428000")
+	require.NoError(t, err)
+	assert.Equal(t, "Filled", order.Status)
+	assert.Equal(t, "Market", order.OrderType)
+	assert.Equal(t, "0", order.Price)
+	assert.Equal(t, "0.001587", order.ExecutedQty)
+	assert.Equal(t, "99.981", order.ExecutedValue)
+}
+
+func TestReplayParsesExecutions(t *testing.T) {
+	executions, err := replayClient().ListExecutions(context.Background(), "BTCUSDT", "1868412334150428000")
+	require.NoError(t, err)
+	require.Len(t, executions, 2)
+	assert.Equal(t, Execution{
+		ExecID:  "2100000000061016141",
+		OrderID: "1868412334150428000",
+		Price:   "62999.9",
+		Qty:     "0.001",
+		Value:   "62.9999",
+		Fee:     "0.000001",
+		Time:    "1759999000118",
+	}, executions[0])
+	assert.Equal(t, "63000.1", executions[1].Price)
+}
+
+func TestReplayParsesWalletBalance(t *testing.T) {
+	balances, err := replayClient().GetWalletBalance(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []CoinBalance{
+		{Coin: "USDT", WalletBalance: "1150.2", Locked: "100"},
+		{Coin: "BTC", WalletBalance: "0.001587", Locked: "0"},
+	}, balances)
+}
+
+func TestReplayParsesOpenOrders(t *testing.T) {
+	orders, err := replayClient().ListOpenOrders(context.Background(), "BTCUSDT")
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	assert.Equal(t, "1868412334150428928", orders[0].OrderID)
+	assert.Equal(t, "61740", orders[0].Price)
+	assert.Equal(t, "0.001619", orders[0].Qty)
+}
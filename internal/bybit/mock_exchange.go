@@ -2,12 +2,22 @@ package bybit
 
 import (
 	"context"
+	"sync"
+	"time"
+
+	"cryptorg/pkg/latency"
+	"cryptorg/pkg/ratelimit"
 
 	"github.com/stretchr/testify/mock"
 )
 
 type MockClient struct {
 	mock.Mock
+
+	trackersOnce sync.Once
+	latency      *latency.Tracker
+	rateLimits   *ratelimit.Tracker
+	clock        *latency.ClockOffset
 }
 
 func (m *MockClient) ExecuteOrder(ctx context.Context, req ExchangeOrderRequest) (*ExchangeOrderResponse, error) {
@@ -30,3 +40,135 @@ func (m *MockClient) FetchOrderInfo(ctx context.Context, symbol string, orderID
 	}
 	return args.Get(0).(*ExchangeOrderResponse), args.Error(1)
 }
+
+func (m *MockClient) FetchOrderByLinkID(ctx context.Context, symbol string, linkID string) (*ExchangeOrderResponse, error) {
+	args := m.Called(ctx, symbol, linkID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ExchangeOrderResponse), args.Error(1)
+}
+
+func (m *MockClient) AmendOrder(ctx context.Context, req ExchangeAmendRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *MockClient) GetTicker(ctx context.Context, symbol string) (*Ticker, error) {
+	args := m.Called(ctx, symbol)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Ticker), args.Error(1)
+}
+
+func (m *MockClient) ListTickers(ctx context.Context) ([]Ticker, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Ticker), args.Error(1)
+}
+
+func (m *MockClient) GetOrderBook(ctx context.Context, symbol string, limit int) (*OrderBook, error) {
+	args := m.Called(ctx, symbol, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*OrderBook), args.Error(1)
+}
+
+func (m *MockClient) GetKlines(ctx context.Context, symbol, interval string, limit int) ([]Kline, error) {
+	args := m.Called(ctx, symbol, interval, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Kline), args.Error(1)
+}
+
+func (m *MockClient) GetKlinesRange(ctx context.Context, symbol, interval string, start, end time.Time) ([]Kline, error) {
+	args := m.Called(ctx, symbol, interval, start, end)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Kline), args.Error(1)
+}
+
+func (m *MockClient) ListOpenOrders(ctx context.Context, symbol string) ([]ExchangeOrderResponse, error) {
+	args := m.Called(ctx, symbol)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]ExchangeOrderResponse), args.Error(1)
+}
+
+func (m *MockClient) FetchOrderHistory(ctx context.Context, symbol string, orderID string) (*ExchangeOrderResponse, error) {
+	args := m.Called(ctx, symbol, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ExchangeOrderResponse), args.Error(1)
+}
+
+func (m *MockClient) ListExecutions(ctx context.Context, symbol string, orderID string) ([]Execution, error) {
+	args := m.Called(ctx, symbol, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Execution), args.Error(1)
+}
+
+func (m *MockClient) GetBalance(ctx context.Context, coin string) (*CoinBalance, error) {
+	args := m.Called(ctx, coin)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*CoinBalance), args.Error(1)
+}
+
+func (m *MockClient) ListWalletChanges(ctx context.Context, coin string, since time.Time) ([]WalletChange, error) {
+	args := m.Called(ctx, coin, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]WalletChange), args.Error(1)
+}
+
+func (m *MockClient) GetWalletBalance(ctx context.Context) ([]CoinBalance, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]CoinBalance), args.Error(1)
+}
+
+func (m *MockClient) GetInstrumentInfo(ctx context.Context, symbol string) (*InstrumentInfo, error) {
+	args := m.Called(ctx, symbol)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*InstrumentInfo), args.Error(1)
+}
+
+// Latency, RateLimits и Clock не ожидаются через On: трекеры нужны только для статистики,
+// поэтому мок отдает пустые экземпляры.
+func (m *MockClient) Latency() *latency.Tracker {
+	m.trackersOnce.Do(m.initTrackers)
+	return m.latency
+}
+
+func (m *MockClient) RateLimits() *ratelimit.Tracker {
+	m.trackersOnce.Do(m.initTrackers)
+	return m.rateLimits
+}
+
+func (m *MockClient) Clock() *latency.ClockOffset {
+	m.trackersOnce.Do(m.initTrackers)
+	return m.clock
+}
+
+func (m *MockClient) initTrackers() {
+	m.latency = latency.NewTracker(latency.DefaultWindow)
+	m.rateLimits = ratelimit.NewTracker(ratelimit.DefaultWindow, ratelimit.DefaultReserve)
+	m.clock = latency.NewClockOffset()
+}
@@ -0,0 +1,146 @@
+package bybit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"cryptorg/pkg/ratelimit"
+)
+
+const (
+	maxAttempts    = 5
+	retryBaseDelay = 200 * time.Millisecond
+	retryMaxDelay  = 5 * time.Second
+)
+
+// Коды v5 API, при которых запрос не был обработан и его можно повторить
+const (
+	retCodeTooManyVisits  = 10006
+	retCodeServerError    = 10016
+	retCodeFreqProtection = 10429
+)
+
+// Категории эндпоинтов с общими лимитами; частоты с запасом от лимитов Bybit для спота.
+const (
+	categoryTrade  = "trade"  // Создание, изменение и отмена ордеров
+	categoryQuery  = "query"  // Приватные запросы ордеров и баланса
+	categoryMarket = "market" // Публичные рыночные данные
+)
+
+func newCategoryBuckets() map[string]*ratelimit.Bucket {
+	return map[string]*ratelimit.Bucket{
+		categoryTrade:  ratelimit.NewBucket(10, 10),
+		categoryQuery:  ratelimit.NewBucket(20, 20),
+		categoryMarket: ratelimit.NewBucket(50, 50),
+	}
+}
+
+func endpointCategory(method, path string) string {
+	switch {
+	case strings.HasPrefix(path, "/v5/market/"):
+		return categoryMarket
+	case method == http.MethodPost:
+		return categoryTrade
+	default:
+		return categoryQuery
+	}
+}
+
+// requestBuilder собирает запрос заново для каждой попытки: подпись включает время,
+// и повтор со старой подписью биржа отклонит по recv_window.
+type requestBuilder func(ctx context.Context) (*http.Request, error)
+
+// do выполняет запрос с ограничением частоты по категории эндпоинта и повторами
+// с экспоненциальной задержкой. POST повторяется только при отказе по лимиту, когда
+// биржа гарантированно его не обработала: ответ 5xx на создание ордера не значит,
+// что ордера нет.
+func (c *Client) do(ctx context.Context, build requestBuilder) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		req, err := build(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if bucket := c.buckets[endpointCategory(req.Method, req.URL.Path)]; bucket != nil {
+			if err := bucket.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := c.send(req)
+		retry := attempt < maxAttempts && c.retryable(req, resp, err)
+		if !retry {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(retryDelay(attempt))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (c *Client) retryable(req *http.Request, resp *http.Response, err error) bool {
+	idempotent := req.Method == http.MethodGet
+	if err != nil {
+		return idempotent && req.Context().Err() == nil
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return true
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return idempotent
+	case resp.StatusCode != http.StatusOK:
+		return false
+	}
+
+	switch peekRetCode(resp) {
+	case retCodeTooManyVisits, retCodeFreqProtection:
+		c.rateLimits.Reject(req.URL.Path)
+		return true
+	case retCodeServerError:
+		return idempotent
+	}
+	return false
+}
+
+// peekRetCode читает retCode из конверта, оставляя тело ответа доступным для разбора.
+func peekRetCode(resp *http.Response) int {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return 0
+	}
+
+	var envelope struct {
+		RetCode int `json:"retCode"`
+	}
+	if json.Unmarshal(body, &envelope) != nil {
+		return 0
+	}
+	return envelope.RetCode
+}
+
+// retryDelay - экспоненциальная задержка со случайным разбросом в ее верхней половине,
+// чтобы параллельные запросы не повторялись одновременно.
+func retryDelay(attempt int) time.Duration {
+	delay := retryBaseDelay << (attempt - 1)
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
@@ -0,0 +1,11 @@
+//go:build !chaos
+
+package chaos
+
+func Enabled() bool {
+	return false
+}
+
+func Inject(point string) error {
+	return nil
+}
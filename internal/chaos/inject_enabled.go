@@ -0,0 +1,89 @@
+//go:build chaos
+
+package chaos
+
+import (
+	"log"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// CHAOS_POINTS=after_entry:kill,exchange_response:drop:0.3
+// Формат: точка:действие[:вероятность], действия - kill (SIGKILL процесса) и drop (ошибка в точке).
+
+type fault struct {
+	action      string
+	probability float64
+}
+
+var (
+	loadOnce sync.Once
+	mu       sync.RWMutex
+	faults   map[string]fault
+)
+
+func load() {
+	faults = parse(os.Getenv("CHAOS_POINTS"))
+	log.Printf("Chaos hooks enabled: %v", faults)
+}
+
+// Configure заменяет сбои из CHAOS_POINTS на заданные тем же форматом; пустая строка
+// отключает все точки. Нужен тестам, которые включают сбои по очереди.
+func Configure(spec string) {
+	parsed := parse(spec)
+	loadOnce.Do(func() {})
+
+	mu.Lock()
+	faults = parsed
+	mu.Unlock()
+}
+
+func parse(spec string) map[string]fault {
+	parsed := make(map[string]fault)
+	for _, item := range strings.Split(spec, ",") {
+		parts := strings.Split(strings.TrimSpace(item), ":")
+		if len(parts) < 2 {
+			continue
+		}
+
+		f := fault{action: parts[1], probability: 1}
+		if len(parts) > 2 {
+			if p, err := strconv.ParseFloat(parts[2], 64); err == nil {
+				f.probability = p
+			}
+		}
+		parsed[parts[0]] = f
+	}
+	return parsed
+}
+
+func Enabled() bool {
+	return true
+}
+
+func Inject(point string) error {
+	loadOnce.Do(load)
+
+	mu.RLock()
+	f, exists := faults[point]
+	mu.RUnlock()
+	if !exists || rand.Float64() >= f.probability {
+		return nil
+	}
+
+	switch f.action {
+	case "kill":
+		log.Printf("Chaos: killing process at %s", point)
+		syscall.Kill(os.Getpid(), syscall.SIGKILL)
+		select {}
+	case "drop":
+		log.Printf("Chaos: dropping at %s", point)
+		return ErrInjected
+	}
+
+	return nil
+}
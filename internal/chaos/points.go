@@ -0,0 +1,16 @@
+package chaos
+
+import "errors"
+
+// Точки жизненного цикла сделки, в которых можно внедрить сбой (только в сборке с тегом chaos).
+const (
+	PointAfterEntry       = "after_entry"
+	PointBeforeTakeProfit = "before_take_profit"
+	PointBeforeDCAGrid    = "before_dca_grid"
+	PointAfterDCAFill     = "after_dca_fill"
+	PointBeforeTPReplace  = "before_tp_replace"
+	PointBeforeFinalize   = "before_finalize"
+	PointExchangeResponse = "exchange_response"
+)
+
+var ErrInjected = errors.New("chaos: injected failure")
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// AccountBalance - балансы монет на счете биржи.
+type AccountBalance struct {
+	Coins     []CoinBalance `json:"coins"`
+	Timestamp time.Time     `json:"timestamp"`
+}
+
+type CoinBalance struct {
+	Coin   string `json:"coin"`
+	Total  string `json:"total"`
+	Locked string `json:"locked"` // Заблокировано в ордерах
+	Free   string `json:"free"`
+}
@@ -0,0 +1,37 @@
+package domain
+
+// BacktestCompareRequest - два конфига, прогоняемые по одной и той же серии свечей.
+type BacktestCompareRequest struct {
+	Symbol   string      `json:"symbol"`
+	Interval string      `json:"interval"` // Интервал свечей Bybit: 1, 5, 15, 60, 240, D; по умолчанию 60
+	From     string      `json:"from"`     // Дата начала, YYYY-MM-DD (UTC)
+	To       string      `json:"to"`       // Дата конца включительно, YYYY-MM-DD (UTC)
+	A        TradeConfig `json:"a"`
+	B        TradeConfig `json:"b"`
+}
+
+// BacktestReport - итог прогона одного конфига. Суммы в котируемой валюте.
+type BacktestReport struct {
+	Cycles              int     `json:"cycles"`      // Циклы, закрытые по TP или SL
+	StopLosses          int     `json:"stop_losses"` // Из них закрыто по SL
+	DCAFills            int     `json:"dca_fills"`
+	RealizedPnL         string  `json:"realized_pnl"`
+	UnrealizedPnL       string  `json:"unrealized_pnl"` // Открытая позиция по закрытию последней свечи
+	PnLPercent          float64 `json:"pnl_percent"`    // Итоговый PnL к максимально задействованному капиталу
+	MaxDrawdown         string  `json:"max_drawdown"`   // Наибольшее падение equity от пика
+	MaxDrawdownPercent  float64 `json:"max_drawdown_percent"`
+	MaxCapitalUsed      string  `json:"max_capital_used"`      // Наибольшая сумма, вложенная в позицию одновременно
+	RequiredCapital     string  `json:"required_capital"`      // Вход и все уровни сетки
+	CapitalUsagePercent float64 `json:"capital_usage_percent"` // MaxCapitalUsed к RequiredCapital
+	OpenAtEnd           bool    `json:"open_at_end"`
+}
+
+type BacktestComparison struct {
+	Symbol   string          `json:"symbol"`
+	Interval string          `json:"interval"`
+	From     string          `json:"from"`
+	To       string          `json:"to"`
+	Candles  int             `json:"candles"`
+	A        *BacktestReport `json:"a"`
+	B        *BacktestReport `json:"b"`
+}
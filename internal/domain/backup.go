@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const BackupFormatVersion = 1
+
+// StorageBackup - согласованный снимок хранилища: журнал событий (с ним и снимки сделок),
+// боты с шаблонами сделок и переопределения точности. Инкрементальная копия содержит только
+// события с Seq > FromSeq и восстанавливается поверх предыдущей копии цепочки.
+type StorageBackup struct {
+	Version            int                          `json:"version"`
+	CreatedAt          time.Time                    `json:"created_at"`
+	FromSeq            uint64                       `json:"from_seq"` // 0 - полная копия
+	LastSeq            uint64                       `json:"last_seq"`
+	Events             []TradeEvent                 `json:"events"`
+	PrecisionOverrides map[string]PrecisionOverride `json:"precision_overrides"`
+	Bots               map[uuid.UUID]Bot            `json:"bots"` // nil - копия старого формата без ботов
+}
+
+// IsFull сообщает, что копия не зависит от предыдущих.
+func (b *StorageBackup) IsFull() bool {
+	return b.FromSeq == 0
+}
@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// DefaultSlippageWarnPercent - проскальзывание входа, начиная с которого симуляция добавляет предупреждение.
+const DefaultSlippageWarnPercent = 0.5
+
+// BookSimulation - прогон входа и сетки по текущему стакану до выставления ордеров.
+type BookSimulation struct {
+	Side             OrderSide   `json:"side"`               // Сторона входа
+	BestPrice        string      `json:"best_price"`         // Лучшая цена встречной стороны
+	ExpectedAvgPrice string      `json:"expected_avg_price"` // Средняя цена рыночного входа по видимым уровням
+	SlippagePercent  float64     `json:"slippage_percent"`   // Отклонение средней цены от лучшей
+	EntryVolume      string      `json:"entry_volume"`       // Сумма входа в котируемой валюте
+	FilledVolume     string      `json:"filled_volume"`      // Сколько из нее покрывает видимый стакан
+	Covered          bool        `json:"covered"`            // Видимого стакана хватает на весь вход
+	LevelsConsumed   int         `json:"levels_consumed"`    // Уровней стакана, съеденных входом
+	BookDepth        int         `json:"book_depth"`         // Уровней на сторону в выборке
+	Grid             []GridDepth `json:"grid"`
+	Warnings         []string    `json:"warnings,omitempty"`
+	SimulatedAt      time.Time   `json:"simulated_at"`
+}
+
+// GridDepth - положение уровня DCA относительно видимой глубины.
+type GridDepth struct {
+	Index       int    `json:"index"`
+	Price       string `json:"price"`
+	VolumeAhead string `json:"volume_ahead"` // Видимый объем в котируемой валюте по ценам лучше уровня
+	WithinBook  bool   `json:"within_book"`  // Уровень внутри видимой глубины стакана
+	Crosses     bool   `json:"crosses"`      // Уровень за лучшей встречной ценой и исполнится сразу
+}
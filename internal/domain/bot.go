@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const DefaultMaxConcurrentDeals = 1
+
+// Bot - шаблон сделки, по которому новые сделки открываются сами, пока открыто меньше
+// MaxConcurrentDeals: закрытая сделка освобождает место для следующего цикла.
+// RealizedProfit копит результат закрытых сделок бота; когда он доходит до ProfitTarget,
+// бот перестает открывать сделки, а открытые доводятся до тейк-профита.
+type Bot struct {
+	ID                 uuid.UUID       `json:"id"`
+	Name               string          `json:"name"`
+	Symbol             string          `json:"symbol"`
+	Exchange           string          `json:"exchange,omitempty"` // Биржа сделок бота; пусто - EXCHANGE
+	Config             TradeConfig     `json:"config"`
+	MaxConcurrentDeals int             `json:"max_concurrent_deals"`
+	Enabled            bool            `json:"enabled"`
+	DealsStarted       int             `json:"deals_started"`
+	ProfitTarget       string          `json:"profit_target,omitempty"` // В котируемой валюте; пусто - без цели
+	RealizedProfit     string          `json:"realized_profit,omitempty"`
+	TargetReachedAt    *time.Time      `json:"target_reached_at,omitempty"` // После этого новые сделки не открываются
+	OpenDeals          int             `json:"open_deals"`                  // Считается при чтении, не хранится
+	LastError          string          `json:"last_error,omitempty"`        // Последняя ошибка открытия сделки
+	LastErrorAt        *time.Time      `json:"last_error_at,omitempty"`
+	Cooldown           *SymbolCooldown `json:"cooldown,omitempty"` // Пауза символа после стопа; считается при чтении
+	CreatedAt          time.Time       `json:"created_at"`
+	UpdatedAt          time.Time       `json:"updated_at"`
+}
+
+// BotRequest - создание или полная замена бота. Новый конфиг действует на следующие сделки,
+// уже открытые доводятся по своему.
+type BotRequest struct {
+	Name               string      `json:"name"`
+	Symbol             string      `json:"symbol"`
+	Exchange           string      `json:"exchange"` // bybit или okx; пусто - EXCHANGE
+	Config             TradeConfig `json:"config"`
+	MaxConcurrentDeals int         `json:"max_concurrent_deals"` // 0 - DefaultMaxConcurrentDeals
+	ProfitTarget       string      `json:"profit_target"`        // Пусто - бот работает без цели
+	Enabled            *bool       `json:"enabled"`              // По умолчанию true
+}
+
+type BotStore interface {
+	Load() (map[uuid.UUID]Bot, error)
+	Save(bots map[uuid.UUID]Bot) error
+}
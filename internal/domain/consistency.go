@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+type ConsistencyIssueKind string
+
+const (
+	IssueMissingTakeProfit ConsistencyIssueKind = "missing_take_profit"  // Активная сделка без открытого TP на бирже
+	IssueMissedFill        ConsistencyIssueKind = "missed_fill"          // Ордер открыт локально, а на бирже уже исполнен
+	IssueOrderGone         ConsistencyIssueKind = "order_gone"           // Ордер открыт локально, а на бирже отменен
+	IssueUnknownStatus     ConsistencyIssueKind = "unknown_order_status" // Биржа вернула статус, которого нет в маппинге
+	IssueDanglingIndex     ConsistencyIssueKind = "dangling_index"       // Запись индекса ордеров ссылается на несуществующую сделку
+	IssueOrphanOrder       ConsistencyIssueKind = "orphan_order"         // Ордер бота открыт на бирже, но не принадлежит активной сделке
+	IssueIncompleteOpen    ConsistencyIssueKind = "incomplete_open"      // Вход исполнен, а TP и сетка не выставлены
+	IssueStaleTakeProfit   ConsistencyIssueKind = "stale_take_profit"    // TP выставлен до последнего исполнения DCA
+)
+
+type ConsistencyIssue struct {
+	Kind     ConsistencyIssueKind `json:"kind"`
+	TradeID  string               `json:"trade_id"`
+	OrderID  string               `json:"order_id,omitempty"`
+	Symbol   string               `json:"symbol,omitempty"`
+	Detail   string               `json:"detail"`
+	Repaired bool                 `json:"repaired"`
+	Error    string               `json:"error,omitempty"` // Почему не удалось исправить автоматически
+}
+
+// ConsistencyReport - результат одной проверки согласованности сделок с биржей.
+type ConsistencyReport struct {
+	CheckedAt     time.Time          `json:"checked_at"`
+	TradesChecked int                `json:"trades_checked"`
+	AutoRepair    bool               `json:"auto_repair"`
+	Issues        []ConsistencyIssue `json:"issues"`
+}
+
+// TradeAttention - пометка сделки, обработка которой прервалась паникой. Сделка продолжает
+// работать, но ее ордера могли остаться в промежуточном состоянии (например, старый TP
+// снят, а новый не выставлен), поэтому ее нужно проверить вручную и снять пометку.
+type TradeAttention struct {
+	Reason    string             `json:"reason"`
+	FlaggedAt time.Time          `json:"flagged_at"`
+	Issues    []ConsistencyIssue `json:"issues"`                // Расхождения с биржей на момент пометки
+	Error     string             `json:"check_error,omitempty"` // Проверка с биржей не удалась
+}
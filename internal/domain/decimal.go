@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Decimal - десятичное число для расчетов цен и объемов. В моделях и JSON значения
+// остаются строками с PricePrecision знаками, Decimal используется только в арифметике,
+// чтобы усреднение и мартингейл не копили ошибку float64.
+type Decimal = decimal.Decimal
+
+var (
+	DecimalZero = decimal.Zero
+	DecimalOne  = decimal.NewFromInt(1)
+)
+
+func ParseDecimal(value string) (Decimal, error) {
+	return decimal.NewFromString(strings.TrimSpace(value))
+}
+
+// DecimalFromFloat переводит float в кратчайшее десятичное представление: 0.1 -> 0.1.
+func DecimalFromFloat(value float64) Decimal {
+	return decimal.NewFromFloat(value)
+}
+
+// FormatDecimal форматирует значение так же, как fmt.Sprintf("%.8f") для строковых полей.
+func FormatDecimal(value Decimal) string {
+	return value.StringFixed(PricePrecision)
+}
@@ -0,0 +1,22 @@
+package domain
+
+// DryRunPlan описывает, что сделал бы разрушающий запрос с ?dry_run=true, ничего не выполняя.
+type DryRunPlan struct {
+	Action         string          `json:"action"`
+	OrdersToCancel []PlannedCancel `json:"orders_to_cancel"`
+	Sales          []PlannedSale   `json:"sales"` // Рыночные продажи; пусто, если запрос ничего не продает
+}
+
+type PlannedCancel struct {
+	Symbol   string    `json:"symbol"`
+	OrderID  string    `json:"order_id"`
+	Role     string    `json:"role"` // take_profit, stop_loss, dca, rebalance
+	Side     OrderSide `json:"side"`
+	Price    string    `json:"price,omitempty"`
+	Quantity string    `json:"quantity"`
+}
+
+type PlannedSale struct {
+	Symbol   string `json:"symbol"`
+	Quantity string `json:"quantity"`
+}
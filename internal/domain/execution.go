@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// DefaultExecutionDedupTTL - сколько помнить обработанные исполнения. Повторная доставка
+// после переподключения приходит в пределах минут, сутки берутся с запасом.
+const DefaultExecutionDedupTTL = 24 * time.Hour
+
+// ExecutionStore помнит ID обработанных исполнений биржи, чтобы повторно доставленное
+// исполнение не применялось к сделке второй раз.
+type ExecutionStore interface {
+	Seen(execID string) (bool, error)
+	// Mark запоминает исполнение; записи старше TTL хранилища удаляются.
+	Mark(execID string, at time.Time) error
+}
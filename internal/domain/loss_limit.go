@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// TradingSuspension - состояние остановки новых циклов по дневному лимиту убытка.
+// Открытые сделки продолжают обслуживаться; снимается остановка только вручную.
+type TradingSuspension struct {
+	Suspended   bool               `json:"suspended"`
+	SuspendedAt *time.Time         `json:"suspended_at,omitempty"`
+	Reason      string             `json:"reason,omitempty"`
+	ResumedAt   *time.Time         `json:"resumed_at,omitempty"`
+	Limits      map[string]float64 `json:"limits"`             // Допустимый убыток за 24 часа по котируемым валютам
+	PnL         map[string]string  `json:"pnl_24h"`            // Реализованный + нереализованный PnL последней проверки
+	Baseline    map[string]string  `json:"baseline,omitempty"` // PnL на момент ручного возобновления, действует 24 часа
+	CheckedAt   *time.Time         `json:"checked_at,omitempty"`
+}
@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+// MarketTicker - текущие цены символа и статистика за 24 часа.
+type MarketTicker struct {
+	Symbol      string    `json:"symbol"`
+	LastPrice   string    `json:"last_price"`
+	BidPrice    string    `json:"bid_price"`
+	AskPrice    string    `json:"ask_price"`
+	HighPrice   string    `json:"high_price_24h"`
+	LowPrice    string    `json:"low_price_24h"`
+	Volume24h   string    `json:"volume_24h"`   // В базовой монете
+	Turnover24h string    `json:"turnover_24h"` // В котируемой валюте
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// MarketOrderBook - снимок стакана: Bids по убыванию цены, Asks по возрастанию.
+type MarketOrderBook struct {
+	Symbol    string            `json:"symbol"`
+	Bids      []MarketBookLevel `json:"bids"`
+	Asks      []MarketBookLevel `json:"asks"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+type MarketBookLevel struct {
+	Price string `json:"price"`
+	Size  string `json:"size"`
+}
+
+// MarketKlines - свечи символа в хронологическом порядке.
+type MarketKlines struct {
+	Symbol   string        `json:"symbol"`
+	Interval string        `json:"interval"`
+	Klines   []MarketKline `json:"klines"`
+}
+
+type MarketKline struct {
+	StartTime time.Time `json:"start_time"`
+	Open      string    `json:"open"`
+	High      string    `json:"high"`
+	Low       string    `json:"low"`
+	Close     string    `json:"close"`
+	Volume    string    `json:"volume"`
+}
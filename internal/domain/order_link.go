@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// OrderLinkPrefix отмечает ордера, выставленные ботом. Идентификатор укладывается в
+// ограничения обеих бирж: Bybit - до 36 символов, OKX - до 32 букв и цифр.
+const OrderLinkPrefix = "dcabot"
+
+// OrderLinkIDFor - идентификатор, однозначно выводимый из параметров заявки. Повтор той же
+// заявки получает тот же идентификатор, и биржа не примет ее второй раз.
+func OrderLinkIDFor(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "|")))
+	return OrderLinkPrefix + hex.EncodeToString(sum[:])[:32-len(OrderLinkPrefix)]
+}
+
+// IsBotOrderLink - ордер выставлен ботом, а не вручную.
+func IsBotOrderLink(linkID string) bool {
+	return strings.HasPrefix(linkID, OrderLinkPrefix)
+}
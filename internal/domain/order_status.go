@@ -0,0 +1,76 @@
+package domain
+
+var bybitOrderStatuses = map[OrderStatusBybit]OrderStatus{
+	OrderStatusBybitNew:                     OrderStatusNew,
+	OrderStatusBybitPartiallyFilled:         OrderStatusPartially,
+	OrderStatusBybitFilled:                  OrderStatusFilled,
+	OrderStatusBybitCanceled:                OrderStatusCanceled,
+	OrderStatusBybitPartiallyFilledCanceled: OrderStatusPartiallyCanceled,
+	OrderStatusBybitRejected:                OrderStatusRejected,
+	OrderStatusBybitUntriggered:             OrderStatusUntriggered,
+	OrderStatusBybitTriggered:               OrderStatusTriggered,
+	OrderStatusBybitDeactivated:             OrderStatusDeactivated,
+}
+
+// ParseOrderStatus переводит статус биржи в доменный. Доменные значения принимаются как есть:
+// в снимках до нормализации статусы хранились в виде ответа биржи, а в новых - уже в домене.
+// Незнакомый статус становится OrderStatusUnknown, чтобы вызывающий код обработал его явно.
+func ParseOrderStatus(raw string) OrderStatus {
+	if status, ok := bybitOrderStatuses[OrderStatusBybit(raw)]; ok {
+		return status
+	}
+	if status := OrderStatus(raw); status.IsValid() {
+		return status
+	}
+	return OrderStatusUnknown
+}
+
+func (s OrderStatus) IsValid() bool {
+	switch s {
+	case OrderStatusNew, OrderStatusPartially, OrderStatusFilled, OrderStatusCanceled,
+		OrderStatusPartiallyCanceled, OrderStatusRejected, OrderStatusUntriggered,
+		OrderStatusTriggered, OrderStatusDeactivated:
+		return true
+	}
+	return false
+}
+
+// Normalize возвращает доменный статус и для значений, сохраненных в виде ответа биржи.
+func (s OrderStatus) Normalize() OrderStatus {
+	return ParseOrderStatus(string(s))
+}
+
+// IsOpen - ордер стоит на бирже и еще может исполниться.
+func (s OrderStatus) IsOpen() bool {
+	switch s.Normalize() {
+	case OrderStatusNew, OrderStatusPartially, OrderStatusUntriggered, OrderStatusTriggered:
+		return true
+	}
+	return false
+}
+
+func (s OrderStatus) IsFilled() bool {
+	return s.Normalize() == OrderStatusFilled
+}
+
+// IsPartial - исполнена часть объема: ордер еще стоит или уже закрыт биржей с остатком.
+func (s OrderStatus) IsPartial() bool {
+	status := s.Normalize()
+	return status == OrderStatusPartially || status == OrderStatusPartiallyCanceled
+}
+
+// IsTerminal - ордер закрыт и больше не изменится. Неизвестный статус терминальным не считается.
+func (s OrderStatus) IsTerminal() bool {
+	switch s.Normalize() {
+	case OrderStatusFilled, OrderStatusCanceled, OrderStatusPartiallyCanceled,
+		OrderStatusRejected, OrderStatusDeactivated:
+		return true
+	}
+	return false
+}
+
+// ClosedWithFills - по закрытому ордеру есть исполнение, которое нужно учесть в позиции.
+func (s OrderStatus) ClosedWithFills() bool {
+	status := s.Normalize()
+	return status == OrderStatusFilled || status == OrderStatusPartiallyCanceled
+}
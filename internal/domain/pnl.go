@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Периоды агрегации PnL; неделя начинается с понедельника в часовом поясе отчетов.
+const (
+	PnLPeriodDay  = "day"
+	PnLPeriodWeek = "week"
+)
+
+// TradePnL - результат сделки в котируемой валюте. У активной сделки позиция оценивается
+// по последней цене и UnrealizedPnL включает частичные выходы; у закрытой RealizedPnL
+// зафиксирован при закрытии и включает комиссии исполнений.
+type TradePnL struct {
+	TradeID           uuid.UUID   `json:"trade_id"`
+	Symbol            string      `json:"symbol"`
+	Quote             string      `json:"quote"`
+	Status            TradeStatus `json:"status"`
+	TotalInvested     string      `json:"total_invested"`
+	PositionQty       string      `json:"current_position_qty"`
+	AveragePrice      string      `json:"average_price"`
+	CurrentPrice      string      `json:"current_price,omitempty"` // Последняя цена, только у активной сделки
+	UnrealizedPnL     string      `json:"unrealized_pnl"`
+	UnrealizedPercent float64     `json:"unrealized_percent"` // К вложенному капиталу
+	RealizedPnL       string      `json:"realized_pnl"`
+	Fees              string      `json:"fees"` // Комиссии исполнений в котируемой валюте
+	ClosedAt          *time.Time  `json:"closed_at,omitempty"`
+	CalculatedAt      time.Time   `json:"calculated_at"`
+}
+
+// PnLBucket - сделки, закрытые за день или неделю. Суммы по котируемым валютам.
+type PnLBucket struct {
+	Start    string            `json:"start"` // YYYY-MM-DD: день или понедельник недели
+	Closed   int               `json:"closed"`
+	Realized map[string]string `json:"realized_pnl"`
+	Fees     map[string]string `json:"fees"`
+}
+
+type PnLStats struct {
+	Timezone     string            `json:"timezone"`
+	Period       string            `json:"period"`
+	From         string            `json:"from"`
+	To           string            `json:"to"`
+	Buckets      []PnLBucket       `json:"buckets"`
+	Realized     map[string]string `json:"realized_pnl"`   // За весь период
+	Unrealized   map[string]string `json:"unrealized_pnl"` // Активные сделки по последней цене, на момент запроса
+	ActiveTrades int               `json:"active_trades"`
+	CalculatedAt time.Time         `json:"calculated_at"`
+}
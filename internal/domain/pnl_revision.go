@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PnLFigures - учетные показатели сделки, которые пересчитываются по исполнениям.
+type PnLFigures struct {
+	TotalInvested      string `json:"total_invested"`
+	CurrentPositionQty string `json:"current_position_qty"`
+	FreedCapital       string `json:"freed_capital"`
+	AveragePrice       string `json:"average_price"`
+	RealizedPnL        string `json:"realized_pnl"`
+}
+
+// PnLRevision - пересчет учета закрытой сделки по исполнениям из журнала. Original -
+// показатели из последнего снимка сделки, они не меняются; каждый пересчет
+// добавляет новую ревизию.
+type PnLRevision struct {
+	TradeID    uuid.UUID  `json:"trade_id"`
+	Revision   int        `json:"revision"` // Номер ревизии сделки, с 1
+	Symbol     string     `json:"symbol"`
+	Status     string     `json:"status"`
+	Reason     string     `json:"reason"`
+	Fills      int        `json:"fills"` // Сколько ордеров с исполнением учтено
+	Original   PnLFigures `json:"original"`
+	Recomputed PnLFigures `json:"recomputed"`
+	PnLDelta   string     `json:"pnl_delta"`
+	ComputedAt time.Time  `json:"computed_at"`
+}
+
+type PnLRecomputeRequest struct {
+	TradeIDs []uuid.UUID `json:"trade_ids"` // Пусто - все закрытые сделки журнала
+	Reason   string      `json:"reason"`
+	DryRun   bool        `json:"dry_run"` // Только показать ревизии, не сохраняя
+}
+
+type PnLRecomputeResult struct {
+	DryRun    bool          `json:"dry_run"`
+	Revisions []PnLRevision `json:"revisions"`
+	Changed   int           `json:"changed"` // Ревизии, где пересчитанный PnL отличается от исходного
+	Skipped   []string      `json:"skipped,omitempty"`
+}
+
+type PnLRevisionStore interface {
+	// Append присваивает ревизии следующий номер для ее сделки и сохраняет ее.
+	Append(revision *PnLRevision) error
+	ReadAll() ([]PnLRevision, error)
+}
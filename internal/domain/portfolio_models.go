@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PortfolioConfig - целевое распределение корзины спотовых активов.
+type PortfolioConfig struct {
+	Name                  string             `json:"name"`
+	QuoteAsset            string             `json:"quote_asset"`             // Котируемая валюта, в которой считается стоимость
+	Allocations           map[string]float64 `json:"allocations"`             // Актив -> целевая доля в %, сумма 100
+	DriftThresholdPercent float64            `json:"drift_threshold_percent"` // Ребалансировка, если доля ушла дальше чем на X п.п.
+	MinOrderVolume        string             `json:"min_order_volume"`        // Минимальный объем ребалансирующего ордера в котируемой валюте
+	IntervalMinutes       int                `json:"interval_minutes"`        // Период плановой ребалансировки
+	AllowOppositeExposure bool               `json:"allow_opposite_exposure"` // Создать портфель, даже если по его символам идут DCA сделки
+}
+
+type PortfolioStatus string
+
+const (
+	PortfolioStatusActive  PortfolioStatus = "ACTIVE"
+	PortfolioStatusStopped PortfolioStatus = "STOPPED"
+)
+
+type AssetHolding struct {
+	Asset         string  `json:"asset"`
+	Quantity      float64 `json:"quantity"`
+	Price         float64 `json:"price"`
+	Value         float64 `json:"value"`          // Стоимость в котируемой валюте
+	WeightPercent float64 `json:"weight_percent"` // Текущая доля
+	TargetPercent float64 `json:"target_percent"`
+	DriftPercent  float64 `json:"drift_percent"` // Текущая доля минус целевая
+}
+
+type Portfolio struct {
+	ID              uuid.UUID       `json:"id"`
+	Config          PortfolioConfig `json:"config"`
+	Status          PortfolioStatus `json:"status"`
+	Holdings        []AssetHolding  `json:"holdings"`
+	TotalValue      float64         `json:"total_value"`
+	OpenOrders      []Order         `json:"open_orders"` // Ордера последней ребалансировки
+	LastRebalanceAt *time.Time      `json:"last_rebalance_at,omitempty"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+}
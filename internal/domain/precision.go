@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// PrecisionOverride - точность символа, выученная из отказов биржи.
+// nil означает, что для величины переопределения нет.
+type PrecisionOverride struct {
+	Symbol        string    `json:"symbol"`
+	QtyDecimals   *int      `json:"qty_decimals,omitempty"`
+	PriceDecimals *int      `json:"price_decimals,omitempty"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type PrecisionStore interface {
+	Load() (map[string]PrecisionOverride, error)
+	Save(overrides map[string]PrecisionOverride) error
+}
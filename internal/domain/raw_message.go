@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// RawMessage - сообщение биржи в том виде, в каком оно пришло. По записям видно, присылала ли
+// биржа событие об исполнении, которое бот пропустил.
+type RawMessage struct {
+	ReceivedAt time.Time       `json:"received_at"`
+	Source     string          `json:"source"`  // Канал доставки: webhook
+	Payload    json.RawMessage `json:"payload"` // Тело сообщения; не-JSON сохраняется строкой
+}
+
+// MessageCapture хранит последние сообщения в пределах заданного размера.
+type MessageCapture interface {
+	Capture(source string, payload []byte) error
+	// Export пишет сохраненные сообщения в w в JSON Lines, от старых к новым
+	Export(w io.Writer) error
+}
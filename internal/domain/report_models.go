@@ -0,0 +1,54 @@
+package domain
+
+// DailyReport - итоги одного календарного дня в часовом поясе отчетов.
+type DailyReport struct {
+	Date           string            `json:"date"` // YYYY-MM-DD
+	Opened         int               `json:"opened"`
+	Completed      int               `json:"completed"`
+	Stopped        int               `json:"stopped"`
+	Cancelled      int               `json:"cancelled"`
+	RealizedProfit map[string]string `json:"realized_profit"` // По закрытым по TP сделкам, по котируемым валютам
+}
+
+type ReportSummary struct {
+	Timezone            string            `json:"timezone"`
+	From                string            `json:"from"`
+	To                  string            `json:"to"`
+	Days                []DailyReport     `json:"days"`
+	TotalRealizedProfit map[string]string `json:"total_realized_profit"`
+}
+
+// HeatmapCell - статистика завершенных сделок, открытых в данный день недели и час.
+type HeatmapCell struct {
+	Weekday          string  `json:"weekday"` // Monday ... Sunday
+	Hour             int     `json:"hour"`    // 0-23 в часовом поясе отчетов
+	Trades           int     `json:"trades"`
+	Fills            int     `json:"fills"` // Исполненные ордера входа и DCA
+	WinRate          float64 `json:"win_rate"`
+	AvgCycleDuration string  `json:"avg_cycle_duration"`
+}
+
+type TimeHeatmap struct {
+	Timezone  string        `json:"timezone"`
+	Trades    int           `json:"trades"`
+	Cells     []HeatmapCell `json:"cells"`      // Только непустые ячейки день/час
+	ByHour    []HeatmapCell `json:"by_hour"`    // Свертка по часам, Weekday пустой
+	ByWeekday []HeatmapCell `json:"by_weekday"` // Свертка по дням недели, Hour = -1
+}
+
+// PublicStats - обезличенная статистика для публичной страницы: только доли и проценты,
+// без символов, сумм и идентификаторов.
+type PublicStats struct {
+	Timezone     string           `json:"timezone"`
+	ClosedTrades int              `json:"closed_trades"`
+	ActiveTrades int              `json:"active_trades"`
+	WinRate      float64          `json:"win_rate"`
+	ReturnPct    float64          `json:"return_percent"` // Итоговая прибыль к капиталу закрытых сделок
+	Curve        []PublicPnLPoint `json:"pnl_curve"`
+}
+
+type PublicPnLPoint struct {
+	Date      string  `json:"date"` // YYYY-MM-DD в часовом поясе отчетов
+	Trades    int     `json:"trades"`
+	ReturnPct float64 `json:"return_percent"` // Накопленная доходность на конец дня
+}
@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SymbolCooldown - пауза по символу после выхода по стоп-лоссу: до Until новые сделки
+// по символу не открываются.
+type SymbolCooldown struct {
+	Symbol    string    `json:"symbol"`
+	TradeID   uuid.UUID `json:"trade_id"` // Сделка, закрытая по стопу
+	Reason    string    `json:"reason"`
+	StartedAt time.Time `json:"started_at"`
+	Until     time.Time `json:"until"`
+}
+
+type CooldownStore interface {
+	Load() (map[string]SymbolCooldown, error)
+	Save(cooldowns map[string]SymbolCooldown) error
+}
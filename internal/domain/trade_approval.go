@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// ApprovalCallbackPrefix - префикс callback_data кнопки подтверждения в Telegram: approve:<trade_id>.
+const ApprovalCallbackPrefix = "approve:"
+
+// TradeApproval - запрос подтверждения сделки, капитал которой выше порога котируемой валюты.
+// До подтверждения сделка в PENDING_APPROVAL: капитал зарезервирован, ордера не выставлены.
+type TradeApproval struct {
+	Quote           string     `json:"quote"`
+	RequiredCapital string     `json:"required_capital"`
+	Threshold       string     `json:"threshold"`
+	RequestedAt     time.Time  `json:"requested_at"`
+	ApprovedAt      *time.Time `json:"approved_at,omitempty"`
+	ApprovedBy      string     `json:"approved_by,omitempty"` // api или telegram:<user id>
+}
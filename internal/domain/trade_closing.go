@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// PendingCancel - ордер закрытой сделки, снятие которого биржа еще не подтвердила.
+// Пока такие ордера есть, сделка остается в CLOSING: оставшийся ордер может исполниться позже.
+type PendingCancel struct {
+	OrderID       string    `json:"order_id"`
+	Role          string    `json:"role"`           // take_profit, stop_loss или dca
+	Stop          bool      `json:"stop,omitempty"` // Условный ордер, снимается как стоп
+	Attempts      int       `json:"attempts"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	LastError     string    `json:"last_error,omitempty"`
+}
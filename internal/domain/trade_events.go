@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TradeEventType string
+
+const (
+	TradeEventOpened        TradeEventType = "trade_opened"
+	TradeEventEntryPlaced   TradeEventType = "entry_placed" // Вход исполнен, сделка сохранена до выставления TP и сетки
+	TradeEventDCAFilled     TradeEventType = "dca_filled"
+	TradeEventDCAPartial    TradeEventType = "dca_partially_filled"
+	TradeEventScheduledBuy  TradeEventType = "scheduled_buy"
+	TradeEventTPReplaced    TradeEventType = "tp_replaced"
+	TradeEventTPCancelled   TradeEventType = "tp_cancelled" // Старый TP снят, новый выставить не удалось
+	TradeEventSLReplaced    TradeEventType = "sl_replaced"
+	TradeEventFinalized     TradeEventType = "trade_finalized"
+	TradeEventGridRefreshed TradeEventType = "grid_refreshed"
+	TradeEventOrdersExpired TradeEventType = "orders_expired"
+	TradeEventStatusForced  TradeEventType = "status_forced"
+	TradeEventExitAssist    TradeEventType = "exit_assist"
+	TradeEventAnnotated     TradeEventType = "annotated"
+	TradeEventQueued        TradeEventType = "trade_queued"
+	TradeEventRetired       TradeEventType = "trade_retired"
+	TradeEventRestarted     TradeEventType = "trade_restarted"
+	TradeEventTPDeferred    TradeEventType = "tp_deferred"
+	TradeEventOrderAmended  TradeEventType = "order_amended"
+	TradeEventAttention     TradeEventType = "attention_required"
+	TradeEventAcknowledged  TradeEventType = "attention_cleared"
+	TradeEventTPFallback    TradeEventType = "tp_fallback"
+	TradeEventApprovalAsked TradeEventType = "approval_requested"
+	TradeEventApproved      TradeEventType = "trade_approved"
+	TradeEventCancelFailed  TradeEventType = "order_cancel_failed" // Ордер мог остаться на бирже
+	TradeEventClosing       TradeEventType = "trade_closing"       // Закрытие ждет снятия ордеров
+	TradeEventCancelsDone   TradeEventType = "cancels_confirmed"
+	TradeEventCreditGap     TradeEventType = "wallet_credit_mismatch" // Приход после TP не совпал с расчетом
+	TradeEventCooldown      TradeEventType = "symbol_cooldown"        // Символ на паузе после стопа
+	TradeEventPaused        TradeEventType = "trade_paused"
+	TradeEventResumed       TradeEventType = "trade_resumed"
+)
+
+// TradeEvent - запись журнала. Snapshot содержит состояние сделки после события,
+// поэтому состояние восстанавливается только из журнала, без обращений к бирже.
+type TradeEvent struct {
+	Seq       uint64          `json:"seq"`
+	Type      TradeEventType  `json:"type"`
+	TradeID   uuid.UUID       `json:"trade_id"`
+	OrderID   string          `json:"order_id,omitempty"`
+	Price     string          `json:"price,omitempty"`
+	Quantity  string          `json:"quantity,omitempty"`
+	Message   string          `json:"message,omitempty"`
+	Snapshot  json.RawMessage `json:"snapshot,omitempty"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// EventPage - страница журнала для инкрементальной синхронизации: следующий запрос
+// передает NextSeq в since_seq.
+type EventPage struct {
+	Events  []TradeEvent `json:"events"`
+	NextSeq uint64       `json:"next_seq"` // Seq последнего события страницы (или since_seq, если страница пуста)
+	LastSeq uint64       `json:"last_seq"` // Seq последнего события журнала
+	HasMore bool         `json:"has_more"`
+}
+
+type EventJournal interface {
+	// Append присваивает событию следующий порядковый номер и сохраняет его.
+	Append(event *TradeEvent) error
+	ReadAll() ([]TradeEvent, error)
+}
+
+// TradeAnnotation - пометка внешней системы (аналитика, модели), хранится вместе со сделкой.
+type TradeAnnotation struct {
+	Key       string    `json:"key"`
+	Value     string    `json:"value"`
+	Source    string    `json:"source,omitempty"`
+	Timestamp time.Time `json:"timestamp"` // Момент, к которому относится пометка
+}
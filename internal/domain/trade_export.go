@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TradeExport - итог закрытой сделки для внешних журналов. Суммы в котируемой валюте.
+type TradeExport struct {
+	TradeID    uuid.UUID     `json:"trade_id"`
+	Symbol     string        `json:"symbol"`
+	Side       OrderSide     `json:"side"`
+	Status     TradeStatus   `json:"status"`
+	EntryPrice string        `json:"entry_price"`
+	ExitPrice  string        `json:"exit_price"`
+	DCACount   int           `json:"dca_count"` // Исполненные уровни DCA
+	PnL        string        `json:"pnl"`
+	Fees       string        `json:"fees"`
+	OpenedAt   time.Time     `json:"opened_at"`
+	ClosedAt   time.Time     `json:"closed_at"`
+	Duration   time.Duration `json:"duration"`
+}
+
+// TradeExporter получает каждую сделку, закрытую по TP или SL. Вызов идет под блокировкой
+// сделки, поэтому реализация не должна ждать внешний сервис.
+type TradeExporter interface {
+	ExportTrade(ctx context.Context, export TradeExport) error
+}
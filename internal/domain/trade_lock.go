@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// TradeLocker сериализует изменение сделки. Реализация на общей базе (storage.PostgresTradeLocker)
+// гарантирует, что сделку в каждый момент меняет только одна реплика.
+type TradeLocker interface {
+	// Lock блокирует сделку до вызова возвращенной функции освобождения.
+	Lock(ctx context.Context, tradeID uuid.UUID) (func(), error)
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// TradeRepository хранит последнее состояние каждой сделки, чтобы после перезапуска
+// продолжить ведение открытых сеток и не оставлять их ордера на бирже без присмотра.
+type TradeRepository interface {
+	// Save сохраняет состояние сделки, заменяя предыдущее.
+	Save(trade *Trade) error
+	// LoadActive возвращает сделки, которые еще не завершены: ACTIVE, WAITING, PENDING_APPROVAL, CLOSING и PAUSED.
+	LoadActive() ([]*Trade, error)
+	// Driver - тип хранилища для статуса: memory, file, postgres или sqlite.
+	Driver() string
+	// Ping проверяет, что хранилище доступно для записи.
+	Ping() error
+}
+
+// TradeLoader читает сохраненное состояние одной сделки. Его реализует хранилище, общее
+// для нескольких реплик. Неизвестная сделка - (nil, nil).
+type TradeLoader interface {
+	Load(id uuid.UUID) (*Trade, error)
+}
+
+// OpenTradeStatuses - статусы сделок, которые еще ведутся ботом.
+var OpenTradeStatuses = []TradeStatus{
+	TradeStatusActive, TradeStatusWaiting, TradeStatusPendingApproval, TradeStatusClosing, TradeStatusPaused,
+}
+
+// IsOpen сообщает, что сделка еще ведется ботом и должна пережить перезапуск.
+func (s TradeStatus) IsOpen() bool {
+	return slices.Contains(OpenTradeStatuses, s)
+}
@@ -1,20 +1,61 @@
 package domain
+
+import "time"
+
 type OrderStatusBybit string
 
+// Статусы ордеров Bybit v5. В домен они переводятся только через ParseOrderStatus.
 const (
-	OrderStatusBybitFilled   OrderStatusBybit = "Filled"
-	OrderStatusBybitNew      OrderStatusBybit = "New"
-	OrderStatusBybitCanceled OrderStatusBybit = "Cancelled"
+	OrderStatusBybitFilled                  OrderStatusBybit = "Filled"
+	OrderStatusBybitNew                     OrderStatusBybit = "New"
+	OrderStatusBybitCanceled                OrderStatusBybit = "Cancelled"
+	OrderStatusBybitPartiallyFilled         OrderStatusBybit = "PartiallyFilled"
+	OrderStatusBybitPartiallyFilledCanceled OrderStatusBybit = "PartiallyFilledCanceled"
+	OrderStatusBybitRejected                OrderStatusBybit = "Rejected"
+	OrderStatusBybitUntriggered             OrderStatusBybit = "Untriggered"
+	OrderStatusBybitTriggered               OrderStatusBybit = "Triggered"
+	OrderStatusBybitDeactivated             OrderStatusBybit = "Deactivated"
 )
 
 const (
-	DefaultMartingale  = 1.0
-	DefaultTimeInForce = "GTC"
-	PricePrecision     = 8
-	MaxSafetyOrders    = 20
-	MaxPositionValue   = 100000.0
-	MinOrderSize       = 0.001
-	MaxOrderSize       = 1000.0
+	DefaultMartingale   = 1.0
+	DefaultTimeInForce  = "GTC"
+	TimeInForcePostOnly = "PostOnly"
+	TimeInForceIOC      = "IOC"
+	DefaultPartialFill  = PartialFillPolicyFilledOnly
+	DefaultMinNotional  = MinNotionalPolicyReject
+	DefaultBudgetPolicy = BudgetPolicyReject
+	DefaultStrategy     = StrategyPriceStep
+	DefaultTradeSide    = OrderSideBuy
+	PricePrecision      = 8
+	MaxSafetyOrders     = 20
+	MaxPositionValue    = 100000.0
+	MinOrderSize        = 0.001
+	MaxOrderSize        = 1000.0
+
+	DefaultRebalanceIntervalMinutes = 60
+
+	// Погоня мейкерского входа: число перестановок и ожидание исполнения на каждой цене
+	MakerChaseAttempts = 10
+	MakerChaseInterval = 5 * time.Second
+
+	// Лимитная цена stop-limit ордера ниже триггера, чтобы он исполнился при резком движении
+	StopLimitSlippagePercent = 0.5
+
+	// Защита перестановки TP при резких движениях: TP дальше порога от цены откладывается
+	// не дольше TPMaxDeferral, TP ниже рынка поднимается на TPAboveMarketPercent выше цены
+	DefaultTPMaxDeviationPercent = 15.0
+	TPMaxDeferral                = 30 * time.Minute
+	TPAboveMarketPercent         = 0.1
+
+	// Сколько цена должна держаться за TP без исполнения, прежде чем TP заменяется по TPFallback.
+	// Проверка идет с интервалом планировщика, поэтому фактическое ожидание может быть дольше
+	DefaultTPFallbackSeconds = 60
+
+	// Базовые комиссии спота, если в конфиге сделки задана только одна из ставок
+	DefaultMakerFeePercent = 0.1
+	DefaultTakerFeePercent = 0.1
+	MaxFeePercent          = 1.0
 )
 
 const (
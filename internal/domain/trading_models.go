@@ -20,6 +20,10 @@ const (
 	OrderSideSell OrderSide = "SELL"
 )
 
+func (s OrderSide) IsValid() bool {
+	return s == OrderSideBuy || s == OrderSideSell
+}
+
 type OrderStatus string
 
 const (
@@ -27,20 +31,62 @@ const (
 	OrderStatusFilled    OrderStatus = "FILLED"
 	OrderStatusCanceled  OrderStatus = "CANCELED"
 	OrderStatusPartially OrderStatus = "PARTIALLY_FILLED"
+	// Ордер закрыт биржей с частичным исполнением (IOC/рыночный остаток, отмена после частичного)
+	OrderStatusPartiallyCanceled OrderStatus = "PARTIALLY_FILLED_CANCELED"
+	OrderStatusRejected          OrderStatus = "REJECTED"
+	// Условный ордер ждет триггера / сработал и выставляется
+	OrderStatusUntriggered OrderStatus = "UNTRIGGERED"
+	OrderStatusTriggered   OrderStatus = "TRIGGERED"
+	// Условный ордер отменен до срабатывания
+	OrderStatusDeactivated OrderStatus = "DEACTIVATED"
+	// Статус, которого нет в маппинге; такой ордер не считается ни открытым, ни исполненным
+	OrderStatusUnknown OrderStatus = "UNKNOWN"
 )
 
 type Order struct {
-	ID          uuid.UUID   `json:"id"`
-	BybitID     string      `json:"bybit_id"`
-	Symbol      string      `json:"symbol"`
-	Side        OrderSide   `json:"side"`
-	Type        OrderType   `json:"type"`
-	Quantity    string      `json:"quantity"`
-	Price       string      `json:"price,omitempty"`
-	Status      OrderStatus `json:"status"`
-	ExecutedQty string      `json:"executed_qty"`
-	CreatedAt   time.Time   `json:"created_at"`
-	UpdatedAt   time.Time   `json:"updated_at"`
+	ID            uuid.UUID         `json:"id"`
+	BybitID       string            `json:"bybit_id"`
+	LinkID        string            `json:"link_id,omitempty"` // orderLinkId: по префиксу биржевой ордер узнается как ордер бота
+	Symbol        string            `json:"symbol"`
+	Side          OrderSide         `json:"side"`
+	Type          OrderType         `json:"type"`
+	Quantity      string            `json:"quantity"`
+	Price         string            `json:"price,omitempty"`
+	Status        OrderStatus       `json:"status"`
+	ExecutedQty   string            `json:"executed_qty"`
+	ExpiresAt     *time.Time        `json:"expires_at,omitempty"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+	Display       *OrderDisplay     `json:"display,omitempty"`        // Округленные значения для UI (?precision=display)
+	ExecutedValue string            `json:"executed_value,omitempty"` // Исполненный объем в котируемой валюте
+	Fee           string            `json:"fee,omitempty"`            // Комиссия: у покупок в базовой монете, у продаж в котируемой
+	Raw           *RawOrderPayloads `json:"raw,omitempty"`            // Сжатые ответы биржи (ORDER_RAW_PAYLOADS)
+}
+
+// RawOrderPayloads - исходные JSON ответы биржи по ордеру, сжатые gzip.
+type RawOrderPayloads struct {
+	Create   []byte `json:"create,omitempty"`   // Ответ на создание ордера
+	Realtime []byte `json:"realtime,omitempty"` // Последний ответ запроса состояния
+}
+
+// OrderUpdate - состояние ордера из события биржи (webhook/stream).
+type OrderUpdate struct {
+	OrderID       string      `json:"order_id"`
+	Symbol        string      `json:"symbol"`
+	Status        OrderStatus `json:"status"`
+	ExecutedQty   string      `json:"executed_qty"`
+	ExecutedValue string      `json:"executed_value,omitempty"` // Накопленная сумма исполнения, если биржа ее прислала
+	Fee           string      `json:"fee,omitempty"`            // Накопленная комиссия, если биржа ее прислала
+	Price         string      `json:"price"`
+	Timestamp     int64       `json:"timestamp,omitempty"` // Время события на бирже, мс
+}
+
+// AmendOrderRequest - изменение активного ордера; Quantity в базовой монете.
+type AmendOrderRequest struct {
+	Symbol   string `json:"-"`
+	OrderID  string `json:"-"`
+	Price    string `json:"price,omitempty"`
+	Quantity string `json:"quantity,omitempty"`
 }
 
 type CreateOrderRequest struct {
@@ -49,32 +95,232 @@ type CreateOrderRequest struct {
 	Type     OrderType `json:"type" binding:"required"`
 	Quantity string    `json:"quantity" binding:"required"`
 	Price    string    `json:"price,omitempty"`
+	// TriggerPrice превращает ордер в условный (stop-limit)
+	TriggerPrice string `json:"trigger_price,omitempty"`
+	// PostOnly - только мейкер: биржа отменит ордер, который исполнился бы сразу
+	PostOnly bool `json:"post_only,omitempty"`
+	// QuoteQuantity - Quantity рыночной продажи задан в котируемой валюте (покупка по рынку всегда в ней)
+	QuoteQuantity bool `json:"quote_quantity,omitempty"`
+	// ImmediateOrCancel - лимитка исполняется сразу насколько возможно, остаток отменяется
+	ImmediateOrCancel bool `json:"ioc,omitempty"`
 }
 
 type TradeConfig struct {
-	Symbol            string  `json:"symbol" binding:"required"`
-	EntryVolume       string  `json:"entry_volume" binding:"required"`        // Объем входа
-	DCAStepPercent    float64 `json:"dca_step_percent" binding:"required"`    // Шаг DCA в %
-	DCAVolume         string  `json:"dca_volume" binding:"required"`          // Объем DCA ордеров
-	DCACount          int     `json:"dca_count" binding:"required"`           // Количество DCA ордеров
-	TakeProfitPercent float64 `json:"take_profit_percent" binding:"required"` // TP в %
-	Martingale        float64 `json:"martingale"`                             // Мартингейл множитель
-	DynamicStep       bool    `json:"dynamic_step"`                           // Динамический шаг цены
+	Symbol         string  `json:"symbol" binding:"required"`
+	Exchange       string  `json:"exchange,omitempty"`                  // bybit или okx; пусто - биржа по умолчанию (EXCHANGE)
+	EntryVolume    string  `json:"entry_volume" binding:"required"`     // Объем входа
+	DCAStepPercent float64 `json:"dca_step_percent" binding:"required"` // Шаг DCA в %
+	// Объем первого DCA ордера. Каждый следующий уровень равен предыдущему, умноженному на Martingale:
+	// уровень i (с 1) = DCAVolume * Martingale^(i-1). При LegacyMartingale уровень i = DCAVolume * Martingale^i.
+	DCAVolume             string               `json:"dca_volume" binding:"required"`          // Объем DCA ордеров
+	DCACount              int                  `json:"dca_count" binding:"required"`           // Количество DCA ордеров
+	TakeProfitPercent     float64              `json:"take_profit_percent" binding:"required"` // TP в %
+	Martingale            float64              `json:"martingale"`                             // Мартингейл множитель
+	DynamicStep           bool                 `json:"dynamic_step"`                           // Динамический шаг цены
+	PartialFillPolicy     PartialFillPolicy    `json:"partial_fill_policy"`                    // Поведение при частичном входе
+	StopLossPercent       float64              `json:"stop_loss_percent"`                      // SL в % от средней цены (0 - без SL)
+	Strategy              StrategyType         `json:"strategy"`                               // Тип стратегии усреднения
+	BuyIntervalHours      int                  `json:"buy_interval_hours"`                     // Период покупок для time_based
+	MaxBudget             string               `json:"max_budget"`                             // Общий бюджет в USDT для time_based
+	TargetPositionQty     string               `json:"target_position_qty"`                    // Целевой объем позиции для time_based
+	GridRefreshPercent    float64              `json:"grid_refresh_percent"`                   // Переставлять сетку, если она отстала от цены на X%
+	DCAOrderTTLMinutes    int                  `json:"dca_order_ttl_minutes"`                  // Срок жизни DCA ордера (0 - бессрочно)
+	ReplaceExpired        bool                 `json:"replace_expired"`                        // Перевыставлять истекшие ордера по свежей цене
+	Force                 bool                 `json:"force,omitempty"`                        // Открыть сделку несмотря на ручные ордера и баланс по символу
+	MakerOnly             bool                 `json:"maker_only"`                             // Все ордера только мейкерские (вход - лимиткой у края стакана)
+	ExitAssistant         *ExitAssistantConfig `json:"exit_assistant,omitempty"`               // Выход по свечным фигурам
+	MinNotionalPolicy     MinNotionalPolicy    `json:"min_notional_policy"`                    // Уровни ниже минимального ордера биржи: reject или bump
+	SimulateBook          bool                 `json:"simulate_book,omitempty"`                // Прогнать вход и сетку по текущему стакану перед открытием
+	MaxSlippagePercent    float64              `json:"max_slippage_percent,omitempty"`         // Не открывать, если ожидаемое проскальзывание входа больше X% (включает симуляцию)
+	BudgetPolicy          BudgetPolicy         `json:"budget_policy"`                          // Сетка больше бюджета котируемой валюты: reject, truncate или scale
+	AllowOppositeExposure bool                 `json:"allow_opposite_exposure,omitempty"`      // Открыть сделку, даже если символ ребалансирует портфель
+	MinLevelVolume        string               `json:"min_level_volume,omitempty"`             // Нижняя граница объема уровня, проставляется при bump
+	StartPrice            string               `json:"start_price,omitempty"`                  // Цена, при пересечении которой сделка открывается
+	StartDirection        StartDirection       `json:"start_direction,omitempty"`              // below - цена опустилась до StartPrice, above - поднялась
+	LegacyMartingale      bool                 `json:"legacy_martingale,omitempty"`            // Старая схема: множитель применяется уже к первому уровню
+	AutoRestart           bool                 `json:"auto_restart"`                           // Открывать новый цикл с тем же конфигом после закрытия сделки
+	MaxCycles             int                  `json:"max_cycles"`                             // Остановиться после N завершенных циклов (0 - без ограничения)
+	StopAfterLoss         bool                 `json:"stop_after_loss"`                        // Не перезапускаться после цикла, закрытого по SL
+	TPMaxDeviationPercent float64              `json:"tp_max_deviation_percent"`               // Отложить перестановку TP, если он дальше X% от цены (0 - по умолчанию)
+	MinPrice              string               `json:"min_price,omitempty"`                    // Ниже этой цены новые циклы и уровни DCA не выставляются
+	MaxPrice              string               `json:"max_price,omitempty"`                    // Выше этой цены новые циклы и покупки не выставляются
+	MakerFeePercent       *float64             `json:"maker_fee_percent,omitempty"`            // Своя мейкерская комиссия в %, учитывается в TP и PnL
+	TakerFeePercent       *float64             `json:"taker_fee_percent,omitempty"`            // Своя тейкерская комиссия в %, учитывается в TP и PnL
+	Side                  OrderSide            `json:"side,omitempty"`                         // BUY - лонг (по умолчанию), SELL - шорт: продажа монет с откупом ниже
+	TPFallback            TPFallback           `json:"tp_fallback,omitempty"`                  // Чем закрыть остаток TP, если цена прошла его без исполнения: ioc или market
+	TPFallbackSeconds     int                  `json:"tp_fallback_seconds,omitempty"`          // Сколько цена должна держаться за TP до замены ордера
+}
+
+// IsShort сообщает, что сделка входит продажей: DCA продает выше входа, TP откупает ниже.
+func (c TradeConfig) IsShort() bool {
+	return c.Side == OrderSideSell
+}
+
+// TPFallback - замена лимитного TP, через который цена прошла без исполнения (неликвидный стакан).
+type TPFallback string
+
+const (
+	TPFallbackIOC    TPFallback = "ioc"    // IOC лимитка по цене TP: не хуже TP, неисполненный остаток снова ставится TP
+	TPFallbackMarket TPFallback = "market" // Остаток закрывается по рынку
+)
+
+func (f TPFallback) IsValid() bool {
+	return f == TPFallbackIOC || f == TPFallbackMarket
+}
+
+type StrategyType string
+
+const (
+	StrategyPriceStep StrategyType = "price_step" // Сетка лимитных DCA ордеров по шагу цены
+	StrategyTimeBased StrategyType = "time_based" // Покупка фиксированной суммы по расписанию
+)
+
+func (t StrategyType) IsValid() bool {
+	return t == StrategyPriceStep || t == StrategyTimeBased
+}
+
+type StartDirection string
+
+const (
+	StartDirectionBelow StartDirection = "below"
+	StartDirectionAbove StartDirection = "above"
+)
+
+func (d StartDirection) IsValid() bool {
+	return d == StartDirectionBelow || d == StartDirectionAbove
+}
+
+type MinNotionalPolicy string
+
+const (
+	MinNotionalPolicyReject MinNotionalPolicy = "reject" // Отказать в открытии сделки
+	MinNotionalPolicyBump   MinNotionalPolicy = "bump"   // Поднять объем уровня до минимума биржи
+)
+
+func (p MinNotionalPolicy) IsValid() bool {
+	return p == MinNotionalPolicyReject || p == MinNotionalPolicyBump
+}
+
+type BudgetPolicy string
+
+const (
+	BudgetPolicyReject   BudgetPolicy = "reject"   // Отказать в открытии сделки
+	BudgetPolicyTruncate BudgetPolicy = "truncate" // Отбросить самые глубокие уровни
+	BudgetPolicyScale    BudgetPolicy = "scale"    // Снизить мартингейл, затем объем уровней
+)
+
+func (p BudgetPolicy) IsValid() bool {
+	return p == BudgetPolicyReject || p == BudgetPolicyTruncate || p == BudgetPolicyScale
+}
+
+// BudgetAdjustment описывает, как сетка была ужата под свободный остаток бюджета.
+type BudgetAdjustment struct {
+	Policy              BudgetPolicy `json:"policy"`
+	Available           string       `json:"available"` // Свободный остаток бюджета котируемой валюты
+	RequestedCapital    string       `json:"requested_capital"`
+	AdjustedCapital     string       `json:"adjusted_capital"`
+	RequestedDCACount   int          `json:"requested_dca_count"`
+	DCACount            int          `json:"dca_count"`
+	RequestedMartingale float64      `json:"requested_martingale"`
+	Martingale          float64      `json:"martingale"`
+	RequestedDCAVolume  string       `json:"requested_dca_volume"`
+	DCAVolume           string       `json:"dca_volume"`
+	Grid                []GridLevel  `json:"grid,omitempty"` // Сетка от фактической цены входа
+}
+
+type PartialFillPolicy string
+
+const (
+	PartialFillPolicyResubmit   PartialFillPolicy = "resubmit"    // Дозаявить остаток по рынку
+	PartialFillPolicyFilledOnly PartialFillPolicy = "filled_only" // Строить сетку и TP от исполненной части
+	PartialFillPolicyAbort      PartialFillPolicy = "abort"       // Отменить сделку и вернуть средства
+)
+
+func (p PartialFillPolicy) IsValid() bool {
+	switch p {
+	case PartialFillPolicyResubmit, PartialFillPolicyFilledOnly, PartialFillPolicyAbort:
+		return true
+	}
+	return false
 }
 
 type Trade struct {
-	ID              uuid.UUID   `json:"id"`
-	Symbol          string      `json:"symbol"`
-	Config          TradeConfig `json:"config"`
-	EntryOrder      *Order      `json:"entry_order"`       // Ордер входа (market)
-	DCAOrders       []Order     `json:"dca_orders"`        // Сетка DCA ордеров
-	TakeProfitOrder *Order      `json:"take_profit_order"` // TP ордер
-	Status          TradeStatus `json:"status"`
-	TotalInvested   string      `json:"total_invested"`
-	AveragePrice    string      `json:"average_price"`
-	CurrentPrice    string      `json:"current_price"`
-	CreatedAt       time.Time   `json:"created_at"`
-	UpdatedAt       time.Time   `json:"updated_at"`
+	ID                 uuid.UUID         `json:"id"`
+	Symbol             string            `json:"symbol"`
+	Config             TradeConfig       `json:"config"`
+	EntryOrder         *Order            `json:"entry_order"`       // Ордер входа (market)
+	DCAOrders          []Order           `json:"dca_orders"`        // Сетка DCA ордеров; меняется только подменой копии, см. service.appendDCAOrder
+	TakeProfitOrder    *Order            `json:"take_profit_order"` // TP ордер
+	StopLossOrder      *Order            `json:"stop_loss_order"`   // SL ордер (OCO с TP)
+	Status             TradeStatus       `json:"status"`
+	TotalInvested      string            `json:"total_invested"`
+	AveragePrice       string            `json:"average_price"`
+	CurrentPrice       string            `json:"current_price"`
+	NextBuyAt          *time.Time        `json:"next_buy_at,omitempty"` // Следующая покупка по расписанию
+	Risk               *RiskAssessment   `json:"risk,omitempty"`
+	CreatedAt          time.Time         `json:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at"`
+	Underfunded        bool              `json:"underfunded"`                 // Свободного баланса не хватает на следующий уровень DCA
+	Display            *TradeDisplay     `json:"display,omitempty"`           // Округленные значения для UI (?precision=display)
+	ExitSignalAt       *time.Time        `json:"exit_signal_at,omitempty"`    // Когда сработал помощник выхода
+	Annotations        []TradeAnnotation `json:"annotations,omitempty"`       // Пометки внешних систем
+	Cycle              int               `json:"cycle"`                       // Номер цикла при автоперезапуске, с 1
+	PreviousTradeID    *uuid.UUID        `json:"previous_trade_id,omitempty"` // Сделка предыдущего цикла
+	BotID              *uuid.UUID        `json:"bot_id,omitempty"`            // Бот, открывший сделку
+	CurrentPositionQty string            `json:"current_position_qty"`        // Монеты в позиции за вычетом комиссий
+	FreedCapital       string            `json:"freed_capital"`               // Котируемая валюта, вернувшаяся от продаж
+	TPDeferredAt       *time.Time        `json:"tp_deferred_at,omitempty"`    // С какого момента перестановка TP отложена
+	PausedLevels       int               `json:"paused_levels,omitempty"`     // Уровни DCA, не выставленные из-за MinPrice/MaxPrice
+	BudgetAdjustment   *BudgetAdjustment `json:"budget_adjustment,omitempty"` // Сетка ужата под бюджет по BudgetPolicy
+	Attention          *TradeAttention   `json:"attention,omitempty"`         // Требует ручной проверки после паники
+	Approval           *TradeApproval    `json:"approval,omitempty"`          // Запрос подтверждения крупной сделки
+	TPCrossedAt        *time.Time        `json:"tp_crossed_at,omitempty"`     // С какого момента цена за TP, а он не исполнен
+	BookSimulation     *BookSimulation   `json:"book_simulation,omitempty"`   // Прогон входа по стакану перед открытием
+	RealizedPnL        string            `json:"realized_pnl,omitempty"`      // Фиксируется при закрытии, с комиссиями
+	Fees               string            `json:"fees,omitempty"`              // Комиссии исполнений в котируемой валюте, при закрытии
+	ClosedAt           *time.Time        `json:"closed_at,omitempty"`
+	ClosingStatus      TradeStatus       `json:"closing_status,omitempty"`  // Итоговый статус сделки в CLOSING
+	PendingCancels     []PendingCancel   `json:"pending_cancels,omitempty"` // Неподтвержденные отмены при закрытии
+	PausedAt           *time.Time        `json:"paused_at,omitempty"`       // Сделка на паузе: TP и сетка сняты, позиция ведется
+	PausedGrid         []Order           `json:"paused_grid,omitempty"`     // Неисполненные уровни DCA, снятые паузой; выставляются при возобновлении
+	Opening            bool              `json:"opening,omitempty"`         // Вход исполнен, TP и сетка еще не выставлены; открытие доводит сверка
+}
+
+type GridLevel struct {
+	Index            int     `json:"index"`
+	Price            string  `json:"price"`
+	Volume           string  `json:"volume"`            // Объем уровня в USDT
+	DeviationPercent float64 `json:"deviation_percent"` // Отклонение от цены входа
+	Bumped           bool    `json:"bumped,omitempty"`  // Объем поднят до минимального ордера биржи
+}
+
+type RiskLevel string
+
+const (
+	RiskLevelLow    RiskLevel = "LOW"
+	RiskLevelMedium RiskLevel = "MEDIUM"
+	RiskLevelHigh   RiskLevel = "HIGH"
+)
+
+type RiskAssessment struct {
+	Score               int       `json:"score"` // 0..100, чем выше - тем рискованнее
+	Level               RiskLevel `json:"level"`
+	MaxDeviationPercent float64   `json:"max_deviation_percent"` // Падение, покрытое сеткой
+	MartingaleSteepness float64   `json:"martingale_steepness"`  // Объем последнего уровня / первого
+	RequiredCapital     string    `json:"required_capital"`      // Вход + все DCA уровни
+	VolatilityPercent   *float64  `json:"volatility_percent"`    // Средний дневной диапазон
+	AvailableBalance    *string   `json:"available_balance"`     // Свободный остаток котируемой валюты
+	Warnings            []string  `json:"warnings"`
+}
+
+type TradePreview struct {
+	Config          TradeConfig     `json:"config"`
+	EntryPrice      string          `json:"entry_price"`
+	TakeProfitPrice string          `json:"take_profit_price"`
+	BreakEvenPrice  string          `json:"break_even_price"` // Выход без прибыли с учетом комиссий
+	Grid            []GridLevel     `json:"grid"`
+	Risk            *RiskAssessment `json:"risk"`
+	BookSimulation  *BookSimulation `json:"book_simulation,omitempty"` // При simulate_book или max_slippage_percent
 }
 
 type TradeStatus string
@@ -84,4 +330,93 @@ const (
 	TradeStatusCompleted TradeStatus = "COMPLETED"
 	TradeStatusCancelled TradeStatus = "CANCELLED"
 	TradeStatusFailed    TradeStatus = "FAILED"
+	TradeStatusStopped   TradeStatus = "STOPPED"
+	TradeStatusWaiting   TradeStatus = "WAITING" // Ждет пересечения StartPrice, ордеров нет
+
+	TradeStatusPendingApproval TradeStatus = "PENDING_APPROVAL" // Ждет подтверждения оператора, ордеров нет
+	TradeStatusClosing         TradeStatus = "CLOSING"          // Закрыта, но не все ордера сняты с биржи
+	TradeStatusPaused          TradeStatus = "PAUSED"           // Позиция ведется, TP и сетка сняты до возобновления
 )
+
+func (s TradeStatus) IsValid() bool {
+	switch s {
+	case TradeStatusActive, TradeStatusCompleted, TradeStatusCancelled, TradeStatusFailed, TradeStatusStopped, TradeStatusWaiting, TradeStatusPendingApproval, TradeStatusClosing, TradeStatusPaused:
+		return true
+	}
+	return false
+}
+
+// OrderDisplay - значения ордера, округленные по фильтрам символа.
+// Исходные поля ордера сохраняют полную точность.
+type OrderDisplay struct {
+	Quantity    string `json:"quantity"`
+	Price       string `json:"price,omitempty"`
+	ExecutedQty string `json:"executed_qty,omitempty"`
+}
+
+type TradeDisplay struct {
+	TotalInvested string `json:"total_invested"`
+	AveragePrice  string `json:"average_price"`
+	CurrentPrice  string `json:"current_price"`
+}
+
+type ExitAction string
+
+const (
+	ExitActionTighten ExitAction = "tighten" // Подтянуть TP к текущей цене
+	ExitActionMarket  ExitAction = "market"  // Закрыть позицию по рынку
+)
+
+func (a ExitAction) IsValid() bool {
+	return a == ExitActionTighten || a == ExitActionMarket
+}
+
+// ExitAssistantConfig - выход по разворотным свечным фигурам, когда позиция в прибыли.
+type ExitAssistantConfig struct {
+	Intervals        []string   `json:"intervals"`          // Интервалы свечей Bybit: "60", "240"
+	Patterns         []string   `json:"patterns"`           // bearish_engulfing, shooting_star, evening_star
+	MinProfitPercent float64    `json:"min_profit_percent"` // Минимальная прибыль от средней цены для срабатывания
+	Action           ExitAction `json:"action"`
+}
+
+// ConfigSuggestion - параметры сетки, которые перекрыли бы исторические просадки символа в пределах бюджета.
+type ConfigSuggestion struct {
+	Config             TradeConfig `json:"config"`
+	ATRPercent         float64     `json:"atr_percent"`          // Средний истинный диапазон от цены закрытия
+	MaxDrawdownPercent float64     `json:"max_drawdown_percent"` // Наибольшая просадка от пика за период
+	CoveredDropPercent float64     `json:"covered_drop_percent"` // Падение, перекрытое предложенной сеткой
+	RequiredCapital    string      `json:"required_capital"`
+	Grid               []GridLevel `json:"grid"`
+	Warnings           []string    `json:"warnings"`
+}
+
+// BulkScreener отбирает символы по обороту за 24 часа вместо явного списка.
+type BulkScreener struct {
+	QuoteAsset  string  `json:"quote_asset"`  // Котируемая валюта, по умолчанию USDT
+	MinTurnover float64 `json:"min_turnover"` // Минимальный оборот за 24ч в котируемой валюте
+	Limit       int     `json:"limit"`        // Сколько символов с наибольшим оборотом взять
+}
+
+// BulkTradeRequest - создание сделки по шаблону для каждого символа.
+// TotalBudget делится поровну; объемы шаблона масштабируются под долю символа.
+type BulkTradeRequest struct {
+	Preset      TradeConfig   `json:"preset"`
+	Symbols     []string      `json:"symbols"`
+	Screener    *BulkScreener `json:"screener,omitempty"`
+	TotalBudget string        `json:"total_budget"` // Пусто - объемы шаблона без изменений
+	DryRun      bool          `json:"dry_run"`
+}
+
+type BulkTradeItem struct {
+	Symbol          string      `json:"symbol"`
+	Config          TradeConfig `json:"config"`
+	RequiredCapital string      `json:"required_capital"`
+	Trade           *Trade      `json:"trade,omitempty"`
+	Error           string      `json:"error,omitempty"`
+}
+
+type BulkTradeResult struct {
+	DryRun               bool            `json:"dry_run"`
+	TotalRequiredCapital string          `json:"total_required_capital"`
+	Items                []BulkTradeItem `json:"items"`
+}
@@ -0,0 +1,70 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"cryptorg/internal/domain"
+)
+
+const maxExportAttempts = 5
+
+// Queue выгружает сделки в фоне по одной, сохраняя порядок закрытия. Неудачная выгрузка
+// повторяется с удвоением задержки; после maxExportAttempts попыток сделка пропускается.
+type Queue struct {
+	target    domain.TradeExporter
+	jobs      chan domain.TradeExport
+	baseDelay time.Duration
+}
+
+func NewQueue(target domain.TradeExporter, size int, baseDelay time.Duration) *Queue {
+	return &Queue{
+		target:    target,
+		jobs:      make(chan domain.TradeExport, size),
+		baseDelay: baseDelay,
+	}
+}
+
+// ExportTrade ставит сделку в очередь и не блокирует вызывающего.
+func (q *Queue) ExportTrade(ctx context.Context, export domain.TradeExport) error {
+	select {
+	case q.jobs <- export:
+		return nil
+	default:
+		return fmt.Errorf("export queue is full")
+	}
+}
+
+func (q *Queue) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case export := <-q.jobs:
+			q.deliver(ctx, export)
+		}
+	}
+}
+
+func (q *Queue) deliver(ctx context.Context, export domain.TradeExport) {
+	delay := q.baseDelay
+	for attempt := 1; ; attempt++ {
+		err := q.target.ExportTrade(ctx, export)
+		if err == nil {
+			return
+		}
+		if attempt == maxExportAttempts {
+			log.Printf("Failed to export trade %s after %d attempts: %v", export.TradeID, attempt, err)
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
@@ -0,0 +1,240 @@
+// Package export выгружает закрытые сделки во внешние журналы.
+package export
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"cryptorg/internal/domain"
+)
+
+const (
+	sheetsScope     = "https://www.googleapis.com/auth/spreadsheets"
+	sheetsBaseURL   = "https://sheets.googleapis.com/v4/spreadsheets/"
+	defaultTokenURI = "https://oauth2.googleapis.com/token"
+	jwtGrantType    = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+	tokenLifetime   = time.Hour
+)
+
+// SheetsHeader - порядок столбцов строки сделки; заголовок в таблицу не пишется.
+var SheetsHeader = []string{
+	"closed_at", "symbol", "side", "status", "entry_price", "exit_price",
+	"dca_count", "pnl", "fees", "duration", "trade_id",
+}
+
+// serviceAccount - нужные поля JSON ключа сервисного аккаунта Google.
+type serviceAccount struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// SheetsExporter дописывает строку в Google таблицу на каждую закрытую сделку. Таблица
+// должна быть открыта на редактирование для client_email сервисного аккаунта.
+type SheetsExporter struct {
+	spreadsheetID string
+	sheetRange    string
+	email         string
+	key           *rsa.PrivateKey
+	tokenURI      string
+	httpClient    *http.Client
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+func NewSheetsExporter(credentials []byte, spreadsheetID, sheetRange string) (*SheetsExporter, error) {
+	var account serviceAccount
+	if err := json.Unmarshal(credentials, &account); err != nil {
+		return nil, fmt.Errorf("invalid service account credentials: %w", err)
+	}
+	if account.ClientEmail == "" || account.PrivateKey == "" {
+		return nil, errors.New("service account credentials must contain client_email and private_key")
+	}
+
+	key, err := parsePrivateKey(account.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenURI := account.TokenURI
+	if tokenURI == "" {
+		tokenURI = defaultTokenURI
+	}
+
+	return &SheetsExporter{
+		spreadsheetID: spreadsheetID,
+		sheetRange:    sheetRange,
+		email:         account.ClientEmail,
+		key:           key,
+		tokenURI:      tokenURI,
+		httpClient:    &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func parsePrivateKey(data string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("service account private key is not PEM encoded")
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		// Старые ключи бывают в PKCS#1
+		if key, pkcs1Err := x509.ParsePKCS1PrivateKey(block.Bytes); pkcs1Err == nil {
+			return key, nil
+		}
+		return nil, fmt.Errorf("failed to parse service account private key: %w", err)
+	}
+
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("service account private key is not RSA")
+	}
+	return key, nil
+}
+
+func (e *SheetsExporter) ExportTrade(ctx context.Context, export domain.TradeExport) error {
+	return e.appendRow(ctx, []interface{}{
+		export.ClosedAt.UTC().Format("2006-01-02 15:04:05"),
+		export.Symbol,
+		string(export.Side),
+		string(export.Status),
+		export.EntryPrice,
+		export.ExitPrice,
+		export.DCACount,
+		export.PnL,
+		export.Fees,
+		export.Duration.String(),
+		export.TradeID.String(),
+	})
+}
+
+// appendRow добавляет строку после последней заполненной строки диапазона.
+func (e *SheetsExporter) appendRow(ctx context.Context, row []interface{}) error {
+	token, err := e.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{"values": [][]interface{}{row}})
+	if err != nil {
+		return fmt.Errorf("failed to marshal sheet row: %w", err)
+	}
+
+	endpoint := sheetsBaseURL + url.PathEscape(e.spreadsheetID) + "/values/" + url.PathEscape(e.sheetRange) +
+		":append?valueInputOption=USER_ENTERED&insertDataOption=INSERT_ROWS"
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to append sheet row: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		e.mu.Lock()
+		e.token = ""
+		e.mu.Unlock()
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("google sheets API error: status %d, body: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// accessToken возвращает OAuth токен сервисного аккаунта, обновляя его за минуту до истечения.
+func (e *SheetsExporter) accessToken(ctx context.Context) (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.token != "" && time.Now().Before(e.tokenExpiry.Add(-time.Minute)) {
+		return e.token, nil
+	}
+
+	assertion, err := e.signedAssertion(time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", jwtGrantType)
+	form.Set("assertion", assertion)
+	req, err := http.NewRequestWithContext(ctx, "POST", e.tokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch google access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("google token error: status %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode google token response: %w", err)
+	}
+
+	e.token = result.AccessToken
+	e.tokenExpiry = time.Now().Add(time.Duration(result.ExpiresIn) * time.Second)
+	return e.token, nil
+}
+
+// signedAssertion собирает JWT сервисного аккаунта, подписанный RS256.
+func (e *SheetsExporter) signedAssertion(now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss":   e.email,
+		"scope": sheetsScope,
+		"aud":   e.tokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(tokenLifetime).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, e.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign google assertion: %w", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
@@ -0,0 +1,145 @@
+package feature
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Известные флаги рискованных изменений поведения
+const (
+	TPAmend     = "tp_amend"     // Изменять TP через amend вместо отмены и нового ордера
+	BatchOrders = "batch_orders" // Выставлять сетку DCA пакетным запросом
+	WSFills     = "ws_fills"     // Получать исполнения по WebSocket вместо вебхуков
+)
+
+var known = []string{TPAmend, BatchOrders, WSFills}
+
+// fileFormat - файл флагов: секция default и переопределения по окружениям.
+//
+//	{"default": {"tp_amend": false}, "production": {"tp_amend": true}}
+type fileFormat map[string]map[string]bool
+
+// Flags - набор флагов, собранный из файла и переменных окружения.
+// Порядок приоритета: default в файле, секция окружения, переменная FEATURE_FLAGS.
+type Flags struct {
+	path        string
+	environment string
+	overrides   map[string]bool
+
+	mu       sync.RWMutex
+	values   map[string]bool
+	modTime  time.Time
+	loadedAt time.Time
+}
+
+func New(path, environment string, overrides map[string]bool) (*Flags, error) {
+	f := &Flags{
+		path:        path,
+		environment: environment,
+		overrides:   overrides,
+	}
+	if err := f.load(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *Flags) Enabled(name string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.values[name]
+}
+
+// All возвращает значения всех известных и заданных флагов.
+func (f *Flags) All() map[string]bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	result := make(map[string]bool, len(f.values))
+	for _, name := range known {
+		result[name] = false
+	}
+	for name, enabled := range f.values {
+		result[name] = enabled
+	}
+	return result
+}
+
+func (f *Flags) LoadedAt() time.Time {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.loadedAt
+}
+
+func Known() []string {
+	names := append([]string(nil), known...)
+	sort.Strings(names)
+	return names
+}
+
+// Reload перечитывает файл, если он изменился с последней загрузки.
+func (f *Flags) Reload() error {
+	if f.path == "" {
+		return nil
+	}
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return fmt.Errorf("failed to stat feature flags file: %w", err)
+	}
+
+	f.mu.RLock()
+	unchanged := info.ModTime().Equal(f.modTime)
+	f.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	return f.load()
+}
+
+func (f *Flags) load() error {
+	values := make(map[string]bool)
+	var modTime time.Time
+
+	if f.path != "" {
+		info, err := os.Stat(f.path)
+		if err != nil {
+			return fmt.Errorf("failed to stat feature flags file: %w", err)
+		}
+		modTime = info.ModTime()
+
+		data, err := os.ReadFile(f.path)
+		if err != nil {
+			return fmt.Errorf("failed to read feature flags file: %w", err)
+		}
+
+		var file fileFormat
+		if err := json.Unmarshal(data, &file); err != nil {
+			return fmt.Errorf("invalid feature flags file %s: %w", f.path, err)
+		}
+		for name, enabled := range file["default"] {
+			values[name] = enabled
+		}
+		for name, enabled := range file[f.environment] {
+			values[name] = enabled
+		}
+	}
+
+	for name, enabled := range f.overrides {
+		values[name] = enabled
+	}
+
+	f.mu.Lock()
+	f.values = values
+	f.modTime = modTime
+	f.loadedAt = time.Now()
+	f.mu.Unlock()
+	return nil
+}
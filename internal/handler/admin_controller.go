@@ -0,0 +1,421 @@
+package handler
+
+import (
+	"bytes"
+	"cryptorg/internal/domain"
+	"cryptorg/internal/feature"
+	"cryptorg/internal/notify"
+	"cryptorg/internal/service"
+	"cryptorg/pkg/config"
+	"encoding/json"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/valyala/fasthttp"
+)
+
+type AdminHandler struct {
+	config            *config.Config
+	tradeManager      *service.TradeService
+	notificationQueue *notify.Queue
+	features          *feature.Flags
+	backupManager     *service.BackupService
+	consistency       *service.ConsistencyService
+	pnlRecompute      *service.PnLRecomputeService
+	exchangeClient    service.ExchangeClient
+	symbolLists       *service.SymbolLists
+	messageCapture    domain.MessageCapture // nil, если MESSAGE_CAPTURE_PATH не задан
+}
+
+func (h *AdminHandler) bindJSON(ctx *fasthttp.RequestCtx, v interface{}) error {
+	return json.Unmarshal(ctx.PostBody(), v)
+}
+
+func (h *AdminHandler) getParam(ctx *fasthttp.RequestCtx, key string) string {
+	return ctx.UserValue(key).(string)
+}
+
+func (h *AdminHandler) sendResponse(ctx *fasthttp.RequestCtx, status int, data interface{}) {
+	ctx.Response.Header.Set("Content-Type", "application/json")
+	ctx.Response.SetStatusCode(status)
+
+	if data != nil {
+		json.NewEncoder(ctx).Encode(data)
+	}
+}
+
+func (h *AdminHandler) sendError(ctx *fasthttp.RequestCtx, status int, message string) {
+	ctx.Response.Header.Set("Content-Type", "application/json")
+	ctx.Response.SetStatusCode(status)
+	ctx.Response.SetBodyString(`{"error": "` + localize(ctx, message) + `"}`)
+}
+
+func NewAdminController(cfg *config.Config, tradeManager *service.TradeService, notificationQueue *notify.Queue, features *feature.Flags, backupManager *service.BackupService, consistency *service.ConsistencyService, pnlRecompute *service.PnLRecomputeService, symbolLists *service.SymbolLists, exchangeClient service.ExchangeClient, messageCapture domain.MessageCapture) *AdminHandler {
+	return &AdminHandler{
+		config:            cfg,
+		tradeManager:      tradeManager,
+		notificationQueue: notificationQueue,
+		features:          features,
+		backupManager:     backupManager,
+		consistency:       consistency,
+		pnlRecompute:      pnlRecompute,
+		exchangeClient:    exchangeClient,
+		symbolLists:       symbolLists,
+		messageCapture:    messageCapture,
+	}
+}
+
+func (h *AdminHandler) ForceTradeStatus(ctx *fasthttp.RequestCtx) {
+	tradeID, err := uuid.Parse(h.getParam(ctx, "tradeId"))
+	if err != nil {
+		h.sendError(ctx, 400, "Invalid trade ID format")
+		return
+	}
+
+	var req struct {
+		Status domain.TradeStatus `json:"status"`
+		Reason string             `json:"reason"`
+	}
+
+	if err := h.bindJSON(ctx, &req); err != nil {
+		h.sendError(ctx, 400, "Invalid JSON")
+		return
+	}
+
+	if !req.Status.IsValid() {
+		h.sendError(ctx, 400, "Status must be one of ACTIVE, COMPLETED, CANCELLED, FAILED, STOPPED")
+		return
+	}
+
+	if req.Reason == "" {
+		h.sendError(ctx, 400, "Reason is required")
+		return
+	}
+
+	trade, err := h.tradeManager.ForceStatus(tradeID, req.Status, req.Reason)
+	if err != nil {
+		h.sendError(ctx, 404, "Trade not found")
+		return
+	}
+
+	log.Printf("AUDIT: force-status request from %s for trade %s", ctx.RemoteIP(), tradeID)
+	h.sendResponse(ctx, 200, trade)
+}
+
+func (h *AdminHandler) GetNotifications(ctx *fasthttp.RequestCtx) {
+	pending := h.notificationQueue.Pending()
+	undelivered := h.notificationQueue.Undelivered()
+
+	h.sendResponse(ctx, 200, map[string]interface{}{
+		"pending":           pending,
+		"undelivered":       undelivered,
+		"pending_count":     len(pending),
+		"undelivered_count": len(undelivered),
+	})
+}
+
+// GetConfig возвращает действующую конфигурацию экземпляра без ключей и токенов.
+func (h *AdminHandler) GetConfig(ctx *fasthttp.RequestCtx) {
+	cfg := h.config
+
+	testnet := cfg.Bybit.Testnet
+	if cfg.Exchange.Name == "okx" {
+		testnet = cfg.OKX.Demo
+	}
+
+	storageDriver := "memory"
+	if cfg.Storage.JournalPath != "" {
+		storageDriver = "file"
+	}
+
+	channels := []string{"log"}
+	if cfg.Notify.TelegramToken != "" && cfg.Notify.TelegramChatID != "" {
+		channels = append(channels, "telegram")
+	}
+
+	h.sendResponse(ctx, 200, map[string]interface{}{
+		"service":     cfg.Base.ServiceID,
+		"version":     cfg.Base.Version,
+		"environment": cfg.Base.Environment,
+		"language":    cfg.Base.Language,
+		"log_level":   cfg.Base.LogLevel,
+		"exchange": map[string]interface{}{
+			"name":            cfg.Exchange.Name,
+			"testnet":         testnet,
+			"symbol":          cfg.Bybit.Symbol,
+			"order_cache_ttl": cfg.Exchange.OrderCacheTTL,
+			"quote_budgets":   cfg.Exchange.QuoteBudgets,
+			"raw_payloads":    cfg.Exchange.RawPayloads,
+			"stop_cooldown":   cfg.Exchange.StopLossCooldown,
+		},
+		"risk_limits": map[string]interface{}{
+			"max_safety_orders":  domain.MaxSafetyOrders,
+			"max_position_value": domain.MaxPositionValue,
+			"min_order_size":     domain.MinOrderSize,
+			"max_order_size":     domain.MaxOrderSize,
+		},
+		"approval": map[string]interface{}{
+			"thresholds":       cfg.Exchange.ApprovalLimits,
+			"api_enabled":      cfg.Server.HasAdminKey(),
+			"telegram_webhook": cfg.Notify.WebhookSecret != "",
+			"order_webhook":    cfg.Server.OrderWebhookSecret != "",
+		},
+		"auth": map[string]interface{}{
+			"api_keys": len(cfg.Server.APIKeys),
+		},
+		"export": map[string]interface{}{
+			"google_sheets": cfg.Export.SheetsSpreadsheetID != "",
+			"sheets_range":  cfg.Export.SheetsRange,
+		},
+		"trade_defaults": cfg.Trade,
+		"storage": map[string]interface{}{
+			"driver":                    storageDriver,
+			"journal_path":              cfg.Storage.JournalPath,
+			"trades_path":               cfg.Storage.TradesPath,
+			"executions_path":           cfg.Storage.ExecutionsPath,
+			"pnl_revisions_path":        cfg.Storage.PnLRevisionsPath,
+			"bots_path":                 cfg.Storage.BotsPath,
+			"precision_overrides_path":  cfg.Storage.PrecisionPath,
+			"cooldowns_path":            cfg.Storage.CooldownsPath,
+			"message_capture_path":      cfg.Storage.MessageCapturePath,
+			"message_capture_max_bytes": cfg.Storage.MessageCaptureMaxBytes,
+			"backup_dir":                cfg.Storage.BackupDir,
+		},
+		"notifications": map[string]interface{}{
+			"channels":   channels,
+			"retry_base": cfg.Notify.RetryBase,
+			"retry_max":  cfg.Notify.RetryMax,
+			"max_age":    cfg.Notify.MaxAge,
+		},
+		"feature_flags":      h.features.All(),
+		"signal":             cfg.Signal,
+		"report":             cfg.Report,
+		"metrics":            map[string]interface{}{"backend": cfg.Metrics.Backend, "prefix": cfg.Metrics.Prefix},
+		"tracing":            map[string]interface{}{"enabled": cfg.Tracing.Enabled, "endpoint": cfg.Tracing.Endpoint, "sample_ratio": cfg.Tracing.SampleRatio},
+		"scheduler_interval": cfg.Worker.SchedulerInterval,
+		"consistency_repair": cfg.Worker.ConsistencyAutoRepair,
+		"reconcile_startup":  cfg.Worker.ReconcileOnStartup,
+		"order_poll":         map[string]interface{}{"interval": cfg.Worker.OrderPollInterval, "per_symbol": cfg.Worker.OrderPollPerSymbol},
+	})
+}
+
+func (h *AdminHandler) GetFeatures(ctx *fasthttp.RequestCtx) {
+	h.sendResponse(ctx, 200, map[string]interface{}{
+		"environment": h.config.Base.Environment,
+		"flags":       h.features.All(),
+		"known":       feature.Known(),
+		"loaded_at":   h.features.LoadedAt(),
+	})
+}
+
+// CreateBackup снимает копию хранилища. С since_seq копия инкрементальная: только события после него.
+func (h *AdminHandler) CreateBackup(ctx *fasthttp.RequestCtx) {
+	var req struct {
+		SinceSeq uint64 `json:"since_seq"`
+	}
+
+	if len(ctx.PostBody()) > 0 {
+		if err := h.bindJSON(ctx, &req); err != nil {
+			h.sendError(ctx, 400, "Invalid JSON")
+			return
+		}
+	}
+
+	path, backup, err := h.backupManager.Create(req.SinceSeq)
+	if err != nil {
+		h.sendError(ctx, 500, err.Error())
+		return
+	}
+
+	log.Printf("AUDIT: backup %s created by %s", path, ctx.RemoteIP())
+	h.sendResponse(ctx, 201, map[string]interface{}{
+		"path":       path,
+		"created_at": backup.CreatedAt,
+		"from_seq":   backup.FromSeq,
+		"last_seq":   backup.LastSeq,
+		"events":     len(backup.Events),
+		"full":       backup.IsFull(),
+	})
+}
+
+// GetRawMessages отдает сохраненные сырые сообщения биржи файлом JSON Lines, от старых к новым.
+func (h *AdminHandler) GetRawMessages(ctx *fasthttp.RequestCtx) {
+	if h.messageCapture == nil {
+		h.sendError(ctx, 404, "Message capture is disabled")
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := h.messageCapture.Export(&buf); err != nil {
+		h.sendError(ctx, 500, err.Error())
+		return
+	}
+
+	ctx.Response.Header.Set("Content-Type", "application/x-ndjson")
+	ctx.Response.Header.Set("Content-Disposition", `attachment; filename="raw-messages.jsonl"`)
+	ctx.Response.SetStatusCode(200)
+	ctx.Response.SetBody(buf.Bytes())
+}
+
+// GetConsistency возвращает отчет последней проверки согласованности сделок с биржей.
+func (h *AdminHandler) GetConsistency(ctx *fasthttp.RequestCtx) {
+	report, err := h.consistency.LastReport(ctx)
+	if err != nil && report == nil {
+		h.sendError(ctx, 500, err.Error())
+		return
+	}
+
+	h.sendResponse(ctx, 200, report)
+}
+
+// Reconcile запускает сверку с биржей с исправлениями и возвращает ее отчет.
+func (h *AdminHandler) Reconcile(ctx *fasthttp.RequestCtx) {
+	report, err := h.consistency.Reconcile(ctx)
+	if err != nil && report == nil {
+		h.sendError(ctx, 502, err.Error())
+		return
+	}
+
+	h.sendResponse(ctx, 200, report)
+}
+
+func (h *AdminHandler) GetSymbolLists(ctx *fasthttp.RequestCtx) {
+	allow, deny := h.symbolLists.Lists()
+	h.sendResponse(ctx, 200, map[string]interface{}{"allow": allow, "deny": deny})
+}
+
+// UpdateSymbolLists заменяет списки разрешенных и запрещенных символов до перезапуска.
+// Уже открытые сделки не закрываются, но новые циклы по запрещенным символам не начнутся.
+func (h *AdminHandler) UpdateSymbolLists(ctx *fasthttp.RequestCtx) {
+	var req struct {
+		Allow []string `json:"allow"`
+		Deny  []string `json:"deny"`
+	}
+
+	if err := h.bindJSON(ctx, &req); err != nil {
+		h.sendError(ctx, 400, "Invalid JSON")
+		return
+	}
+
+	if err := h.symbolLists.Set(req.Allow, req.Deny); err != nil {
+		h.sendError(ctx, 400, err.Error())
+		return
+	}
+
+	allow, deny := h.symbolLists.Lists()
+	log.Printf("AUDIT: symbol lists set by %s: allow=%v deny=%v", ctx.RemoteIP(), allow, deny)
+	h.sendResponse(ctx, 200, map[string]interface{}{"allow": allow, "deny": deny})
+}
+
+func (h *AdminHandler) GetTradingStatus(ctx *fasthttp.RequestCtx) {
+	h.sendResponse(ctx, 200, h.tradeManager.TradingSuspension())
+}
+
+// ResumeTrading снимает остановку по дневному лимиту убытка.
+func (h *AdminHandler) ResumeTrading(ctx *fasthttp.RequestCtx) {
+	if err := h.tradeManager.ResumeTrading(); err != nil {
+		h.sendError(ctx, 409, err.Error())
+		return
+	}
+
+	log.Printf("AUDIT: trading resumed by %s", ctx.RemoteIP())
+	h.sendResponse(ctx, 200, h.tradeManager.TradingSuspension())
+}
+
+// GetAttention возвращает сделки, обработка которых прервалась паникой и еще не проверена.
+func (h *AdminHandler) GetAttention(ctx *fasthttp.RequestCtx) {
+	trades := h.tradeManager.AttentionTrades()
+	h.sendResponse(ctx, 200, map[string]interface{}{
+		"trades": trades,
+		"count":  len(trades),
+	})
+}
+
+func (h *AdminHandler) AcknowledgeAttention(ctx *fasthttp.RequestCtx) {
+	tradeID, err := uuid.Parse(h.getParam(ctx, "tradeId"))
+	if err != nil {
+		h.sendError(ctx, 400, "Invalid trade ID format")
+		return
+	}
+
+	var req struct {
+		Note string `json:"note"`
+	}
+
+	if len(ctx.PostBody()) > 0 {
+		if err := h.bindJSON(ctx, &req); err != nil {
+			h.sendError(ctx, 400, "Invalid JSON")
+			return
+		}
+	}
+
+	trade, err := h.tradeManager.AcknowledgeAttention(tradeID, req.Note)
+	if err != nil {
+		h.sendError(ctx, 404, err.Error())
+		return
+	}
+
+	log.Printf("AUDIT: attention on trade %s cleared by %s", tradeID, ctx.RemoteIP())
+	h.sendResponse(ctx, 200, trade)
+}
+
+// RecomputePnL пересчитывает учет закрытых сделок по исполнениям из журнала и сохраняет
+// новые ревизии; исходные записи не меняются. С dry_run ревизии только возвращаются.
+func (h *AdminHandler) RecomputePnL(ctx *fasthttp.RequestCtx) {
+	var req domain.PnLRecomputeRequest
+	if err := h.bindJSON(ctx, &req); err != nil {
+		h.sendError(ctx, 400, "Invalid JSON")
+		return
+	}
+
+	if req.Reason == "" && !req.DryRun {
+		h.sendError(ctx, 400, "Reason is required")
+		return
+	}
+
+	result, err := h.pnlRecompute.Recompute(req)
+	if err != nil {
+		h.sendError(ctx, 500, err.Error())
+		return
+	}
+
+	if !req.DryRun {
+		log.Printf("AUDIT: pnl recompute by %s: %d revisions, %d changed: %s", ctx.RemoteIP(), len(result.Revisions), result.Changed, req.Reason)
+	}
+	h.sendResponse(ctx, 200, result)
+}
+
+// GetPnLRevisions возвращает сохраненные ревизии пересчета, ?trade_id= - одной сделки.
+func (h *AdminHandler) GetPnLRevisions(ctx *fasthttp.RequestCtx) {
+	var tradeID *uuid.UUID
+	if raw := string(ctx.QueryArgs().Peek("trade_id")); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			h.sendError(ctx, 400, "Invalid trade ID format")
+			return
+		}
+		tradeID = &id
+	}
+
+	revisions, err := h.pnlRecompute.Revisions(tradeID)
+	if err != nil {
+		h.sendError(ctx, 500, err.Error())
+		return
+	}
+
+	h.sendResponse(ctx, 200, map[string]interface{}{
+		"revisions": revisions,
+		"count":     len(revisions),
+	})
+}
+
+// GetRateLimits возвращает квоты API биржи по эндпоинтам: лимит и остаток из последнего
+// ответа, запросы за скользящую минуту, задержки и отказы по лимиту.
+func (h *AdminHandler) GetRateLimits(ctx *fasthttp.RequestCtx) {
+	endpoints := h.exchangeClient.RateLimits().Snapshot()
+	h.sendResponse(ctx, 200, map[string]interface{}{
+		"exchange":  h.config.Exchange.Name,
+		"endpoints": endpoints,
+		"count":     len(endpoints),
+	})
+}
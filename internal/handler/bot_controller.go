@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"cryptorg/internal/domain"
+	"cryptorg/internal/service"
+	apperrors "cryptorg/pkg/errors"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/valyala/fasthttp"
+)
+
+type BotHandler struct {
+	botManager *service.BotService
+	defaults   domain.TradeConfig
+}
+
+func (h *BotHandler) bindJSON(ctx *fasthttp.RequestCtx, v interface{}) error {
+	return json.Unmarshal(ctx.PostBody(), v)
+}
+
+func (h *BotHandler) getParam(ctx *fasthttp.RequestCtx, key string) string {
+	return ctx.UserValue(key).(string)
+}
+
+func (h *BotHandler) sendResponse(ctx *fasthttp.RequestCtx, status int, data interface{}) {
+	ctx.Response.Header.Set("Content-Type", "application/json")
+	ctx.Response.SetStatusCode(status)
+
+	if data != nil {
+		json.NewEncoder(ctx).Encode(data)
+	}
+}
+
+func (h *BotHandler) sendError(ctx *fasthttp.RequestCtx, status int, message string) {
+	ctx.Response.Header.Set("Content-Type", "application/json")
+	ctx.Response.SetStatusCode(status)
+	ctx.Response.SetBodyString(`{"error": "` + localize(ctx, message) + `"}`)
+}
+
+func (h *BotHandler) sendMessage(ctx *fasthttp.RequestCtx, message string) {
+	h.sendResponse(ctx, 200, map[string]string{"message": localize(ctx, message)})
+}
+
+func NewBotController(botManager *service.BotService, defaults domain.TradeConfig) *BotHandler {
+	return &BotHandler{
+		botManager: botManager,
+		defaults:   defaults,
+	}
+}
+
+// bindRequest разбирает и проверяет бота; поля конфига, отсутствующие в JSON,
+// берутся из глобальных значений по умолчанию, как при создании сделки.
+func (h *BotHandler) bindRequest(ctx *fasthttp.RequestCtx) (domain.BotRequest, bool) {
+	req := domain.BotRequest{Config: h.defaults}
+	if err := h.bindJSON(ctx, &req); err != nil {
+		h.sendError(ctx, 400, "Invalid JSON")
+		return req, false
+	}
+
+	if req.Symbol == "" {
+		req.Symbol = req.Config.Symbol
+	}
+	req.Config.Symbol = req.Symbol
+
+	if req.MaxConcurrentDeals < 0 {
+		h.sendError(ctx, 400, "Max concurrent deals must not be negative")
+		return req, false
+	}
+	if req.ProfitTarget != "" {
+		target, err := strconv.ParseFloat(req.ProfitTarget, 64)
+		if err != nil || target <= 0 {
+			h.sendError(ctx, 400, "Profit target must be a positive number")
+			return req, false
+		}
+	}
+	if req.Config.AutoRestart {
+		h.sendError(ctx, 400, "Auto restart is not supported for bots, the bot opens new cycles itself")
+		return req, false
+	}
+	if message := validateTradeConfig(&req.Config); message != "" {
+		h.sendError(ctx, 400, message)
+		return req, false
+	}
+	return req, true
+}
+
+func (h *BotHandler) botID(ctx *fasthttp.RequestCtx) (uuid.UUID, bool) {
+	botID, err := uuid.Parse(h.getParam(ctx, "botId"))
+	if err != nil {
+		h.sendError(ctx, 400, "Invalid bot ID format")
+		return uuid.Nil, false
+	}
+	return botID, true
+}
+
+func (h *BotHandler) sendBotError(ctx *fasthttp.RequestCtx, err error, message string) {
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) {
+		h.sendResponse(ctx, appErr.GetHTTPStatus(), appErr)
+		return
+	}
+	h.sendError(ctx, 500, message)
+}
+
+func (h *BotHandler) CreateBot(ctx *fasthttp.RequestCtx) {
+	req, ok := h.bindRequest(ctx)
+	if !ok {
+		return
+	}
+
+	bot, err := h.botManager.CreateBot(ctx, req)
+	if err != nil {
+		h.sendBotError(ctx, err, "Failed to create bot")
+		return
+	}
+
+	h.sendResponse(ctx, 201, bot)
+}
+
+func (h *BotHandler) GetAllBots(ctx *fasthttp.RequestCtx) {
+	bots := h.botManager.GetAllBots()
+
+	h.sendResponse(ctx, 200, map[string]interface{}{
+		"bots":  bots,
+		"count": len(bots),
+	})
+}
+
+func (h *BotHandler) GetBot(ctx *fasthttp.RequestCtx) {
+	botID, ok := h.botID(ctx)
+	if !ok {
+		return
+	}
+
+	bot, err := h.botManager.GetBot(botID)
+	if err != nil {
+		h.sendError(ctx, 404, "Bot not found")
+		return
+	}
+
+	h.sendResponse(ctx, 200, bot)
+}
+
+func (h *BotHandler) UpdateBot(ctx *fasthttp.RequestCtx) {
+	botID, ok := h.botID(ctx)
+	if !ok {
+		return
+	}
+	req, ok := h.bindRequest(ctx)
+	if !ok {
+		return
+	}
+
+	bot, err := h.botManager.UpdateBot(ctx, botID, req)
+	if err != nil {
+		h.sendBotError(ctx, err, "Failed to update bot")
+		return
+	}
+
+	h.sendResponse(ctx, 200, bot)
+}
+
+func (h *BotHandler) DeleteBot(ctx *fasthttp.RequestCtx) {
+	botID, ok := h.botID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.botManager.DeleteBot(botID); err != nil {
+		h.sendBotError(ctx, err, "Failed to delete bot")
+		return
+	}
+
+	h.sendMessage(ctx, "Bot deleted successfully")
+}
+
+func (h *BotHandler) PauseBot(ctx *fasthttp.RequestCtx) {
+	botID, ok := h.botID(ctx)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if len(ctx.PostBody()) > 0 {
+		if err := h.bindJSON(ctx, &req); err != nil {
+			h.sendError(ctx, 400, "Invalid JSON")
+			return
+		}
+	}
+	reason := "Manual pause"
+	if req.Reason != "" {
+		reason = req.Reason
+	}
+
+	bot, err := h.botManager.PauseBot(ctx, botID, reason)
+	if err != nil {
+		h.sendBotError(ctx, err, "Failed to pause bot")
+		return
+	}
+
+	h.sendResponse(ctx, 200, bot)
+}
+
+func (h *BotHandler) ResumeBot(ctx *fasthttp.RequestCtx) {
+	botID, ok := h.botID(ctx)
+	if !ok {
+		return
+	}
+
+	bot, err := h.botManager.ResumeBot(ctx, botID)
+	if err != nil {
+		h.sendBotError(ctx, err, "Failed to resume bot")
+		return
+	}
+
+	h.sendResponse(ctx, 200, bot)
+}
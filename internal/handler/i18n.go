@@ -0,0 +1,15 @@
+package handler
+
+import (
+	"cryptorg/internal/i18n"
+
+	"github.com/valyala/fasthttp"
+)
+
+// localize переводит сообщение ответа на язык из Accept-Language, а без заголовка - на язык
+// из конфига. Сообщения с подставленными значениями (ошибки сервисов) остаются как есть.
+func localize(ctx *fasthttp.RequestCtx, message string) string {
+	lang := i18n.FromAcceptLanguage(string(ctx.Request.Header.Peek(fasthttp.HeaderAcceptLanguage)), i18n.Default())
+	ctx.Response.Header.Set(fasthttp.HeaderContentLanguage, string(lang))
+	return i18n.T(lang, message)
+}
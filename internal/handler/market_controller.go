@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"cryptorg/internal/bybit"
+	"cryptorg/internal/service"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+// MarketHandler отдает рыночные данные биржи для UI и внешних стратегий.
+type MarketHandler struct {
+	orderManager *service.OrderService
+}
+
+func (h *MarketHandler) getParam(ctx *fasthttp.RequestCtx, key string) string {
+	return ctx.UserValue(key).(string)
+}
+
+func (h *MarketHandler) sendResponse(ctx *fasthttp.RequestCtx, status int, data interface{}) {
+	ctx.Response.Header.Set("Content-Type", "application/json")
+	ctx.Response.SetStatusCode(status)
+
+	if data != nil {
+		json.NewEncoder(ctx).Encode(data)
+	}
+}
+
+func (h *MarketHandler) sendError(ctx *fasthttp.RequestCtx, status int, message string) {
+	ctx.Response.Header.Set("Content-Type", "application/json")
+	ctx.Response.SetStatusCode(status)
+	ctx.Response.SetBodyString(`{"error": "` + localize(ctx, message) + `"}`)
+}
+
+func NewMarketController(orderManager *service.OrderService) *MarketHandler {
+	return &MarketHandler{
+		orderManager: orderManager,
+	}
+}
+
+// queryLimit читает ?limit=, возвращая fallback для пустого значения.
+func (h *MarketHandler) queryLimit(ctx *fasthttp.RequestCtx, fallback, max int) (int, bool) {
+	value := string(ctx.QueryArgs().Peek("limit"))
+	if value == "" {
+		return fallback, true
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > max {
+		return 0, false
+	}
+	return limit, true
+}
+
+func (h *MarketHandler) GetTicker(ctx *fasthttp.RequestCtx) {
+	symbol := strings.ToUpper(h.getParam(ctx, "symbol"))
+
+	ticker, err := h.orderManager.MarketTicker(ctx, symbol)
+	if err != nil {
+		h.sendError(ctx, 502, "Failed to fetch ticker")
+		return
+	}
+
+	h.sendResponse(ctx, 200, ticker)
+}
+
+// GetOrderBook возвращает стакан символа; ?limit= - уровней на сторону, до 200.
+func (h *MarketHandler) GetOrderBook(ctx *fasthttp.RequestCtx) {
+	symbol := strings.ToUpper(h.getParam(ctx, "symbol"))
+
+	limit, ok := h.queryLimit(ctx, service.DefaultMarketBookDepth, bybit.OrderBookMaxDepth)
+	if !ok {
+		h.sendError(ctx, 400, "limit must be between 1 and 200")
+		return
+	}
+
+	book, err := h.orderManager.MarketOrderBook(ctx, symbol, limit)
+	if err != nil {
+		h.sendError(ctx, 502, "Failed to fetch order book")
+		return
+	}
+
+	h.sendResponse(ctx, 200, book)
+}
+
+// GetKlines возвращает последние свечи символа: ?interval= в формате Bybit (1, 5, 60, D...),
+// ?limit= - число свечей, до 1000.
+func (h *MarketHandler) GetKlines(ctx *fasthttp.RequestCtx) {
+	symbol := strings.ToUpper(h.getParam(ctx, "symbol"))
+
+	interval := string(ctx.QueryArgs().Peek("interval"))
+	if interval == "" {
+		interval = service.DefaultMarketInterval
+	}
+	if !service.ValidKlineInterval(interval) {
+		h.sendError(ctx, 400, "Unsupported kline interval")
+		return
+	}
+
+	limit, ok := h.queryLimit(ctx, service.DefaultMarketKlines, service.MaxMarketKlines)
+	if !ok {
+		h.sendError(ctx, 400, "limit must be between 1 and 1000")
+		return
+	}
+
+	klines, err := h.orderManager.MarketKlines(ctx, symbol, interval, limit)
+	if err != nil {
+		h.sendError(ctx, 502, "Failed to fetch klines")
+		return
+	}
+
+	h.sendResponse(ctx, 200, klines)
+}
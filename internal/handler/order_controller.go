@@ -3,13 +3,17 @@ package handler
 import (
 	"cryptorg/internal/domain"
 	"cryptorg/internal/service"
+	apperrors "cryptorg/pkg/errors"
 	"encoding/json"
+	"errors"
+	"strings"
 
 	"github.com/valyala/fasthttp"
 )
 
 type OrderHandler struct {
 	orderManager *service.OrderService
+	tradeManager *service.TradeService
 }
 
 func (h *OrderHandler) bindJSON(ctx *fasthttp.RequestCtx, v interface{}) error {
@@ -32,16 +36,39 @@ func (h *OrderHandler) sendResponse(ctx *fasthttp.RequestCtx, status int, data i
 func (h *OrderHandler) sendError(ctx *fasthttp.RequestCtx, status int, message string) {
 	ctx.Response.Header.Set("Content-Type", "application/json")
 	ctx.Response.SetStatusCode(status)
-	ctx.Response.SetBodyString(`{"error": "` + message + `"}`)
+	ctx.Response.SetBodyString(`{"error": "` + localize(ctx, message) + `"}`)
+}
+
+// sendOrderError отдает отказ биржи с его HTTP статусом, прочие ошибки - как 500.
+func (h *OrderHandler) sendOrderError(ctx *fasthttp.RequestCtx, err error, message string) {
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) {
+		h.sendResponse(ctx, appErr.GetHTTPStatus(), appErr)
+		return
+	}
+	h.sendError(ctx, 500, message)
+}
+
+// precision возвращает режим ?precision=display|raw; по умолчанию display.
+func (h *OrderHandler) precision(ctx *fasthttp.RequestCtx) (string, bool) {
+	mode := string(ctx.QueryArgs().Peek("precision"))
+	switch mode {
+	case "":
+		return service.PrecisionDisplay, true
+	case service.PrecisionDisplay, service.PrecisionRaw:
+		return mode, true
+	}
+	return "", false
 }
 
 func (h *OrderHandler) sendMessage(ctx *fasthttp.RequestCtx, message string) {
-	h.sendResponse(ctx, 200, map[string]string{"message": message})
+	h.sendResponse(ctx, 200, map[string]string{"message": localize(ctx, message)})
 }
 
-func NewOrderController(orderManager *service.OrderService) *OrderHandler {
+func NewOrderController(orderManager *service.OrderService, tradeManager *service.TradeService) *OrderHandler {
 	return &OrderHandler{
 		orderManager: orderManager,
+		tradeManager: tradeManager,
 	}
 }
 
@@ -61,7 +88,7 @@ func (h *OrderHandler) ExecuteMarketOrder(ctx *fasthttp.RequestCtx) {
 
 	order, err := h.orderManager.ExecuteMarketOrder(ctx, req)
 	if err != nil {
-		h.sendError(ctx, 500, "Failed to execute market order")
+		h.sendOrderError(ctx, err, "Failed to execute market order")
 		return
 	}
 
@@ -84,7 +111,7 @@ func (h *OrderHandler) ExecuteLimitOrder(ctx *fasthttp.RequestCtx) {
 
 	order, err := h.orderManager.ExecuteLimitOrder(ctx, req)
 	if err != nil {
-		h.sendError(ctx, 500, "Failed to execute limit order")
+		h.sendOrderError(ctx, err, "Failed to execute limit order")
 		return
 	}
 
@@ -101,13 +128,42 @@ func (h *OrderHandler) TerminateOrder(ctx *fasthttp.RequestCtx) {
 	}
 
 	if err := h.orderManager.TerminateOrder(ctx, symbol, orderIDStr); err != nil {
-		h.sendError(ctx, 500, "Failed to terminate order")
+		h.sendOrderError(ctx, err, "Failed to terminate order")
 		return
 	}
 
 	h.sendMessage(ctx, "Order terminated successfully")
 }
 
+// AmendOrder меняет цену и/или количество ордера через бота, чтобы правка попала в журнал сделки.
+func (h *OrderHandler) AmendOrder(ctx *fasthttp.RequestCtx) {
+	var req domain.AmendOrderRequest
+	if err := h.bindJSON(ctx, &req); err != nil {
+		h.sendError(ctx, 400, "Invalid JSON")
+		return
+	}
+
+	req.Symbol = h.getParam(ctx, "symbol")
+	req.OrderID = h.getParam(ctx, "orderId")
+	if req.Symbol == "" || req.OrderID == "" {
+		h.sendError(ctx, 400, "Symbol and orderId are required")
+		return
+	}
+
+	order, err := h.tradeManager.AmendOrder(ctx, req)
+	if err != nil {
+		var appErr *apperrors.AppError
+		if errors.As(err, &appErr) {
+			h.sendResponse(ctx, appErr.GetHTTPStatus(), appErr)
+			return
+		}
+		h.sendError(ctx, 502, "Failed to amend order")
+		return
+	}
+
+	h.sendResponse(ctx, 200, order)
+}
+
 func (h *OrderHandler) FetchOrderStatus(ctx *fasthttp.RequestCtx) {
 	symbol := h.getParam(ctx, "symbol")
 	orderIDStr := h.getParam(ctx, "orderId")
@@ -117,15 +173,46 @@ func (h *OrderHandler) FetchOrderStatus(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
+	mode, ok := h.precision(ctx)
+	if !ok {
+		h.sendError(ctx, 400, "Precision must be one of display, raw")
+		return
+	}
+
 	order, err := h.orderManager.FetchOrderStatus(ctx, symbol, orderIDStr)
 	if err != nil {
-		h.sendError(ctx, 500, "Failed to fetch order status")
+		h.sendOrderError(ctx, err, "Failed to fetch order status")
 		return
 	}
 
+	if mode == service.PrecisionDisplay {
+		order = h.orderManager.DisplayOrder(ctx, order)
+	}
+
 	h.sendResponse(ctx, 200, order)
 }
 
+// GetAccountBalance возвращает ненулевые балансы счета; ?coin=USDT оставляет одну монету.
+func (h *OrderHandler) GetAccountBalance(ctx *fasthttp.RequestCtx) {
+	balance, err := h.orderManager.FetchWalletBalance(ctx)
+	if err != nil {
+		h.sendError(ctx, 500, "Failed to fetch account balance")
+		return
+	}
+
+	if coin := strings.ToUpper(string(ctx.QueryArgs().Peek("coin"))); coin != "" {
+		coins := make([]domain.CoinBalance, 0, 1)
+		for _, balance := range balance.Coins {
+			if balance.Coin == coin {
+				coins = append(coins, balance)
+			}
+		}
+		balance.Coins = coins
+	}
+
+	h.sendResponse(ctx, 200, balance)
+}
+
 func (h *OrderHandler) ComputeTakeProfit(ctx *fasthttp.RequestCtx) {
 	var req struct {
 		EntryPrice    string           `json:"entry_price"`
@@ -187,3 +274,19 @@ func (h *OrderHandler) ComputeDCAPrice(ctx *fasthttp.RequestCtx) {
 		"dca_price":     dcaPrice,
 	})
 }
+
+// FetchRawPayloads отдает исходные ответы биржи по ID ордера на бирже, если включен ORDER_RAW_PAYLOADS.
+func (h *OrderHandler) FetchRawPayloads(ctx *fasthttp.RequestCtx) {
+	orderID := h.getParam(ctx, "orderId")
+
+	payloads, err := h.orderManager.RawPayloads(orderID)
+	if err != nil {
+		h.sendError(ctx, 404, err.Error())
+		return
+	}
+
+	h.sendResponse(ctx, 200, map[string]interface{}{
+		"order_id": orderID,
+		"payloads": payloads,
+	})
+}
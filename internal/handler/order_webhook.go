@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+// orderWebhookSignatureHeader - HMAC-SHA256 тела запроса в hex с общим секретом
+// ORDER_WEBHOOK_SECRET; префикс "sha256=" допускается.
+const orderWebhookSignatureHeader = "X-Webhook-Signature"
+
+// orderWebhookEvent - обновление ордера из вебхука в общем виде для обоих форматов.
+type orderWebhookEvent struct {
+	OrderID       string
+	Symbol        string
+	Status        string
+	ExecutedQty   string
+	ExecutedValue string // Только у ордеров Bybit v5
+	Fee           string // Только у ордеров Bybit v5
+	Price         string
+	ExecID        string // Только у исполнений в формате Binance; пусто - без отсева повторов
+	Timestamp     int64  // Время события на бирже, мс
+}
+
+// verifyWebhookSignature сверяет подпись тела запроса. Без секрета проверка выключена.
+func (h *TradeHandler) verifyWebhookSignature(ctx *fasthttp.RequestCtx) bool {
+	if h.webhookSecret == "" {
+		return true
+	}
+
+	header := strings.TrimSpace(string(ctx.Request.Header.Peek(orderWebhookSignatureHeader)))
+	signature, err := hex.DecodeString(strings.TrimPrefix(header, "sha256="))
+	if err != nil || len(signature) == 0 {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(h.webhookSecret))
+	mac.Write(ctx.PostBody())
+	return hmac.Equal(signature, mac.Sum(nil))
+}
+
+// WebhookSigned сообщает, что вебхук ордеров проверяет подпись сам и API ключ ему не нужен.
+func (h *TradeHandler) WebhookSigned() bool {
+	return h.webhookSecret != ""
+}
+
+// bybitOrderUpdate - ордер из сообщения топика order Bybit v5.
+type bybitOrderUpdate struct {
+	Category     string `json:"category"`
+	Symbol       string `json:"symbol"`
+	OrderID      string `json:"orderId"`
+	OrderStatus  string `json:"orderStatus"`
+	CumExecQty   string `json:"cumExecQty"`
+	CumExecValue string `json:"cumExecValue"`
+	CumExecFee   string `json:"cumExecFee"`
+	AvgPrice     string `json:"avgPrice"`
+	UpdatedTime  string `json:"updatedTime"`
+}
+
+// parseOrderWebhook разбирает тело вебхука: сообщение топика order Bybit v5
+// ({"topic": "order", "data": [...]}), отдельный ордер v5 или событие в формате Binance
+// (e, s, i, X, z, L, t, T). Ордера не спотовых категорий пропускаются.
+func parseOrderWebhook(body []byte) ([]orderWebhookEvent, error) {
+	var probe struct {
+		Topic       string          `json:"topic"`
+		Data        json.RawMessage `json:"data"`
+		OrderStatus string          `json:"orderStatus"`
+	}
+	if err := json.Unmarshal(body, &probe); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case probe.Topic != "" || len(probe.Data) > 0:
+		var orders []bybitOrderUpdate
+		if err := json.Unmarshal(probe.Data, &orders); err != nil {
+			return nil, err
+		}
+		return bybitOrderEvents(orders), nil
+	case probe.OrderStatus != "":
+		var order bybitOrderUpdate
+		if err := json.Unmarshal(body, &order); err != nil {
+			return nil, err
+		}
+		return bybitOrderEvents([]bybitOrderUpdate{order}), nil
+	}
+
+	var event struct {
+		EventType   string `json:"e"` // Event type
+		Symbol      string `json:"s"` // Symbol
+		OrderID     string `json:"i"` // Order ID
+		Status      string `json:"X"` // Order status
+		Side        string `json:"S"` // Side
+		Type        string `json:"o"` // Order type
+		ExecutedQty string `json:"z"` // Cumulative filled quantity
+		LastPrice   string `json:"L"` // Last executed price
+		ExecID      string `json:"t"` // Execution ID
+		TradeTime   int64  `json:"T"` // Transaction time, ms
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, err
+	}
+	return []orderWebhookEvent{{
+		OrderID:     event.OrderID,
+		Symbol:      event.Symbol,
+		Status:      event.Status,
+		ExecutedQty: event.ExecutedQty,
+		Price:       event.LastPrice,
+		ExecID:      event.ExecID,
+		Timestamp:   event.TradeTime,
+	}}, nil
+}
+
+func bybitOrderEvents(orders []bybitOrderUpdate) []orderWebhookEvent {
+	events := make([]orderWebhookEvent, 0, len(orders))
+	for _, order := range orders {
+		if order.Category != "" && order.Category != "spot" {
+			continue
+		}
+		updatedAt, _ := strconv.ParseInt(order.UpdatedTime, 10, 64)
+		events = append(events, orderWebhookEvent{
+			OrderID:       order.OrderID,
+			Symbol:        order.Symbol,
+			Status:        order.OrderStatus,
+			ExecutedQty:   order.CumExecQty,
+			ExecutedValue: order.CumExecValue,
+			Fee:           order.CumExecFee,
+			Price:         order.AvgPrice,
+			Timestamp:     updatedAt,
+		})
+	}
+	return events
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"cryptorg/internal/domain"
+	"cryptorg/internal/service"
+	apperrors "cryptorg/pkg/errors"
+	"encoding/json"
+	"errors"
+	"math"
+
+	"github.com/google/uuid"
+	"github.com/valyala/fasthttp"
+)
+
+type RebalancerHandler struct {
+	rebalancerManager *service.RebalancerService
+}
+
+func (h *RebalancerHandler) bindJSON(ctx *fasthttp.RequestCtx, v interface{}) error {
+	return json.Unmarshal(ctx.PostBody(), v)
+}
+
+func (h *RebalancerHandler) getParam(ctx *fasthttp.RequestCtx, key string) string {
+	return ctx.UserValue(key).(string)
+}
+
+func (h *RebalancerHandler) sendResponse(ctx *fasthttp.RequestCtx, status int, data interface{}) {
+	ctx.Response.Header.Set("Content-Type", "application/json")
+	ctx.Response.SetStatusCode(status)
+
+	if data != nil {
+		json.NewEncoder(ctx).Encode(data)
+	}
+}
+
+func (h *RebalancerHandler) sendError(ctx *fasthttp.RequestCtx, status int, message string) {
+	ctx.Response.Header.Set("Content-Type", "application/json")
+	ctx.Response.SetStatusCode(status)
+	ctx.Response.SetBodyString(`{"error": "` + localize(ctx, message) + `"}`)
+}
+
+func (h *RebalancerHandler) sendMessage(ctx *fasthttp.RequestCtx, message string) {
+	h.sendResponse(ctx, 200, map[string]string{"message": localize(ctx, message)})
+}
+
+func NewRebalancerController(rebalancerManager *service.RebalancerService) *RebalancerHandler {
+	return &RebalancerHandler{
+		rebalancerManager: rebalancerManager,
+	}
+}
+
+func (h *RebalancerHandler) CreatePortfolio(ctx *fasthttp.RequestCtx) {
+	var config domain.PortfolioConfig
+	if err := h.bindJSON(ctx, &config); err != nil {
+		h.sendError(ctx, 400, "Invalid JSON")
+		return
+	}
+
+	if config.QuoteAsset == "" {
+		config.QuoteAsset = "USDT"
+	}
+
+	if len(config.Allocations) < 2 {
+		h.sendError(ctx, 400, "At least two allocations are required")
+		return
+	}
+
+	total := 0.0
+	for _, percent := range config.Allocations {
+		if percent <= 0 {
+			h.sendError(ctx, 400, "Allocation percents must be positive")
+			return
+		}
+		total += percent
+	}
+	if math.Abs(total-100) > 0.01 {
+		h.sendError(ctx, 400, "Allocations must sum to 100")
+		return
+	}
+
+	if config.DriftThresholdPercent <= 0 {
+		h.sendError(ctx, 400, "Drift threshold percent must be positive")
+		return
+	}
+
+	if config.IntervalMinutes <= 0 {
+		config.IntervalMinutes = domain.DefaultRebalanceIntervalMinutes
+	}
+
+	portfolio, err := h.rebalancerManager.CreatePortfolio(ctx, config)
+	if err != nil {
+		var appErr *apperrors.AppError
+		if errors.As(err, &appErr) {
+			h.sendResponse(ctx, appErr.GetHTTPStatus(), appErr)
+			return
+		}
+		h.sendError(ctx, 500, "Failed to create portfolio")
+		return
+	}
+
+	h.sendResponse(ctx, 201, portfolio)
+}
+
+func (h *RebalancerHandler) GetAllPortfolios(ctx *fasthttp.RequestCtx) {
+	portfolios := h.rebalancerManager.GetAllPortfolios()
+
+	h.sendResponse(ctx, 200, map[string]interface{}{
+		"portfolios": portfolios,
+		"count":      len(portfolios),
+	})
+}
+
+func (h *RebalancerHandler) GetPortfolio(ctx *fasthttp.RequestCtx) {
+	portfolioID, err := uuid.Parse(h.getParam(ctx, "portfolioId"))
+	if err != nil {
+		h.sendError(ctx, 400, "Invalid portfolio ID format")
+		return
+	}
+
+	portfolio, err := h.rebalancerManager.GetPortfolio(portfolioID)
+	if err != nil {
+		h.sendError(ctx, 404, "Portfolio not found")
+		return
+	}
+
+	h.sendResponse(ctx, 200, portfolio)
+}
+
+func (h *RebalancerHandler) RebalancePortfolio(ctx *fasthttp.RequestCtx) {
+	portfolioID, err := uuid.Parse(h.getParam(ctx, "portfolioId"))
+	if err != nil {
+		h.sendError(ctx, 400, "Invalid portfolio ID format")
+		return
+	}
+
+	portfolio, err := h.rebalancerManager.Rebalance(ctx, portfolioID)
+	if err != nil {
+		h.sendError(ctx, 500, "Failed to rebalance portfolio")
+		return
+	}
+
+	h.sendResponse(ctx, 200, portfolio)
+}
+
+func (h *RebalancerHandler) StopPortfolio(ctx *fasthttp.RequestCtx) {
+	portfolioID, err := uuid.Parse(h.getParam(ctx, "portfolioId"))
+	if err != nil {
+		h.sendError(ctx, 400, "Invalid portfolio ID format")
+		return
+	}
+
+	if ctx.QueryArgs().GetBool("dry_run") {
+		plan, err := h.rebalancerManager.PlanStop(portfolioID)
+		if err != nil {
+			h.sendError(ctx, 404, "Portfolio not found")
+			return
+		}
+		h.sendResponse(ctx, 200, plan)
+		return
+	}
+
+	if err := h.rebalancerManager.StopPortfolio(ctx, portfolioID); err != nil {
+		h.sendError(ctx, 404, "Portfolio not found")
+		return
+	}
+
+	h.sendMessage(ctx, "Portfolio stopped successfully")
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"cryptorg/internal/domain"
+	"cryptorg/internal/service"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	defaultReportDays = 7
+	maxReportDays     = 366
+)
+
+type ReportHandler struct {
+	reportManager *service.ReportService
+}
+
+func (h *ReportHandler) sendResponse(ctx *fasthttp.RequestCtx, status int, data interface{}) {
+	ctx.Response.Header.Set("Content-Type", "application/json")
+	ctx.Response.SetStatusCode(status)
+
+	if data != nil {
+		json.NewEncoder(ctx).Encode(data)
+	}
+}
+
+func (h *ReportHandler) sendError(ctx *fasthttp.RequestCtx, status int, message string) {
+	ctx.Response.Header.Set("Content-Type", "application/json")
+	ctx.Response.SetStatusCode(status)
+	ctx.Response.SetBodyString(`{"error": "` + localize(ctx, message) + `"}`)
+}
+
+func NewReportController(reportManager *service.ReportService) *ReportHandler {
+	return &ReportHandler{
+		reportManager: reportManager,
+	}
+}
+
+// GetSummary принимает from/to (YYYY-MM-DD в часовом поясе отчетов) либо days - число последних дней.
+func (h *ReportHandler) GetSummary(ctx *fasthttp.RequestCtx) {
+	from, to, ok := h.reportPeriod(ctx)
+	if !ok {
+		return
+	}
+
+	h.sendResponse(ctx, 200, h.reportManager.Summary(from, to))
+}
+
+// GetPnLStats принимает period=day|week и границы как GetSummary.
+func (h *ReportHandler) GetPnLStats(ctx *fasthttp.RequestCtx) {
+	period := string(ctx.QueryArgs().Peek("period"))
+	if period == "" {
+		period = domain.PnLPeriodDay
+	}
+	if period != domain.PnLPeriodDay && period != domain.PnLPeriodWeek {
+		h.sendError(ctx, 400, "Period must be day or week")
+		return
+	}
+
+	from, to, ok := h.reportPeriod(ctx)
+	if !ok {
+		return
+	}
+
+	stats, err := h.reportManager.PnLStats(ctx, period, from, to)
+	if err != nil {
+		h.sendError(ctx, 502, "Failed to calculate PnL")
+		return
+	}
+
+	h.sendResponse(ctx, 200, stats)
+}
+
+// reportPeriod разбирает from/to/days; при ошибке ответ уже отправлен.
+func (h *ReportHandler) reportPeriod(ctx *fasthttp.RequestCtx) (time.Time, time.Time, bool) {
+	args := ctx.QueryArgs()
+	location := h.reportManager.Location()
+
+	to := time.Now().In(location)
+	if value := string(args.Peek("to")); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, location)
+		if err != nil {
+			h.sendError(ctx, 400, "Invalid to date, expected YYYY-MM-DD")
+			return time.Time{}, time.Time{}, false
+		}
+		to = parsed
+	}
+
+	days := defaultReportDays
+	if value := string(args.Peek("days")); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			h.sendError(ctx, 400, "Days must be a positive integer")
+			return time.Time{}, time.Time{}, false
+		}
+		days = parsed
+	}
+	from := to.AddDate(0, 0, -(days - 1))
+
+	if value := string(args.Peek("from")); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, location)
+		if err != nil {
+			h.sendError(ctx, 400, "Invalid from date, expected YYYY-MM-DD")
+			return time.Time{}, time.Time{}, false
+		}
+		from = parsed
+	}
+
+	if from.After(to) || to.Sub(from) > maxReportDays*24*time.Hour {
+		h.sendError(ctx, 400, "Report period must be between 1 and 366 days")
+		return time.Time{}, time.Time{}, false
+	}
+
+	return from, to, true
+}
+
+// GetTimeHeatmap принимает необязательный days - учитывать только сделки, открытые за последние N дней.
+func (h *ReportHandler) GetTimeHeatmap(ctx *fasthttp.RequestCtx) {
+	var since time.Time
+	if value := string(ctx.QueryArgs().Peek("days")); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil || days <= 0 {
+			h.sendError(ctx, 400, "Days must be a positive integer")
+			return
+		}
+		since = time.Now().AddDate(0, 0, -days)
+	}
+
+	h.sendResponse(ctx, 200, h.reportManager.TimeHeatmap(since))
+}
+
+// GetPublicStats отдает обезличенную статистику для публичной страницы.
+func (h *ReportHandler) GetPublicStats(ctx *fasthttp.RequestCtx) {
+	h.sendResponse(ctx, 200, h.reportManager.PublicStats())
+}
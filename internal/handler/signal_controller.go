@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"cryptorg/internal/domain"
+	"cryptorg/internal/service"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/valyala/fasthttp"
+)
+
+type SignalHandler struct {
+	tradeManager *service.TradeService
+	signalGate   *service.SignalGate
+	defaults     domain.TradeConfig
+}
+
+func (h *SignalHandler) bindJSON(ctx *fasthttp.RequestCtx, v interface{}) error {
+	return json.Unmarshal(ctx.PostBody(), v)
+}
+
+func (h *SignalHandler) sendResponse(ctx *fasthttp.RequestCtx, status int, data interface{}) {
+	ctx.Response.Header.Set("Content-Type", "application/json")
+	ctx.Response.SetStatusCode(status)
+
+	if data != nil {
+		json.NewEncoder(ctx).Encode(data)
+	}
+}
+
+func (h *SignalHandler) sendError(ctx *fasthttp.RequestCtx, status int, message string) {
+	ctx.Response.Header.Set("Content-Type", "application/json")
+	ctx.Response.SetStatusCode(status)
+	ctx.Response.SetBodyString(`{"error": "` + localize(ctx, message) + `"}`)
+}
+
+func NewSignalController(tradeManager *service.TradeService, signalGate *service.SignalGate, defaults domain.TradeConfig) *SignalHandler {
+	return &SignalHandler{
+		tradeManager: tradeManager,
+		signalGate:   signalGate,
+		defaults:     defaults,
+	}
+}
+
+// ReceiveSignal открывает сделку по внешнему алерту. Тело - TradeConfig поверх
+// значений по умолчанию, поэтому достаточно {"symbol": "SOLUSDT"}.
+func (h *SignalHandler) ReceiveSignal(ctx *fasthttp.RequestCtx) {
+	config := h.defaults
+	if err := h.bindJSON(ctx, &config); err != nil {
+		h.sendError(ctx, 400, "Invalid JSON")
+		return
+	}
+
+	if message := validateTradeConfig(&config); message != "" {
+		h.sendError(ctx, 400, message)
+		return
+	}
+
+	sum := sha256.Sum256(ctx.PostBody())
+	key := hex.EncodeToString(sum[:])
+
+	duplicateOf, retryAfter, err := h.signalGate.Admit(config.Symbol, key)
+	if errors.Is(err, service.ErrSignalCooldown) {
+		ctx.Response.Header.Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		h.sendError(ctx, 429, "Symbol is in cooldown")
+		return
+	}
+	if errors.Is(err, service.ErrSignalInFlight) {
+		h.sendResponse(ctx, 202, map[string]interface{}{"duplicate": true})
+		return
+	}
+	if duplicateOf != nil {
+		h.sendResponse(ctx, 200, map[string]interface{}{"duplicate": true, "trade_id": duplicateOf})
+		return
+	}
+
+	trade, err := h.tradeManager.InitializeTrade(ctx, config)
+	if err != nil {
+		h.signalGate.Release(config.Symbol, key)
+		h.sendError(ctx, 500, "Failed to initialize trade")
+		return
+	}
+
+	h.signalGate.Complete(key, trade.ID)
+	h.sendResponse(ctx, 201, trade)
+}
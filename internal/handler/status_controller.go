@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"cryptorg/internal/notify"
+	"cryptorg/internal/okx"
+	"cryptorg/internal/service"
+	"cryptorg/pkg/config"
+	"cryptorg/pkg/version"
+	"encoding/json"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+type StatusHandler struct {
+	config         *config.Config
+	exchangeClient service.ExchangeClient
+	tradeManager   *service.TradeService
+	notifications  *notify.Queue
+	fillPool       *service.FillPool
+	orderStream    *okx.OrderStream // nil - исполнения приходят только вебхуком
+	startedAt      time.Time
+}
+
+func (h *StatusHandler) sendResponse(ctx *fasthttp.RequestCtx, status int, data interface{}) {
+	ctx.Response.Header.Set("Content-Type", "application/json")
+	ctx.Response.SetStatusCode(status)
+
+	if data != nil {
+		json.NewEncoder(ctx).Encode(data)
+	}
+}
+
+func NewStatusController(cfg *config.Config, exchangeClient service.ExchangeClient, tradeManager *service.TradeService, notifications *notify.Queue, fillPool *service.FillPool, orderStream *okx.OrderStream) *StatusHandler {
+	return &StatusHandler{
+		config:         cfg,
+		exchangeClient: exchangeClient,
+		tradeManager:   tradeManager,
+		notifications:  notifications,
+		fillPool:       fillPool,
+		orderStream:    orderStream,
+		startedAt:      time.Now(),
+	}
+}
+
+func (h *StatusHandler) GetStatus(ctx *fasthttp.RequestCtx) {
+	exchangeLatency := h.exchangeClient.Latency()
+
+	var lastFill interface{}
+	if at := h.tradeManager.LastFillAt(); !at.IsZero() {
+		lastFill = at
+	}
+
+	driver, pingErr := h.tradeManager.StorageStatus()
+	storage := map[string]interface{}{
+		"driver":  driver,
+		"healthy": pingErr == nil,
+	}
+	if pingErr != nil {
+		storage["error"] = pingErr.Error()
+	}
+
+	h.sendResponse(ctx, 200, map[string]interface{}{
+		"service":        h.config.Base.ServiceID,
+		"version":        h.config.Base.Version,
+		"commit":         version.Commit,
+		"environment":    h.config.Base.Environment,
+		"started_at":     h.startedAt,
+		"uptime_seconds": int64(time.Since(h.startedAt).Seconds()),
+		"exchange": map[string]interface{}{
+			"name":            h.config.Exchange.Name,
+			"testnet":         h.config.Bybit.Testnet,
+			"latency_p50_ms":  exchangeLatency.Percentile(50).Milliseconds(),
+			"latency_p99_ms":  exchangeLatency.Percentile(99).Milliseconds(),
+			"samples":         exchangeLatency.Count(),
+			"clock_offset_ms": h.exchangeClient.Clock().Offset().Milliseconds(),
+		},
+		"websocket": h.websocketStatus(),
+		"storage":   storage,
+		"queues": map[string]interface{}{
+			"notifications": h.notifications.Len(),
+			"fills":         h.fillPool.QueueDepth(),
+		},
+		"trades": map[string]interface{}{
+			"active":                 h.tradeManager.CountActiveTrades(),
+			"last_fill_processed_at": lastFill,
+		},
+		"budgets": h.tradeManager.BudgetUsage(),
+	})
+}
+
+// websocketStatus - состояние приватного потока ордеров OKX и возраст соединения.
+func (h *StatusHandler) websocketStatus() map[string]interface{} {
+	if h.orderStream == nil {
+		return map[string]interface{}{"status": "disabled"}
+	}
+
+	connectedAt, ok := h.orderStream.ConnectedAt()
+	if !ok {
+		return map[string]interface{}{"status": "disconnected"}
+	}
+	return map[string]interface{}{
+		"status":            "connected",
+		"connected_at":      connectedAt,
+		"connected_seconds": int64(time.Since(connectedAt).Seconds()),
+	}
+}
+
+func (h *StatusHandler) GetVersion(ctx *fasthttp.RequestCtx) {
+	info := version.Info()
+	info["service"] = h.config.Base.ServiceID
+	h.sendResponse(ctx, 200, info)
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"cryptorg/internal/domain"
+	"cryptorg/internal/notify"
+	"cryptorg/internal/service"
+	"cryptorg/pkg/config"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/valyala/fasthttp"
+)
+
+// TelegramHandler принимает вебхук бота: нажатия кнопок подтверждения сделок.
+type TelegramHandler struct {
+	tradeManager *service.TradeService
+	telegram     *notify.TelegramNotifier
+	chatID       string
+	secret       string
+}
+
+type telegramUpdate struct {
+	CallbackQuery *struct {
+		ID   string `json:"id"`
+		Data string `json:"data"`
+		From struct {
+			ID int64 `json:"id"`
+		} `json:"from"`
+		Message *struct {
+			Chat struct {
+				ID int64 `json:"id"`
+			} `json:"chat"`
+		} `json:"message"`
+	} `json:"callback_query"`
+}
+
+func (h *TelegramHandler) bindJSON(ctx *fasthttp.RequestCtx, v interface{}) error {
+	return json.Unmarshal(ctx.PostBody(), v)
+}
+
+func (h *TelegramHandler) sendResponse(ctx *fasthttp.RequestCtx, status int, data interface{}) {
+	ctx.Response.Header.Set("Content-Type", "application/json")
+	ctx.Response.SetStatusCode(status)
+
+	if data != nil {
+		json.NewEncoder(ctx).Encode(data)
+	}
+}
+
+func (h *TelegramHandler) sendError(ctx *fasthttp.RequestCtx, status int, message string) {
+	ctx.Response.Header.Set("Content-Type", "application/json")
+	ctx.Response.SetStatusCode(status)
+	ctx.Response.SetBodyString(`{"error": "` + localize(ctx, message) + `"}`)
+}
+
+func NewTelegramController(cfg *config.Config, tradeManager *service.TradeService) *TelegramHandler {
+	handler := &TelegramHandler{
+		tradeManager: tradeManager,
+		chatID:       cfg.Notify.TelegramChatID,
+		secret:       cfg.Notify.WebhookSecret,
+	}
+	if cfg.Notify.TelegramToken != "" && cfg.Notify.TelegramChatID != "" {
+		handler.telegram = notify.NewTelegramNotifier(cfg.Notify.TelegramToken, cfg.Notify.TelegramChatID)
+	}
+	return handler
+}
+
+// Webhook обрабатывает нажатие кнопки подтверждения. Запрос принимается только с
+// TELEGRAM_WEBHOOK_SECRET в X-Telegram-Bot-Api-Secret-Token и из чата уведомлений.
+// Telegram получает 200 на любое обновление, результат показывается ответом на нажатие.
+func (h *TelegramHandler) Webhook(ctx *fasthttp.RequestCtx) {
+	secret := ctx.Request.Header.Peek("X-Telegram-Bot-Api-Secret-Token")
+	if h.telegram == nil || h.secret == "" || subtle.ConstantTimeCompare(secret, []byte(h.secret)) != 1 {
+		h.sendError(ctx, 403, "Invalid webhook secret")
+		return
+	}
+
+	var update telegramUpdate
+	if err := h.bindJSON(ctx, &update); err != nil {
+		h.sendError(ctx, 400, "Invalid JSON")
+		return
+	}
+
+	callback := update.CallbackQuery
+	if callback == nil || !strings.HasPrefix(callback.Data, domain.ApprovalCallbackPrefix) {
+		h.sendResponse(ctx, 200, map[string]string{"status": "ignored"})
+		return
+	}
+
+	answer := h.approve(ctx, callback.Data, callback.From.ID, callback.Message != nil && strconv.FormatInt(callback.Message.Chat.ID, 10) == h.chatID)
+	if err := h.telegram.AnswerCallback(ctx, callback.ID, answer); err != nil {
+		log.Printf("Failed to answer telegram callback: %v", err)
+	}
+	h.sendResponse(ctx, 200, map[string]string{"status": "ok"})
+}
+
+func (h *TelegramHandler) approve(ctx *fasthttp.RequestCtx, data string, userID int64, fromChat bool) string {
+	if !fromChat {
+		return localize(ctx, "Trade approval is not allowed from this chat")
+	}
+
+	tradeID, err := uuid.Parse(strings.TrimPrefix(data, domain.ApprovalCallbackPrefix))
+	if err != nil {
+		return localize(ctx, "Invalid trade ID format")
+	}
+
+	approvedBy := fmt.Sprintf("telegram:%d", userID)
+	if _, err := h.tradeManager.ApproveTrade(ctx, tradeID, approvedBy); err != nil {
+		return err.Error()
+	}
+
+	log.Printf("AUDIT: trade %s approved by %s", tradeID, approvedBy)
+	return localize(ctx, "Trade approved")
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"cryptorg/internal/domain"
+	"cryptorg/internal/service"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+type ToolsHandler struct {
+	riskManager     *service.RiskService
+	backtestManager *service.BacktestService
+}
+
+func (h *ToolsHandler) sendResponse(ctx *fasthttp.RequestCtx, status int, data interface{}) {
+	ctx.Response.Header.Set("Content-Type", "application/json")
+	ctx.Response.SetStatusCode(status)
+
+	if data != nil {
+		json.NewEncoder(ctx).Encode(data)
+	}
+}
+
+func (h *ToolsHandler) sendError(ctx *fasthttp.RequestCtx, status int, message string) {
+	ctx.Response.Header.Set("Content-Type", "application/json")
+	ctx.Response.SetStatusCode(status)
+	ctx.Response.SetBodyString(`{"error": "` + localize(ctx, message) + `"}`)
+}
+
+func NewToolsController(riskManager *service.RiskService, backtestManager *service.BacktestService) *ToolsHandler {
+	return &ToolsHandler{
+		riskManager:     riskManager,
+		backtestManager: backtestManager,
+	}
+}
+
+func (h *ToolsHandler) SuggestConfig(ctx *fasthttp.RequestCtx) {
+	args := ctx.QueryArgs()
+	symbol := string(args.Peek("symbol"))
+	if symbol == "" {
+		h.sendError(ctx, 400, "Symbol is required")
+		return
+	}
+
+	budget, err := strconv.ParseFloat(string(args.Peek("budget")), 64)
+	if err != nil || budget <= 0 {
+		h.sendError(ctx, 400, "Budget must be a positive number")
+		return
+	}
+
+	suggestion, err := h.riskManager.SuggestConfig(ctx, symbol, budget)
+	if err != nil {
+		h.sendError(ctx, 422, err.Error())
+		return
+	}
+
+	h.sendResponse(ctx, 200, suggestion)
+}
+
+// CompareBacktest прогоняет конфиги a и b по одним и тем же свечам symbol за период
+// from..to (YYYY-MM-DD, UTC, to включительно) и возвращает отчеты рядом.
+func (h *ToolsHandler) CompareBacktest(ctx *fasthttp.RequestCtx) {
+	var req domain.BacktestCompareRequest
+	if err := json.Unmarshal(ctx.PostBody(), &req); err != nil {
+		h.sendError(ctx, 400, "Invalid request body")
+		return
+	}
+
+	if req.Symbol == "" {
+		req.Symbol = req.A.Symbol
+	}
+	if req.Interval == "" {
+		req.Interval = service.DefaultBacktestInterval
+	}
+
+	for _, config := range []*domain.TradeConfig{&req.A, &req.B} {
+		config.Symbol = req.Symbol
+		if msg := validateTradeConfig(config); msg != "" {
+			h.sendError(ctx, 400, msg)
+			return
+		}
+		if config.Strategy != domain.StrategyPriceStep {
+			h.sendError(ctx, 400, "Backtest supports price_step strategy only")
+			return
+		}
+		if config.IsShort() {
+			h.sendError(ctx, 400, "Backtest supports long trades only")
+			return
+		}
+	}
+
+	from, err := time.Parse("2006-01-02", req.From)
+	if err != nil {
+		h.sendError(ctx, 400, "Invalid from date, expected YYYY-MM-DD")
+		return
+	}
+	to, err := time.Parse("2006-01-02", req.To)
+	if err != nil {
+		h.sendError(ctx, 400, "Invalid to date, expected YYYY-MM-DD")
+		return
+	}
+	to = to.AddDate(0, 0, 1)
+
+	if err := service.ValidBacktestRange(req.Interval, from, to); err != nil {
+		h.sendError(ctx, 400, err.Error())
+		return
+	}
+
+	comparison, err := h.backtestManager.Compare(ctx, req, from, to)
+	if err != nil {
+		h.sendError(ctx, 422, err.Error())
+		return
+	}
+
+	h.sendResponse(ctx, 200, comparison)
+}
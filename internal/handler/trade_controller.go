@@ -1,16 +1,37 @@
 package handler
 
 import (
+	"context"
 	"cryptorg/internal/domain"
+	"cryptorg/internal/indicator"
 	"cryptorg/internal/service"
+	apperrors "cryptorg/pkg/errors"
 	"encoding/json"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/valyala/fasthttp"
 )
 
+const (
+	maxAnnotationKeyLength   = 64
+	maxAnnotationValueLength = 1024
+	defaultEventsLimit       = 100
+	maxEventsLimit           = 1000
+	attentionCheckTimeout    = 30 * time.Second
+)
+
 type TradeHandler struct {
-	tradeManager *service.TradeService
+	tradeManager  *service.TradeService
+	fillPool      *service.FillPool
+	defaults      domain.TradeConfig
+	approvals     bool                  // Есть API ключ с ролью admin; без него подтверждение через API выключено
+	webhookSecret string                // ORDER_WEBHOOK_SECRET для подписи вебхука ордеров
+	capture       domain.MessageCapture // Сырые тела вебхука ордеров; nil - не сохраняются
 }
 
 func (h *TradeHandler) bindJSON(ctx *fasthttp.RequestCtx, v interface{}) error {
@@ -33,47 +54,282 @@ func (h *TradeHandler) sendResponse(ctx *fasthttp.RequestCtx, status int, data i
 func (h *TradeHandler) sendError(ctx *fasthttp.RequestCtx, status int, message string) {
 	ctx.Response.Header.Set("Content-Type", "application/json")
 	ctx.Response.SetStatusCode(status)
-	ctx.Response.SetBodyString(`{"error": "` + message + `"}`)
+	ctx.Response.SetBodyString(`{"error": "` + localize(ctx, message) + `"}`)
+}
+
+// precision возвращает режим ?precision=display|raw; по умолчанию display.
+func (h *TradeHandler) precision(ctx *fasthttp.RequestCtx) (string, bool) {
+	mode := string(ctx.QueryArgs().Peek("precision"))
+	switch mode {
+	case "":
+		return service.PrecisionDisplay, true
+	case service.PrecisionDisplay, service.PrecisionRaw:
+		return mode, true
+	}
+	return "", false
 }
 
 func (h *TradeHandler) sendMessage(ctx *fasthttp.RequestCtx, message string) {
-	h.sendResponse(ctx, 200, map[string]string{"message": message})
+	h.sendResponse(ctx, 200, map[string]string{"message": localize(ctx, message)})
 }
 
-func NewTradeController(tradeManager *service.TradeService) *TradeHandler {
+func NewTradeController(tradeManager *service.TradeService, fillPool *service.FillPool, defaults domain.TradeConfig, approvals bool, webhookSecret string, capture domain.MessageCapture) *TradeHandler {
 	return &TradeHandler{
-		tradeManager: tradeManager,
+		tradeManager:  tradeManager,
+		fillPool:      fillPool,
+		defaults:      defaults,
+		approvals:     approvals,
+		webhookSecret: webhookSecret,
+		capture:       capture,
 	}
 }
 
+// bindConfig накладывает тело запроса на глобальные значения по умолчанию:
+// поля, отсутствующие в JSON, остаются из defaults.
+func (h *TradeHandler) bindConfig(ctx *fasthttp.RequestCtx, config *domain.TradeConfig) error {
+	*config = h.defaults
+	return h.bindJSON(ctx, config)
+}
+
 func (h *TradeHandler) InitializeTrade(ctx *fasthttp.RequestCtx) {
 	var config domain.TradeConfig
-	if err := h.bindJSON(ctx, &config); err != nil {
+	if err := h.bindConfig(ctx, &config); err != nil {
 		h.sendError(ctx, 400, "Invalid JSON")
 		return
 	}
 
-	if config.Symbol == "" || config.EntryVolume == "" || config.DCAVolume == "" {
-		h.sendError(ctx, 400, "Symbol, entry volume and DCA volume are required")
+	if message := validateTradeConfig(&config); message != "" {
+		h.sendError(ctx, 400, message)
 		return
 	}
 
-	if config.DCACount <= 0 || config.DCAStepPercent <= 0 || config.TakeProfitPercent <= 0 {
-		h.sendError(ctx, 400, "DCA count, step percent and take profit percent must be positive")
+	trade, err := h.tradeManager.InitializeTrade(ctx, config)
+	if err != nil {
+		var appErr *apperrors.AppError
+		if errors.As(err, &appErr) {
+			h.sendResponse(ctx, appErr.GetHTTPStatus(), appErr)
+			return
+		}
+		h.sendError(ctx, 500, "Failed to initialize trade")
 		return
 	}
 
-	if config.Martingale <= 0 {
-		config.Martingale = domain.DefaultMartingale
+	if mode, ok := h.precision(ctx); ok && mode == service.PrecisionDisplay {
+		trade = h.tradeManager.DisplayTrade(ctx, trade)
 	}
 
-	trade, err := h.tradeManager.InitializeTrade(ctx, config)
+	h.sendResponse(ctx, 201, trade)
+}
+
+func (h *TradeHandler) PreviewTrade(ctx *fasthttp.RequestCtx) {
+	var config domain.TradeConfig
+	if err := h.bindConfig(ctx, &config); err != nil {
+		h.sendError(ctx, 400, "Invalid JSON")
+		return
+	}
+
+	if message := validateTradeConfig(&config); message != "" {
+		h.sendError(ctx, 400, message)
+		return
+	}
+
+	preview, err := h.tradeManager.PreviewTrade(ctx, config)
 	if err != nil {
-		h.sendError(ctx, 500, "Failed to initialize trade")
+		var appErr *apperrors.AppError
+		if errors.As(err, &appErr) {
+			h.sendResponse(ctx, appErr.GetHTTPStatus(), appErr)
+			return
+		}
+		h.sendError(ctx, 500, "Failed to preview trade")
 		return
 	}
 
-	h.sendResponse(ctx, 201, trade)
+	h.sendResponse(ctx, 200, preview)
+}
+
+// validateTradeConfig проставляет значения по умолчанию и возвращает текст ошибки валидации.
+func validateTradeConfig(config *domain.TradeConfig) string {
+	if config.Symbol == "" || config.EntryVolume == "" || config.DCAVolume == "" {
+		return "Symbol, entry volume and DCA volume are required"
+	}
+
+	if config.Strategy == "" {
+		config.Strategy = domain.DefaultStrategy
+	} else if !config.Strategy.IsValid() {
+		return "Strategy must be one of price_step, time_based"
+	}
+
+	if config.Strategy == domain.StrategyTimeBased {
+		if config.BuyIntervalHours <= 0 || config.TakeProfitPercent <= 0 {
+			return "Buy interval and take profit percent must be positive"
+		}
+		if config.MaxBudget == "" && config.TargetPositionQty == "" {
+			return "Max budget or target position is required for time based strategy"
+		}
+	} else if config.DCACount <= 0 || config.DCAStepPercent <= 0 || config.TakeProfitPercent <= 0 {
+		return "DCA count, step percent and take profit percent must be positive"
+	}
+
+	if config.Martingale <= 0 {
+		config.Martingale = domain.DefaultMartingale
+	}
+
+	if config.Side == "" {
+		config.Side = domain.DefaultTradeSide
+	} else if !config.Side.IsValid() {
+		return "Side must be one of BUY, SELL"
+	}
+	if config.IsShort() && config.Strategy == domain.StrategyTimeBased {
+		return "Short trades are not supported for time based strategy"
+	}
+	if config.IsShort() && config.ExitAssistant != nil {
+		return "Exit assistant is not supported for short trades"
+	}
+
+	if config.StopLossPercent < 0 || config.StopLossPercent >= 100 {
+		return "Stop loss percent must be between 0 and 100"
+	}
+
+	if config.MakerOnly && config.StopLossPercent > 0 {
+		return "Stop loss is not supported in maker only mode"
+	}
+
+	if config.GridRefreshPercent < 0 || (config.GridRefreshPercent > 0 && config.GridRefreshPercent <= config.DCAStepPercent) {
+		return "Grid refresh percent must be greater than DCA step percent"
+	}
+
+	if config.PartialFillPolicy == "" {
+		config.PartialFillPolicy = domain.DefaultPartialFill
+	} else if !config.PartialFillPolicy.IsValid() {
+		return "Partial fill policy must be one of resubmit, filled_only, abort"
+	}
+
+	if config.MaxCycles < 0 {
+		return "Max cycles must not be negative"
+	}
+
+	if config.TPMaxDeviationPercent < 0 {
+		return "TP max deviation percent must not be negative"
+	}
+
+	if config.MaxSlippagePercent < 0 {
+		return "Max slippage percent must not be negative"
+	}
+
+	if config.TPFallback != "" {
+		if !config.TPFallback.IsValid() {
+			return "TP fallback must be one of ioc, market"
+		}
+		if config.MakerOnly {
+			return "TP fallback is not supported in maker only mode"
+		}
+		if config.TPFallbackSeconds < 0 {
+			return "TP fallback seconds must not be negative"
+		}
+		if config.TPFallbackSeconds == 0 {
+			config.TPFallbackSeconds = domain.DefaultTPFallbackSeconds
+		}
+	}
+
+	if config.MinNotionalPolicy == "" {
+		config.MinNotionalPolicy = domain.DefaultMinNotional
+	} else if !config.MinNotionalPolicy.IsValid() {
+		return "Min notional policy must be one of reject, bump"
+	}
+	if config.BudgetPolicy == "" {
+		config.BudgetPolicy = domain.DefaultBudgetPolicy
+	} else if !config.BudgetPolicy.IsValid() {
+		return "Budget policy must be one of reject, truncate, scale"
+	}
+
+	// Граница выставляется сервисом по данным биржи, а не клиентом
+	config.MinLevelVolume = ""
+
+	if config.StartPrice != "" {
+		if price, err := strconv.ParseFloat(config.StartPrice, 64); err != nil || price <= 0 {
+			return "Start price must be a positive number"
+		}
+		if config.StartDirection == "" {
+			config.StartDirection = domain.StartDirectionBelow
+		} else if !config.StartDirection.IsValid() {
+			return "Start direction must be one of below, above"
+		}
+	}
+
+	if msg := validateFees(config); msg != "" {
+		return msg
+	}
+
+	if msg := validatePriceBounds(config); msg != "" {
+		return msg
+	}
+
+	if msg := validateExitAssistant(config.ExitAssistant); msg != "" {
+		return msg
+	}
+
+	return ""
+}
+
+func validateFees(config *domain.TradeConfig) string {
+	for _, fee := range []*float64{config.MakerFeePercent, config.TakerFeePercent} {
+		if fee != nil && (*fee < 0 || *fee > domain.MaxFeePercent) {
+			return "Fee percent must be between 0 and 1"
+		}
+	}
+
+	return ""
+}
+
+func validatePriceBounds(config *domain.TradeConfig) string {
+	var minPrice, maxPrice float64
+	var err error
+
+	if config.MinPrice != "" {
+		if minPrice, err = strconv.ParseFloat(config.MinPrice, 64); err != nil || minPrice <= 0 {
+			return "Min price must be a positive number"
+		}
+	}
+	if config.MaxPrice != "" {
+		if maxPrice, err = strconv.ParseFloat(config.MaxPrice, 64); err != nil || maxPrice <= 0 {
+			return "Max price must be a positive number"
+		}
+	}
+	if minPrice > 0 && maxPrice > 0 && minPrice >= maxPrice {
+		return "Min price must be lower than max price"
+	}
+
+	return ""
+}
+
+func validateExitAssistant(assistant *domain.ExitAssistantConfig) string {
+	if assistant == nil {
+		return ""
+	}
+
+	if len(assistant.Intervals) == 0 {
+		assistant.Intervals = []string{"60", "240"}
+	}
+	if len(assistant.Patterns) == 0 {
+		return "Exit assistant requires at least one pattern"
+	}
+	for _, name := range assistant.Patterns {
+		if _, ok := indicator.Lookup(name); !ok {
+			return "Unknown exit pattern " + name + ", expected one of " + strings.Join(indicator.Names(), ", ")
+		}
+	}
+
+	if assistant.MinProfitPercent < 0 {
+		return "Exit assistant min profit percent must not be negative"
+	}
+
+	if assistant.Action == "" {
+		assistant.Action = domain.ExitActionTighten
+	} else if !assistant.Action.IsValid() {
+		return "Exit assistant action must be one of tighten, market"
+	}
+
+	return ""
 }
 
 func (h *TradeHandler) GetTrade(ctx *fasthttp.RequestCtx) {
@@ -89,21 +345,39 @@ func (h *TradeHandler) GetTrade(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
+	mode, ok := h.precision(ctx)
+	if !ok {
+		h.sendError(ctx, 400, "Precision must be one of display, raw")
+		return
+	}
+
 	trade, err := h.tradeManager.GetTrade(tradeID)
 	if err != nil {
 		h.sendError(ctx, 404, "Trade not found")
 		return
 	}
 
+	if mode == service.PrecisionDisplay {
+		trade = h.tradeManager.DisplayTrade(ctx, trade)
+	}
+
 	h.sendResponse(ctx, 200, trade)
 }
 
 func (h *TradeHandler) GetAllTrades(ctx *fasthttp.RequestCtx) {
-	tradesMap := h.tradeManager.GetAllTrades()
+	mode, ok := h.precision(ctx)
+	if !ok {
+		h.sendError(ctx, 400, "Precision must be one of display, raw")
+		return
+	}
 
-	var trades []*domain.Trade
-	for _, trade := range tradesMap {
-		trades = append(trades, trade)
+	trades := h.tradeManager.GetAllTrades()
+	if mode == service.PrecisionDisplay {
+		displayed := make([]*domain.Trade, 0, len(trades))
+		for _, trade := range trades {
+			displayed = append(displayed, h.tradeManager.DisplayTrade(ctx, trade))
+		}
+		trades = displayed
 	}
 
 	h.sendResponse(ctx, 200, map[string]interface{}{
@@ -127,6 +401,7 @@ func (h *TradeHandler) ProcessOrderExecution(ctx *fasthttp.RequestCtx) {
 
 	var req struct {
 		OrderID string `json:"order_id"`
+		ExecID  string `json:"exec_id"` // ID исполнения; повторный запрос с тем же ID не применяется
 	}
 
 	if err := h.bindJSON(ctx, &req); err != nil {
@@ -139,7 +414,7 @@ func (h *TradeHandler) ProcessOrderExecution(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
-	if err := h.tradeManager.ProcessOrderExecution(ctx, tradeID, req.OrderID); err != nil {
+	if err := h.tradeManager.ProcessOrderExecution(ctx, tradeID, req.OrderID, req.ExecID); err != nil {
 		h.sendError(ctx, 500, "Failed to process order execution")
 		return
 	}
@@ -160,6 +435,16 @@ func (h *TradeHandler) CloseTrade(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
+	if ctx.QueryArgs().GetBool("dry_run") {
+		plan, err := h.tradeManager.PlanClose(tradeID)
+		if err != nil {
+			h.sendError(ctx, 404, "Trade not found")
+			return
+		}
+		h.sendResponse(ctx, 200, plan)
+		return
+	}
+
 	var req struct {
 		Reason string `json:"reason"`
 	}
@@ -182,54 +467,308 @@ func (h *TradeHandler) CloseTrade(ctx *fasthttp.RequestCtx) {
 	h.sendMessage(ctx, "Trade closed successfully")
 }
 
-func (h *TradeHandler) WebhookOrderUpdate(ctx *fasthttp.RequestCtx) {
-	var webhookData struct {
-		EventType string `json:"e"` // Event type
-		Symbol    string `json:"s"` // Symbol
-		OrderID   string `json:"i"` // Order ID
-		Status    string `json:"X"` // Order status
-		Side      string `json:"S"` // Side
-		Type      string `json:"o"` // Order type
+// ApproveTrade подтверждает сделку в PENDING_APPROVAL. Роутер пускает сюда только ключ с ролью
+// admin; без такого ключа (в том числе без API_KEYS) подтверждение через API выключено.
+func (h *TradeHandler) ApproveTrade(ctx *fasthttp.RequestCtx) {
+	if !h.approvals {
+		h.sendError(ctx, 403, "Trade approval needs an admin API key")
+		return
 	}
 
-	if err := h.bindJSON(ctx, &webhookData); err != nil {
-		h.sendError(ctx, 400, "Invalid JSON")
+	tradeID, err := uuid.Parse(h.getParam(ctx, "tradeId"))
+	if err != nil {
+		h.sendError(ctx, 400, "Invalid trade ID format")
 		return
 	}
 
-	if webhookData.Status == string(domain.OrderStatusBybitFilled) {
-		trade, err := h.tradeManager.FindTradeByOrderID(webhookData.OrderID)
-		if err != nil {
-			h.sendMessage(ctx, "Order not found")
+	trade, err := h.tradeManager.ApproveTrade(ctx, tradeID, "api")
+	if err != nil {
+		var appErr *apperrors.AppError
+		if errors.As(err, &appErr) {
+			h.sendResponse(ctx, appErr.GetHTTPStatus(), appErr)
 			return
 		}
+		h.sendError(ctx, 500, err.Error())
+		return
+	}
 
-		orderType := h.determineOrderType(trade, webhookData.OrderID)
+	log.Printf("AUDIT: trade %s approved by %s", tradeID, ctx.RemoteIP())
+	h.sendResponse(ctx, 200, trade)
+}
 
-		if orderType == "entry" {
-		} else {
-			if err := h.tradeManager.ProcessOrderExecution(ctx, trade.ID, webhookData.OrderID); err != nil {
+// PauseTrade снимает TP и открытые DCA ордера сделки, оставляя позицию и стоп-лосс.
+func (h *TradeHandler) PauseTrade(ctx *fasthttp.RequestCtx) {
+	tradeID, err := uuid.Parse(h.getParam(ctx, "tradeId"))
+	if err != nil {
+		h.sendError(ctx, 400, "Invalid trade ID format")
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	// Тело необязательно: причина нужна только для журнала
+	if len(ctx.PostBody()) > 0 {
+		if err := h.bindJSON(ctx, &req); err != nil {
+			h.sendError(ctx, 400, "Invalid JSON")
+			return
+		}
+	}
+	reason := "Manual pause"
+	if req.Reason != "" {
+		reason = req.Reason
+	}
+
+	trade, err := h.tradeManager.PauseTrade(ctx, tradeID, reason)
+	if err != nil {
+		h.sendTradeError(ctx, err, "Failed to pause trade")
+		return
+	}
+	h.sendResponse(ctx, 200, trade)
+}
+
+// ResumeTrade заново выставляет снятую паузой сетку и TP.
+func (h *TradeHandler) ResumeTrade(ctx *fasthttp.RequestCtx) {
+	tradeID, err := uuid.Parse(h.getParam(ctx, "tradeId"))
+	if err != nil {
+		h.sendError(ctx, 400, "Invalid trade ID format")
+		return
+	}
+
+	trade, err := h.tradeManager.ResumeTrade(ctx, tradeID)
+	if err != nil {
+		h.sendTradeError(ctx, err, "Failed to resume trade")
+		return
+	}
+	h.sendResponse(ctx, 200, trade)
+}
+
+// sendTradeError отдает доменные ошибки с их HTTP статусом, прочие - как 500.
+func (h *TradeHandler) sendTradeError(ctx *fasthttp.RequestCtx, err error, message string) {
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) {
+		h.sendResponse(ctx, appErr.GetHTTPStatus(), appErr)
+		return
+	}
+	h.sendError(ctx, 500, message)
+}
+
+// BulkCreateTrades создает сделки по шаблону для списка символов или результатов скринера.
+func (h *TradeHandler) BulkCreateTrades(ctx *fasthttp.RequestCtx) {
+	req := domain.BulkTradeRequest{Preset: h.defaults}
+	if err := h.bindJSON(ctx, &req); err != nil {
+		h.sendError(ctx, 400, "Invalid JSON")
+		return
+	}
+
+	if len(req.Symbols) == 0 && req.Screener == nil {
+		h.sendError(ctx, 400, "Symbols or screener is required")
+		return
+	}
+
+	if ctx.QueryArgs().GetBool("dry_run") {
+		req.DryRun = true
+	}
+
+	// Символ шаблона подставляется для каждой сделки отдельно
+	req.Preset.Symbol = "BULK"
+	if message := validateTradeConfig(&req.Preset); message != "" {
+		h.sendError(ctx, 400, message)
+		return
+	}
+
+	result, err := h.tradeManager.BulkCreate(ctx, req)
+	if err != nil {
+		h.sendError(ctx, 422, err.Error())
+		return
+	}
+
+	status := 201
+	if req.DryRun {
+		status = 200
+	}
+	h.sendResponse(ctx, status, result)
+}
+
+func (h *TradeHandler) AddAnnotation(ctx *fasthttp.RequestCtx) {
+	tradeID, err := uuid.Parse(h.getParam(ctx, "tradeId"))
+	if err != nil {
+		h.sendError(ctx, 400, "Invalid trade ID format")
+		return
+	}
+
+	var annotation domain.TradeAnnotation
+	if err := h.bindJSON(ctx, &annotation); err != nil {
+		h.sendError(ctx, 400, "Invalid JSON")
+		return
+	}
+
+	if annotation.Key == "" {
+		h.sendError(ctx, 400, "Annotation key is required")
+		return
+	}
+	if len(annotation.Key) > maxAnnotationKeyLength || len(annotation.Value) > maxAnnotationValueLength {
+		h.sendError(ctx, 400, "Annotation key or value is too long")
+		return
+	}
+
+	trade, err := h.tradeManager.AnnotateTrade(tradeID, annotation)
+	if err != nil {
+		h.sendError(ctx, 404, "Trade not found")
+		return
+	}
+
+	h.sendResponse(ctx, 201, trade.Annotations)
+}
+
+func (h *TradeHandler) GetTradeEvents(ctx *fasthttp.RequestCtx) {
+	tradeID, err := uuid.Parse(h.getParam(ctx, "tradeId"))
+	if err != nil {
+		h.sendError(ctx, 400, "Invalid trade ID format")
+		return
+	}
+
+	if _, err := h.tradeManager.GetTrade(tradeID); err != nil {
+		h.sendError(ctx, 404, "Trade not found")
+		return
+	}
+
+	events, err := h.tradeManager.TradeEvents(tradeID)
+	if err != nil {
+		h.sendError(ctx, 500, "Failed to read trade events")
+		return
+	}
+
+	// type оставляет события одного типа, например order_cancel_failed
+	if eventType := domain.TradeEventType(ctx.QueryArgs().Peek("type")); eventType != "" {
+		filtered := make([]domain.TradeEvent, 0, len(events))
+		for _, event := range events {
+			if event.Type == eventType {
+				filtered = append(filtered, event)
 			}
 		}
+		events = filtered
 	}
 
-	h.sendMessage(ctx, "Webhook processed")
+	h.sendResponse(ctx, 200, events)
 }
 
-func (h *TradeHandler) determineOrderType(trade *domain.Trade, orderID string) string {
-	if trade.EntryOrder != nil && trade.EntryOrder.BybitID == orderID {
-		return "entry"
+// GetTradePnL отдает PnL сделки; активная сделка оценивается по последней цене.
+func (h *TradeHandler) GetTradePnL(ctx *fasthttp.RequestCtx) {
+	tradeID, err := uuid.Parse(h.getParam(ctx, "tradeId"))
+	if err != nil {
+		h.sendError(ctx, 400, "Invalid trade ID format")
+		return
 	}
 
-	if trade.TakeProfitOrder != nil && trade.TakeProfitOrder.BybitID == orderID {
-		return "take_profit"
+	if _, err := h.tradeManager.GetTrade(tradeID); err != nil {
+		h.sendError(ctx, 404, "Trade not found")
+		return
 	}
 
-	for _, dcaOrder := range trade.DCAOrders {
-		if dcaOrder.BybitID == orderID {
-			return "dca"
+	pnl, err := h.tradeManager.TradePnL(ctx, tradeID)
+	if err != nil {
+		h.sendError(ctx, 502, "Failed to calculate PnL")
+		return
+	}
+
+	h.sendResponse(ctx, 200, pnl)
+}
+
+// GetEvents отдает события всех сделок после since_seq страницами по limit.
+// snapshots=true добавляет снимки состояния сделок.
+func (h *TradeHandler) GetEvents(ctx *fasthttp.RequestCtx) {
+	args := ctx.QueryArgs()
+
+	var sinceSeq uint64
+	if value := string(args.Peek("since_seq")); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			h.sendError(ctx, 400, "since_seq must be a non-negative integer")
+			return
+		}
+		sinceSeq = parsed
+	}
+
+	limit := defaultEventsLimit
+	if value := string(args.Peek("limit")); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 || parsed > maxEventsLimit {
+			h.sendError(ctx, 400, "limit must be between 1 and 1000")
+			return
 		}
+		limit = parsed
+	}
+
+	page, err := h.tradeManager.EventsSince(sinceSeq, limit, args.GetBool("snapshots"))
+	if err != nil {
+		h.sendError(ctx, 500, "Failed to read events")
+		return
+	}
+
+	h.sendResponse(ctx, 200, page)
+}
+
+// WebhookOrderUpdate принимает обновления ордеров в формате Bybit v5 или Binance.
+// С ORDER_WEBHOOK_SECRET запрос без верной подписи X-Webhook-Signature отклоняется.
+func (h *TradeHandler) WebhookOrderUpdate(ctx *fasthttp.RequestCtx) {
+	if !h.verifyWebhookSignature(ctx) {
+		h.sendError(ctx, 401, "Invalid webhook signature")
+		return
+	}
+
+	// Тело сохраняется до разбора: по нему видно, присылала ли биржа пропущенное исполнение
+	if h.capture != nil {
+		if err := h.capture.Capture("webhook", ctx.PostBody()); err != nil {
+			log.Printf("Failed to capture order webhook: %v", err)
+		}
+	}
+
+	events, err := parseOrderWebhook(ctx.PostBody())
+	if err != nil {
+		h.sendError(ctx, 400, "Invalid JSON")
+		return
+	}
+
+	for _, event := range events {
+		if !h.applyOrderWebhook(ctx, event) {
+			return
+		}
+	}
+
+	h.sendMessage(ctx, "Webhook processed")
+}
+
+// applyOrderWebhook применяет одно обновление ордера; false - ответ уже отправлен.
+func (h *TradeHandler) applyOrderWebhook(ctx *fasthttp.RequestCtx, event orderWebhookEvent) bool {
+	err := h.fillPool.Dispatch(domain.OrderUpdate{
+		OrderID:       event.OrderID,
+		Symbol:        event.Symbol,
+		Status:        domain.ParseOrderStatus(event.Status),
+		ExecutedQty:   event.ExecutedQty,
+		ExecutedValue: event.ExecutedValue,
+		Fee:           event.Fee,
+		Price:         event.Price,
+		Timestamp:     event.Timestamp,
+	}, event.ExecID)
+	if err != nil {
+		h.sendError(ctx, 503, "Fill queue is full")
+		return false
+	}
+	return true
+}
+
+// FlagPanic помечает сделку, обработчик запроса которой упал с паникой. Сверка с биржей
+// идет в фоне, чтобы не задерживать ответ и не зависеть от контекста упавшего запроса.
+func (h *TradeHandler) FlagPanic(tradeIDStr, reason string) {
+	tradeID, err := uuid.Parse(tradeIDStr)
+	if err != nil {
+		return
 	}
 
-	return "unknown"
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), attentionCheckTimeout)
+		defer cancel()
+		h.tradeManager.FlagAttention(ctx, tradeID, reason)
+	}()
 }
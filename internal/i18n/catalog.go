@@ -0,0 +1,161 @@
+package i18n
+
+// catalogs - переводы по языкам. Ключ - английский текст сообщения или формат fmt,
+// перевод должен сохранять порядок и типы глаголов формата.
+var catalogs = map[Lang]map[string]string{
+	LangRU: ru,
+}
+
+var ru = map[string]string{
+	// Уведомления
+	"Low balance":                "Недостаточно средств",
+	"Take profit deferred":       "Перестановка тейк-профита отложена",
+	"Trade start failed":         "Сделка не открылась",
+	"Trading suspended":          "Торговля приостановлена",
+	"Take profit fallback":       "Принудительное закрытие по тейк-профиту",
+	"Daily report":               "Дневной отчет",
+	"Exit assistant":             "Помощник выхода",
+	"Trade retired":              "Сделка выведена из работы",
+	"Trade restart failed":       "Не удалось перезапустить сделку",
+	"Inconsistent trades":        "Расхождения в сделках",
+	"Trade requires attention":   "Сделка требует внимания",
+	"Exchange check found: %s":   "Проверка на бирже нашла: %s",
+	"Exchange check failed: %s":  "Проверка на бирже не удалась: %s",
+	"Trade %s on %s retired: %s": "Сделка %s по %s выведена из работы: %s",
+	"Trade approval required":    "Требуется подтверждение сделки",
+	"Bot profit target reached":  "Бот достиг цели по прибыли",
+	"Trade closing stalled":      "Сделка не закрылась до конца",
+	"Approve":                    "Подтвердить",
+
+	"Free %s balance %.2f does not cover next DCA levels (%.2f required). Underfunded trades: %v":                     "Свободный баланс %s %.2f не покрывает следующие уровни DCA (требуется %.2f). Сделки без покрытия: %v",
+	"Trade %s on %s closed as %s, but %d orders are still on the exchange; retrying cancels":                          "Сделка %s по %s закрыта как %s, но %d ордеров еще стоят на бирже; отмена повторяется",
+	"Take profit replacement for %s deferred: %s":                                                                     "Перестановка тейк-профита по %s отложена: %s",
+	"Trade %s on %s failed to open at start price %s: %v":                                                             "Сделка %s по %s не открылась по стартовой цене %s: %v",
+	"%s loss %.2f over 24h exceeds limit %.2f. Open trades are still managed; resume with POST /api/emergency/resume": "Убыток %s %.2f за 24 часа превышает лимит %.2f. Открытые сделки продолжают сопровождаться; возобновление - POST /api/emergency/resume",
+	"%s: price %.8f held past TP %.8f for %s, %s fallback filled %.8f of %.8f":                                        "%s: цена %.8f держалась за TP %.8f в течение %s, закрытие %s исполнило %.8f из %.8f",
+	"%s (%s): opened %d, completed %d, stopped %d, cancelled %d, realized profit %s":                                  "%s (%s): открыто %d, завершено %d, остановлено %d, отменено %d, реализованная прибыль %s",
+	"%s detected on %s at %.8f, action: %s":                                                                           "%s обнаружен на %s по цене %.8f, действие: %s",
+	"Trade %s on %s failed to start cycle %d: %v":                                                                     "Сделка %s по %s не смогла начать цикл %d: %v",
+	"Consistency check found %d issues, %d left unresolved. See /api/admin/consistency":                               "Сверка нашла расхождений: %d, не устранено: %d. Подробности: /api/admin/consistency",
+	"Trade %s on %s was interrupted: %s":                                                                              "Обработка сделки %s по %s прервана: %s",
+	"Trade %s on %s needs %s %s, above the approval threshold %s":                                                     "Сделке %s по %s нужно %s %s, это выше порога подтверждения %s",
+	"Bot %s on %s reached profit target %s %s with %s realized, open deals close at take profit":                      "Бот %s по %s достиг цели по прибыли %s %s (реализовано %s), открытые сделки закроются по тейк-профиту",
+
+	// Ответы API
+	"Order execution processed successfully":       "Исполнение ордера обработано",
+	"Trade closed successfully":                    "Сделка закрыта",
+	"Trade approved":                               "Сделка подтверждена",
+	"Failed to fetch account balance":              "Не удалось получить баланс счета",
+	"API key required":                             "Требуется API ключ",
+	"API key is read-only":                         "API ключ только для чтения",
+	"API key has no admin role":                    "У API ключа нет роли администратора",
+	"Trade approval needs an admin API key":        "Для подтверждения сделок через API нужен API ключ с ролью admin",
+	"Invalid webhook signature":                    "Неверная подпись вебхука",
+	"Message capture is disabled":                  "Сохранение сообщений биржи выключено",
+	"Invalid webhook secret":                       "Неверный секрет вебхука",
+	"Trade approval is not allowed from this chat": "Подтверждение сделок из этого чата запрещено",
+	"Order not found":                              "Ордер не найден",
+	"Webhook processed":                            "Вебхук обработан",
+	"Order terminated successfully":                "Ордер отменен",
+	"Portfolio stopped successfully":               "Портфель остановлен",
+	"Bot deleted successfully":                     "Бот удален",
+
+	"All fields are required":                                                 "Все поля обязательны",
+	"Allocation percents must be positive":                                    "Доли распределения должны быть положительными",
+	"Allocations must sum to 100":                                             "Сумма долей распределения должна быть 100",
+	"Annotation key is required":                                              "Требуется ключ аннотации",
+	"Annotation key or value is too long":                                     "Ключ или значение аннотации слишком длинные",
+	"At least two allocations are required":                                   "Требуется не меньше двух долей распределения",
+	"Auto restart is not supported for bots, the bot opens new cycles itself": "Автоперезапуск не поддерживается для ботов, бот сам открывает новые циклы",
+	"Bot not found":                                                           "Бот не найден",
+	"Failed to create bot":                                                    "Не удалось создать бота",
+	"Failed to update bot":                                                    "Не удалось обновить бота",
+	"Failed to delete bot":                                                    "Не удалось удалить бота",
+	"Invalid bot ID format":                                                   "Некорректный формат ID бота",
+	"Profit target must be a positive number":                                 "Цель по прибыли должна быть положительным числом",
+	"Max concurrent deals must not be negative":                               "Число одновременных сделок не может быть отрицательным",
+	"Backtest supports long trades only":                                      "Бэктест поддерживает только длинные сделки",
+	"Backtest supports price_step strategy only":                              "Бэктест поддерживает только стратегию price_step",
+	"Budget must be a positive number":                                        "Бюджет должен быть положительным числом",
+	"Budget policy must be one of reject, truncate, scale":                    "Политика бюджета должна быть одной из: reject, truncate, scale",
+	"Buy interval and take profit percent must be positive":                   "Интервал покупок и процент тейк-профита должны быть положительными",
+	"DCA count, step percent and take profit percent must be positive":        "Число DCA, шаг и процент тейк-профита должны быть положительными",
+	"Days must be a positive integer":                                         "Число дней должно быть положительным целым",
+	"Drift threshold percent must be positive":                                "Порог отклонения должен быть положительным",
+	"Exit assistant action must be one of tighten, market":                    "Действие помощника выхода должно быть одним из: tighten, market",
+	"Exit assistant is not supported for short trades":                        "Помощник выхода не поддерживается для коротких сделок",
+	"Exit assistant min profit percent must not be negative":                  "Минимальная прибыль помощника выхода не может быть отрицательной",
+	"Exit assistant requires at least one pattern":                            "Помощнику выхода нужен хотя бы один паттерн",
+	"Failed to amend order":                                                   "Не удалось изменить ордер",
+	"Failed to pause trade":                                                   "Не удалось приостановить сделку",
+	"Failed to resume trade":                                                  "Не удалось возобновить сделку",
+	"Failed to pause bot":                                                     "Не удалось приостановить бота",
+	"Failed to resume bot":                                                    "Не удалось возобновить бота",
+	"Failed to close trade":                                                   "Не удалось закрыть сделку",
+	"Failed to compute DCA price":                                             "Не удалось рассчитать цену DCA",
+	"Failed to compute take profit price":                                     "Не удалось рассчитать цену тейк-профита",
+	"Failed to create portfolio":                                              "Не удалось создать портфель",
+	"Failed to execute limit order":                                           "Не удалось выставить лимитный ордер",
+	"Failed to execute market order":                                          "Не удалось выставить рыночный ордер",
+	"Failed to fetch order status":                                            "Не удалось получить статус ордера",
+	"Failed to initialize trade":                                              "Не удалось создать сделку",
+	"Failed to preview trade":                                                 "Не удалось рассчитать сделку",
+	"Failed to process order execution":                                       "Не удалось обработать исполнение ордера",
+	"Failed to read events":                                                   "Не удалось прочитать события",
+	"Failed to read trade events":                                             "Не удалось прочитать события сделки",
+	"Failed to rebalance portfolio":                                           "Не удалось ребалансировать портфель",
+	"Failed to terminate order":                                               "Не удалось отменить ордер",
+	"Fee percent must be between 0 and 1":                                     "Комиссия должна быть от 0 до 1 процента",
+	"Fill queue is full":                                                      "Очередь исполнений переполнена",
+	"Grid refresh percent must be greater than DCA step percent":              "Порог обновления сетки должен быть больше шага DCA",
+	"Invalid JSON":                                                            "Некорректный JSON",
+	"Invalid from date, expected YYYY-MM-DD":                                  "Некорректная дата from, ожидается YYYY-MM-DD",
+	"Invalid portfolio ID format":                                             "Некорректный формат ID портфеля",
+	"Invalid request body":                                                    "Некорректное тело запроса",
+	"Invalid to date, expected YYYY-MM-DD":                                    "Некорректная дата to, ожидается YYYY-MM-DD",
+	"Invalid trade ID format":                                                 "Некорректный формат ID сделки",
+	"Max budget or target position is required for time based strategy":       "Для стратегии time_based нужен максимальный бюджет или целевая позиция",
+	"Max cycles must not be negative":                                         "Число циклов не может быть отрицательным",
+	"Max price must be a positive number":                                     "Максимальная цена должна быть положительным числом",
+	"Min notional policy must be one of reject, bump":                         "Политика минимальной суммы должна быть одной из: reject, bump",
+	"Min price must be a positive number":                                     "Минимальная цена должна быть положительным числом",
+	"Min price must be lower than max price":                                  "Минимальная цена должна быть ниже максимальной",
+	"Order ID is required":                                                    "Требуется ID ордера",
+	"Partial fill policy must be one of resubmit, filled_only, abort":         "Политика частичного исполнения должна быть одной из: resubmit, filled_only, abort",
+	"Portfolio not found":                                                     "Портфель не найден",
+	"Precision must be one of display, raw":                                   "Точность должна быть одной из: display, raw",
+	"Reason is required":                                                      "Требуется причина",
+	"Period must be day or week":                                              "Период должен быть day или week",
+	"Failed to calculate PnL":                                                 "Не удалось рассчитать PnL",
+	"Report period must be between 1 and 366 days":                            "Период отчета должен быть от 1 до 366 дней",
+	"Short trades are not supported for time based strategy":                  "Короткие сделки не поддерживаются стратегией time_based",
+	"Side must be one of BUY, SELL":                                           "Сторона должна быть одной из: BUY, SELL",
+	"Start direction must be one of below, above":                             "Направление старта должно быть одним из: below, above",
+	"Start price must be a positive number":                                   "Стартовая цена должна быть положительным числом",
+	"Status must be one of ACTIVE, COMPLETED, CANCELLED, FAILED, STOPPED":     "Статус должен быть одним из: ACTIVE, COMPLETED, CANCELLED, FAILED, STOPPED",
+	"Stop loss is not supported in maker only mode":                           "Стоп-лосс не поддерживается в режиме только мейкер",
+	"Stop loss percent must be between 0 and 100":                             "Процент стоп-лосса должен быть от 0 до 100",
+	"Strategy must be one of price_step, time_based":                          "Стратегия должна быть одной из: price_step, time_based",
+	"Symbol and orderId are required":                                         "Требуются symbol и orderId",
+	"Symbol and quantity are required":                                        "Требуются symbol и quantity",
+	"Symbol is in cooldown":                                                   "Символ на паузе после закрытия",
+	"Symbol is required":                                                      "Требуется символ",
+	"Symbol, entry volume and DCA volume are required":                        "Требуются символ, объем входа и объем DCA",
+	"Symbol, quantity and price are required":                                 "Требуются symbol, quantity и price",
+	"Symbols or screener is required":                                         "Требуются символы или скринер",
+	"TP fallback is not supported in maker only mode":                         "Принудительное закрытие TP не поддерживается в режиме только мейкер",
+	"TP fallback must be one of ioc, market":                                  "Принудительное закрытие TP должно быть одним из: ioc, market",
+	"TP fallback seconds must not be negative":                                "Ожидание принудительного закрытия TP не может быть отрицательным",
+	"TP max deviation percent must not be negative":                           "Допустимое отклонение TP не может быть отрицательным",
+	"Max slippage percent must not be negative":                               "Допустимое проскальзывание не может быть отрицательным",
+	"Trade ID is required":                                                    "Требуется ID сделки",
+	"Trade not found":                                                         "Сделка не найдена",
+	"limit must be between 1 and 1000":                                        "limit должен быть от 1 до 1000",
+	"limit must be between 1 and 200":                                         "limit должен быть от 1 до 200",
+	"Unsupported kline interval":                                              "Неподдерживаемый интервал свечей",
+	"Failed to fetch ticker":                                                  "Не удалось получить тикер",
+	"Failed to fetch order book":                                              "Не удалось получить стакан",
+	"Failed to fetch klines":                                                  "Не удалось получить свечи",
+	"since_seq must be a non-negative integer":                                "since_seq должен быть неотрицательным целым",
+	"Internal server error":                                                   "Внутренняя ошибка сервера",
+}
@@ -0,0 +1,95 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// Lang - язык сообщений уведомлений и API.
+type Lang string
+
+const (
+	LangEN Lang = "en"
+	LangRU Lang = "ru"
+)
+
+var defaultLang atomic.Value
+
+func init() {
+	defaultLang.Store(LangEN)
+}
+
+// Parse разбирает код языка вида "ru" или "ru-RU".
+func Parse(value string) (Lang, error) {
+	code := strings.ToLower(strings.TrimSpace(value))
+	if i := strings.IndexAny(code, "-_"); i >= 0 {
+		code = code[:i]
+	}
+	switch Lang(code) {
+	case LangEN, LangRU:
+		return Lang(code), nil
+	}
+	return "", fmt.Errorf("unsupported language %q, expected one of en, ru", value)
+}
+
+// SetDefault задает язык по умолчанию: им пишутся уведомления и ответы API без Accept-Language.
+func SetDefault(lang Lang) {
+	defaultLang.Store(lang)
+}
+
+func Default() Lang {
+	return defaultLang.Load().(Lang)
+}
+
+// T переводит сообщение. Ключ каталога - английский текст, поэтому для английского
+// и для сообщений без перевода возвращается сам ключ.
+func T(lang Lang, message string) string {
+	if translated, ok := catalogs[lang][message]; ok {
+		return translated
+	}
+	return message
+}
+
+// Sprintf переводит формат и подставляет в него аргументы.
+func Sprintf(lang Lang, format string, args ...interface{}) string {
+	return fmt.Sprintf(T(lang, format), args...)
+}
+
+// FromAcceptLanguage выбирает из заголовка Accept-Language поддерживаемый язык
+// с наибольшим весом q. Если подходящего нет, возвращается fallback.
+func FromAcceptLanguage(header string, fallback Lang) Lang {
+	type candidate struct {
+		lang   Lang
+		weight float64
+	}
+	var candidates []candidate
+
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang, err := Parse(tag)
+		if err != nil {
+			continue
+		}
+
+		weight := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if weight, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		if weight > 0 {
+			candidates = append(candidates, candidate{lang, weight})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return fallback
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].weight > candidates[j].weight
+	})
+	return candidates[0].lang
+}
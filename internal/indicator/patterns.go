@@ -0,0 +1,99 @@
+package indicator
+
+import (
+	"math"
+	"sort"
+
+	"cryptorg/internal/bybit"
+)
+
+// Pattern распознает свечную фигуру по последним закрытым свечам (в хронологическом порядке).
+type Pattern interface {
+	Name() string
+	Detect(klines []bybit.Kline) bool
+}
+
+var patterns = map[string]Pattern{}
+
+func register(p Pattern) {
+	patterns[p.Name()] = p
+}
+
+func init() {
+	register(BearishEngulfing{})
+	register(ShootingStar{})
+	register(EveningStar{})
+}
+
+func Lookup(name string) (Pattern, bool) {
+	p, ok := patterns[name]
+	return p, ok
+}
+
+func Names() []string {
+	names := make([]string, 0, len(patterns))
+	for name := range patterns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func body(k bybit.Kline) float64 {
+	return math.Abs(k.Close - k.Open)
+}
+
+func isBullish(k bybit.Kline) bool {
+	return k.Close > k.Open
+}
+
+func isBearish(k bybit.Kline) bool {
+	return k.Close < k.Open
+}
+
+// BearishEngulfing - медвежья свеча полностью поглощает тело предыдущей бычьей.
+type BearishEngulfing struct{}
+
+func (BearishEngulfing) Name() string { return "bearish_engulfing" }
+
+func (BearishEngulfing) Detect(klines []bybit.Kline) bool {
+	if len(klines) < 2 {
+		return false
+	}
+	prev, last := klines[len(klines)-2], klines[len(klines)-1]
+	return isBullish(prev) && isBearish(last) && last.Open >= prev.Close && last.Close <= prev.Open
+}
+
+// ShootingStar - маленькое тело внизу диапазона и верхняя тень минимум в два тела
+// после растущей свечи.
+type ShootingStar struct{}
+
+func (ShootingStar) Name() string { return "shooting_star" }
+
+func (ShootingStar) Detect(klines []bybit.Kline) bool {
+	if len(klines) < 2 {
+		return false
+	}
+	prev, last := klines[len(klines)-2], klines[len(klines)-1]
+
+	b := body(last)
+	upperWick := last.High - math.Max(last.Open, last.Close)
+	lowerWick := math.Min(last.Open, last.Close) - last.Low
+	return isBullish(prev) && b > 0 && upperWick >= 2*b && lowerWick <= b/2
+}
+
+// EveningStar - крупная бычья свеча, свеча с маленьким телом и медвежья,
+// закрывшаяся ниже середины первой.
+type EveningStar struct{}
+
+func (EveningStar) Name() string { return "evening_star" }
+
+func (EveningStar) Detect(klines []bybit.Kline) bool {
+	if len(klines) < 3 {
+		return false
+	}
+	first, star, last := klines[len(klines)-3], klines[len(klines)-2], klines[len(klines)-1]
+
+	midpoint := (first.Open + first.Close) / 2
+	return isBullish(first) && body(star) < body(first)/3 && isBearish(last) && last.Close < midpoint
+}
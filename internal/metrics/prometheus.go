@@ -0,0 +1,154 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var defaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type series struct {
+	name   string
+	labels Labels
+}
+
+type histogram struct {
+	series
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// PrometheusRecorder хранит метрики в памяти и отдает их в текстовом формате экспозиции.
+type PrometheusRecorder struct {
+	mu         sync.Mutex
+	prefix     string
+	counters   map[string]*series
+	counterVal map[string]float64
+	gauges     map[string]*series
+	gaugeVal   map[string]float64
+	histograms map[string]*histogram
+}
+
+func NewPrometheusRecorder(prefix string) *PrometheusRecorder {
+	return &PrometheusRecorder{
+		prefix:     prefix,
+		counters:   make(map[string]*series),
+		counterVal: make(map[string]float64),
+		gauges:     make(map[string]*series),
+		gaugeVal:   make(map[string]float64),
+		histograms: make(map[string]*histogram),
+	}
+}
+
+func (p *PrometheusRecorder) IncCounter(name string, labels Labels) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := seriesKey(name, labels)
+	if _, exists := p.counters[key]; !exists {
+		p.counters[key] = &series{name: name, labels: labels}
+	}
+	p.counterVal[key]++
+}
+
+func (p *PrometheusRecorder) SetGauge(name string, value float64, labels Labels) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := seriesKey(name, labels)
+	if _, exists := p.gauges[key]; !exists {
+		p.gauges[key] = &series{name: name, labels: labels}
+	}
+	p.gaugeVal[key] = value
+}
+
+func (p *PrometheusRecorder) ObserveHistogram(name string, value float64, labels Labels) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := seriesKey(name, labels)
+	h, exists := p.histograms[key]
+	if !exists {
+		h = &histogram{series: series{name: name, labels: labels}, counts: make([]uint64, len(defaultBuckets))}
+		p.histograms[key] = h
+	}
+
+	for i, bound := range defaultBuckets {
+		if value <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += value
+	h.count++
+}
+
+func (p *PrometheusRecorder) Export(w io.Writer) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var b strings.Builder
+
+	for _, key := range sortedMapKeys(p.counters) {
+		s := p.counters[key]
+		fmt.Fprintf(&b, "%s%s %s\n", p.metricName(s.name), formatLabels(s.labels, "", ""), formatValue(p.counterVal[key]))
+	}
+
+	for _, key := range sortedMapKeys(p.gauges) {
+		s := p.gauges[key]
+		fmt.Fprintf(&b, "%s%s %s\n", p.metricName(s.name), formatLabels(s.labels, "", ""), formatValue(p.gaugeVal[key]))
+	}
+
+	for _, key := range sortedMapKeys(p.histograms) {
+		h := p.histograms[key]
+		name := p.metricName(h.name)
+		for i, bound := range defaultBuckets {
+			fmt.Fprintf(&b, "%s_bucket%s %d\n", name, formatLabels(h.labels, "le", formatValue(bound)), h.counts[i])
+		}
+		fmt.Fprintf(&b, "%s_bucket%s %d\n", name, formatLabels(h.labels, "le", "+Inf"), h.count)
+		fmt.Fprintf(&b, "%s_sum%s %s\n", name, formatLabels(h.labels, "", ""), formatValue(h.sum))
+		fmt.Fprintf(&b, "%s_count%s %d\n", name, formatLabels(h.labels, "", ""), h.count)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (p *PrometheusRecorder) metricName(name string) string {
+	if p.prefix == "" {
+		return name
+	}
+	return p.prefix + "_" + name
+}
+
+func formatLabels(labels Labels, extraKey, extraValue string) string {
+	if len(labels) == 0 && extraKey == "" {
+		return ""
+	}
+
+	parts := make([]string, 0, len(labels)+1)
+	for _, k := range sortedKeys(labels) {
+		parts = append(parts, fmt.Sprintf("%s=%q", k, labels[k]))
+	}
+	if extraKey != "" {
+		parts = append(parts, fmt.Sprintf("%s=%q", extraKey, extraValue))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func sortedMapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Labels map[string]string
+
+type Recorder interface {
+	IncCounter(name string, labels Labels)
+	ObserveHistogram(name string, value float64, labels Labels)
+	SetGauge(name string, value float64, labels Labels)
+}
+
+const (
+	BackendNone       = "none"
+	BackendPrometheus = "prometheus"
+	BackendStatsD     = "statsd"
+)
+
+func New(backend, prefix, statsdAddr string) (Recorder, error) {
+	switch backend {
+	case "", BackendNone:
+		return NoopRecorder{}, nil
+	case BackendPrometheus:
+		return NewPrometheusRecorder(prefix), nil
+	case BackendStatsD:
+		return NewStatsDRecorder(statsdAddr, prefix)
+	default:
+		return nil, fmt.Errorf("unsupported metrics backend: %s", backend)
+	}
+}
+
+func Since(start time.Time) float64 {
+	return time.Since(start).Seconds()
+}
+
+type NoopRecorder struct{}
+
+func (NoopRecorder) IncCounter(string, Labels)                {}
+func (NoopRecorder) ObserveHistogram(string, float64, Labels) {}
+func (NoopRecorder) SetGauge(string, float64, Labels)         {}
+
+func sortedKeys(labels Labels) []string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func seriesKey(name string, labels Labels) string {
+	var b strings.Builder
+	b.WriteString(name)
+	for _, k := range sortedKeys(labels) {
+		b.WriteString("|")
+		b.WriteString(k)
+		b.WriteString("=")
+		b.WriteString(labels[k])
+	}
+	return b.String()
+}
@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// StatsDRecorder шлет метрики по UDP в формате DogStatsD (теги поддерживаются Datadog агентом).
+type StatsDRecorder struct {
+	conn   net.Conn
+	prefix string
+}
+
+func NewStatsDRecorder(addr, prefix string) (*StatsDRecorder, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to statsd at %s: %w", addr, err)
+	}
+
+	return &StatsDRecorder{conn: conn, prefix: prefix}, nil
+}
+
+func (s *StatsDRecorder) IncCounter(name string, labels Labels) {
+	s.send(name, "1", "c", labels)
+}
+
+func (s *StatsDRecorder) ObserveHistogram(name string, value float64, labels Labels) {
+	s.send(name, formatValue(value), "h", labels)
+}
+
+func (s *StatsDRecorder) SetGauge(name string, value float64, labels Labels) {
+	s.send(name, formatValue(value), "g", labels)
+}
+
+func (s *StatsDRecorder) send(name, value, kind string, labels Labels) {
+	if s.prefix != "" {
+		name = s.prefix + "." + name
+	}
+
+	line := name + ":" + value + "|" + kind
+	if len(labels) > 0 {
+		tags := make([]string, 0, len(labels))
+		for _, k := range sortedKeys(labels) {
+			tags = append(tags, k+":"+labels[k])
+		}
+		line += "|#" + strings.Join(tags, ",")
+	}
+
+	// UDP без подтверждений: потеря метрики не должна влиять на торговлю
+	s.conn.Write([]byte(line))
+}
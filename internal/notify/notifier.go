@@ -0,0 +1,77 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"cryptorg/internal/i18n"
+	"cryptorg/pkg/version"
+)
+
+type Level string
+
+const (
+	LevelInfo     Level = "INFO"
+	LevelWarning  Level = "WARNING"
+	LevelCritical Level = "CRITICAL"
+)
+
+type Notification struct {
+	Level     Level     `json:"level"`
+	Title     string    `json:"title"`
+	Message   string    `json:"message"`
+	Timestamp time.Time `json:"timestamp"`
+	Build     string    `json:"build"` // Версия и коммит сборки, отправившей уведомление
+	Actions   []Action  `json:"actions,omitempty"`
+}
+
+// Action - кнопка под уведомлением. Каналы без кнопок ее не показывают,
+// нажатие в Telegram приходит в вебхук с Data в callback_data.
+type Action struct {
+	Text string `json:"text"`
+	Data string `json:"data"`
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) error
+}
+
+// LogNotifier пишет уведомления в лог; используется, когда внешние каналы не настроены.
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(ctx context.Context, notification Notification) error {
+	log.Printf("[%s] %s: %s", notification.Level, notification.Title, notification.Message)
+	return nil
+}
+
+// MultiNotifier рассылает уведомление во все каналы и собирает ошибки доставки.
+type MultiNotifier []Notifier
+
+func (m MultiNotifier) Notify(ctx context.Context, notification Notification) error {
+	var errs []error
+	for _, notifier := range m {
+		if err := notifier.Notify(ctx, notification); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func New(level Level, title, message string) Notification {
+	return Notification{
+		Level:     level,
+		Title:     title,
+		Message:   message,
+		Timestamp: time.Now(),
+		Build:     version.Short(),
+	}
+}
+
+// Localized собирает уведомление на языке по умолчанию: заголовок и формат сообщения
+// переводятся по каталогу i18n, аргументы подставляются как есть.
+func Localized(level Level, title, format string, args ...interface{}) Notification {
+	lang := i18n.Default()
+	return New(level, i18n.T(lang, title), i18n.Sprintf(lang, format, args...))
+}
@@ -0,0 +1,150 @@
+package notify
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// QueuedNotification - уведомление в очереди доставки вместе с историей попыток.
+type QueuedNotification struct {
+	Notification  Notification `json:"notification"`
+	Attempts      int          `json:"attempts"`
+	EnqueuedAt    time.Time    `json:"enqueued_at"`
+	NextAttemptAt time.Time    `json:"next_attempt_at"`
+	LastError     string       `json:"last_error,omitempty"`
+}
+
+// Queue доставляет уведомления в фоне с повторами и экспоненциальной задержкой.
+// Уведомления старше maxAge перестают отправляться и остаются в списке недоставленных.
+type Queue struct {
+	mu          sync.Mutex
+	target      Notifier
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	maxAge      time.Duration
+	pending     []*QueuedNotification
+	undelivered []QueuedNotification
+}
+
+const maxUndelivered = 100
+
+func NewQueue(target Notifier, baseDelay, maxDelay, maxAge time.Duration) *Queue {
+	return &Queue{
+		target:    target,
+		baseDelay: baseDelay,
+		maxDelay:  maxDelay,
+		maxAge:    maxAge,
+	}
+}
+
+// Notify ставит уведомление в очередь и не блокирует вызывающего.
+func (q *Queue) Notify(ctx context.Context, notification Notification) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	q.pending = append(q.pending, &QueuedNotification{
+		Notification:  notification,
+		EnqueuedAt:    now,
+		NextAttemptAt: now,
+	})
+	return nil
+}
+
+func (q *Queue) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			q.deliver(ctx)
+		}
+	}
+}
+
+func (q *Queue) deliver(ctx context.Context) {
+	now := time.Now()
+
+	q.mu.Lock()
+	due := make([]*QueuedNotification, 0)
+	for _, item := range q.pending {
+		if !item.NextAttemptAt.After(now) {
+			due = append(due, item)
+		}
+	}
+	q.mu.Unlock()
+
+	for _, item := range due {
+		err := q.target.Notify(ctx, item.Notification)
+
+		q.mu.Lock()
+		item.Attempts++
+		if err == nil {
+			q.remove(item)
+		} else {
+			item.LastError = err.Error()
+			if time.Since(item.EnqueuedAt) > q.maxAge {
+				log.Printf("Dropping notification %q after %d attempts: %v", item.Notification.Title, item.Attempts, err)
+				q.remove(item)
+				q.undelivered = append(q.undelivered, *item)
+				if len(q.undelivered) > maxUndelivered {
+					q.undelivered = q.undelivered[len(q.undelivered)-maxUndelivered:]
+				}
+			} else {
+				item.NextAttemptAt = time.Now().Add(q.backoff(item.Attempts))
+			}
+		}
+		q.mu.Unlock()
+	}
+}
+
+func (q *Queue) backoff(attempts int) time.Duration {
+	delay := q.baseDelay
+	for i := 1; i < attempts && delay < q.maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > q.maxDelay {
+		delay = q.maxDelay
+	}
+	return delay
+}
+
+func (q *Queue) remove(target *QueuedNotification) {
+	for i, item := range q.pending {
+		if item == target {
+			q.pending = append(q.pending[:i], q.pending[i+1:]...)
+			return
+		}
+	}
+}
+
+// Len возвращает число уведомлений, ожидающих доставки.
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.pending)
+}
+
+func (q *Queue) Pending() []QueuedNotification {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	result := make([]QueuedNotification, 0, len(q.pending))
+	for _, item := range q.pending {
+		result = append(result, *item)
+	}
+	return result
+}
+
+func (q *Queue) Undelivered() []QueuedNotification {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return append([]QueuedNotification(nil), q.undelivered...)
+}
@@ -0,0 +1,74 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+type TelegramNotifier struct {
+	token      string
+	chatID     string
+	httpClient *http.Client
+}
+
+func NewTelegramNotifier(token, chatID string) *TelegramNotifier {
+	return &TelegramNotifier{
+		token:      token,
+		chatID:     chatID,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (t *TelegramNotifier) Notify(ctx context.Context, notification Notification) error {
+	message := map[string]interface{}{
+		"chat_id": t.chatID,
+		"text":    fmt.Sprintf("[%s] %s\n%s\n%s", notification.Level, notification.Title, notification.Message, notification.Build),
+	}
+	if len(notification.Actions) > 0 {
+		row := make([]map[string]string, 0, len(notification.Actions))
+		for _, action := range notification.Actions {
+			row = append(row, map[string]string{"text": action.Text, "callback_data": action.Data})
+		}
+		message["reply_markup"] = map[string]interface{}{"inline_keyboard": [][]map[string]string{row}}
+	}
+	return t.call(ctx, "sendMessage", message)
+}
+
+// AnswerCallback подтверждает нажатие кнопки, чтобы Telegram убрал индикатор загрузки.
+func (t *TelegramNotifier) AnswerCallback(ctx context.Context, callbackID, text string) error {
+	return t.call(ctx, "answerCallbackQuery", map[string]interface{}{
+		"callback_query_id": callbackID,
+		"text":              text,
+	})
+}
+
+func (t *TelegramNotifier) call(ctx context.Context, method string, body map[string]interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal telegram message: %w", err)
+	}
+
+	url := "https://api.telegram.org/bot" + t.token + "/" + method
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send telegram message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("telegram API error: status %d, body: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
@@ -0,0 +1,856 @@
+package okx
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cryptorg/internal/bybit"
+	"cryptorg/internal/service"
+	"cryptorg/internal/tracing"
+	"cryptorg/pkg/latency"
+	"cryptorg/pkg/ratelimit"
+)
+
+const baseURL = "https://www.okx.com"
+
+var _ service.ExchangeClient = (*Client)(nil)
+
+var quoteAssets = []string{"USDT", "USDC", "BTC", "ETH", "EUR"}
+
+type Client struct {
+	apiKey      string
+	secretKey   string
+	passphrase  string
+	demo        bool
+	httpClient  *http.Client
+	latency     *latency.Tracker
+	rateLimits  *ratelimit.Tracker
+	clock       *latency.ClockOffset
+	instruments map[string]*Instrument
+	mu          sync.RWMutex
+}
+
+type Instrument struct {
+	InstID string `json:"instId"`
+	LotSz  string `json:"lotSz"`
+	TickSz string `json:"tickSz"`
+	MinSz  string `json:"minSz"`
+}
+
+func NewExchangeClient(apiKey, secretKey, passphrase string, demo bool) *Client {
+	return &Client{
+		apiKey:      apiKey,
+		secretKey:   secretKey,
+		passphrase:  passphrase,
+		demo:        demo,
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		latency:     latency.NewTracker(latency.DefaultWindow),
+		rateLimits:  ratelimit.NewTracker(ratelimit.DefaultWindow, ratelimit.DefaultReserve),
+		clock:       latency.NewClockOffset(),
+		instruments: make(map[string]*Instrument),
+	}
+}
+
+func (c *Client) Latency() *latency.Tracker {
+	return c.latency
+}
+
+// RateLimits - OKX не сообщает квоту в заголовках, поэтому учитываются только
+// частота запросов и отказы по лимиту.
+func (c *Client) RateLimits() *ratelimit.Tracker {
+	return c.rateLimits
+}
+
+// Clock - OKX не отдает время сервера в заголовках ответов, смещение считается нулевым.
+func (c *Client) Clock() *latency.ClockOffset {
+	return c.clock
+}
+
+type orderRequest struct {
+	InstID  string `json:"instId"`
+	TdMode  string `json:"tdMode"`
+	Side    string `json:"side"`
+	OrdType string `json:"ordType"`
+	Sz      string `json:"sz"`
+	Px      string `json:"px,omitempty"`
+	TgtCcy  string `json:"tgtCcy,omitempty"`
+	ClOrdID string `json:"clOrdId,omitempty"`
+}
+
+type algoOrderRequest struct {
+	InstID    string `json:"instId"`
+	TdMode    string `json:"tdMode"`
+	Side      string `json:"side"`
+	OrdType   string `json:"ordType"`
+	Sz        string `json:"sz"`
+	TriggerPx string `json:"triggerPx"`
+	OrderPx   string `json:"orderPx"`
+	AlgoClID  string `json:"algoClOrdId,omitempty"`
+}
+
+type orderDetails struct {
+	InstID    string `json:"instId"`
+	OrdID     string `json:"ordId"`
+	ClOrdID   string `json:"clOrdId"`
+	Px        string `json:"px"`
+	Sz        string `json:"sz"`
+	AccFillSz string `json:"accFillSz"`
+	AvgPx     string `json:"avgPx"`
+	State     string `json:"state"`
+	Side      string `json:"side"`
+	OrdType   string `json:"ordType"`
+	CTime     string `json:"cTime"`
+	UTime     string `json:"uTime"`
+	Fee       string `json:"fee"`
+}
+
+func (c *Client) ExecuteOrder(ctx context.Context, req bybit.ExchangeOrderRequest) (*bybit.ExchangeOrderResponse, error) {
+	instID := toInstID(req.Symbol)
+
+	instrument, err := c.getInstrument(ctx, instID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load instrument filters: %w", err)
+	}
+
+	side := strings.ToLower(req.Side)
+	qty := req.Qty
+	price := req.Price
+	if price != "" {
+		price = roundToStep(price, instrument.TickSz)
+	}
+
+	if req.OrderFilter == bybit.OrderFilterStopOrder {
+		qty = roundToStep(qty, instrument.LotSz)
+		return c.executeAlgoOrder(ctx, algoOrderRequest{
+			InstID:    instID,
+			TdMode:    "cash",
+			Side:      side,
+			OrdType:   "trigger",
+			Sz:        qty,
+			TriggerPx: roundToStep(req.TriggerPrice, instrument.TickSz),
+			OrderPx:   price,
+			AlgoClID:  req.OrderLinkID,
+		}, req)
+	}
+
+	okxReq := orderRequest{
+		InstID:  instID,
+		TdMode:  "cash",
+		Side:    side,
+		OrdType: toOrdType(req.OrderType, req.TimeInForce),
+		Sz:      qty,
+		Px:      price,
+		ClOrdID: req.OrderLinkID,
+	}
+	if okxReq.OrdType == "market" && (side == "buy" || req.MarketUnit == bybit.MarketUnitQuoteCoin) {
+		// Как и на Bybit, рыночная покупка задается суммой в котируемой валюте
+		okxReq.TgtCcy = "quote_ccy"
+	} else {
+		okxReq.Sz = roundToStep(qty, instrument.LotSz)
+	}
+
+	var result []struct {
+		OrdID   string `json:"ordId"`
+		ClOrdID string `json:"clOrdId"`
+		SCode   string `json:"sCode"`
+		SMsg    string `json:"sMsg"`
+	}
+	if err := c.makeAuthenticatedRequest(ctx, "POST", "/api/v5/trade/order", nil, okxReq, &result); err != nil {
+		return nil, fmt.Errorf("failed to create order: %w", err)
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("okx API error: empty order response")
+	}
+	if result[0].SCode != "0" {
+		return nil, fmt.Errorf("okx API error: sCode %s, sMsg: %s", result[0].SCode, result[0].SMsg)
+	}
+
+	return &bybit.ExchangeOrderResponse{
+		Symbol:      req.Symbol,
+		OrderID:     result[0].OrdID,
+		OrderLinkID: result[0].ClOrdID,
+		Price:       okxReq.Px,
+		Qty:         okxReq.Sz,
+		Status:      "New",
+		TimeInForce: req.TimeInForce,
+		OrderType:   req.OrderType,
+		Side:        req.Side,
+		CreatedTime: strconv.FormatInt(time.Now().UnixMilli(), 10),
+	}, nil
+}
+
+func (c *Client) executeAlgoOrder(ctx context.Context, algoReq algoOrderRequest, req bybit.ExchangeOrderRequest) (*bybit.ExchangeOrderResponse, error) {
+	var result []struct {
+		AlgoID string `json:"algoId"`
+		SCode  string `json:"sCode"`
+		SMsg   string `json:"sMsg"`
+	}
+	if err := c.makeAuthenticatedRequest(ctx, "POST", "/api/v5/trade/order-algo", nil, algoReq, &result); err != nil {
+		return nil, fmt.Errorf("failed to create algo order: %w", err)
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("okx API error: empty algo order response")
+	}
+	if result[0].SCode != "0" {
+		return nil, fmt.Errorf("okx API error: sCode %s, sMsg: %s", result[0].SCode, result[0].SMsg)
+	}
+
+	return &bybit.ExchangeOrderResponse{
+		Symbol:      req.Symbol,
+		OrderID:     result[0].AlgoID,
+		OrderLinkID: algoReq.AlgoClID,
+		Price:       algoReq.OrderPx,
+		Qty:         algoReq.Sz,
+		Status:      "Untriggered",
+		OrderType:   req.OrderType,
+		Side:        req.Side,
+		CreatedTime: strconv.FormatInt(time.Now().UnixMilli(), 10),
+	}, nil
+}
+
+func (c *Client) TerminateOrder(ctx context.Context, req bybit.ExchangeCancelRequest) error {
+	instID := toInstID(req.Symbol)
+
+	if req.OrderFilter == bybit.OrderFilterStopOrder {
+		payload := []map[string]string{{"algoId": req.OrderID, "instId": instID}}
+		if err := c.makeAuthenticatedRequest(ctx, "POST", "/api/v5/trade/cancel-algos", nil, payload, nil); err != nil {
+			return fmt.Errorf("failed to cancel algo order: %w", err)
+		}
+		return nil
+	}
+
+	payload := map[string]string{"instId": instID, "ordId": req.OrderID}
+	if err := c.makeAuthenticatedRequest(ctx, "POST", "/api/v5/trade/cancel-order", nil, payload, nil); err != nil {
+		return fmt.Errorf("failed to cancel order: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) AmendOrder(ctx context.Context, req bybit.ExchangeAmendRequest) error {
+	instID := toInstID(req.Symbol)
+
+	instrument, err := c.getInstrument(ctx, instID)
+	if err != nil {
+		return fmt.Errorf("failed to load instrument filters: %w", err)
+	}
+
+	payload := map[string]string{"instId": instID, "ordId": req.OrderID}
+	if req.Qty != "" {
+		payload["newSz"] = roundToStep(req.Qty, instrument.LotSz)
+	}
+	if req.Price != "" {
+		payload["newPx"] = roundToStep(req.Price, instrument.TickSz)
+	}
+
+	if err := c.makeAuthenticatedRequest(ctx, "POST", "/api/v5/trade/amend-order", nil, payload, nil); err != nil {
+		return fmt.Errorf("failed to amend order: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) FetchOrderInfo(ctx context.Context, symbol string, orderID string) (*bybit.ExchangeOrderResponse, error) {
+	params := url.Values{}
+	params.Set("instId", toInstID(symbol))
+	params.Set("ordId", orderID)
+
+	var result []orderDetails
+	if err := c.makeAuthenticatedRequest(ctx, "GET", "/api/v5/trade/order", params, nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to fetch order: %w", err)
+	}
+	if len(result) == 0 {
+		return nil, bybit.ErrOrderNotFound
+	}
+
+	return toOrderResponse(symbol, result[0]), nil
+}
+
+// FetchOrderHistory: /api/v5/trade/order отдает и исполненные ордера за последние 3 месяца,
+// отдельный запрос истории не нужен.
+func (c *Client) FetchOrderHistory(ctx context.Context, symbol string, orderID string) (*bybit.ExchangeOrderResponse, error) {
+	return c.FetchOrderInfo(ctx, symbol, orderID)
+}
+
+// ListExecutions возвращает исполнения ордера за последние 3 месяца.
+func (c *Client) ListExecutions(ctx context.Context, symbol string, orderID string) ([]bybit.Execution, error) {
+	params := url.Values{}
+	params.Set("instType", "SPOT")
+	params.Set("instId", toInstID(symbol))
+	params.Set("ordId", orderID)
+
+	var result []struct {
+		TradeID string `json:"tradeId"`
+		OrdID   string `json:"ordId"`
+		FillPx  string `json:"fillPx"`
+		FillSz  string `json:"fillSz"`
+		Fee     string `json:"fee"`
+		TS      string `json:"ts"`
+	}
+	if err := c.makeAuthenticatedRequest(ctx, "GET", "/api/v5/trade/fills-history", params, nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to list executions: %w", err)
+	}
+
+	executions := make([]bybit.Execution, 0, len(result))
+	for _, fill := range result {
+		price, _ := strconv.ParseFloat(fill.FillPx, 64)
+		qty, _ := strconv.ParseFloat(fill.FillSz, 64)
+		executions = append(executions, bybit.Execution{
+			ExecID:  fill.TradeID,
+			OrderID: fill.OrdID,
+			Price:   fill.FillPx,
+			Qty:     fill.FillSz,
+			Value:   fmt.Sprintf("%.8f", price*qty),
+			Fee:     strings.TrimPrefix(fill.Fee, "-"),
+			Time:    fill.TS,
+		})
+	}
+	return executions, nil
+}
+
+func (c *Client) FetchOrderByLinkID(ctx context.Context, symbol string, linkID string) (*bybit.ExchangeOrderResponse, error) {
+	params := url.Values{}
+	params.Set("instId", toInstID(symbol))
+	params.Set("clOrdId", linkID)
+
+	var result []orderDetails
+	if err := c.makeAuthenticatedRequest(ctx, "GET", "/api/v5/trade/order", params, nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to fetch order by link id: %w", err)
+	}
+	if len(result) == 0 {
+		return nil, bybit.ErrOrderNotFound
+	}
+
+	return toOrderResponse(symbol, result[0]), nil
+}
+
+func (c *Client) ListOpenOrders(ctx context.Context, symbol string) ([]bybit.ExchangeOrderResponse, error) {
+	params := url.Values{}
+	params.Set("instType", "SPOT")
+	if symbol != "" {
+		params.Set("instId", toInstID(symbol))
+	}
+
+	var result []orderDetails
+	if err := c.makeAuthenticatedRequest(ctx, "GET", "/api/v5/trade/orders-pending", params, nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to list open orders: %w", err)
+	}
+
+	orders := make([]bybit.ExchangeOrderResponse, 0, len(result))
+	for _, details := range result {
+		orderSymbol := symbol
+		if orderSymbol == "" {
+			orderSymbol = strings.ReplaceAll(details.InstID, "-", "")
+		}
+		orders = append(orders, *toOrderResponse(orderSymbol, details))
+	}
+	return orders, nil
+}
+
+// GetWalletBalance возвращает балансы всех монет торгового счета.
+func (c *Client) GetWalletBalance(ctx context.Context) ([]bybit.CoinBalance, error) {
+	var result []struct {
+		Details []struct {
+			Ccy       string `json:"ccy"`
+			CashBal   string `json:"cashBal"`
+			FrozenBal string `json:"frozenBal"`
+		} `json:"details"`
+	}
+	if err := c.makeAuthenticatedRequest(ctx, "GET", "/api/v5/account/balance", url.Values{}, nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to get wallet balance: %w", err)
+	}
+
+	balances := make([]bybit.CoinBalance, 0)
+	for _, account := range result {
+		for _, details := range account.Details {
+			balances = append(balances, bybit.CoinBalance{Coin: details.Ccy, WalletBalance: details.CashBal, Locked: details.FrozenBal})
+		}
+	}
+	return balances, nil
+}
+
+func (c *Client) ListWalletChanges(ctx context.Context, coin string, since time.Time) ([]bybit.WalletChange, error) {
+	params := url.Values{}
+	params.Set("instType", "SPOT")
+	params.Set("ccy", coin)
+	params.Set("type", "2") // Торговые операции
+	params.Set("begin", strconv.FormatInt(since.UnixMilli(), 10))
+
+	var result []struct {
+		Ccy    string `json:"ccy"`
+		OrdID  string `json:"ordId"`
+		BalChg string `json:"balChg"`
+	}
+	if err := c.makeAuthenticatedRequest(ctx, "GET", "/api/v5/account/bills", params, nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to list wallet changes: %w", err)
+	}
+
+	changes := make([]bybit.WalletChange, 0, len(result))
+	for _, bill := range result {
+		changes = append(changes, bybit.WalletChange{Coin: bill.Ccy, OrderID: bill.OrdID, Change: bill.BalChg})
+	}
+	return changes, nil
+}
+
+func (c *Client) GetBalance(ctx context.Context, coin string) (*bybit.CoinBalance, error) {
+	params := url.Values{}
+	params.Set("ccy", coin)
+
+	var result []struct {
+		Details []struct {
+			Ccy       string `json:"ccy"`
+			CashBal   string `json:"cashBal"`
+			FrozenBal string `json:"frozenBal"`
+		} `json:"details"`
+	}
+	if err := c.makeAuthenticatedRequest(ctx, "GET", "/api/v5/account/balance", params, nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+
+	for _, account := range result {
+		for _, details := range account.Details {
+			if details.Ccy == coin {
+				return &bybit.CoinBalance{Coin: coin, WalletBalance: details.CashBal, Locked: details.FrozenBal}, nil
+			}
+		}
+	}
+
+	return &bybit.CoinBalance{Coin: coin, WalletBalance: "0", Locked: "0"}, nil
+}
+
+func toOrderResponse(symbol string, details orderDetails) *bybit.ExchangeOrderResponse {
+	price := details.AvgPx
+	if price == "" || price == "0" {
+		price = details.Px
+	}
+
+	executedValue := ""
+	if filled, err := strconv.ParseFloat(details.AccFillSz, 64); err == nil {
+		if avgPrice, err := strconv.ParseFloat(details.AvgPx, 64); err == nil {
+			executedValue = strconv.FormatFloat(filled*avgPrice, 'f', -1, 64)
+		}
+	}
+
+	return &bybit.ExchangeOrderResponse{
+		Symbol:      symbol,
+		OrderID:     details.OrdID,
+		OrderLinkID: details.ClOrdID,
+		Price:       price,
+		Qty:         details.Sz,
+		ExecutedQty: details.AccFillSz,
+		Status:      toOrderStatus(details.State, details.AccFillSz),
+		OrderType:   strings.ToUpper(details.OrdType),
+		Side:        strings.ToUpper(details.Side),
+		CreatedTime: details.CTime,
+		UpdatedTime: details.UTime,
+		// OKX возвращает списанную комиссию отрицательным числом
+		ExecutedValue: executedValue,
+		ExecutedFee:   strings.TrimPrefix(details.Fee, "-"),
+	}
+}
+
+func (c *Client) GetTicker(ctx context.Context, symbol string) (*bybit.Ticker, error) {
+	params := url.Values{}
+	params.Set("instId", toInstID(symbol))
+
+	var result []struct {
+		Last      string `json:"last"`
+		BidPx     string `json:"bidPx"`
+		AskPx     string `json:"askPx"`
+		High24h   string `json:"high24h"`
+		Low24h    string `json:"low24h"`
+		Vol24h    string `json:"vol24h"`
+		VolCcy24h string `json:"volCcy24h"`
+	}
+	if err := c.getPublic(ctx, "/api/v5/market/ticker", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to get ticker: %w", err)
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("ticker not found for %s", symbol)
+	}
+
+	return &bybit.Ticker{
+		Symbol:    symbol,
+		LastPrice: result[0].Last,
+		Bid1Price: result[0].BidPx,
+		Ask1Price: result[0].AskPx,
+		HighPrice: result[0].High24h,
+		LowPrice:  result[0].Low24h,
+		Volume24h: result[0].Vol24h,
+		Turnover:  result[0].VolCcy24h,
+	}, nil
+}
+
+// GetOrderBook возвращает до limit уровней стакана на каждую сторону (OKX отдает до 400).
+func (c *Client) GetOrderBook(ctx context.Context, symbol string, limit int) (*bybit.OrderBook, error) {
+	params := url.Values{}
+	params.Set("instId", toInstID(symbol))
+	params.Set("sz", strconv.Itoa(limit))
+
+	var result []struct {
+		Asks [][]string `json:"asks"`
+		Bids [][]string `json:"bids"`
+		Ts   string     `json:"ts"`
+	}
+	if err := c.getPublic(ctx, "/api/v5/market/books", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to get order book: %w", err)
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("order book not found for %s", symbol)
+	}
+
+	ts, _ := strconv.ParseInt(result[0].Ts, 10, 64)
+	return &bybit.OrderBook{
+		Symbol: symbol,
+		Bids:   bybit.ParseBookLevels(result[0].Bids),
+		Asks:   bybit.ParseBookLevels(result[0].Asks),
+		Time:   time.UnixMilli(ts),
+	}, nil
+}
+
+func (c *Client) ListTickers(ctx context.Context) ([]bybit.Ticker, error) {
+	params := url.Values{}
+	params.Set("instType", "SPOT")
+
+	var result []struct {
+		InstID    string `json:"instId"`
+		Last      string `json:"last"`
+		BidPx     string `json:"bidPx"`
+		AskPx     string `json:"askPx"`
+		High24h   string `json:"high24h"`
+		Low24h    string `json:"low24h"`
+		Vol24h    string `json:"vol24h"`
+		VolCcy24h string `json:"volCcy24h"`
+	}
+	if err := c.getPublic(ctx, "/api/v5/market/tickers", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to list tickers: %w", err)
+	}
+
+	tickers := make([]bybit.Ticker, 0, len(result))
+	for _, t := range result {
+		tickers = append(tickers, bybit.Ticker{
+			Symbol:    strings.ReplaceAll(t.InstID, "-", ""),
+			LastPrice: t.Last,
+			Bid1Price: t.BidPx,
+			Ask1Price: t.AskPx,
+			HighPrice: t.High24h,
+			LowPrice:  t.Low24h,
+			Volume24h: t.Vol24h,
+			Turnover:  t.VolCcy24h,
+		})
+	}
+	return tickers, nil
+}
+
+func (c *Client) GetKlines(ctx context.Context, symbol, interval string, limit int) ([]bybit.Kline, error) {
+	params := url.Values{}
+	params.Set("instId", toInstID(symbol))
+	params.Set("bar", toBar(interval))
+	params.Set("limit", strconv.Itoa(limit))
+
+	var result [][]string
+	if err := c.getPublic(ctx, "/api/v5/market/candles", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to get klines: %w", err)
+	}
+
+	return parseCandles(result), nil
+}
+
+// historyPageLimit - максимум свечей в одном ответе /api/v5/market/history-candles.
+const historyPageLimit = 100
+
+// GetKlinesRange возвращает свечи с start по end в хронологическом порядке. История
+// листается параметром after (свечи строго раньше указанного времени).
+func (c *Client) GetKlinesRange(ctx context.Context, symbol, interval string, start, end time.Time) ([]bybit.Kline, error) {
+	startMs := start.UnixMilli()
+	cursor := end.UnixMilli() + 1
+
+	var klines []bybit.Kline
+	for cursor > startMs {
+		params := url.Values{}
+		params.Set("instId", toInstID(symbol))
+		params.Set("bar", toBar(interval))
+		params.Set("after", strconv.FormatInt(cursor, 10))
+		params.Set("limit", strconv.Itoa(historyPageLimit))
+
+		var result [][]string
+		if err := c.getPublic(ctx, "/api/v5/market/history-candles", params, &result); err != nil {
+			return nil, fmt.Errorf("failed to get klines: %w", err)
+		}
+
+		page := parseCandles(result)
+		if len(page) == 0 {
+			break
+		}
+		cursor = page[0].StartTime
+		for len(page) > 0 && page[0].StartTime < startMs {
+			page = page[1:]
+		}
+		klines = append(page, klines...)
+		if len(result) < historyPageLimit {
+			break
+		}
+	}
+
+	return klines, nil
+}
+
+// parseCandles разбирает строки свечей OKX (от новых к старым) в хронологическом порядке.
+func parseCandles(rows [][]string) []bybit.Kline {
+	klines := make([]bybit.Kline, 0, len(rows))
+	for i := len(rows) - 1; i >= 0; i-- {
+		row := rows[i]
+		if len(row) < 6 {
+			continue
+		}
+		var k bybit.Kline
+		k.StartTime, _ = strconv.ParseInt(row[0], 10, 64)
+		k.Open, _ = strconv.ParseFloat(row[1], 64)
+		k.High, _ = strconv.ParseFloat(row[2], 64)
+		k.Low, _ = strconv.ParseFloat(row[3], 64)
+		k.Close, _ = strconv.ParseFloat(row[4], 64)
+		k.Volume, _ = strconv.ParseFloat(row[5], 64)
+		klines = append(klines, k)
+	}
+	return klines
+}
+
+func (c *Client) GetInstrumentInfo(ctx context.Context, symbol string) (*bybit.InstrumentInfo, error) {
+	instrument, err := c.getInstrument(ctx, toInstID(symbol))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instrument info: %w", err)
+	}
+
+	return &bybit.InstrumentInfo{
+		Symbol:         symbol,
+		TickSize:       instrument.TickSz,
+		QtyStep:        instrument.LotSz,
+		QuotePrecision: instrument.TickSz,
+		MinOrderQty:    instrument.MinSz,
+	}, nil
+}
+
+func (c *Client) getInstrument(ctx context.Context, instID string) (*Instrument, error) {
+	c.mu.RLock()
+	instrument, exists := c.instruments[instID]
+	c.mu.RUnlock()
+	if exists {
+		return instrument, nil
+	}
+
+	params := url.Values{}
+	params.Set("instType", "SPOT")
+	params.Set("instId", instID)
+
+	var result []Instrument
+	if err := c.getPublic(ctx, "/api/v5/public/instruments", params, &result); err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("instrument %s not found", instID)
+	}
+
+	c.mu.Lock()
+	c.instruments[instID] = &result[0]
+	c.mu.Unlock()
+
+	return &result[0], nil
+}
+
+func (c *Client) getPublic(ctx context.Context, endpoint string, params url.Values, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if c.demo {
+		req.Header.Set("x-simulated-trading", "1")
+	}
+
+	return c.do(req, result)
+}
+
+func (c *Client) makeAuthenticatedRequest(ctx context.Context, method, endpoint string, params url.Values, payload interface{}, result interface{}) error {
+	requestPath := endpoint
+	if len(params) > 0 {
+		requestPath += "?" + params.Encode()
+	}
+
+	var body []byte
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal payload: %w", err)
+		}
+		body = data
+	}
+
+	timestamp := time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+	signature := c.createSignature(timestamp + method + requestPath + string(body))
+
+	req, err := http.NewRequestWithContext(ctx, method, baseURL+requestPath, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("OK-ACCESS-KEY", c.apiKey)
+	req.Header.Set("OK-ACCESS-SIGN", signature)
+	req.Header.Set("OK-ACCESS-TIMESTAMP", timestamp)
+	req.Header.Set("OK-ACCESS-PASSPHRASE", c.passphrase)
+	req.Header.Set("Content-Type", "application/json")
+	if c.demo {
+		req.Header.Set("x-simulated-trading", "1")
+	}
+
+	return c.do(req, result)
+}
+
+func (c *Client) do(req *http.Request, result interface{}) (err error) {
+	ctx, span := tracing.Start(req.Context(), "okx "+req.Method+" "+req.URL.Path)
+	defer func() { tracing.End(span, err) }()
+	req = req.WithContext(ctx)
+
+	if err := c.rateLimits.Wait(ctx, req.URL.Path); err != nil {
+		return err
+	}
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	c.latency.Record(time.Since(start))
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		c.rateLimits.Reject(req.URL.Path)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("okx API error: status %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var apiResp struct {
+		Code string          `json:"code"`
+		Msg  string          `json:"msg"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if apiResp.Code != "0" {
+		return fmt.Errorf("okx API error: code %s, msg: %s", apiResp.Code, apiResp.Msg)
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(apiResp.Data, result)
+}
+
+func (c *Client) createSignature(prehash string) string {
+	h := hmac.New(sha256.New, []byte(c.secretKey))
+	h.Write([]byte(prehash))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+func toInstID(symbol string) string {
+	if strings.Contains(symbol, "-") {
+		return symbol
+	}
+	for _, quote := range quoteAssets {
+		if strings.HasSuffix(symbol, quote) && len(symbol) > len(quote) {
+			return strings.TrimSuffix(symbol, quote) + "-" + quote
+		}
+	}
+	return symbol
+}
+
+func toOrdType(orderType, timeInForce string) string {
+	switch {
+	case strings.EqualFold(orderType, "MARKET"):
+		return "market"
+	case strings.EqualFold(timeInForce, "PostOnly"):
+		return "post_only"
+	case strings.EqualFold(timeInForce, "IOC"):
+		return "ioc"
+	case strings.EqualFold(timeInForce, "FOK"):
+		return "fok"
+	default:
+		return "limit"
+	}
+}
+
+// toOrderStatus переводит state OKX в статус Bybit. Отмена после частичного исполнения
+// у OKX не отличается от простой, ее выдает накопленный объем.
+func toOrderStatus(state, filled string) string {
+	switch state {
+	case "live":
+		return "New"
+	case "partially_filled":
+		return "PartiallyFilled"
+	case "filled":
+		return "Filled"
+	case "canceled", "mmp_canceled":
+		if qty, err := strconv.ParseFloat(filled, 64); err == nil && qty > 0 {
+			return "PartiallyFilledCanceled"
+		}
+		return "Cancelled"
+	default:
+		return state
+	}
+}
+
+func toBar(interval string) string {
+	switch interval {
+	case "D":
+		return "1D"
+	case "W":
+		return "1W"
+	case "M":
+		return "1M"
+	case "60":
+		return "1H"
+	case "120":
+		return "2H"
+	case "240":
+		return "4H"
+	case "360":
+		return "6H"
+	case "720":
+		return "12H"
+	default:
+		return interval + "m"
+	}
+}
+
+// roundToStep округляет значение вниз до шага инструмента (lotSz/tickSz).
+func roundToStep(value, step string) string {
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return value
+	}
+	s, err := strconv.ParseFloat(step, 64)
+	if err != nil || s <= 0 {
+		return value
+	}
+
+	decimals := 0
+	if idx := strings.IndexByte(step, '.'); idx >= 0 {
+		decimals = len(strings.TrimRight(step[idx+1:], "0"))
+	}
+
+	rounded := math.Floor(v/s+1e-9) * s
+	return strconv.FormatFloat(rounded, 'f', decimals, 64)
+}
@@ -0,0 +1,266 @@
+package okx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cryptorg/internal/domain"
+
+	"golang.org/x/net/websocket"
+)
+
+const (
+	privateStreamURL     = "wss://ws.okx.com:8443/ws/v5/private"
+	demoPrivateStreamURL = "wss://wspap.okx.com:8443/ws/v5/private?brokerId=9999"
+
+	streamPingInterval = 25 * time.Second // OKX закрывает соединение после 30 секунд тишины
+	streamDialTimeout  = 10 * time.Second
+	streamMaxBackoff   = time.Minute
+)
+
+// OrderHandler получает обновление ордера и ID сделки (tradeId) последнего исполнения.
+type OrderHandler func(update domain.OrderUpdate, execID string) error
+
+// OrderStream - приватный WebSocket OKX, канал orders: обновления спотовых ордеров счета.
+// Заменяет для OKX вебхук ордеров; после разрыва переподключается с растущей паузой.
+type OrderStream struct {
+	client  *Client
+	url     string
+	handler OrderHandler
+	capture domain.MessageCapture // nil - сообщения не сохраняются
+
+	mu          sync.RWMutex
+	connectedAt time.Time // Нулевое время - соединения нет
+}
+
+func NewOrderStream(client *Client, handler OrderHandler, capture domain.MessageCapture) *OrderStream {
+	url := privateStreamURL
+	if client.demo {
+		url = demoPrivateStreamURL
+	}
+	return &OrderStream{
+		client:  client,
+		url:     url,
+		handler: handler,
+		capture: capture,
+	}
+}
+
+// ConnectedAt возвращает время установки текущего соединения.
+func (s *OrderStream) ConnectedAt() (time.Time, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.connectedAt, !s.connectedAt.IsZero()
+}
+
+func (s *OrderStream) setConnectedAt(at time.Time) {
+	s.mu.Lock()
+	s.connectedAt = at
+	s.mu.Unlock()
+}
+
+// Run держит соединение до отмены ctx.
+func (s *OrderStream) Run(ctx context.Context) {
+	backoff := time.Second
+	for {
+		started := time.Now()
+		err := s.session(ctx)
+		s.setConnectedAt(time.Time{})
+		if ctx.Err() != nil {
+			return
+		}
+		// Соединение, прожившее дольше паузы, считается успешным
+		if time.Since(started) > streamMaxBackoff {
+			backoff = time.Second
+		}
+		log.Printf("OKX order stream disconnected: %v; reconnecting in %s", err, backoff)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, streamMaxBackoff)
+	}
+}
+
+type streamRequest struct {
+	Op   string        `json:"op"`
+	Args []interface{} `json:"args"`
+}
+
+type loginArgs struct {
+	APIKey     string `json:"apiKey"`
+	Passphrase string `json:"passphrase"`
+	Timestamp  string `json:"timestamp"`
+	Sign       string `json:"sign"`
+}
+
+type channelArgs struct {
+	Channel  string `json:"channel"`
+	InstType string `json:"instType"`
+}
+
+type streamMessage struct {
+	Event string          `json:"event"`
+	Code  string          `json:"code"`
+	Msg   string          `json:"msg"`
+	Arg   channelArgs     `json:"arg"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// streamOrder - ордер из push канала orders.
+type streamOrder struct {
+	orderDetails
+	TradeID string `json:"tradeId"`
+}
+
+func (s *OrderStream) session(ctx context.Context) error {
+	config, err := websocket.NewConfig(s.url, "https://www.okx.com")
+	if err != nil {
+		return fmt.Errorf("invalid stream url: %w", err)
+	}
+	config.Dialer = &net.Dialer{Timeout: streamDialTimeout}
+
+	conn, err := websocket.DialConfig(config)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close()
+
+	// Отмена ctx прерывает чтение закрытием соединения
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	if err := s.login(conn); err != nil {
+		return err
+	}
+	if err := websocket.JSON.Send(conn, streamRequest{
+		Op:   "subscribe",
+		Args: []interface{}{channelArgs{Channel: "orders", InstType: "SPOT"}},
+	}); err != nil {
+		return fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	s.setConnectedAt(time.Now())
+	log.Printf("OKX order stream connected")
+
+	for {
+		if err := conn.SetReadDeadline(time.Now().Add(streamPingInterval)); err != nil {
+			return err
+		}
+
+		var raw string
+		err := websocket.Message.Receive(conn, &raw)
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			if err := websocket.Message.Send(conn, "ping"); err != nil {
+				return fmt.Errorf("failed to ping: %w", err)
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if raw == "pong" {
+			continue
+		}
+
+		if err := s.handleMessage([]byte(raw)); err != nil {
+			return err
+		}
+	}
+}
+
+// login подписывает вход так же, как REST запрос: timestamp + GET + /users/self/verify,
+// но с временем в секундах.
+func (s *OrderStream) login(conn *websocket.Conn) error {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	if err := websocket.JSON.Send(conn, streamRequest{
+		Op: "login",
+		Args: []interface{}{loginArgs{
+			APIKey:     s.client.apiKey,
+			Passphrase: s.client.passphrase,
+			Timestamp:  timestamp,
+			Sign:       s.client.createSignature(timestamp + "GET" + "/users/self/verify"),
+		}},
+	}); err != nil {
+		return fmt.Errorf("failed to send login: %w", err)
+	}
+
+	if err := conn.SetReadDeadline(time.Now().Add(streamDialTimeout)); err != nil {
+		return err
+	}
+	var reply streamMessage
+	if err := websocket.JSON.Receive(conn, &reply); err != nil {
+		return fmt.Errorf("failed to read login reply: %w", err)
+	}
+	if reply.Event != "login" || reply.Code != "0" {
+		return fmt.Errorf("okx login failed: code %s, msg: %s", reply.Code, reply.Msg)
+	}
+	return nil
+}
+
+// handleMessage передает обновления ордеров обработчику. Ошибка обработчика рвет
+// соединение: после переподключения пропущенное найдут опрос ордеров и сверка.
+func (s *OrderStream) handleMessage(raw []byte) error {
+	var message streamMessage
+	if err := json.Unmarshal(raw, &message); err != nil {
+		return fmt.Errorf("failed to decode message: %w", err)
+	}
+
+	switch {
+	case message.Event == "error":
+		return fmt.Errorf("okx stream error: code %s, msg: %s", message.Code, message.Msg)
+	case message.Event != "" || message.Arg.Channel != "orders" || len(message.Data) == 0:
+		return nil
+	}
+
+	if s.capture != nil {
+		if err := s.capture.Capture("okx_orders", raw); err != nil {
+			log.Printf("Failed to capture OKX order message: %v", err)
+		}
+	}
+
+	var orders []streamOrder
+	if err := json.Unmarshal(message.Data, &orders); err != nil {
+		return fmt.Errorf("failed to decode orders: %w", err)
+	}
+	for _, order := range orders {
+		update, execID := toOrderUpdate(order)
+		if err := s.handler(update, execID); err != nil {
+			return fmt.Errorf("order %s: %w", order.OrdID, err)
+		}
+	}
+	return nil
+}
+
+func toOrderUpdate(order streamOrder) (domain.OrderUpdate, string) {
+	response := toOrderResponse(strings.ReplaceAll(order.InstID, "-", ""), order.orderDetails)
+	timestamp, _ := strconv.ParseInt(order.UTime, 10, 64)
+	return domain.OrderUpdate{
+		OrderID:       response.OrderID,
+		Symbol:        response.Symbol,
+		Status:        domain.ParseOrderStatus(response.Status),
+		ExecutedQty:   response.ExecutedQty,
+		ExecutedValue: response.ExecutedValue,
+		Fee:           response.ExecutedFee,
+		Price:         response.Price,
+		Timestamp:     timestamp,
+	}, order.TradeID
+}
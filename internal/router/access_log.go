@@ -0,0 +1,160 @@
+package router
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"cryptorg/pkg/latency"
+
+	"github.com/valyala/fasthttp"
+)
+
+// accessLatencyWindow - размер скользящего окна задержек на маршрут.
+const accessLatencyWindow = 512
+
+type accessEntry struct {
+	Type          string  `json:"type"`
+	Method        string  `json:"method"`
+	Route         string  `json:"route"`
+	Path          string  `json:"path"`
+	Status        int     `json:"status"`
+	LatencyMs     float64 `json:"latency_ms"`
+	RequestBytes  int     `json:"request_bytes"`
+	ResponseBytes int     `json:"response_bytes"`
+	TokenID       string  `json:"token_id,omitempty"`
+	RemoteIP      string  `json:"remote_ip"`
+}
+
+type routeStats struct {
+	latency       *latency.Tracker
+	requests      int64
+	errors        int64 // Ответы 5xx
+	requestBytes  int64
+	responseBytes int64
+}
+
+// AccessStats копит по маршрутам число запросов, объем данных и скользящее окно задержек.
+type AccessStats struct {
+	mu     sync.Mutex
+	routes map[string]*routeStats
+	since  time.Time
+}
+
+func NewAccessStats() *AccessStats {
+	return &AccessStats{
+		routes: make(map[string]*routeStats),
+		since:  time.Now(),
+	}
+}
+
+func (s *AccessStats) record(key string, status int, elapsed time.Duration, requestBytes, responseBytes int) {
+	s.mu.Lock()
+	stats, ok := s.routes[key]
+	if !ok {
+		stats = &routeStats{latency: latency.NewTracker(accessLatencyWindow)}
+		s.routes[key] = stats
+	}
+	stats.requests++
+	if status >= 500 {
+		stats.errors++
+	}
+	stats.requestBytes += int64(requestBytes)
+	stats.responseBytes += int64(responseBytes)
+	s.mu.Unlock()
+
+	stats.latency.Record(elapsed)
+}
+
+// Summary возвращает статистику по маршрутам, отсортированную по числу запросов.
+func (s *AccessStats) Summary() map[string]interface{} {
+	s.mu.Lock()
+	keys := make([]string, 0, len(s.routes))
+	for key := range s.routes {
+		keys = append(keys, key)
+	}
+	snapshot := make(map[string]routeStats, len(s.routes))
+	for key, stats := range s.routes {
+		snapshot[key] = *stats
+	}
+	s.mu.Unlock()
+
+	sort.Slice(keys, func(i, j int) bool { return snapshot[keys[i]].requests > snapshot[keys[j]].requests })
+
+	routes := make([]map[string]interface{}, 0, len(keys))
+	for _, key := range keys {
+		stats := snapshot[key]
+		routes = append(routes, map[string]interface{}{
+			"route":          key,
+			"requests":       stats.requests,
+			"errors":         stats.errors,
+			"request_bytes":  stats.requestBytes,
+			"response_bytes": stats.responseBytes,
+			"p50_ms":         stats.latency.Percentile(50).Milliseconds(),
+			"p90_ms":         stats.latency.Percentile(90).Milliseconds(),
+			"p99_ms":         stats.latency.Percentile(99).Milliseconds(),
+			"window":         stats.latency.Count(),
+		})
+	}
+
+	return map[string]interface{}{
+		"since":  s.since,
+		"routes": routes,
+	}
+}
+
+// logAccess пишет строку журнала доступа в JSON и обновляет статистику маршрута.
+func (r *Router) logAccess(ctx *fasthttp.RequestCtx, method, path string, start time.Time) {
+	elapsed := time.Since(start)
+	status := ctx.Response.StatusCode()
+	requestBytes := len(ctx.Request.Body())
+	responseBytes := len(ctx.Response.Body())
+
+	r.accessStats.record(method+" "+path, status, elapsed, requestBytes, responseBytes)
+
+	if !r.accessLog {
+		return
+	}
+
+	entry, err := json.Marshal(accessEntry{
+		Type:          "access",
+		Method:        method,
+		Route:         path,
+		Path:          string(ctx.Path()),
+		Status:        status,
+		LatencyMs:     float64(elapsed.Microseconds()) / 1000,
+		RequestBytes:  requestBytes,
+		ResponseBytes: responseBytes,
+		TokenID:       tokenID(ctx),
+		RemoteIP:      ctx.RemoteIP().String(),
+	})
+	if err != nil {
+		return
+	}
+	log.Print(string(entry))
+}
+
+// tokenID - отпечаток токена из Authorization: в журнал попадает хеш, а не сам токен.
+func tokenID(ctx *fasthttp.RequestCtx) string {
+	header := strings.TrimSpace(string(ctx.Request.Header.Peek("Authorization")))
+	if header == "" {
+		return ""
+	}
+	if token, ok := strings.CutPrefix(header, "Bearer "); ok {
+		header = strings.TrimSpace(token)
+	}
+
+	sum := sha256.Sum256([]byte(header))
+	return hex.EncodeToString(sum[:4])
+}
+
+func (r *Router) httpStats(ctx *fasthttp.RequestCtx) {
+	ctx.Response.Header.Set("Content-Type", "application/json")
+	ctx.Response.SetStatusCode(200)
+	json.NewEncoder(ctx).Encode(r.accessStats.Summary())
+}
@@ -0,0 +1,106 @@
+package router
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"cryptorg/internal/i18n"
+
+	"github.com/valyala/fasthttp"
+)
+
+// Роли API ключей: read - только чтение (GET), trade - торговые операции, admin - все,
+// включая /api/admin и подтверждение сделок.
+const (
+	RoleRead  = "read"
+	RoleTrade = "trade"
+	RoleAdmin = "admin"
+)
+
+// selfAuthenticated - маршруты /api со своей проверкой подлинности, которым ключ не нужен.
+func (r *Router) selfAuthenticated(path string) bool {
+	switch path {
+	case "/api/telegram/webhook":
+		return true
+	case "/api/webhook/order-update":
+		return r.tradeController.WebhookSigned()
+	}
+	return false
+}
+
+// adminOnly - маршруты, доступные только ключу с ролью admin.
+func adminOnly(path string) bool {
+	if strings.HasPrefix(path, "/api/admin/") {
+		return true
+	}
+	return strings.HasPrefix(path, "/api/trades/") && strings.HasSuffix(path, "/approve")
+}
+
+// apiKey - ключ доступа к /api и его роль.
+type apiKey struct {
+	token []byte
+	role  string
+}
+
+func newAPIKeys(keys map[string]string) []apiKey {
+	result := make([]apiKey, 0, len(keys))
+	for token, role := range keys {
+		result = append(result, apiKey{token: []byte(token), role: role})
+	}
+	return result
+}
+
+// authorize проверяет ключ из Authorization: Bearer или X-API-Key для маршрутов /api.
+// Без настроенных ключей проверка выключена. При отказе ответ уже записан.
+func (r *Router) authorize(ctx *fasthttp.RequestCtx, method string, route route) bool {
+	if len(r.apiKeys) == 0 || !strings.HasPrefix(route.path, "/api/") || r.selfAuthenticated(route.path) {
+		return true
+	}
+
+	role := r.keyRole(requestToken(ctx))
+	switch {
+	case role == "":
+		r.sendAuthError(ctx, 401, "API key required")
+		return false
+	case role == RoleRead && method != "GET":
+		r.sendAuthError(ctx, 403, "API key is read-only")
+		return false
+	case role != RoleAdmin && adminOnly(route.path):
+		r.sendAuthError(ctx, 403, "API key has no admin role")
+		return false
+	}
+	return true
+}
+
+// keyRole сравнивает токен со всеми ключами за постоянное время и возвращает роль совпавшего.
+func (r *Router) keyRole(token string) string {
+	if token == "" {
+		return ""
+	}
+	role := ""
+	for _, key := range r.apiKeys {
+		if subtle.ConstantTimeCompare([]byte(token), key.token) == 1 {
+			role = key.role
+		}
+	}
+	return role
+}
+
+func requestToken(ctx *fasthttp.RequestCtx) string {
+	if key := strings.TrimSpace(string(ctx.Request.Header.Peek("X-API-Key"))); key != "" {
+		return key
+	}
+	token, _ := strings.CutPrefix(strings.TrimSpace(string(ctx.Request.Header.Peek("Authorization"))), "Bearer ")
+	return strings.TrimSpace(token)
+}
+
+func (r *Router) sendAuthError(ctx *fasthttp.RequestCtx, status int, message string) {
+	ctx.Response.Header.Set("Content-Type", "application/json")
+	ctx.Response.SetStatusCode(status)
+	if status == 401 {
+		ctx.Response.Header.Set("WWW-Authenticate", `Bearer realm="api"`)
+	}
+	lang := i18n.FromAcceptLanguage(string(ctx.Request.Header.Peek(fasthttp.HeaderAcceptLanguage)), i18n.Default())
+	ctx.Response.Header.Set(fasthttp.HeaderContentLanguage, string(lang))
+	ctx.Response.SetBodyString(`{"error": "` + i18n.T(lang, message) + `"}`)
+}
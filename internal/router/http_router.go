@@ -2,17 +2,37 @@ package router
 
 import (
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"cryptorg/internal/handler"
+	"cryptorg/internal/metrics"
+	"cryptorg/internal/tracing"
 
 	"github.com/valyala/fasthttp"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type Router struct {
-	orderController *handler.OrderHandler
-	tradeController *handler.TradeHandler
-	routes          []route
+	orderController      *handler.OrderHandler
+	tradeController      *handler.TradeHandler
+	statusController     *handler.StatusHandler
+	adminController      *handler.AdminHandler
+	signalController     *handler.SignalHandler
+	rebalancerController *handler.RebalancerHandler
+	botController        *handler.BotHandler
+	telegramController   *handler.TelegramHandler
+	toolsController      *handler.ToolsHandler
+	reportController     *handler.ReportHandler
+	marketController     *handler.MarketHandler
+	metrics              metrics.Recorder
+	routes               []route
+	accessLog            bool // Писать строку журнала доступа на каждый запрос
+	accessStats          *AccessStats
+	defaultNumberFormat  string   // NumberFormatString или NumberFormatNumber
+	publicStats          bool     // Открыть /public/stats
+	apiKeys              []apiKey // Ключи доступа к /api; пусто - без проверки
 }
 
 type route struct {
@@ -20,13 +40,29 @@ type route struct {
 	pattern *regexp.Regexp
 	handler fasthttp.RequestHandler
 	params  []string
+	path    string
 }
 
-func NewRouter(orderController *handler.OrderHandler, tradeController *handler.TradeHandler) *Router {
+func NewRouter(orderController *handler.OrderHandler, tradeController *handler.TradeHandler, statusController *handler.StatusHandler, adminController *handler.AdminHandler, signalController *handler.SignalHandler, rebalancerController *handler.RebalancerHandler, botController *handler.BotHandler, telegramController *handler.TelegramHandler, toolsController *handler.ToolsHandler, reportController *handler.ReportHandler, marketController *handler.MarketHandler, recorder metrics.Recorder, accessLog bool, numberFormat string, publicStats bool, apiKeys map[string]string) *Router {
 	r := &Router{
-		orderController: orderController,
-		tradeController: tradeController,
-		routes:          make([]route, 0),
+		orderController:      orderController,
+		tradeController:      tradeController,
+		statusController:     statusController,
+		adminController:      adminController,
+		signalController:     signalController,
+		rebalancerController: rebalancerController,
+		botController:        botController,
+		telegramController:   telegramController,
+		toolsController:      toolsController,
+		reportController:     reportController,
+		marketController:     marketController,
+		metrics:              recorder,
+		routes:               make([]route, 0),
+		accessLog:            accessLog,
+		accessStats:          NewAccessStats(),
+		defaultNumberFormat:  numberFormat,
+		publicStats:          publicStats,
+		apiKeys:              newAPIKeys(apiKeys),
 	}
 
 	r.setupRoutes()
@@ -43,6 +79,7 @@ func (r *Router) Handler(ctx *fasthttp.RequestCtx) {
 
 	method := string(ctx.Method())
 	path := string(ctx.Path())
+	start := time.Now()
 
 	for _, route := range r.routes {
 		if route.method == method {
@@ -53,7 +90,21 @@ func (r *Router) Handler(ctx *fasthttp.RequestCtx) {
 						ctx.SetUserValue(param, matches[i+1])
 					}
 				}
-				route.handler(ctx)
+				_, span := tracing.Start(ctx, method+" "+route.path,
+					attribute.String("http.method", method),
+					attribute.String("http.route", route.path),
+				)
+				tracing.Bind(ctx, span)
+
+				if r.authorize(ctx, method, route) {
+					r.serve(ctx, method, route)
+					r.renderNumbers(ctx)
+				}
+
+				span.SetAttributes(attribute.Int("http.status_code", ctx.Response.StatusCode()))
+				span.End()
+				r.observeRequest(ctx, method, route.path, start)
+				r.logAccess(ctx, method, route.path, start)
 				return
 			}
 		}
@@ -61,12 +112,24 @@ func (r *Router) Handler(ctx *fasthttp.RequestCtx) {
 	ctx.Response.SetStatusCode(404)
 	ctx.Response.Header.Set("Content-Type", "application/json")
 	ctx.Response.SetBodyString(`{"error": "Not Found", "message": "The requested resource was not found"}`)
+	r.logAccess(ctx, method, "not_found", start)
+}
+
+// observeRequest пишет метрики запроса с шаблоном маршрута в качестве метки,
+// чтобы идентификаторы из пути не раздували кардинальность.
+func (r *Router) observeRequest(ctx *fasthttp.RequestCtx, method, path string, start time.Time) {
+	labels := metrics.Labels{"method": method, "route": path}
+	r.metrics.ObserveHistogram("http_request_duration_seconds", metrics.Since(start), labels)
+
+	labels["status"] = strconv.Itoa(ctx.Response.StatusCode())
+	r.metrics.IncCounter("http_requests_total", labels)
 }
 
 func (r *Router) setupCORS(ctx *fasthttp.RequestCtx) {
 	ctx.Response.Header.Set("Access-Control-Allow-Origin", "*")
-	ctx.Response.Header.Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-	ctx.Response.Header.Set("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+	ctx.Response.Header.Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+	ctx.Response.Header.Set("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-API-Key, If-None-Match")
+	ctx.Response.Header.Set("Access-Control-Expose-Headers", "ETag")
 }
 
 func (r *Router) setupRoutes() {
@@ -76,20 +139,98 @@ func (r *Router) setupRoutes() {
 		ctx.Response.SetBodyString(`{"status": "ok", "service": "cryptorg-bot"}`)
 	})
 
+	r.addRoute("GET", "/api/status", r.cached(r.statusController.GetStatus))
+	r.addRoute("GET", "/api/version", r.statusController.GetVersion)
+
+	// Без авторизации и без чувствительных данных; по умолчанию выключено
+	if r.publicStats {
+		r.addRoute("GET", "/public/stats", r.cached(r.reportController.GetPublicStats))
+	}
+
+	if exporter, ok := r.metrics.(*metrics.PrometheusRecorder); ok {
+		r.addRoute("GET", "/metrics", func(ctx *fasthttp.RequestCtx) {
+			ctx.Response.Header.Set("Content-Type", "text/plain; version=0.0.4")
+			ctx.Response.SetStatusCode(200)
+			if err := exporter.Export(ctx); err != nil {
+			}
+		})
+	}
+
 	r.addRoute("POST", "/api/orders/market", r.orderController.ExecuteMarketOrder)
 	r.addRoute("POST", "/api/orders/limit", r.orderController.ExecuteLimitOrder)
 	r.addRoute("DELETE", "/api/orders/([^/]+)/([^/]+)", r.orderController.TerminateOrder)
+	r.addRoute("PATCH", "/api/orders/([^/]+)/([^/]+)", r.orderController.AmendOrder)
+	// Раньше общего маршрута /api/orders/{symbol}/{orderId}, иначе raw примется за orderId
+	r.addRoute("GET", "/api/orders/(?P<orderId>[^/]+)/raw", r.orderController.FetchRawPayloads)
 	r.addRoute("GET", "/api/orders/([^/]+)/([^/]+)", r.orderController.FetchOrderStatus)
 	r.addRoute("POST", "/api/orders/calculate-tp", r.orderController.ComputeTakeProfit)
 	r.addRoute("POST", "/api/orders/calculate-dca", r.orderController.ComputeDCAPrice)
 
+	r.addRoute("GET", "/api/account/balance", r.orderController.GetAccountBalance)
+
+	r.addRoute("GET", "/api/market/(?P<symbol>[^/]+)/ticker", r.marketController.GetTicker)
+	r.addRoute("GET", "/api/market/(?P<symbol>[^/]+)/orderbook", r.marketController.GetOrderBook)
+	r.addRoute("GET", "/api/market/(?P<symbol>[^/]+)/klines", r.marketController.GetKlines)
+
 	r.addRoute("POST", "/api/trades", r.tradeController.InitializeTrade)
-	r.addRoute("GET", "/api/trades", r.tradeController.GetAllTrades)
+	r.addRoute("POST", "/api/trades/preview", r.tradeController.PreviewTrade)
+	r.addRoute("GET", "/api/trades", r.cached(r.tradeController.GetAllTrades))
 	r.addRoute("POST", "/api/trades/([^/]+)/order-filled", r.tradeController.ProcessOrderExecution)
 	r.addRoute("POST", "/api/trades/([^/]+)/close", r.tradeController.CloseTrade)
+	r.addRoute("POST", "/api/trades/(?P<tradeId>[^/]+)/approve", r.tradeController.ApproveTrade)
+	r.addRoute("POST", "/api/trades/(?P<tradeId>[^/]+)/pause", r.tradeController.PauseTrade)
+	r.addRoute("POST", "/api/trades/(?P<tradeId>[^/]+)/resume", r.tradeController.ResumeTrade)
 	r.addRoute("GET", "/api/trades/([^/]+)", r.tradeController.GetTrade)
+	r.addRoute("POST", "/api/trades/(?P<tradeId>[^/]+)/annotations", r.tradeController.AddAnnotation)
+	r.addRoute("GET", "/api/trades/(?P<tradeId>[^/]+)/events", r.tradeController.GetTradeEvents)
+	r.addRoute("GET", "/api/trades/(?P<tradeId>[^/]+)/pnl", r.tradeController.GetTradePnL)
+
+	r.addRoute("POST", "/api/bots/bulk", r.tradeController.BulkCreateTrades)
+	r.addRoute("POST", "/api/bots", r.botController.CreateBot)
+	r.addRoute("GET", "/api/bots", r.botController.GetAllBots)
+	r.addRoute("GET", "/api/bots/(?P<botId>[^/]+)", r.botController.GetBot)
+	r.addRoute("PUT", "/api/bots/(?P<botId>[^/]+)", r.botController.UpdateBot)
+	r.addRoute("DELETE", "/api/bots/(?P<botId>[^/]+)", r.botController.DeleteBot)
+	r.addRoute("POST", "/api/bots/(?P<botId>[^/]+)/pause", r.botController.PauseBot)
+	r.addRoute("POST", "/api/bots/(?P<botId>[^/]+)/resume", r.botController.ResumeBot)
+
+	r.addRoute("POST", "/api/telegram/webhook", r.telegramController.Webhook)
+	r.addRoute("GET", "/api/events", r.tradeController.GetEvents)
+
+	r.addRoute("POST", "/api/portfolios", r.rebalancerController.CreatePortfolio)
+	r.addRoute("GET", "/api/portfolios", r.rebalancerController.GetAllPortfolios)
+	r.addRoute("GET", "/api/portfolios/(?P<portfolioId>[^/]+)", r.rebalancerController.GetPortfolio)
+	r.addRoute("POST", "/api/portfolios/(?P<portfolioId>[^/]+)/rebalance", r.rebalancerController.RebalancePortfolio)
+	r.addRoute("POST", "/api/portfolios/(?P<portfolioId>[^/]+)/stop", r.rebalancerController.StopPortfolio)
+
+	r.addRoute("GET", "/api/tools/suggest-config", r.toolsController.SuggestConfig)
+	r.addRoute("POST", "/api/backtest/compare", r.toolsController.CompareBacktest)
+	r.addRoute("GET", "/api/reports/summary", r.reportController.GetSummary)
+	r.addRoute("GET", "/api/stats/time-heatmap", r.reportController.GetTimeHeatmap)
+	r.addRoute("GET", "/api/stats/pnl", r.cached(r.reportController.GetPnLStats))
 
 	r.addRoute("POST", "/api/webhook/order-update", r.tradeController.WebhookOrderUpdate)
+	r.addRoute("POST", "/api/webhook/signal", r.signalController.ReceiveSignal)
+
+	r.addRoute("POST", "/api/admin/trades/(?P<tradeId>[^/]+)/force-status", r.adminController.ForceTradeStatus)
+	r.addRoute("GET", "/api/admin/notifications", r.adminController.GetNotifications)
+	r.addRoute("GET", "/api/admin/config", r.adminController.GetConfig)
+	r.addRoute("GET", "/api/admin/features", r.adminController.GetFeatures)
+	r.addRoute("POST", "/api/admin/backup", r.adminController.CreateBackup)
+	r.addRoute("GET", "/api/admin/raw-messages", r.adminController.GetRawMessages)
+	r.addRoute("GET", "/api/admin/http-stats", r.httpStats)
+	r.addRoute("GET", "/api/admin/rate-limits", r.adminController.GetRateLimits)
+	r.addRoute("GET", "/api/admin/consistency", r.adminController.GetConsistency)
+	r.addRoute("POST", "/api/admin/reconcile", r.adminController.Reconcile)
+	r.addRoute("GET", "/api/admin/symbol-lists", r.adminController.GetSymbolLists)
+	r.addRoute("PUT", "/api/admin/symbol-lists", r.adminController.UpdateSymbolLists)
+	r.addRoute("GET", "/api/admin/attention", r.adminController.GetAttention)
+	r.addRoute("POST", "/api/admin/trades/(?P<tradeId>[^/]+)/acknowledge", r.adminController.AcknowledgeAttention)
+	r.addRoute("POST", "/api/admin/recompute-pnl", r.adminController.RecomputePnL)
+	r.addRoute("GET", "/api/admin/pnl-revisions", r.adminController.GetPnLRevisions)
+
+	r.addRoute("GET", "/api/emergency/status", r.adminController.GetTradingStatus)
+	r.addRoute("POST", "/api/emergency/resume", r.adminController.ResumeTrading)
 }
 
 func (r *Router) addRoute(method, pattern string, handler fasthttp.RequestHandler) {
@@ -99,12 +240,24 @@ func (r *Router) addRoute(method, pattern string, handler fasthttp.RequestHandle
 		pattern: regex,
 		handler: handler,
 		params:  params,
+		path:    pattern,
 	})
 }
 
 func (r *Router) patternToRegex(pattern string) (*regexp.Regexp, []string) {
 	var params []string
 
+	// Именованные группы (?P<name>...) задают имена параметров явно
+	regex := regexp.MustCompile("^" + pattern + "$")
+	for _, name := range regex.SubexpNames()[1:] {
+		if name != "" {
+			params = append(params, name)
+		}
+	}
+	if len(params) > 0 {
+		return regex, params
+	}
+
 	groupCount := strings.Count(pattern, "([^/]+)")
 
 	if strings.Contains(pattern, "/api/orders/") && groupCount == 2 {
@@ -119,6 +272,5 @@ func (r *Router) patternToRegex(pattern string) (*regexp.Regexp, []string) {
 		}
 	}
 
-	regex := regexp.MustCompile("^" + pattern + "$")
 	return regex, params
 }
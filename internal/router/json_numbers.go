@@ -0,0 +1,99 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"regexp"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+// Форматы денежных значений в ответах: строки (по умолчанию, как хранится в домене)
+// или JSON числа с точным десятичным представлением без прохода через float64.
+const (
+	NumberFormatString = "string"
+	NumberFormatNumber = "number"
+)
+
+// decimalFields - поля с ценами, объемами и суммами, которые хранятся строками.
+var decimalFields = map[string]bool{
+	"price": true, "quantity": true, "executed_qty": true, "executed_value": true, "fee": true, "fees": true,
+	"trigger_price": true, "average_price": true, "current_price": true, "entry_price": true,
+	"take_profit_price": true, "total_invested": true, "current_position_qty": true,
+	"freed_capital": true, "entry_volume": true, "dca_volume": true, "max_budget": true,
+	"target_position_qty": true, "min_level_volume": true, "min_order_volume": true,
+	"start_price": true, "min_price": true, "max_price": true, "volume": true,
+	"required_capital": true, "total_required_capital": true, "total_budget": true,
+	"realized_pnl": true, "unrealized_pnl": true, "profit_target": true, "realized_profit": true, "max_drawdown": true, "max_capital_used": true,
+	"available": true, "requested_capital": true, "adjusted_capital": true, "requested_dca_volume": true,
+	"threshold": true, "break_even_price": true, "total": true, "locked": true, "free": true, "pnl_delta": true, "best_price": true, "expected_avg_price": true, "filled_volume": true, "volume_ahead": true,
+	"last_price": true, "bid_price": true, "ask_price": true, "high_price_24h": true, "low_price_24h": true,
+	"volume_24h": true, "turnover_24h": true, "size": true, "open": true, "high": true, "low": true, "close": true,
+}
+
+var decimalPattern = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+// numberFormat выбирает формат по профилю Accept (application/json; profile=number|string),
+// иначе берется настройка JSON_NUMBER_FORMAT.
+func (r *Router) numberFormat(ctx *fasthttp.RequestCtx) string {
+	for _, accept := range strings.Split(string(ctx.Request.Header.Peek("Accept")), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil || (mediaType != "application/json" && mediaType != "*/*") {
+			continue
+		}
+		switch params["profile"] {
+		case NumberFormatNumber, NumberFormatString:
+			return params["profile"]
+		}
+	}
+	return r.defaultNumberFormat
+}
+
+// renderNumbers переписывает строковые десятичные поля JSON ответа в числа, если клиент
+// выбрал формат number. Сжатые и не-JSON ответы не трогаются.
+func (r *Router) renderNumbers(ctx *fasthttp.RequestCtx) {
+	if r.numberFormat(ctx) != NumberFormatNumber {
+		return
+	}
+	if len(ctx.Response.Header.Peek("Content-Encoding")) > 0 ||
+		!bytes.HasPrefix(ctx.Response.Header.ContentType(), []byte("application/json")) {
+		return
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(ctx.Response.Body()))
+	decoder.UseNumber()
+
+	var body interface{}
+	if err := decoder.Decode(&body); err != nil {
+		return
+	}
+
+	data, err := json.Marshal(decimalsToNumbers(body, ""))
+	if err != nil {
+		return
+	}
+	ctx.Response.SetBody(append(data, '\n'))
+}
+
+func decimalsToNumbers(value interface{}, key string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			v[k] = decimalsToNumbers(item, k)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = decimalsToNumbers(item, key)
+		}
+		return v
+	case string:
+		if decimalFields[key] && decimalPattern.MatchString(v) {
+			// json.Number выводится как есть, все знаки после запятой сохраняются
+			return json.Number(v)
+		}
+	}
+	return value
+}
@@ -0,0 +1,38 @@
+package router
+
+import (
+	"fmt"
+	"log"
+	"runtime/debug"
+
+	"cryptorg/internal/i18n"
+	"cryptorg/internal/metrics"
+
+	"github.com/valyala/fasthttp"
+)
+
+// serve вызывает обработчик маршрута и перехватывает его панику: запрос получает 500,
+// стек пишется в журнал, а сделка из пути маршрута помечается для ручной проверки.
+func (r *Router) serve(ctx *fasthttp.RequestCtx, method string, route route) {
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+
+		log.Printf("PANIC: %s %s: %v\n%s", method, route.path, recovered, debug.Stack())
+		r.metrics.IncCounter("http_panics_total", metrics.Labels{"route": route.path})
+
+		ctx.Response.Header.Set("Content-Type", "application/json")
+		ctx.Response.SetStatusCode(500)
+		lang := i18n.FromAcceptLanguage(string(ctx.Request.Header.Peek(fasthttp.HeaderAcceptLanguage)), i18n.Default())
+		ctx.Response.Header.Set(fasthttp.HeaderContentLanguage, string(lang))
+		ctx.Response.SetBodyString(`{"error": "` + i18n.T(lang, "Internal server error") + `"}`)
+
+		if tradeID, ok := ctx.UserValue("tradeId").(string); ok {
+			r.tradeController.FlagPanic(tradeID, fmt.Sprintf("panic in %s %s: %v", method, route.path, recovered))
+		}
+	}()
+
+	route.handler(ctx)
+}
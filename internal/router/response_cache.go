@@ -0,0 +1,45 @@
+package router
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/valyala/fasthttp"
+)
+
+// cached добавляет ETag/If-None-Match и gzip/deflate сжатие для тяжелых GET ответов.
+func (r *Router) cached(handler fasthttp.RequestHandler) fasthttp.RequestHandler {
+	withETag := func(ctx *fasthttp.RequestCtx) {
+		handler(ctx)
+		// До расчета ETag и сжатия: представление зависит от выбранного формата чисел
+		r.renderNumbers(ctx)
+
+		if ctx.Response.StatusCode() != fasthttp.StatusOK {
+			return
+		}
+
+		sum := sha256.Sum256(ctx.Response.Body())
+		etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+		ctx.Response.Header.Set("ETag", etag)
+		ctx.Response.Header.Set("Cache-Control", "no-cache")
+
+		if match := ctx.Request.Header.Peek("If-None-Match"); len(match) > 0 && etagMatches(match, etag) {
+			ctx.Response.ResetBody()
+			ctx.Response.SetStatusCode(fasthttp.StatusNotModified)
+		}
+	}
+
+	return fasthttp.CompressHandlerLevel(withETag, fasthttp.CompressDefaultCompression)
+}
+
+func etagMatches(header []byte, etag string) bool {
+	for _, candidate := range bytes.Split(header, []byte(",")) {
+		candidate = bytes.TrimSpace(candidate)
+		if string(candidate) == "*" || string(candidate) == etag ||
+			string(candidate) == etag[2:] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"cryptorg/internal/bybit"
+	"cryptorg/internal/domain"
+)
+
+const (
+	DefaultBacktestInterval = "60"
+	maxBacktestCandles      = 20000 // Ограничение длины серии, чтобы не выкачивать историю бесконечно
+)
+
+// backtestIntervals - длительность свечи для поддерживаемых интервалов Bybit.
+var backtestIntervals = map[string]time.Duration{
+	"1": time.Minute, "3": 3 * time.Minute, "5": 5 * time.Minute, "15": 15 * time.Minute,
+	"30": 30 * time.Minute, "60": time.Hour, "120": 2 * time.Hour, "240": 4 * time.Hour,
+	"360": 6 * time.Hour, "720": 12 * time.Hour, "D": 24 * time.Hour,
+}
+
+// BacktestService прогоняет конфиги сделок по историческим свечам биржи.
+type BacktestService struct {
+	orderManager *OrderService
+}
+
+func NewBacktestManager(orderManager *OrderService) *BacktestService {
+	return &BacktestService{
+		orderManager: orderManager,
+	}
+}
+
+// ValidBacktestRange проверяет интервал и длину периода до запроса истории.
+func ValidBacktestRange(interval string, from, to time.Time) error {
+	step, ok := backtestIntervals[interval]
+	if !ok {
+		return fmt.Errorf("unsupported interval %s", interval)
+	}
+	if !to.After(from) {
+		return fmt.Errorf("range end must be after range start")
+	}
+	if candles := to.Sub(from) / step; candles > maxBacktestCandles {
+		return fmt.Errorf("range covers %d candles, at most %d allowed", candles, maxBacktestCandles)
+	}
+	return nil
+}
+
+// Compare прогоняет оба конфига по одной серии свечей [from, to).
+func (s *BacktestService) Compare(ctx context.Context, req domain.BacktestCompareRequest, from, to time.Time) (*domain.BacktestComparison, error) {
+	if err := ValidBacktestRange(req.Interval, from, to); err != nil {
+		return nil, err
+	}
+
+	klines, err := s.orderManager.FetchKlinesRange(ctx, req.Symbol, req.Interval, from, to.Add(-time.Millisecond))
+	if err != nil {
+		return nil, err
+	}
+	if len(klines) == 0 {
+		return nil, fmt.Errorf("no candles for %s between %s and %s", req.Symbol, req.From, req.To)
+	}
+
+	return &domain.BacktestComparison{
+		Symbol:   req.Symbol,
+		Interval: req.Interval,
+		From:     req.From,
+		To:       req.To,
+		Candles:  len(klines),
+		A:        RunBacktest(req.A, klines),
+		B:        RunBacktest(req.B, klines),
+	}, nil
+}
+
+// RunBacktest моделирует циклы стратегии price_step по свечам. Вход - по открытию свечи,
+// уровни сетки исполняются, если минимум свечи до них дошел. Внутри свечи порядок
+// пессимистичный: сначала DCA, затем SL, и только потом TP от новой средней цены.
+// Комиссии, условия старта, ассистент выхода и перестановка сетки не моделируются.
+func RunBacktest(config domain.TradeConfig, klines []bybit.Kline) *domain.BacktestReport {
+	report := &domain.BacktestReport{}
+	if len(klines) == 0 {
+		return report
+	}
+
+	entryVolume, _ := strconv.ParseFloat(config.EntryVolume, 64)
+	required := requiredCapital(config, BuildGrid(config, domain.DecimalFromFloat(klines[0].Open)))
+
+	var (
+		realized, quantity, cost   float64
+		maxCapital, peak, drawdown float64
+		inPosition, retired        bool
+		levels                     []domain.GridLevel
+		next                       int
+	)
+
+	for _, k := range klines {
+		if !inPosition && !retired && entryVolume > 0 && withinPriceBounds(config, domain.DecimalFromFloat(k.Open)) {
+			inPosition = true
+			quantity, cost = entryVolume/k.Open, entryVolume
+			levels, next = BuildGrid(config, domain.DecimalFromFloat(k.Open)), 0
+		}
+
+		if inPosition {
+			for ; next < len(levels); next++ {
+				price, _ := strconv.ParseFloat(levels[next].Price, 64)
+				if price <= 0 || k.Low > price {
+					break
+				}
+				// Уровни вне MinPrice/MaxPrice в работе стоят на паузе
+				if !withinPriceBounds(config, domain.DecimalFromFloat(price)) {
+					continue
+				}
+				volume, _ := strconv.ParseFloat(levels[next].Volume, 64)
+				quantity += volume / price
+				cost += volume
+				report.DCAFills++
+			}
+			maxCapital = math.Max(maxCapital, cost)
+
+			average := cost / quantity
+			exitPrice, stoppedOut := 0.0, false
+			if config.StopLossPercent > 0 && k.Low <= average*(1-config.StopLossPercent/100) {
+				exitPrice, stoppedOut = average*(1-config.StopLossPercent/100), true
+			} else if tpPrice := takeProfitPrice(config, domain.DecimalFromFloat(average)).InexactFloat64(); k.High >= tpPrice {
+				exitPrice = tpPrice
+			}
+
+			if exitPrice > 0 {
+				realized += quantity*exitPrice - cost
+				if entryFee, exitFee, ok := tradeFees(config); ok {
+					realized -= cost*entryFee + quantity*exitPrice*exitFee
+				}
+				quantity, cost, inPosition = 0, 0, false
+				report.Cycles++
+				if stoppedOut {
+					report.StopLosses++
+				}
+				retired = !config.AutoRestart ||
+					(config.MaxCycles > 0 && report.Cycles >= config.MaxCycles) ||
+					(stoppedOut && config.StopAfterLoss)
+			}
+		}
+
+		// Equity считается по закрытию свечи: реализованный PnL плюс переоценка позиции
+		equity := realized
+		if inPosition {
+			equity += quantity*k.Close - cost
+		}
+		peak = math.Max(peak, equity)
+		drawdown = math.Max(drawdown, peak-equity)
+	}
+
+	unrealized := 0.0
+	if inPosition {
+		unrealized = quantity*klines[len(klines)-1].Close - cost
+	}
+
+	report.RealizedPnL = fmt.Sprintf("%.8f", realized)
+	report.UnrealizedPnL = fmt.Sprintf("%.8f", unrealized)
+	report.MaxDrawdown = fmt.Sprintf("%.8f", drawdown)
+	report.MaxCapitalUsed = fmt.Sprintf("%.8f", maxCapital)
+	report.RequiredCapital = fmt.Sprintf("%.8f", required)
+	report.OpenAtEnd = inPosition
+	if maxCapital > 0 {
+		report.PnLPercent = (realized + unrealized) / maxCapital * 100
+		report.MaxDrawdownPercent = drawdown / maxCapital * 100
+	}
+	if required > 0 {
+		report.CapitalUsagePercent = maxCapital / required * 100
+	}
+	return report
+}
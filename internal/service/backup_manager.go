@@ -0,0 +1,111 @@
+package service
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"cryptorg/internal/domain"
+	"cryptorg/internal/storage"
+)
+
+// BackupService снимает копии хранилища в каталог BACKUP_DIR. Восстановление выполняется
+// при остановленном боте командой cmd/backup restore.
+type BackupService struct {
+	journal        domain.EventJournal
+	precisionStore domain.PrecisionStore
+	botStore       domain.BotStore
+	dir            string
+}
+
+func NewBackupManager(journal domain.EventJournal, precisionStore domain.PrecisionStore, botStore domain.BotStore, dir string) *BackupService {
+	return &BackupService{
+		journal:        journal,
+		precisionStore: precisionStore,
+		botStore:       botStore,
+		dir:            dir,
+	}
+}
+
+// Create записывает копию событий с Seq > sinceSeq (0 - полная копия) и возвращает путь к файлу.
+func (s *BackupService) Create(sinceSeq uint64) (string, *domain.StorageBackup, error) {
+	backup, err := BuildBackup(s.journal, s.precisionStore, s.botStore, sinceSeq)
+	if err != nil {
+		return "", nil, err
+	}
+
+	name := fmt.Sprintf("backup-%s-%d-%d.json", backup.CreatedAt.Format("20060102T150405Z"), backup.FromSeq, backup.LastSeq)
+	path := filepath.Join(s.dir, name)
+	if err := storage.WriteBackupFile(path, backup); err != nil {
+		return "", nil, err
+	}
+
+	return path, backup, nil
+}
+
+// BuildBackup собирает копию из журнала, хранилища точности и ботов.
+func BuildBackup(journal domain.EventJournal, precisionStore domain.PrecisionStore, botStore domain.BotStore, sinceSeq uint64) (*domain.StorageBackup, error) {
+	events, err := journal.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	overrides, err := precisionStore.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load precision overrides: %w", err)
+	}
+
+	bots, err := botStore.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load bots: %w", err)
+	}
+
+	backup := &domain.StorageBackup{
+		Version:            domain.BackupFormatVersion,
+		CreatedAt:          time.Now().UTC(),
+		FromSeq:            sinceSeq,
+		LastSeq:            sinceSeq,
+		Events:             make([]domain.TradeEvent, 0),
+		PrecisionOverrides: overrides,
+		Bots:               bots,
+	}
+
+	var journalSeq uint64
+	if len(events) > 0 {
+		journalSeq = events[len(events)-1].Seq
+	}
+	if journalSeq < sinceSeq {
+		return nil, fmt.Errorf("since_seq %d is beyond the last journal event %d", sinceSeq, journalSeq)
+	}
+
+	for _, event := range events {
+		if event.Seq <= sinceSeq {
+			continue
+		}
+		backup.Events = append(backup.Events, event)
+		backup.LastSeq = event.Seq
+	}
+
+	return backup, nil
+}
+
+// RestoreTrades проигрывает восстановленный журнал и сохраняет открытые сделки в repository:
+// после перезапуска бот поднимает сделки из него, а не из журнала.
+func RestoreTrades(events []domain.TradeEvent, repository domain.TradeRepository) (int, error) {
+	trades, err := ReplayEvents(events, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	restored := 0
+	for _, trade := range trades {
+		if !trade.Status.IsOpen() {
+			continue
+		}
+		if err := repository.Save(trade); err != nil {
+			return 0, fmt.Errorf("failed to save trade %s: %w", trade.ID, err)
+		}
+		restored++
+	}
+	return restored, nil
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"cryptorg/internal/domain"
+	"cryptorg/internal/storage"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// applyFills проводит исполнения так же, как поток ордеров: кэш, затем обработка сделки.
+func applyFills(t *testing.T, trades *TradeService, updates []domain.OrderUpdate) {
+	t.Helper()
+
+	for _, update := range updates {
+		trades.ApplyOrderUpdate(update)
+		trade, err := trades.FindTradeByOrderID(update.OrderID)
+		require.NoError(t, err)
+		require.NoError(t, trades.ProcessOrderExecution(context.Background(), trade.ID, update.OrderID, update.OrderID+"-exec"))
+	}
+}
+
+// assertSameJSON сравнивает значения через JSON: время после чтения из файла теряет
+// монотонные часы, и прямое сравнение time.Time не проходит.
+func assertSameJSON(t *testing.T, want, got interface{}) {
+	t.Helper()
+
+	wantJSON, err := json.Marshal(want)
+	require.NoError(t, err)
+	gotJSON, err := json.Marshal(got)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(wantJSON), string(gotJSON))
+}
+
+func sortedTrades(trades []*domain.Trade) []*domain.Trade {
+	sort.Slice(trades, func(i, j int) bool { return trades[i].ID.String() < trades[j].ID.String() })
+	return trades
+}
+
+func TestBackupRestoreRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	exchange := newFakeExchange(map[string]float64{"BTCUSDT": 100})
+	journal := storage.NewMemoryJournal()
+	repository := storage.NewMemoryTradeRepository()
+	trades := newFakeTradeService(t, exchange, journal, repository)
+
+	decimals := 4
+	precisionStore := storage.NewMemoryPrecisionStore()
+	require.NoError(t, precisionStore.Save(map[string]domain.PrecisionOverride{
+		"BTCUSDT": {Symbol: "BTCUSDT", QtyDecimals: &decimals, UpdatedAt: time.Now().UTC()},
+	}))
+	botID := uuid.New()
+	botStore := storage.NewMemoryBotStore()
+	require.NoError(t, botStore.Save(map[uuid.UUID]domain.Bot{
+		botID: {
+			ID:                 botID,
+			Name:               "btc ladder",
+			Symbol:             "BTCUSDT",
+			Config:             testTradeConfig(),
+			MaxConcurrentDeals: 2,
+			Enabled:            true,
+			CreatedAt:          time.Now().UTC(),
+			UpdatedAt:          time.Now().UTC(),
+		},
+	}))
+
+	// Первая сделка закрывается по TP до полной копии
+	closed, err := trades.InitializeTrade(ctx, testTradeConfig())
+	require.NoError(t, err)
+	applyFills(t, trades, exchange.setPrice("BTCUSDT", 110))
+
+	backups := NewBackupManager(journal, precisionStore, botStore, t.TempDir())
+	fullPath, full, err := backups.Create(0)
+	require.NoError(t, err)
+	require.NotEmpty(t, full.Events)
+
+	// Вторая сделка открыта и набрала уровень DCA - она попадает только в инкрементальную копию
+	open, err := trades.InitializeTrade(ctx, testTradeConfig())
+	require.NoError(t, err)
+	grid := BuildGrid(open.Config, domain.DecimalFromFloat(110))
+	applyFills(t, trades, exchange.setPrice("BTCUSDT", parseFake(grid[0].Price)))
+
+	incrementalPath, incremental, err := backups.Create(full.LastSeq)
+	require.NoError(t, err)
+	require.NotEmpty(t, incremental.Events)
+
+	// Восстановление с диска в пустой каталог
+	chain := make([]*domain.StorageBackup, 0, 2)
+	for _, path := range []string{fullPath, incrementalPath} {
+		backup, err := storage.ReadBackupFile(path)
+		require.NoError(t, err)
+		chain = append(chain, backup)
+	}
+
+	dir := t.TempDir()
+	journalPath := filepath.Join(dir, "journal.jsonl")
+	precisionPath := filepath.Join(dir, "precision.json")
+	botsPath := filepath.Join(dir, "bots.json")
+	require.NoError(t, storage.RestoreBackups(chain, journalPath, precisionPath, botsPath))
+
+	wantEvents, err := journal.ReadAll()
+	require.NoError(t, err)
+	gotEvents, err := storage.ReadJournalFile(journalPath)
+	require.NoError(t, err)
+	assertSameJSON(t, wantEvents, gotEvents)
+
+	restoredRepository, err := storage.NewFileTradeRepository(filepath.Join(dir, "trades.json"))
+	require.NoError(t, err)
+	restored, err := RestoreTrades(gotEvents, restoredRepository)
+	require.NoError(t, err)
+	assert.Equal(t, 1, restored)
+
+	wantTrades, err := repository.LoadActive()
+	require.NoError(t, err)
+	gotTrades, err := restoredRepository.LoadActive()
+	require.NoError(t, err)
+	require.Len(t, gotTrades, 1)
+	assert.Equal(t, open.ID, gotTrades[0].ID)
+	assertSameJSON(t, sortedTrades(wantTrades), sortedTrades(gotTrades))
+
+	// Закрытая сделка осталась в истории журнала
+	history, err := ReplayEvents(gotEvents, 0)
+	require.NoError(t, err)
+	require.Contains(t, history, closed.ID)
+	assert.Equal(t, domain.TradeStatusCompleted, history[closed.ID].Status)
+
+	wantOverrides, err := precisionStore.Load()
+	require.NoError(t, err)
+	gotOverrides, err := storage.NewFilePrecisionStore(precisionPath)
+	require.NoError(t, err)
+	loadedOverrides, err := gotOverrides.Load()
+	require.NoError(t, err)
+	assertSameJSON(t, wantOverrides, loadedOverrides)
+
+	wantBots, err := botStore.Load()
+	require.NoError(t, err)
+	gotBots, err := storage.NewFileBotStore(botsPath)
+	require.NoError(t, err)
+	loadedBots, err := gotBots.Load()
+	require.NoError(t, err)
+	assertSameJSON(t, wantBots, loadedBots)
+
+	// Бот поднимает восстановленную сделку и продолжает ее вести
+	node := newFakeTradeService(t, exchange, storage.NewMemoryJournal(), restoredRepository)
+	loaded, err := node.LoadTrades()
+	require.NoError(t, err)
+	assert.Equal(t, 1, loaded)
+	trade, err := node.GetTrade(open.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.TradeStatusActive, trade.Status)
+}
+
+func TestRestoreBackupsKeepsBotsOfOldBackups(t *testing.T) {
+	dir := t.TempDir()
+	botsPath := filepath.Join(dir, "bots.json")
+	botID := uuid.New()
+	store, err := storage.NewFileBotStore(botsPath)
+	require.NoError(t, err)
+	require.NoError(t, store.Save(map[uuid.UUID]domain.Bot{botID: {ID: botID, Name: "kept"}}))
+
+	// Копия без поля bots (старый формат) файл ботов не стирает
+	old := &domain.StorageBackup{Version: domain.BackupFormatVersion, Events: []domain.TradeEvent{}}
+	require.NoError(t, storage.RestoreBackups([]*domain.StorageBackup{old}, filepath.Join(dir, "journal.jsonl"), "", botsPath))
+
+	bots, err := store.Load()
+	require.NoError(t, err)
+	assert.Contains(t, bots, botID)
+}
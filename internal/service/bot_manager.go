@@ -0,0 +1,387 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"cryptorg/internal/bybit"
+	"cryptorg/internal/domain"
+	"cryptorg/internal/notify"
+	apperrors "cryptorg/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// BotService ведет ботов - шаблоны сделок, которые открывают новые сделки сами, как
+// классический DCA-бот. Свободные места проверяет планировщик, поэтому следующий цикл
+// открывается на ближайшем тике после закрытия сделки.
+type BotService struct {
+	tradeManager *TradeService
+	store        domain.BotStore
+	bots         map[uuid.UUID]*domain.Bot
+	mu           sync.RWMutex
+	starting     sync.Mutex // Не дает API и планировщику одновременно добирать сделки
+}
+
+func NewBotManager(tradeManager *TradeService, store domain.BotStore) (*BotService, error) {
+	stored, err := store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load bots: %w", err)
+	}
+
+	bots := make(map[uuid.UUID]*domain.Bot, len(stored))
+	for id, bot := range stored {
+		bot := bot
+		bots[id] = &bot
+	}
+
+	manager := &BotService{
+		tradeManager: tradeManager,
+		store:        store,
+		bots:         bots,
+	}
+	tradeManager.OnTradeClosed(manager.settleTrade)
+	return manager, nil
+}
+
+// CreateBot сохраняет бота и, если он включен, сразу открывает первые сделки.
+// Ошибка открытия не отменяет создание: она видна в LastError, а попытка повторится.
+func (s *BotService) CreateBot(ctx context.Context, req domain.BotRequest) (*domain.Bot, error) {
+	if err := s.tradeManager.checkExchange(req.Exchange); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	bot := &domain.Bot{
+		ID:             uuid.New(),
+		RealizedProfit: fmt.Sprintf("%.8f", 0.0),
+		CreatedAt:      now,
+	}
+	applyBotRequest(bot, req)
+
+	s.mu.Lock()
+	s.bots[bot.ID] = bot
+	err := s.persist()
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("AUDIT: bot %s created for %s, max concurrent deals %d", bot.ID, bot.Symbol, bot.MaxConcurrentDeals)
+	s.startDeals(ctx, bot.ID)
+	return s.GetBot(bot.ID)
+}
+
+func (s *BotService) GetBot(id uuid.UUID) (*domain.Bot, error) {
+	open := s.tradeManager.OpenTradesByBot()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	bot, exists := s.bots[id]
+	if !exists {
+		return nil, apperrors.NotFoundError("bot", id.String())
+	}
+	return s.view(bot, open[id]), nil
+}
+
+func (s *BotService) GetAllBots() []*domain.Bot {
+	open := s.tradeManager.OpenTradesByBot()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	bots := make([]*domain.Bot, 0, len(s.bots))
+	for id, bot := range s.bots {
+		bots = append(bots, s.view(bot, open[id]))
+	}
+	sort.Slice(bots, func(i, j int) bool { return bots[i].CreatedAt.Before(bots[j].CreatedAt) })
+	return bots
+}
+
+// view - копия бота с полями, которые считаются при чтении.
+func (s *BotService) view(bot *domain.Bot, openDeals int) *domain.Bot {
+	view := *bot
+	view.OpenDeals = openDeals
+	if cooldown, ok := s.tradeManager.riskManager.Cooldowns().Get(bot.Symbol); ok {
+		view.Cooldown = &cooldown
+	}
+	return &view
+}
+
+// UpdateBot заменяет настройки бота. Открытые сделки доводятся по прежнему конфигу.
+func (s *BotService) UpdateBot(ctx context.Context, id uuid.UUID, req domain.BotRequest) (*domain.Bot, error) {
+	if err := s.tradeManager.checkExchange(req.Exchange); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	bot, exists := s.bots[id]
+	if !exists {
+		s.mu.Unlock()
+		return nil, apperrors.NotFoundError("bot", id.String())
+	}
+	reached := applyBotRequest(bot, req)
+	snapshot := *bot
+	err := s.persist()
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("AUDIT: bot %s updated, enabled %t", id, req.Enabled == nil || *req.Enabled)
+	if reached {
+		s.closeOut(ctx, &snapshot)
+	}
+	s.startDeals(ctx, id)
+	return s.GetBot(id)
+}
+
+// PauseBot выключает бота и ставит на паузу его активные сделки. Сделка, которую не удалось
+// приостановить, остается активной; это видно в журнале и в ее событиях.
+func (s *BotService) PauseBot(ctx context.Context, id uuid.UUID, reason string) (*domain.Bot, error) {
+	if err := s.setEnabled(id, false); err != nil {
+		return nil, err
+	}
+
+	log.Printf("AUDIT: bot %s paused: %s", id, reason)
+	for _, tradeID := range s.tradeManager.BotTradesByStatus(id, domain.TradeStatusActive) {
+		if _, err := s.tradeManager.PauseTrade(ctx, tradeID, reason); err != nil {
+			log.Printf("Failed to pause trade %s of bot %s: %v", tradeID, id, err)
+		}
+	}
+	return s.GetBot(id)
+}
+
+// ResumeBot включает бота, возобновляет его сделки на паузе и добирает новые.
+func (s *BotService) ResumeBot(ctx context.Context, id uuid.UUID) (*domain.Bot, error) {
+	if err := s.setEnabled(id, true); err != nil {
+		return nil, err
+	}
+
+	log.Printf("AUDIT: bot %s resumed", id)
+	for _, tradeID := range s.tradeManager.BotTradesByStatus(id, domain.TradeStatusPaused) {
+		if _, err := s.tradeManager.ResumeTrade(ctx, tradeID); err != nil {
+			log.Printf("Failed to resume trade %s of bot %s: %v", tradeID, id, err)
+		}
+	}
+	s.startDeals(ctx, id)
+	return s.GetBot(id)
+}
+
+func (s *BotService) setEnabled(id uuid.UUID, enabled bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bot, exists := s.bots[id]
+	if !exists {
+		return apperrors.NotFoundError("bot", id.String())
+	}
+	bot.Enabled = enabled
+	bot.UpdatedAt = time.Now()
+	return s.persist()
+}
+
+// DeleteBot удаляет бота. Его открытые сделки продолжают сопровождаться, но новых не будет.
+func (s *BotService) DeleteBot(id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.bots[id]; !exists {
+		return apperrors.NotFoundError("bot", id.String())
+	}
+	delete(s.bots, id)
+	if err := s.persist(); err != nil {
+		return err
+	}
+
+	log.Printf("AUDIT: bot %s deleted", id)
+	return nil
+}
+
+// RunBots открывает сделки включенных ботов, пока у каждого открыто меньше
+// MaxConcurrentDeals. Пока торговля остановлена по лимиту убытка, боты ждут.
+// Используется планировщиком.
+func (s *BotService) RunBots(ctx context.Context) error {
+	if s.tradeManager.riskManager.checkSuspended() != nil {
+		return nil
+	}
+
+	s.mu.RLock()
+	ids := make([]uuid.UUID, 0, len(s.bots))
+	for id, bot := range s.bots {
+		if bot.Enabled {
+			ids = append(ids, id)
+		}
+	}
+	s.mu.RUnlock()
+
+	for _, id := range ids {
+		s.startDeals(ctx, id)
+	}
+	return nil
+}
+
+// startDeals добирает сделки бота до MaxConcurrentDeals. После первой ошибки
+// открытие прекращается до следующего тика. Пока символ на паузе после стопа, бот ждет.
+func (s *BotService) startDeals(ctx context.Context, id uuid.UUID) {
+	s.starting.Lock()
+	defer s.starting.Unlock()
+
+	open := s.tradeManager.OpenTradesByBot()[id]
+
+	for {
+		s.mu.RLock()
+		bot, exists := s.bots[id]
+		if !exists || !bot.Enabled || bot.TargetReachedAt != nil || open >= bot.MaxConcurrentDeals {
+			s.mu.RUnlock()
+			return
+		}
+		if _, cooling := s.tradeManager.riskManager.Cooldowns().Get(bot.Symbol); cooling {
+			s.mu.RUnlock()
+			return
+		}
+		config := bot.Config
+		s.mu.RUnlock()
+
+		trade, err := s.tradeManager.StartBotTrade(ctx, id, config)
+		s.recordStart(id, trade, err)
+		if err != nil {
+			return
+		}
+		open++
+	}
+}
+
+func (s *BotService) recordStart(id uuid.UUID, trade *domain.Trade, startErr error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bot, exists := s.bots[id]
+	if !exists {
+		return
+	}
+
+	now := time.Now()
+	if startErr != nil {
+		// Одна и та же ошибка повторяется каждый тик, в журнал пишется только новая
+		if bot.LastError != startErr.Error() {
+			log.Printf("Bot %s failed to open trade on %s: %v", id, bot.Symbol, startErr)
+		}
+		bot.LastError = startErr.Error()
+		bot.LastErrorAt = &now
+	} else {
+		log.Printf("AUDIT: bot %s opened trade %s on %s", id, trade.ID, bot.Symbol)
+		bot.DealsStarted++
+		bot.LastError = ""
+		bot.LastErrorAt = nil
+	}
+	bot.UpdatedAt = now
+
+	if err := s.persist(); err != nil {
+		log.Printf("Failed to persist bots: %v", err)
+	}
+}
+
+// persist сохраняет всех ботов; вызывается под s.mu.
+func (s *BotService) persist() error {
+	snapshot := make(map[uuid.UUID]domain.Bot, len(s.bots))
+	for id, bot := range s.bots {
+		snapshot[id] = *bot
+	}
+	if err := s.store.Save(snapshot); err != nil {
+		return fmt.Errorf("failed to save bots: %w", err)
+	}
+	return nil
+}
+
+// settleTrade добавляет результат закрытой сделки к прибыли ее бота. Цель проверяется
+// здесь же, чтобы следующий тик планировщика уже не открыл сделку.
+func (s *BotService) settleTrade(ctx context.Context, trade *domain.Trade) {
+	if trade.BotID == nil {
+		return
+	}
+	profit, ok := tradeRealizedPnL(trade)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	bot, exists := s.bots[*trade.BotID]
+	if !exists {
+		s.mu.Unlock()
+		return
+	}
+	realized, _ := strconv.ParseFloat(bot.RealizedProfit, 64)
+	bot.RealizedProfit = fmt.Sprintf("%.8f", realized+profit)
+	reached := markTargetReached(bot)
+	bot.UpdatedAt = time.Now()
+	if err := s.persist(); err != nil {
+		log.Printf("Failed to persist bots: %v", err)
+	}
+	snapshot := *bot
+	s.mu.Unlock()
+
+	if reached {
+		s.closeOut(ctx, &snapshot)
+	}
+}
+
+// closeOut вызывается один раз при достижении цели: сделки бота, еще не вошедшие
+// в позицию, отменяются, активные закроются по своему тейк-профиту.
+func (s *BotService) closeOut(ctx context.Context, bot *domain.Bot) {
+	log.Printf("AUDIT: bot %s reached profit target %s with realized profit %s", bot.ID, bot.ProfitTarget, bot.RealizedProfit)
+
+	for _, tradeID := range s.tradeManager.PendingBotTrades(bot.ID) {
+		if err := s.tradeManager.CloseTrade(ctx, tradeID, "bot profit target reached"); err != nil {
+			log.Printf("Failed to cancel trade %s of bot %s: %v", tradeID, bot.ID, err)
+		}
+	}
+
+	quote := bybit.QuoteAsset(bot.Symbol)
+	notification := notify.Localized(notify.LevelInfo, "Bot profit target reached",
+		"Bot %s on %s reached profit target %s %s with %s realized, open deals close at take profit",
+		bot.Name, bot.Symbol, bot.ProfitTarget, quote, bot.RealizedProfit)
+	if err := s.tradeManager.notifier.Notify(ctx, notification); err != nil {
+	}
+}
+
+// markTargetReached отмечает достижение цели; true - только в момент достижения.
+func markTargetReached(bot *domain.Bot) bool {
+	target, _ := strconv.ParseFloat(bot.ProfitTarget, 64)
+	realized, _ := strconv.ParseFloat(bot.RealizedProfit, 64)
+	if target <= 0 || realized < target {
+		// Цель подняли или сняли - бот снова открывает сделки
+		bot.TargetReachedAt = nil
+		return false
+	}
+	if bot.TargetReachedAt != nil {
+		return false
+	}
+	now := time.Now()
+	bot.TargetReachedAt = &now
+	return true
+}
+
+// applyBotRequest возвращает true, если новая цель уже достигнута накопленной прибылью.
+func applyBotRequest(bot *domain.Bot, req domain.BotRequest) bool {
+	bot.Name = req.Name
+	bot.Symbol = req.Symbol
+	bot.Exchange = req.Exchange
+	bot.Config = req.Config
+	bot.Config.Symbol = req.Symbol
+	bot.Config.Exchange = req.Exchange
+	bot.MaxConcurrentDeals = req.MaxConcurrentDeals
+	if bot.MaxConcurrentDeals <= 0 {
+		bot.MaxConcurrentDeals = domain.DefaultMaxConcurrentDeals
+	}
+	bot.Enabled = req.Enabled == nil || *req.Enabled
+	bot.ProfitTarget = req.ProfitTarget
+	bot.UpdatedAt = time.Now()
+	return markTargetReached(bot)
+}
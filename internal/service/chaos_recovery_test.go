@@ -0,0 +1,200 @@
+//go:build chaos
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cryptorg/internal/bybit"
+	"cryptorg/internal/chaos"
+	"cryptorg/internal/domain"
+	"cryptorg/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const chaosSymbol = "BTCUSDT"
+
+// startChaosNode поднимает экземпляр бота на общих бирже и хранилище и восстанавливает
+// сделки так же, как при запуске приложения.
+func startChaosNode(t *testing.T, exchange *fakeExchange, repository domain.TradeRepository) *TradeService {
+	t.Helper()
+
+	trades := newFakeTradeService(t, exchange, storage.NewMemoryJournal(), repository)
+	_, err := trades.LoadTrades()
+	require.NoError(t, err)
+	return trades
+}
+
+// deliverFills передает исполнения так же, как поток ордеров: кэш, затем обработка сделки.
+// Ошибка внедренного сбоя возвращается, остальные ошибки валят тест.
+func deliverFills(t *testing.T, trades *TradeService, updates []domain.OrderUpdate) error {
+	t.Helper()
+
+	var injected error
+	for _, update := range updates {
+		trades.ApplyOrderUpdate(update)
+		trade, err := trades.FindTradeByOrderID(update.OrderID)
+		require.NoError(t, err)
+
+		err = trades.ProcessOrderExecution(context.Background(), trade.ID, update.OrderID, update.OrderID+"-exec")
+		if errors.Is(err, chaos.ErrInjected) {
+			injected = err
+			continue
+		}
+		require.NoError(t, err)
+	}
+	return injected
+}
+
+type chaosScenario struct {
+	point string
+	// dcaFills - сколько уровней DCA исполняется после открытия
+	dcaFills int
+	// exit - цена доходит до TP
+	exit bool
+	// issue - расхождение, которое находит сверка после перезапуска; пусто - сбой не оставляет следов
+	issue domain.ConsistencyIssueKind
+}
+
+// runChaosScenario открывает сделку и проводит исполнения со сбоем spec ("" - без сбоя).
+func runChaosScenario(t *testing.T, scenario chaosScenario, spec string) (*fakeExchange, domain.TradeRepository, *domain.Trade) {
+	t.Helper()
+
+	exchange := newFakeExchange(map[string]float64{chaosSymbol: 100})
+	repository := storage.NewMemoryTradeRepository()
+	trades := startChaosNode(t, exchange, repository)
+
+	chaos.Configure(spec)
+	defer chaos.Configure("")
+
+	trade, err := trades.InitializeTrade(context.Background(), testTradeConfig())
+	require.NoError(t, err)
+
+	grid := BuildGrid(trade.Config, domain.DecimalFromFloat(100))
+	for i := 0; i < scenario.dcaFills; i++ {
+		err := deliverFills(t, trades, exchange.setPrice(chaosSymbol, parseFake(grid[i].Price)))
+		if spec == "" {
+			require.NoError(t, err)
+		}
+	}
+	if scenario.exit {
+		err := deliverFills(t, trades, exchange.setPrice(chaosSymbol, 110))
+		if spec == "" {
+			require.NoError(t, err)
+		}
+	}
+	return exchange, repository, trade
+}
+
+// restartChaosNode - перезапуск после сбоя: новый экземпляр читает сохраненные сделки и
+// сверяет их с биржей с исправлением. Перезапуск длиннее задержки поиска сирот и не
+// укладывается в ту же миллисекунду: orderLinkId повторной заявки содержит ее время.
+func restartChaosNode(t *testing.T, exchange *fakeExchange, repository domain.TradeRepository) (*TradeService, *domain.ConsistencyReport) {
+	t.Helper()
+
+	exchange.age(2 * orphanGracePeriod)
+	time.Sleep(2 * time.Millisecond)
+	trades := startChaosNode(t, exchange, repository)
+	report, err := trades.CheckConsistency(context.Background(), true)
+	require.NoError(t, err)
+	return trades, report
+}
+
+func TestChaosRecovery(t *testing.T) {
+	scenarios := []chaosScenario{
+		{point: chaos.PointAfterEntry, issue: domain.IssueIncompleteOpen},
+		{point: chaos.PointBeforeTakeProfit, issue: domain.IssueIncompleteOpen},
+		{point: chaos.PointBeforeDCAGrid, issue: domain.IssueIncompleteOpen},
+		{point: chaos.PointAfterDCAFill, dcaFills: 1, issue: domain.IssueStaleTakeProfit},
+		{point: chaos.PointBeforeTPReplace, dcaFills: 2, issue: domain.IssueStaleTakeProfit},
+		{point: chaos.PointBeforeFinalize, dcaFills: 1, exit: true, issue: domain.IssueMissedFill},
+		{point: chaos.PointExchangeResponse, dcaFills: 1},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.point, func(t *testing.T) {
+			// Эталон - тот же сценарий без сбоя
+			refExchange, refRepository, want := runChaosScenario(t, scenario, "")
+			_, refReport := restartChaosNode(t, refExchange, refRepository)
+			assert.Empty(t, refReport.Issues, "reference run must be consistent")
+
+			exchange, repository, opened := runChaosScenario(t, scenario, scenario.point+":drop")
+			tradeID := opened.ID
+			trades, report := restartChaosNode(t, exchange, repository)
+
+			if scenario.issue != "" {
+				found := false
+				for _, issue := range report.Issues {
+					if issue.Kind == scenario.issue && issue.TradeID == tradeID.String() {
+						found = true
+						assert.True(t, issue.Repaired, "issue %s: %s", issue.Kind, issue.Error)
+					}
+				}
+				assert.True(t, found, "expected %s issue, got %+v", scenario.issue, report.Issues)
+			}
+
+			// Повторная сверка не находит ничего: состояние сошлось
+			again, err := trades.CheckConsistency(context.Background(), true)
+			require.NoError(t, err)
+			assert.Empty(t, again.Issues)
+
+			got, err := trades.GetTrade(tradeID)
+			require.NoError(t, err)
+			assertRecoveredTrade(t, want, got, refExchange, exchange)
+		})
+	}
+}
+
+// assertRecoveredTrade сравнивает сделку после восстановления с эталоном без сбоя: статус,
+// позицию, TP и сетку в состоянии сделки и на бирже.
+func assertRecoveredTrade(t *testing.T, want, got *domain.Trade, refExchange, exchange *fakeExchange) {
+	t.Helper()
+
+	assert.Equal(t, want.Status, got.Status)
+	assert.False(t, got.Opening)
+	assert.Equal(t, want.AveragePrice, got.AveragePrice)
+	assert.Equal(t, want.CurrentPositionQty, got.CurrentPositionQty)
+	assert.Equal(t, want.TotalInvested, got.TotalInvested)
+
+	if want.TakeProfitOrder == nil {
+		assert.Nil(t, got.TakeProfitOrder)
+	} else if assert.NotNil(t, got.TakeProfitOrder) {
+		wantTP, _ := refExchange.order(want.TakeProfitOrder.BybitID)
+		gotTP, ok := exchange.order(got.TakeProfitOrder.BybitID)
+		require.True(t, ok, "take profit %s is not on the exchange", got.TakeProfitOrder.BybitID)
+		assert.Equal(t, wantTP.Price, gotTP.Price)
+		assert.Equal(t, wantTP.Qty, gotTP.Qty)
+		assert.Equal(t, wantTP.Status, gotTP.Status)
+	}
+
+	require.Len(t, got.DCAOrders, len(want.DCAOrders))
+	for i := range want.DCAOrders {
+		wantDCA, _ := refExchange.order(want.DCAOrders[i].BybitID)
+		gotDCA, _ := exchange.order(got.DCAOrders[i].BybitID)
+		assert.Equal(t, wantDCA.Price, gotDCA.Price, "dca %d", i)
+		assert.Equal(t, wantDCA.Qty, gotDCA.Qty, "dca %d", i)
+		assert.Equal(t, wantDCA.Status, gotDCA.Status, "dca %d", i)
+	}
+
+	// На бирже остались ровно ордера сделки: сироты после сбоя сняты, дублей нет
+	assert.Equal(t, openOrderShape(refExchange.open(chaosSymbol)), openOrderShape(exchange.open(chaosSymbol)))
+}
+
+type orderShape struct {
+	side  string
+	price string
+	qty   string
+}
+
+func openOrderShape(orders []bybit.ExchangeOrderResponse) []orderShape {
+	shapes := make([]orderShape, 0, len(orders))
+	for _, order := range orders {
+		shapes = append(shapes, orderShape{side: order.Side, price: order.Price, qty: order.Qty})
+	}
+	return shapes
+}
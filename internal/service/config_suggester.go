@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	"cryptorg/internal/bybit"
+	"cryptorg/internal/domain"
+)
+
+const (
+	suggestLookback     = 90  // Дневных свечей для анализа
+	atrPeriod           = 14  // Период ATR
+	drawdownMargin      = 1.1 // Запас над исторической просадкой
+	maxSuggestedLevels  = 25
+	maxSuggestedDrop    = 90.0
+	minSuggestedPercent = 0.5
+)
+
+// Чем выше мартингейл, тем сильнее глубокие уровни подтягивают среднюю цену,
+// поэтому берется наибольший, при котором первый уровень не меньше минимального ордера.
+var martingaleCandidates = []float64{1.5, 1.4, 1.3, 1.2, 1.1, 1.0}
+
+// SuggestConfig подбирает шаг, количество уровней, мартингейл и TP по ATR и максимальной
+// просадке символа так, чтобы сетка в пределах бюджета перекрыла исторические падения.
+func (s *RiskService) SuggestConfig(ctx context.Context, symbol string, budget float64) (*domain.ConfigSuggestion, error) {
+	klines, err := s.exchangeClient.GetKlines(ctx, symbol, volatilityInterval, suggestLookback)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch klines: %w", err)
+	}
+	if len(klines) < atrPeriod+1 {
+		return nil, fmt.Errorf("not enough history for %s: %d candles", symbol, len(klines))
+	}
+
+	suggestion := &domain.ConfigSuggestion{
+		ATRPercent:         averageTrueRangePercent(klines),
+		MaxDrawdownPercent: maxDrawdownPercent(klines),
+		Warnings:           make([]string, 0),
+	}
+
+	minOrderAmt := 0.0
+	if info, err := s.exchangeClient.GetInstrumentInfo(ctx, symbol); err == nil {
+		minOrderAmt, _ = strconv.ParseFloat(info.MinOrderAmt, 64)
+	} else {
+		suggestion.Warnings = append(suggestion.Warnings, "minimum order amount is unknown, volumes are not checked against it")
+	}
+
+	target := math.Min(suggestion.MaxDrawdownPercent*drawdownMargin, maxSuggestedDrop)
+	step := roundUpTenth(math.Max(suggestion.ATRPercent, minSuggestedPercent))
+	count := levelsToCover(step, target)
+	if count > maxSuggestedLevels {
+		count = maxSuggestedLevels
+		step = roundUpTenth((1 - math.Pow(1-target/100, 1/float64(count))) * 100)
+	}
+
+	martingale, base := 0.0, 0.0
+	for ; count > 0; count-- {
+		for _, m := range martingaleCandidates {
+			if volume := budget / gridWeight(m, count); volume >= minOrderAmt {
+				martingale, base = m, volume
+				break
+			}
+		}
+		if martingale > 0 {
+			break
+		}
+	}
+	if martingale == 0 {
+		return nil, fmt.Errorf("budget %.2f is below the minimum order amount %.2f", budget, minOrderAmt)
+	}
+
+	volume := fmt.Sprintf("%.2f", math.Floor(base*100)/100)
+	suggestion.Config = domain.TradeConfig{
+		Symbol:            symbol,
+		Strategy:          domain.DefaultStrategy,
+		EntryVolume:       volume,
+		DCAVolume:         volume,
+		DCACount:          count,
+		DCAStepPercent:    step,
+		Martingale:        martingale,
+		TakeProfitPercent: roundUpTenth(math.Max(suggestion.ATRPercent/2, minSuggestedPercent)),
+	}
+
+	lastClose := klines[len(klines)-1].Close
+	suggestion.Grid = BuildGrid(suggestion.Config, domain.DecimalFromFloat(lastClose))
+	suggestion.RequiredCapital = fmt.Sprintf("%.8f", requiredCapital(suggestion.Config, suggestion.Grid))
+	if len(suggestion.Grid) > 0 {
+		suggestion.CoveredDropPercent = suggestion.Grid[len(suggestion.Grid)-1].DeviationPercent
+	}
+
+	if suggestion.CoveredDropPercent < suggestion.MaxDrawdownPercent {
+		suggestion.Warnings = append(suggestion.Warnings, fmt.Sprintf(
+			"budget covers only a %.1f%% drop while the historical drawdown is %.1f%%",
+			suggestion.CoveredDropPercent, suggestion.MaxDrawdownPercent))
+	}
+
+	return suggestion, nil
+}
+
+// averageTrueRangePercent - ATR за последние atrPeriod свечей в процентах от последнего закрытия.
+func averageTrueRangePercent(klines []bybit.Kline) float64 {
+	total := 0.0
+	recent := klines[len(klines)-atrPeriod:]
+	for i, k := range recent {
+		prevClose := klines[len(klines)-atrPeriod+i-1].Close
+		trueRange := math.Max(k.High-k.Low, math.Max(math.Abs(k.High-prevClose), math.Abs(k.Low-prevClose)))
+		total += trueRange
+	}
+
+	lastClose := klines[len(klines)-1].Close
+	if lastClose <= 0 {
+		return 0
+	}
+	return total / atrPeriod / lastClose * 100
+}
+
+func maxDrawdownPercent(klines []bybit.Kline) float64 {
+	peak, drawdown := 0.0, 0.0
+	for _, k := range klines {
+		peak = math.Max(peak, k.High)
+		if peak > 0 {
+			drawdown = math.Max(drawdown, (peak-k.Low)/peak*100)
+		}
+	}
+	return drawdown
+}
+
+// levelsToCover - сколько уровней с фиксированным шагом нужно, чтобы перекрыть падение на dropPercent.
+func levelsToCover(stepPercent, dropPercent float64) int {
+	if dropPercent <= 0 {
+		return 1
+	}
+	return int(math.Ceil(math.Log(1-dropPercent/100) / math.Log(1-stepPercent/100)))
+}
+
+// gridWeight - сумма объемов входа и уровней в единицах базового объема (как в BuildGrid).
+func gridWeight(martingale float64, count int) float64 {
+	weight, volume := 1.0, 1.0
+	for i := 0; i < count; i++ {
+		if i > 0 {
+			volume *= martingale
+		}
+		weight += volume
+	}
+	return weight
+}
+
+func roundUpTenth(value float64) float64 {
+	return math.Ceil(value*10) / 10
+}
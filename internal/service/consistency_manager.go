@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"cryptorg/internal/domain"
+	"cryptorg/internal/notify"
+)
+
+// ConsistencyService периодически сверяет состояние сделок с биржей и хранит последний отчет.
+type ConsistencyService struct {
+	tradeManager *TradeService
+	notifier     notify.Notifier
+	autoRepair   bool
+	last         *domain.ConsistencyReport
+	mu           sync.Mutex
+}
+
+func NewConsistencyManager(tradeManager *TradeService, notifier notify.Notifier, autoRepair bool) *ConsistencyService {
+	return &ConsistencyService{
+		tradeManager: tradeManager,
+		notifier:     notifier,
+		autoRepair:   autoRepair,
+	}
+}
+
+// Run - задача планировщика. О найденных и не исправленных проблемах отправляется уведомление.
+func (s *ConsistencyService) Run(ctx context.Context) error {
+	report, err := s.tradeManager.CheckConsistency(ctx, s.autoRepair)
+
+	s.mu.Lock()
+	s.last = report
+	s.mu.Unlock()
+
+	unresolved := 0
+	for _, issue := range report.Issues {
+		if !issue.Repaired {
+			unresolved++
+		}
+	}
+	if unresolved > 0 {
+		notification := notify.Localized(notify.LevelWarning, "Inconsistent trades",
+			"Consistency check found %d issues, %d left unresolved. See /api/admin/consistency", len(report.Issues), unresolved)
+		if err := s.notifier.Notify(ctx, notification); err != nil {
+		}
+	}
+
+	return err
+}
+
+// Reconcile сверяет сделки с биржей и сразу исправляет расхождения, независимо от
+// autoRepair: запускается при старте после сбоя и вручную из админки.
+func (s *ConsistencyService) Reconcile(ctx context.Context) (*domain.ConsistencyReport, error) {
+	report, err := s.tradeManager.CheckConsistency(ctx, true)
+
+	s.mu.Lock()
+	s.last = report
+	s.mu.Unlock()
+
+	repaired := 0
+	for _, issue := range report.Issues {
+		if issue.Repaired {
+			repaired++
+		}
+	}
+	log.Printf("Reconciliation checked %d trades: %d issues, %d repaired", report.TradesChecked, len(report.Issues), repaired)
+	return report, err
+}
+
+// LastReport возвращает отчет последней проверки; если проверок еще не было,
+// выполняет проверку без исправлений.
+func (s *ConsistencyService) LastReport(ctx context.Context) (*domain.ConsistencyReport, error) {
+	s.mu.Lock()
+	last := s.last
+	s.mu.Unlock()
+	if last != nil {
+		return last, nil
+	}
+
+	return s.tradeManager.CheckConsistency(ctx, false)
+}
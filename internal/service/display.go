@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"cryptorg/internal/bybit"
+	"cryptorg/internal/domain"
+)
+
+const (
+	PrecisionDisplay = "display"
+	PrecisionRaw     = "raw"
+)
+
+// DisplayTrade возвращает копию сделки с полями Display, округленными по фильтрам символа.
+// Исходная сделка не меняется: она может быть частью общего снимка.
+func (s *TradeService) DisplayTrade(ctx context.Context, trade *domain.Trade) *domain.Trade {
+	info, err := s.orderManager.InstrumentInfo(ctx, trade.Symbol)
+	if err != nil {
+		info = &bybit.InstrumentInfo{}
+	}
+
+	clone := cloneTrade(trade)
+	clone.Display = &domain.TradeDisplay{
+		TotalInvested: formatToStep(trade.TotalInvested, info.QuotePrecision),
+		AveragePrice:  formatToStep(trade.AveragePrice, info.TickSize),
+		CurrentPrice:  formatToStep(trade.CurrentPrice, info.TickSize),
+	}
+
+	displayOrder(clone.EntryOrder, info)
+	displayOrder(clone.TakeProfitOrder, info)
+	displayOrder(clone.StopLossOrder, info)
+	for i := range clone.DCAOrders {
+		displayOrder(&clone.DCAOrders[i], info)
+	}
+
+	return clone
+}
+
+// DisplayOrder - то же для отдельного ордера.
+func (s *OrderService) DisplayOrder(ctx context.Context, order *domain.Order) *domain.Order {
+	info, err := s.InstrumentInfo(ctx, order.Symbol)
+	if err != nil {
+		info = &bybit.InstrumentInfo{}
+	}
+
+	clone := cloneOrder(order)
+	displayOrder(clone, info)
+	return clone
+}
+
+func displayOrder(order *domain.Order, info *bybit.InstrumentInfo) {
+	if order == nil {
+		return
+	}
+	order.Display = &domain.OrderDisplay{
+		Quantity:    formatToStep(order.Quantity, info.QtyStep),
+		Price:       formatToStep(order.Price, info.TickSize),
+		ExecutedQty: formatToStep(order.ExecutedQty, info.QtyStep),
+	}
+}
+
+// formatToStep округляет значение до числа знаков шага. Без известного шага
+// просто отбрасывает хвостовые нули: 1.50000000 -> 1.5.
+func formatToStep(value, step string) string {
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return value
+	}
+
+	if _, err := strconv.ParseFloat(step, 64); err != nil {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	decimals := 0
+	if idx := strings.IndexByte(step, '.'); idx >= 0 {
+		decimals = len(strings.TrimRight(step[idx+1:], "0"))
+	}
+	return strconv.FormatFloat(v, 'f', decimals, 64)
+}
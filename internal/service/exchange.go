@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"cryptorg/internal/bybit"
+	"cryptorg/pkg/latency"
+	"cryptorg/pkg/ratelimit"
+)
+
+// ExchangeClient - общий контракт биржевых адаптеров. Модели запросов и ответов
+// берутся из пакета bybit, остальные биржи приводят свои форматы к ним.
+type ExchangeClient interface {
+	ExecuteOrder(ctx context.Context, req bybit.ExchangeOrderRequest) (*bybit.ExchangeOrderResponse, error)
+	TerminateOrder(ctx context.Context, req bybit.ExchangeCancelRequest) error
+	AmendOrder(ctx context.Context, req bybit.ExchangeAmendRequest) error
+	FetchOrderInfo(ctx context.Context, symbol string, orderID string) (*bybit.ExchangeOrderResponse, error)
+	FetchOrderByLinkID(ctx context.Context, symbol string, linkID string) (*bybit.ExchangeOrderResponse, error)
+	GetTicker(ctx context.Context, symbol string) (*bybit.Ticker, error)
+	ListTickers(ctx context.Context) ([]bybit.Ticker, error)
+	GetOrderBook(ctx context.Context, symbol string, limit int) (*bybit.OrderBook, error)
+	GetKlines(ctx context.Context, symbol, interval string, limit int) ([]bybit.Kline, error)
+	GetKlinesRange(ctx context.Context, symbol, interval string, start, end time.Time) ([]bybit.Kline, error)
+	ListOpenOrders(ctx context.Context, symbol string) ([]bybit.ExchangeOrderResponse, error)
+	FetchOrderHistory(ctx context.Context, symbol string, orderID string) (*bybit.ExchangeOrderResponse, error)
+	ListExecutions(ctx context.Context, symbol string, orderID string) ([]bybit.Execution, error)
+	GetBalance(ctx context.Context, coin string) (*bybit.CoinBalance, error)
+	GetWalletBalance(ctx context.Context) ([]bybit.CoinBalance, error)
+	ListWalletChanges(ctx context.Context, coin string, since time.Time) ([]bybit.WalletChange, error)
+	GetInstrumentInfo(ctx context.Context, symbol string) (*bybit.InstrumentInfo, error)
+	Latency() *latency.Tracker
+	RateLimits() *ratelimit.Tracker
+	Clock() *latency.ClockOffset
+}
+
+var (
+	_ ExchangeClient = (*bybit.Client)(nil)
+	_ ExchangeClient = (*bybit.MockClient)(nil)
+)
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"cryptorg/internal/domain"
+	apperrors "cryptorg/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// ExposureGuard не дает одновременно вести по символу DCA сделку, которая только покупает
+// и продает по TP, и портфель, чей ребалансировщик продает тот же актив: стороны гоняют
+// монеты друг другу и теряют на комиссиях. Гард хранит символы активных портфелей;
+// сделки ребалансировщик берет из TradeService.
+type ExposureGuard struct {
+	mu         sync.RWMutex
+	portfolios map[string]uuid.UUID // symbol -> портфель, который его ребалансирует
+}
+
+func NewExposureGuard() *ExposureGuard {
+	return &ExposureGuard{
+		portfolios: make(map[string]uuid.UUID),
+	}
+}
+
+func (g *ExposureGuard) ClaimPortfolio(id uuid.UUID, symbols []string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, symbol := range symbols {
+		g.portfolios[symbol] = id
+	}
+}
+
+func (g *ExposureGuard) ReleasePortfolio(id uuid.UUID) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for symbol, owner := range g.portfolios {
+		if owner == id {
+			delete(g.portfolios, symbol)
+		}
+	}
+}
+
+// CheckTrade отказывает в сделке по символу, который ребалансирует активный портфель.
+func (g *ExposureGuard) CheckTrade(config domain.TradeConfig) error {
+	g.mu.RLock()
+	portfolioID, ok := g.portfolios[config.Symbol]
+	g.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	if config.AllowOppositeExposure {
+		return nil
+	}
+
+	appErr := apperrors.DomainError(
+		fmt.Sprintf("symbol %s is rebalanced by portfolio %s; set allow_opposite_exposure to open the trade anyway", config.Symbol, portfolioID),
+		"OPPOSITE_EXPOSURE",
+	)
+	appErr.Details = map[string]interface{}{"symbol": config.Symbol, "portfolio_id": portfolioID.String()}
+	return appErr
+}
+
+// portfolioSymbols - символы, которыми торгует ребалансировщик портфеля.
+func portfolioSymbols(config domain.PortfolioConfig) []string {
+	symbols := make([]string, 0, len(config.Allocations))
+	for asset := range config.Allocations {
+		if asset != config.QuoteAsset {
+			symbols = append(symbols, asset+config.QuoteAsset)
+		}
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+func oppositeTradesError(conflicts map[string][]string) error {
+	symbols := make([]string, 0, len(conflicts))
+	for symbol := range conflicts {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	appErr := apperrors.DomainError(
+		fmt.Sprintf("active DCA trades hold %s; set allow_opposite_exposure to create the portfolio anyway", strings.Join(symbols, ", ")),
+		"OPPOSITE_EXPOSURE",
+	)
+	appErr.Details = map[string]interface{}{"trades": conflicts}
+	return appErr
+}
@@ -0,0 +1,388 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"cryptorg/internal/bybit"
+	"cryptorg/internal/chaos"
+	"cryptorg/internal/domain"
+	"cryptorg/internal/metrics"
+	"cryptorg/internal/notify"
+	"cryptorg/internal/storage"
+	"cryptorg/pkg/latency"
+	"cryptorg/pkg/ratelimit"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeExchange - биржа в памяти для тестов сервиса: рыночные ордера исполняются сразу по
+// текущей цене, лимитные - целиком, когда setPrice пересекает их цену. Комиссий нет.
+// Рыночный ордер, как у Bybit, хранит price "0".
+type fakeExchange struct {
+	mu         sync.Mutex
+	prices     map[string]float64
+	orders     map[string]*bybit.ExchangeOrderResponse
+	links      map[string]string // orderLinkId -> orderId
+	executions map[string][]bybit.Execution
+	placed     []string // orderId в порядке создания
+	seq        int
+
+	latency    *latency.Tracker
+	rateLimits *ratelimit.Tracker
+	clock      *latency.ClockOffset
+}
+
+var _ ExchangeClient = (*fakeExchange)(nil)
+
+func newFakeExchange(prices map[string]float64) *fakeExchange {
+	return &fakeExchange{
+		prices:     prices,
+		orders:     make(map[string]*bybit.ExchangeOrderResponse),
+		links:      make(map[string]string),
+		executions: make(map[string][]bybit.Execution),
+		latency:    latency.NewTracker(0),
+		rateLimits: ratelimit.NewTracker(0, 0),
+		clock:      latency.NewClockOffset(),
+	}
+}
+
+func formatFake(value float64) string {
+	return strconv.FormatFloat(value, 'f', 8, 64)
+}
+
+func parseFake(value string) float64 {
+	parsed, _ := strconv.ParseFloat(value, 64)
+	return parsed
+}
+
+func (e *fakeExchange) ExecuteOrder(ctx context.Context, req bybit.ExchangeOrderRequest) (*bybit.ExchangeOrderResponse, error) {
+	e.mu.Lock()
+	if req.OrderLinkID != "" {
+		if _, exists := e.links[req.OrderLinkID]; exists {
+			e.mu.Unlock()
+			return nil, &bybit.APIError{RetCode: 170141, RetMsg: "Duplicate clientOrderId"}
+		}
+	}
+
+	e.seq++
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	order := &bybit.ExchangeOrderResponse{
+		Symbol:        req.Symbol,
+		OrderID:       fmt.Sprintf("fake-%d", e.seq),
+		OrderLinkID:   req.OrderLinkID,
+		Price:         req.Price,
+		Qty:           req.Qty,
+		ExecutedQty:   "0",
+		ExecutedValue: "0",
+		ExecutedFee:   "0",
+		Status:        string(domain.OrderStatusNew),
+		TimeInForce:   req.TimeInForce,
+		OrderType:     req.OrderType,
+		Side:          req.Side,
+		CreatedTime:   now,
+		UpdatedTime:   now,
+	}
+	e.orders[order.OrderID] = order
+	e.placed = append(e.placed, order.OrderID)
+	if req.OrderLinkID != "" {
+		e.links[req.OrderLinkID] = order.OrderID
+	}
+
+	price := e.prices[req.Symbol]
+	switch {
+	case req.TriggerPrice != "":
+		order.Status = string(domain.OrderStatusUntriggered)
+	case req.OrderType == string(domain.OrderTypeMarket):
+		qty := parseFake(req.Qty)
+		// Рыночная покупка по умолчанию задана суммой в котируемой валюте
+		if req.MarketUnit == bybit.MarketUnitQuoteCoin || (req.Side == string(domain.OrderSideBuy) && req.MarketUnit == "") {
+			qty /= price
+		}
+		e.fill(order, qty, price)
+		order.Price = "0"
+	default:
+		e.match(order, price)
+	}
+
+	// Как у Bybit, ответ на создание содержит только идентификаторы, а клиент считает
+	// принятый ордер New: цена, количество и исполнение видны только в запросах статуса
+	resp := bybit.ExchangeOrderResponse{
+		OrderID:     order.OrderID,
+		OrderLinkID: order.OrderLinkID,
+		Status:      string(domain.OrderStatusBybitNew),
+	}
+	e.mu.Unlock()
+
+	// Сбой ответа: ордер уже принят биржей, а клиент получает ошибку соединения
+	if err := chaos.Inject(chaos.PointExchangeResponse); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// fill исполняет ордер целиком по цене price; вызывается под mu.
+func (e *fakeExchange) fill(order *bybit.ExchangeOrderResponse, qty, price float64) {
+	order.Qty = formatFake(qty)
+	order.ExecutedQty = formatFake(qty)
+	order.ExecutedValue = formatFake(qty * price)
+	order.Status = string(domain.OrderStatusFilled)
+	order.UpdatedTime = strconv.FormatInt(time.Now().UnixMilli(), 10)
+
+	e.executions[order.OrderID] = append(e.executions[order.OrderID], bybit.Execution{
+		ExecID:  order.OrderID + "-exec",
+		OrderID: order.OrderID,
+		Price:   formatFake(price),
+		Qty:     order.ExecutedQty,
+		Value:   order.ExecutedValue,
+		Fee:     "0",
+		Time:    order.UpdatedTime,
+	})
+}
+
+// match исполняет открытый лимитный ордер, если цена дошла до него; вызывается под mu.
+func (e *fakeExchange) match(order *bybit.ExchangeOrderResponse, price float64) bool {
+	if order.Status != string(domain.OrderStatusNew) || order.OrderType != string(domain.OrderTypeLimit) {
+		return false
+	}
+	limit := parseFake(order.Price)
+	buy := order.Side == string(domain.OrderSideBuy)
+	if (buy && price > limit) || (!buy && price < limit) {
+		return false
+	}
+	e.fill(order, parseFake(order.Qty), limit)
+	return true
+}
+
+// setPrice меняет цену символа и возвращает обновления исполненных лимитных ордеров.
+func (e *fakeExchange) setPrice(symbol string, price float64) []domain.OrderUpdate {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.prices[symbol] = price
+	updates := make([]domain.OrderUpdate, 0)
+	for _, id := range e.placed {
+		order := e.orders[id]
+		if order.Symbol != symbol || !e.match(order, price) {
+			continue
+		}
+		updates = append(updates, domain.OrderUpdate{
+			OrderID:       order.OrderID,
+			Symbol:        order.Symbol,
+			Status:        domain.OrderStatusFilled,
+			ExecutedQty:   order.ExecutedQty,
+			ExecutedValue: order.ExecutedValue,
+			Fee:           order.ExecutedFee,
+			Price:         order.Price,
+		})
+	}
+	return updates
+}
+
+// age сдвигает время создания ордеров в прошлое, как будто перезапуск занял d.
+func (e *fakeExchange) age(d time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, order := range e.orders {
+		for _, field := range []*string{&order.CreatedTime, &order.UpdatedTime} {
+			ms, _ := strconv.ParseInt(*field, 10, 64)
+			*field = strconv.FormatInt(ms-d.Milliseconds(), 10)
+		}
+	}
+}
+
+// open - открытые ордера символа в порядке создания.
+func (e *fakeExchange) open(symbol string) []bybit.ExchangeOrderResponse {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	orders := make([]bybit.ExchangeOrderResponse, 0)
+	for _, id := range e.placed {
+		order := e.orders[id]
+		if (symbol == "" || order.Symbol == symbol) && domain.OrderStatus(order.Status).IsOpen() {
+			orders = append(orders, *order)
+		}
+	}
+	return orders
+}
+
+func (e *fakeExchange) order(orderID string) (bybit.ExchangeOrderResponse, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	order, ok := e.orders[orderID]
+	if !ok {
+		return bybit.ExchangeOrderResponse{}, false
+	}
+	return *order, true
+}
+
+func (e *fakeExchange) TerminateOrder(ctx context.Context, req bybit.ExchangeCancelRequest) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	order, ok := e.orders[req.OrderID]
+	if !ok || !domain.OrderStatus(order.Status).IsOpen() {
+		return &bybit.APIError{RetCode: 170213, RetMsg: "Order does not exist."}
+	}
+	order.Status = string(domain.OrderStatusCanceled)
+	order.UpdatedTime = strconv.FormatInt(time.Now().UnixMilli(), 10)
+	return nil
+}
+
+func (e *fakeExchange) AmendOrder(ctx context.Context, req bybit.ExchangeAmendRequest) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	order, ok := e.orders[req.OrderID]
+	if !ok || !domain.OrderStatus(order.Status).IsOpen() {
+		return &bybit.APIError{RetCode: 170213, RetMsg: "Order does not exist."}
+	}
+	if req.Qty != "" {
+		order.Qty = req.Qty
+	}
+	if req.Price != "" {
+		order.Price = req.Price
+	}
+	return nil
+}
+
+func (e *fakeExchange) FetchOrderInfo(ctx context.Context, symbol string, orderID string) (*bybit.ExchangeOrderResponse, error) {
+	order, ok := e.order(orderID)
+	if !ok {
+		return nil, bybit.ErrOrderNotFound
+	}
+	return &order, nil
+}
+
+func (e *fakeExchange) FetchOrderByLinkID(ctx context.Context, symbol string, linkID string) (*bybit.ExchangeOrderResponse, error) {
+	e.mu.Lock()
+	orderID, ok := e.links[linkID]
+	e.mu.Unlock()
+	if !ok {
+		return nil, bybit.ErrOrderNotFound
+	}
+	return e.FetchOrderInfo(ctx, symbol, orderID)
+}
+
+func (e *fakeExchange) FetchOrderHistory(ctx context.Context, symbol string, orderID string) (*bybit.ExchangeOrderResponse, error) {
+	return e.FetchOrderInfo(ctx, symbol, orderID)
+}
+
+func (e *fakeExchange) ListOpenOrders(ctx context.Context, symbol string) ([]bybit.ExchangeOrderResponse, error) {
+	return e.open(symbol), nil
+}
+
+func (e *fakeExchange) ListExecutions(ctx context.Context, symbol string, orderID string) ([]bybit.Execution, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return append([]bybit.Execution(nil), e.executions[orderID]...), nil
+}
+
+func (e *fakeExchange) GetTicker(ctx context.Context, symbol string) (*bybit.Ticker, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	price, ok := e.prices[symbol]
+	if !ok {
+		return nil, fmt.Errorf("unknown symbol %s", symbol)
+	}
+	return &bybit.Ticker{Symbol: symbol, LastPrice: formatFake(price)}, nil
+}
+
+func (e *fakeExchange) ListTickers(ctx context.Context) ([]bybit.Ticker, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	tickers := make([]bybit.Ticker, 0, len(e.prices))
+	for symbol, price := range e.prices {
+		tickers = append(tickers, bybit.Ticker{Symbol: symbol, LastPrice: formatFake(price)})
+	}
+	return tickers, nil
+}
+
+func (e *fakeExchange) GetOrderBook(ctx context.Context, symbol string, limit int) (*bybit.OrderBook, error) {
+	return nil, errors.New("order book is not simulated")
+}
+
+func (e *fakeExchange) GetKlines(ctx context.Context, symbol, interval string, limit int) ([]bybit.Kline, error) {
+	return nil, errors.New("klines are not simulated")
+}
+
+func (e *fakeExchange) GetKlinesRange(ctx context.Context, symbol, interval string, start, end time.Time) ([]bybit.Kline, error) {
+	return nil, errors.New("klines are not simulated")
+}
+
+func (e *fakeExchange) GetBalance(ctx context.Context, coin string) (*bybit.CoinBalance, error) {
+	return &bybit.CoinBalance{Coin: coin, WalletBalance: "1000000", Locked: "0"}, nil
+}
+
+func (e *fakeExchange) GetWalletBalance(ctx context.Context) ([]bybit.CoinBalance, error) {
+	return []bybit.CoinBalance{{Coin: "USDT", WalletBalance: "1000000", Locked: "0"}}, nil
+}
+
+func (e *fakeExchange) ListWalletChanges(ctx context.Context, coin string, since time.Time) ([]bybit.WalletChange, error) {
+	return nil, nil
+}
+
+func (e *fakeExchange) GetInstrumentInfo(ctx context.Context, symbol string) (*bybit.InstrumentInfo, error) {
+	return &bybit.InstrumentInfo{
+		Symbol:         symbol,
+		TickSize:       "0.01",
+		QtyStep:        "0.000001",
+		QuotePrecision: "0.01",
+		MinOrderQty:    "0.000001",
+		MinOrderAmt:    "1",
+	}, nil
+}
+
+func (e *fakeExchange) Latency() *latency.Tracker {
+	return e.latency
+}
+
+func (e *fakeExchange) RateLimits() *ratelimit.Tracker {
+	return e.rateLimits
+}
+
+func (e *fakeExchange) Clock() *latency.ClockOffset {
+	return e.clock
+}
+
+// testTradeConfig - конфигурация сделки тестов сервиса: вход 100, три уровня DCA по 2%, TP 1%.
+func testTradeConfig() domain.TradeConfig {
+	return domain.TradeConfig{
+		Symbol:            "BTCUSDT",
+		EntryVolume:       "100",
+		DCAStepPercent:    2,
+		DCAVolume:         "100",
+		DCACount:          3,
+		TakeProfitPercent: 1,
+		Martingale:        1.5,
+		Force:             true,
+	}
+}
+
+// newFakeTradeService собирает TradeService на фейковой бирже и хранилищах в памяти.
+func newFakeTradeService(t *testing.T, exchange ExchangeClient, journal domain.EventJournal, repository domain.TradeRepository) *TradeService {
+	t.Helper()
+
+	precision, err := NewPrecisionOverrides(storage.NewMemoryPrecisionStore())
+	require.NoError(t, err)
+	symbols, err := NewSymbolLists(nil, nil)
+	require.NoError(t, err)
+	cooldowns, err := NewSymbolCooldowns(storage.NewMemoryCooldownStore(), 0)
+	require.NoError(t, err)
+
+	orders := NewOrderManager(exchange, NewOrderStateCache(0), metrics.NoopRecorder{}, precision, false)
+	risk := NewRiskManager(exchange, nil, symbols, cooldowns, nil, nil)
+	return NewTradeManager(orders, risk, journal, repository,
+		storage.NewMemoryExecutionStore(domain.DefaultExecutionDedupTTL), metrics.NoopRecorder{},
+		notify.LogNotifier{}, storage.NewMemoryTradeLocker(), NewExposureGuard(), nil)
+}
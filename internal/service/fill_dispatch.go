@@ -0,0 +1,71 @@
+package service
+
+import (
+	"errors"
+	"log"
+
+	"cryptorg/internal/domain"
+)
+
+// ErrFillQueueFull - очередь воркера сделки переполнена, отправитель должен повторить доставку.
+var ErrFillQueueFull = errors.New("fill queue is full")
+
+// Dispatch применяет обновление ордера к кэшу и ставит в очередь исполнения, которые
+// меняют сделку. Общий путь для вебхука и потоков ордеров бирж.
+func (p *FillPool) Dispatch(update domain.OrderUpdate, execID string) error {
+	p.tradeManager.ApplyOrderUpdate(update)
+
+	switch update.Status {
+	case domain.OrderStatusNew, domain.OrderStatusUntriggered, domain.OrderStatusTriggered:
+		// Ордер еще стоит на бирже, состояние уже в кэше
+	case domain.OrderStatusPartially:
+		// Частичное исполнение DCA сразу входит в позицию и TP; выходы ждут полного исполнения
+		trade, err := p.tradeManager.FindTradeByOrderID(update.OrderID)
+		if err != nil || orderRole(trade, update.OrderID) != "dca" {
+			return nil
+		}
+		return p.Submit(trade.ID, update.OrderID, execID)
+	case domain.OrderStatusCanceled:
+		// Отмены идут в основном от самого бота при перестановке ордеров
+	case domain.OrderStatusRejected, domain.OrderStatusDeactivated:
+		// Пропавший ордер сделки найдет сверка с биржей
+		log.Printf("Order %s on %s closed without fill: %s", update.OrderID, update.Symbol, update.Status)
+	case domain.OrderStatusFilled, domain.OrderStatusPartiallyCanceled:
+		trade, err := p.tradeManager.FindTradeByOrderID(update.OrderID)
+		if err != nil {
+			// Чужой ордер на том же счете
+			return nil
+		}
+
+		if orderRole(trade, update.OrderID) != "entry" {
+			// Обработка идет в воркере сделки, ответ отправителю не ждет биржу
+			return p.Submit(trade.ID, update.OrderID, execID)
+		}
+	default:
+		log.Printf("Unknown status %q of order %s on %s", update.Status, update.OrderID, update.Symbol)
+	}
+	return nil
+}
+
+// orderRole - роль ордера в сделке: entry, take_profit, stop_loss, dca или unknown.
+func orderRole(trade *domain.Trade, orderID string) string {
+	if trade.EntryOrder != nil && trade.EntryOrder.BybitID == orderID {
+		return "entry"
+	}
+
+	if trade.TakeProfitOrder != nil && trade.TakeProfitOrder.BybitID == orderID {
+		return "take_profit"
+	}
+
+	if trade.StopLossOrder != nil && trade.StopLossOrder.BybitID == orderID {
+		return "stop_loss"
+	}
+
+	for _, dcaOrder := range trade.DCAOrders {
+		if dcaOrder.BybitID == orderID {
+			return "dca"
+		}
+	}
+
+	return "unknown"
+}
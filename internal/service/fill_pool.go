@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"runtime/debug"
+	"strconv"
+	"sync"
+	"time"
+
+	"cryptorg/internal/metrics"
+
+	"github.com/google/uuid"
+)
+
+type fillJob struct {
+	tradeID    uuid.UUID
+	orderID    string
+	execID     string
+	enqueuedAt time.Time
+}
+
+// FillPool обрабатывает исполнения ордеров пулом воркеров. Воркер выбирается по хешу
+// ID сделки, поэтому исполнения одной сделки идут по порядку, а разных - параллельно.
+type FillPool struct {
+	tradeManager *TradeService
+	metrics      metrics.Recorder
+	queues       []chan fillJob
+	wg           sync.WaitGroup
+}
+
+func NewFillPool(tradeManager *TradeService, recorder metrics.Recorder, workers, queueSize int) *FillPool {
+	if workers <= 0 {
+		workers = 1
+	}
+
+	queues := make([]chan fillJob, workers)
+	for i := range queues {
+		queues[i] = make(chan fillJob, queueSize)
+	}
+
+	return &FillPool{
+		tradeManager: tradeManager,
+		metrics:      recorder,
+		queues:       queues,
+	}
+}
+
+// Submit ставит исполнение в очередь воркера сделки. Если очередь переполнена,
+// возвращается ошибка, чтобы отправитель вебхука повторил доставку.
+func (p *FillPool) Submit(tradeID uuid.UUID, orderID, execID string) error {
+	job := fillJob{tradeID: tradeID, orderID: orderID, execID: execID, enqueuedAt: time.Now()}
+
+	select {
+	case p.queues[p.worker(tradeID)] <- job:
+		p.metrics.SetGauge("fill_queue_depth", float64(p.QueueDepth()), nil)
+		return nil
+	default:
+		p.metrics.IncCounter("fills_rejected_total", nil)
+		return ErrFillQueueFull
+	}
+}
+
+// Run запускает воркеры и ждет их завершения после отмены ctx.
+// Уже поставленные в очередь исполнения при остановке не обрабатываются.
+func (p *FillPool) Run(ctx context.Context) {
+	for i, queue := range p.queues {
+		p.wg.Add(1)
+		go p.work(ctx, i, queue)
+	}
+	p.wg.Wait()
+}
+
+func (p *FillPool) work(ctx context.Context, index int, queue chan fillJob) {
+	defer p.wg.Done()
+
+	worker := strconv.Itoa(index)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-queue:
+			p.metrics.ObserveHistogram("fill_queue_latency_seconds", metrics.Since(job.enqueuedAt), metrics.Labels{"worker": worker})
+
+			p.metrics.IncCounter("fills_processed_total", metrics.Labels{"result": p.process(ctx, job)})
+		}
+	}
+}
+
+// process обрабатывает одно исполнение. Паника не роняет воркер и процесс: стек пишется
+// в журнал, а сделка помечается для ручной проверки, так как перестановка TP могла прерваться.
+func (p *FillPool) process(ctx context.Context, job fillJob) (result string) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			result = "panic"
+			log.Printf("PANIC: processing fill %s of trade %s: %v\n%s", job.orderID, job.tradeID, recovered, debug.Stack())
+			p.tradeManager.FlagAttention(ctx, job.tradeID, fmt.Sprintf("panic while processing fill %s: %v", job.orderID, recovered))
+		}
+	}()
+
+	if err := p.tradeManager.ProcessOrderExecution(ctx, job.tradeID, job.orderID, job.execID); err != nil {
+		log.Printf("Failed to process fill %s of trade %s: %v", job.orderID, job.tradeID, err)
+		return "error"
+	}
+	return "ok"
+}
+
+// QueueDepth возвращает число исполнений, ожидающих обработки.
+func (p *FillPool) QueueDepth() int {
+	depth := 0
+	for _, queue := range p.queues {
+		depth += len(queue)
+	}
+	return depth
+}
+
+func (p *FillPool) worker(tradeID uuid.UUID) int {
+	hash := fnv.New32a()
+	hash.Write(tradeID[:])
+	return int(hash.Sum32() % uint32(len(p.queues)))
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"cryptorg/internal/domain"
+	"cryptorg/pkg/pricing"
+)
+
+// BuildGrid рассчитывает уровни DCA сетки от цены входа: ниже нее для лонга, выше для шорта.
+// Первый уровень имеет объем DCAVolume, каждый следующий - объем предыдущего, умноженный на Martingale.
+func BuildGrid(config domain.TradeConfig, entryPrice domain.Decimal) []domain.GridLevel {
+	levels := make([]domain.GridLevel, 0, config.DCACount)
+	if config.Strategy == domain.StrategyTimeBased || !entryPrice.IsPositive() {
+		return levels
+	}
+
+	volume, _ := domain.ParseDecimal(config.DCAVolume)
+	minVolume, _ := domain.ParseDecimal(config.MinLevelVolume)
+	rungs := pricing.Ladder(entryPrice, pricing.LadderParams{
+		Count:            config.DCACount,
+		StepPercent:      config.DCAStepPercent,
+		DynamicStep:      config.DynamicStep,
+		Volume:           volume,
+		Martingale:       domain.DecimalFromFloat(config.Martingale),
+		LegacyMartingale: config.LegacyMartingale,
+		MinVolume:        minVolume,
+		Short:            config.IsShort(),
+	})
+
+	for _, rung := range rungs {
+		levels = append(levels, domain.GridLevel{
+			Index:            rung.Index,
+			Price:            domain.FormatDecimal(rung.Price),
+			Volume:           domain.FormatDecimal(rung.Volume),
+			DeviationPercent: rung.DeviationPercent.InexactFloat64(),
+			Bumped:           rung.Bumped,
+		})
+	}
+
+	return levels
+}
+
+func requiredCapital(config domain.TradeConfig, grid []domain.GridLevel) float64 {
+	if config.Strategy == domain.StrategyTimeBased && config.MaxBudget != "" {
+		budget, _ := domain.ParseDecimal(config.MaxBudget)
+		return budget.InexactFloat64()
+	}
+
+	total, _ := domain.ParseDecimal(config.EntryVolume)
+	for _, level := range grid {
+		volume, _ := domain.ParseDecimal(level.Volume)
+		total = total.Add(volume)
+	}
+	return total.InexactFloat64()
+}
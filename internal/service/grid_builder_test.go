@@ -0,0 +1,130 @@
+package service
+
+import (
+	"testing"
+
+	"cryptorg/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type goldenLevel struct {
+	price  string
+	volume string
+	bumped bool
+}
+
+func TestBuildGridGolden(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(config *domain.TradeConfig)
+		levels  []goldenLevel
+		capital float64
+	}{
+		{
+			name: "martingale from second level",
+			levels: []goldenLevel{
+				{price: "98.00000000", volume: "100.00000000"},
+				{price: "96.04000000", volume: "150.00000000"},
+				{price: "94.11920000", volume: "225.00000000"},
+			},
+			capital: 575,
+		},
+		{
+			name:   "legacy martingale from first level",
+			mutate: func(config *domain.TradeConfig) { config.LegacyMartingale = true },
+			levels: []goldenLevel{
+				{price: "98.00000000", volume: "150.00000000"},
+				{price: "96.04000000", volume: "225.00000000"},
+				{price: "94.11920000", volume: "337.50000000"},
+			},
+			capital: 812.5,
+		},
+		{
+			name:   "without martingale",
+			mutate: func(config *domain.TradeConfig) { config.Martingale = 0 },
+			levels: []goldenLevel{
+				{price: "98.00000000", volume: "100.00000000"},
+				{price: "96.04000000", volume: "100.00000000"},
+				{price: "94.11920000", volume: "100.00000000"},
+			},
+			capital: 400,
+		},
+		{
+			name:   "short goes up",
+			mutate: func(config *domain.TradeConfig) { config.Side = domain.OrderSideSell },
+			levels: []goldenLevel{
+				{price: "102.00000000", volume: "100.00000000"},
+				{price: "104.04000000", volume: "150.00000000"},
+				{price: "106.12080000", volume: "225.00000000"},
+			},
+			capital: 575,
+		},
+		{
+			name:   "dynamic step",
+			mutate: func(config *domain.TradeConfig) { config.DynamicStep = true },
+			levels: []goldenLevel{
+				{price: "98.00000000", volume: "100.00000000"},
+				{price: "94.08000000", volume: "150.00000000"},
+				{price: "88.43520000", volume: "225.00000000"},
+			},
+			capital: 575,
+		},
+		{
+			name: "min level volume bump keeps martingale base",
+			mutate: func(config *domain.TradeConfig) {
+				config.DCAVolume = "4"
+				config.MinLevelVolume = "5"
+			},
+			levels: []goldenLevel{
+				{price: "98.00000000", volume: "5.00000000", bumped: true},
+				{price: "96.04000000", volume: "6.00000000"},
+				{price: "94.11920000", volume: "9.00000000"},
+			},
+			capital: 120,
+		},
+		{
+			name: "time based has no grid",
+			mutate: func(config *domain.TradeConfig) {
+				config.Strategy = domain.StrategyTimeBased
+				config.MaxBudget = "1000"
+			},
+			capital: 1000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testTradeConfig()
+			if tt.mutate != nil {
+				tt.mutate(&config)
+			}
+
+			grid := BuildGrid(config, domain.DecimalFromFloat(100))
+			require.Len(t, grid, len(tt.levels))
+			for i, want := range tt.levels {
+				assert.Equal(t, i+1, grid[i].Index)
+				assert.Equal(t, want.price, grid[i].Price, "level %d price", i+1)
+				assert.Equal(t, want.volume, grid[i].Volume, "level %d volume", i+1)
+				assert.Equal(t, want.bumped, grid[i].Bumped, "level %d bumped", i+1)
+			}
+			assert.InDelta(t, tt.capital, requiredCapital(config, grid), 1e-9)
+		})
+	}
+}
+
+func TestBuildGridWithoutEntryPrice(t *testing.T) {
+	assert.Empty(t, BuildGrid(testTradeConfig(), domain.DecimalZero))
+}
+
+func TestTakeProfitAmount(t *testing.T) {
+	// TP закрывает только набранную позицию, а не всю будущую сетку
+	trade := &domain.Trade{CurrentPositionQty: "0.999"}
+	amount, err := takeProfitAmount(trade, domain.DecimalFromFloat(101))
+	require.NoError(t, err)
+	assert.Equal(t, "100.89900000", amount)
+
+	_, err = takeProfitAmount(&domain.Trade{}, domain.DecimalFromFloat(101))
+	assert.Error(t, err)
+}
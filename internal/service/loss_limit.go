@@ -0,0 +1,109 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"cryptorg/internal/domain"
+	apperrors "cryptorg/pkg/errors"
+)
+
+const lossWindow = 24 * time.Hour
+
+// DailyLossLimits - допустимый убыток за скользящие 24 часа по котируемым валютам.
+func (s *RiskService) DailyLossLimits() map[string]float64 {
+	return s.lossLimits
+}
+
+func (s *RiskService) Suspension() domain.TradingSuspension {
+	s.lossMu.Lock()
+	defer s.lossMu.Unlock()
+
+	suspension := s.suspension
+	suspension.Limits = s.lossLimits
+	suspension.PnL = formatByQuote(s.lastPnL)
+	if s.baselineActive(time.Now()) {
+		suspension.Baseline = formatByQuote(s.baseline)
+	}
+	return suspension
+}
+
+// checkSuspended возвращает доменную ошибку TRADING_SUSPENDED, пока торговля остановлена.
+func (s *RiskService) checkSuspended() error {
+	s.lossMu.Lock()
+	defer s.lossMu.Unlock()
+
+	if !s.suspension.Suspended {
+		return nil
+	}
+	appErr := apperrors.DomainError("new trades are suspended: "+s.suspension.Reason, "TRADING_SUSPENDED")
+	appErr.Details = map[string]interface{}{"suspended_at": s.suspension.SuspendedAt}
+	return appErr
+}
+
+// evaluateLoss запоминает PnL проверки и возвращает первую валюту, убыток по которой
+// (за вычетом базы после ручного возобновления) превысил лимит.
+func (s *RiskService) evaluateLoss(pnl map[string]float64) (quote string, loss float64, breached bool) {
+	s.lossMu.Lock()
+	defer s.lossMu.Unlock()
+
+	now := time.Now()
+	s.lastPnL = pnl
+	s.suspension.CheckedAt = &now
+
+	quotes := make([]string, 0, len(s.lossLimits))
+	for quote := range s.lossLimits {
+		quotes = append(quotes, quote)
+	}
+	sort.Strings(quotes)
+
+	for _, quote := range quotes {
+		loss := -pnl[quote]
+		if s.baselineActive(now) {
+			loss += math.Min(s.baseline[quote], 0)
+		}
+		if limit := s.lossLimits[quote]; limit > 0 && loss > limit {
+			return quote, loss, true
+		}
+	}
+	return "", 0, false
+}
+
+// suspend останавливает открытие новых циклов; false - если торговля уже остановлена.
+func (s *RiskService) suspend(reason string) bool {
+	s.lossMu.Lock()
+	defer s.lossMu.Unlock()
+
+	if s.suspension.Suspended {
+		return false
+	}
+	now := time.Now()
+	s.suspension.Suspended = true
+	s.suspension.SuspendedAt = &now
+	s.suspension.Reason = reason
+	return true
+}
+
+// Resume снимает остановку. Убыток на этот момент становится базой на 24 часа,
+// чтобы те же потери сразу не остановили торговлю снова.
+func (s *RiskService) Resume() error {
+	s.lossMu.Lock()
+	defer s.lossMu.Unlock()
+
+	if !s.suspension.Suspended {
+		return fmt.Errorf("trading is not suspended")
+	}
+
+	now := time.Now()
+	s.suspension.Suspended = false
+	s.suspension.Reason = ""
+	s.suspension.ResumedAt = &now
+	s.baseline = s.lastPnL
+	return nil
+}
+
+func (s *RiskService) baselineActive(now time.Time) bool {
+	return s.suspension.ResumedAt != nil && now.Sub(*s.suspension.ResumedAt) < lossWindow
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"cryptorg/internal/domain"
+)
+
+// FetchBestPrice возвращает лучшую цену своей стороны стакана: bid для покупки, ask для продажи.
+func (s *OrderService) FetchBestPrice(ctx context.Context, symbol string, side domain.OrderSide) (float64, error) {
+	start := time.Now()
+	ticker, err := s.exchangeClient.GetTicker(ctx, symbol)
+	s.observeExchange("get_ticker", start, err)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch ticker: %w", err)
+	}
+
+	price := ticker.Bid1Price
+	if side == domain.OrderSideSell {
+		price = ticker.Ask1Price
+	}
+
+	best, err := strconv.ParseFloat(price, 64)
+	if err != nil || best <= 0 {
+		return 0, fmt.Errorf("invalid best price %q", price)
+	}
+	return best, nil
+}
+
+// ExecuteMakerOrder заменяет рыночный ордер PostOnly лимиткой у края стакана и
+// переставляет ее за ценой, пока она не исполнится. Частичное исполнение
+// возвращается как есть - дальше его обрабатывает политика частичного входа.
+func (s *OrderService) ExecuteMakerOrder(ctx context.Context, req domain.CreateOrderRequest) (*domain.Order, error) {
+	for attempt := 1; attempt <= domain.MakerChaseAttempts; attempt++ {
+		price, err := s.FetchBestPrice(ctx, req.Symbol, req.Side)
+		if err != nil {
+			return nil, err
+		}
+
+		limitReq := req
+		limitReq.Type = domain.OrderTypeLimit
+		limitReq.Price = fmt.Sprintf("%.8f", price)
+		limitReq.PostOnly = true
+
+		order, err := s.ExecuteLimitOrder(ctx, limitReq)
+		if err != nil {
+			return nil, fmt.Errorf("failed to place maker order: %w", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			if err := s.TerminateOrder(context.Background(), req.Symbol, order.BybitID); err != nil {
+			}
+			return nil, ctx.Err()
+		case <-time.After(domain.MakerChaseInterval):
+		}
+
+		status, err := s.FetchOrderStatus(ctx, req.Symbol, order.BybitID)
+		if err != nil {
+			return nil, err
+		}
+		if isFilledStatus(status.Status) {
+			return status, nil
+		}
+
+		// Ордер мог исполниться между проверкой и отменой - это видно по ExecutedQty ниже
+		if err := s.TerminateOrder(ctx, req.Symbol, order.BybitID); err != nil {
+		}
+
+		if executed, _ := strconv.ParseFloat(status.ExecutedQty, 64); executed > 0 {
+			status.Status = domain.OrderStatusPartially
+			return status, nil
+		}
+	}
+
+	return nil, fmt.Errorf("maker order for %s not filled after %d attempts", req.Symbol, domain.MakerChaseAttempts)
+}
+
+func isFilledStatus(status domain.OrderStatus) bool {
+	return status.IsFilled()
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cryptorg/internal/bybit"
+	"cryptorg/internal/domain"
+)
+
+// Глубина стакана и число свечей по умолчанию для запросов рыночных данных.
+const (
+	DefaultMarketBookDepth = 50
+	DefaultMarketKlines    = 200
+	MaxMarketKlines        = 1000
+	DefaultMarketInterval  = DefaultBacktestInterval
+)
+
+// ValidKlineInterval проверяет интервал свечей Bybit.
+func ValidKlineInterval(interval string) bool {
+	_, ok := backtestIntervals[interval]
+	return ok
+}
+
+// MarketTicker возвращает текущие цены символа.
+func (s *OrderService) MarketTicker(ctx context.Context, symbol string) (*domain.MarketTicker, error) {
+	start := time.Now()
+	ticker, err := s.exchangeClient.GetTicker(ctx, symbol)
+	s.observeExchange("get_ticker", start, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ticker: %w", err)
+	}
+
+	return &domain.MarketTicker{
+		Symbol:      symbol,
+		LastPrice:   formatExchangeDecimal(ticker.LastPrice),
+		BidPrice:    formatExchangeDecimal(ticker.Bid1Price),
+		AskPrice:    formatExchangeDecimal(ticker.Ask1Price),
+		HighPrice:   formatExchangeDecimal(ticker.HighPrice),
+		LowPrice:    formatExchangeDecimal(ticker.LowPrice),
+		Volume24h:   formatExchangeDecimal(ticker.Volume24h),
+		Turnover24h: formatExchangeDecimal(ticker.Turnover),
+		Timestamp:   time.Now(),
+	}, nil
+}
+
+// MarketOrderBook возвращает до depth уровней стакана на каждую сторону.
+func (s *OrderService) MarketOrderBook(ctx context.Context, symbol string, depth int) (*domain.MarketOrderBook, error) {
+	book, err := s.FetchOrderBook(ctx, symbol, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.MarketOrderBook{
+		Symbol:    symbol,
+		Bids:      marketBookLevels(book.Bids),
+		Asks:      marketBookLevels(book.Asks),
+		Timestamp: book.Time,
+	}, nil
+}
+
+// MarketKlines возвращает последние limit свечей интервала Bybit (1, 5, 60, D и т.д.).
+func (s *OrderService) MarketKlines(ctx context.Context, symbol, interval string, limit int) (*domain.MarketKlines, error) {
+	klines, err := s.FetchKlines(ctx, symbol, interval, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.MarketKlines{
+		Symbol:   symbol,
+		Interval: interval,
+		Klines:   make([]domain.MarketKline, 0, len(klines)),
+	}
+	for _, kline := range klines {
+		result.Klines = append(result.Klines, domain.MarketKline{
+			StartTime: time.UnixMilli(kline.StartTime).UTC(),
+			Open:      formatFloat(kline.Open),
+			High:      formatFloat(kline.High),
+			Low:       formatFloat(kline.Low),
+			Close:     formatFloat(kline.Close),
+			Volume:    formatFloat(kline.Volume),
+		})
+	}
+	return result, nil
+}
+
+func marketBookLevels(levels []bybit.BookLevel) []domain.MarketBookLevel {
+	result := make([]domain.MarketBookLevel, 0, len(levels))
+	for _, level := range levels {
+		result = append(result, domain.MarketBookLevel{Price: formatFloat(level.Price), Size: formatFloat(level.Size)})
+	}
+	return result
+}
+
+func formatFloat(value float64) string {
+	return domain.FormatDecimal(domain.DecimalFromFloat(value))
+}
+
+// formatExchangeDecimal приводит десятичную строку биржи к формату домена; пустое
+// или некорректное значение (у малоликвидных пар бывает пустой bid/ask) остается пустым.
+func formatExchangeDecimal(value string) string {
+	parsed, err := domain.ParseDecimal(value)
+	if err != nil {
+		return ""
+	}
+	return domain.FormatDecimal(parsed)
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"cryptorg/internal/bybit"
+	"cryptorg/internal/domain"
+	"cryptorg/internal/tracing"
+	apperrors "cryptorg/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// AmendOrder меняет цену и/или количество активного ордера. Значения проверяются по фильтрам
+// инструмента до запроса к бирже; quantity задается в базовой монете, как на бирже.
+func (s *OrderService) AmendOrder(ctx context.Context, req domain.AmendOrderRequest) (*domain.Order, error) {
+	if req.Price == "" && req.Quantity == "" {
+		return nil, apperrors.ValidationError("price", "price or quantity is required")
+	}
+
+	info, err := s.InstrumentInfo(ctx, req.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateAmend(info, req); err != nil {
+		return nil, err
+	}
+
+	ctx, span := tracing.Start(ctx, "OrderService.AmendOrder", tracing.Symbol(req.Symbol), tracing.OrderID(req.OrderID))
+	start := time.Now()
+	err = s.exchangeClient.AmendOrder(ctx, bybit.ExchangeAmendRequest{
+		Symbol:  req.Symbol,
+		OrderID: req.OrderID,
+		Qty:     req.Quantity,
+		Price:   req.Price,
+	})
+	s.observeExchange("amend_order", start, err)
+	tracing.End(span, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to amend order: %w", err)
+	}
+
+	order, err := s.FetchOrderStatus(ctx, req.Symbol, req.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	s.orderCache.Update(*order)
+	return order, nil
+}
+
+func validateAmend(info *bybit.InstrumentInfo, req domain.AmendOrderRequest) error {
+	var price, qty float64
+	var err error
+
+	if req.Price != "" {
+		if price, err = strconv.ParseFloat(req.Price, 64); err != nil || price <= 0 {
+			return apperrors.ValidationError("price", "must be a positive number")
+		}
+		if !multipleOfStep(price, info.TickSize) {
+			return apperrors.DomainError(fmt.Sprintf("price %s is not a multiple of tick size %s", req.Price, info.TickSize), "INVALID_PRICE_STEP")
+		}
+	}
+
+	if req.Quantity != "" {
+		if qty, err = strconv.ParseFloat(req.Quantity, 64); err != nil || qty <= 0 {
+			return apperrors.ValidationError("quantity", "must be a positive number")
+		}
+		if !multipleOfStep(qty, info.QtyStep) {
+			return apperrors.DomainError(fmt.Sprintf("quantity %s is not a multiple of qty step %s", req.Quantity, info.QtyStep), "INVALID_QTY_STEP")
+		}
+		if minQty, err := strconv.ParseFloat(info.MinOrderQty, 64); err == nil && qty < minQty {
+			return apperrors.DomainError(fmt.Sprintf("quantity %s is below minimum %s", req.Quantity, info.MinOrderQty), "BELOW_MIN_QTY")
+		}
+	}
+
+	if price > 0 && qty > 0 {
+		if minAmt, err := strconv.ParseFloat(info.MinOrderAmt, 64); err == nil && price*qty < minAmt {
+			return apperrors.DomainError(fmt.Sprintf("order value %.8f is below minimum %s", price*qty, info.MinOrderAmt), "BELOW_MIN_NOTIONAL")
+		}
+	}
+
+	return nil
+}
+
+// multipleOfStep проверяет кратность шагу фильтра; без шага проверка пропускается.
+func multipleOfStep(value float64, step string) bool {
+	s, err := strconv.ParseFloat(step, 64)
+	if err != nil || s <= 0 {
+		return true
+	}
+	ratio := value / s
+	return math.Abs(ratio-math.Round(ratio)) < 1e-6
+}
+
+// AmendOrder изменяет ордер через биржу и, если он принадлежит сделке, обновляет
+// его в сделке и пишет событие в журнал.
+func (s *TradeService) AmendOrder(ctx context.Context, req domain.AmendOrderRequest) (*domain.Order, error) {
+	tradeID, owned := s.lookupOrderTrade(req.OrderID)
+	orders := s.orderManager
+	if owned {
+		unlock, err := s.locker.Lock(ctx, tradeID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock trade: %w", err)
+		}
+		defer unlock()
+
+		s.mu.RLock()
+		if trade, ok := s.trades[tradeID]; ok {
+			orders = s.ordersFor(trade.Config)
+		}
+		s.mu.RUnlock()
+	}
+
+	amended, err := orders.AmendOrder(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("AUDIT: order %s on %s amended: price=%q quantity=%q", req.OrderID, req.Symbol, req.Price, req.Quantity)
+	if !owned {
+		return amended, nil
+	}
+
+	s.mu.Lock()
+	trade, exists := s.trades[tradeID]
+	var order *domain.Order
+	if exists {
+		order = mutableTradeOrder(trade, req.OrderID)
+	}
+	if order != nil {
+		order.Price = amended.Price
+		order.Quantity = amended.Quantity
+		order.UpdatedAt = time.Now()
+		trade.UpdatedAt = order.UpdatedAt
+	}
+	s.mu.Unlock()
+
+	if order != nil {
+		s.recordEvent(trade, domain.TradeEventOrderAmended, order, fmt.Sprintf("price=%s quantity=%s", amended.Price, amended.Quantity))
+	}
+	return amended, nil
+}
+
+func (s *TradeService) lookupOrderTrade(orderID string) (uuid.UUID, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tradeID, ok := s.orderIndex[orderID]
+	return tradeID, ok
+}
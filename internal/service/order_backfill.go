@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"cryptorg/internal/domain"
+)
+
+// backfillQtyTolerance - относительное расхождение суммы исполнений с executedQty ордера,
+// при котором список исполнений считается полным.
+const backfillQtyTolerance = 1e-6
+
+// BackfillOrder запрашивает состояние ордера, закрытого, пока бот не следил за ним, и
+// уточняет его по списку исполнений: время ордера - последнее исполнение, а не последнее
+// изменение на бирже; объем, сумма и комиссия - суммы по исполнениям. Неполный список
+// исполнений уточняет только время.
+func (s *OrderService) BackfillOrder(ctx context.Context, symbol, orderID string) (*domain.Order, error) {
+	order, err := s.FetchOrderStatus(ctx, symbol, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !order.Status.ClosedWithFills() {
+		return order, nil
+	}
+
+	start := time.Now()
+	executions, err := s.exchangeClient.ListExecutions(ctx, symbol, orderID)
+	s.observeExchange("list_executions", start, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list executions of order %s: %w", orderID, err)
+	}
+	if len(executions) == 0 {
+		return order, nil
+	}
+
+	var qty, value, fee float64
+	var filledAt time.Time
+	for _, execution := range executions {
+		execQty, _ := strconv.ParseFloat(execution.Qty, 64)
+		execValue, _ := strconv.ParseFloat(execution.Value, 64)
+		execFee, _ := strconv.ParseFloat(execution.Fee, 64)
+		if execValue == 0 {
+			price, _ := strconv.ParseFloat(execution.Price, 64)
+			execValue = price * execQty
+		}
+		qty += execQty
+		value += execValue
+		fee += execFee
+
+		if at, ok := s.parseExchangeTime(execution.Time); ok && at.After(filledAt) {
+			filledAt = at
+		}
+	}
+
+	if !filledAt.IsZero() {
+		order.UpdatedAt = filledAt
+	}
+	executed, _ := strconv.ParseFloat(order.ExecutedQty, 64)
+	if executed > 0 && qty >= executed*(1-backfillQtyTolerance) {
+		order.ExecutedQty = fmt.Sprintf("%.8f", qty)
+		order.ExecutedValue = fmt.Sprintf("%.8f", value)
+		order.Fee = fmt.Sprintf("%.8f", fee)
+	}
+	s.metrics.IncCounter("orders_backfilled_total", nil)
+	return order, nil
+}
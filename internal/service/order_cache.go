@@ -0,0 +1,81 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"cryptorg/internal/domain"
+)
+
+type cachedOrder struct {
+	order     domain.Order
+	updatedAt time.Time
+}
+
+// OrderStateCache хранит последнее известное состояние ордеров из событий биржи,
+// чтобы не дергать REST на каждое исполнение. Ордера закрытых сделок удаляются сразу,
+// остальные устаревшие записи вычищает Update не чаще раза в ttl.
+type OrderStateCache struct {
+	mu        sync.RWMutex
+	ttl       time.Duration
+	entries   map[string]cachedOrder
+	lastSweep time.Time
+}
+
+func NewOrderStateCache(ttl time.Duration) *OrderStateCache {
+	return &OrderStateCache{
+		ttl:     ttl,
+		entries: make(map[string]cachedOrder),
+	}
+}
+
+func (c *OrderStateCache) Update(order domain.Order) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.entries[order.BybitID] = cachedOrder{order: order, updatedAt: now}
+	if now.Sub(c.lastSweep) > c.ttl {
+		c.sweep(now)
+	}
+}
+
+// sweep удаляет записи старше ttl; вызывается под mu.
+func (c *OrderStateCache) sweep(now time.Time) {
+	for orderID, entry := range c.entries {
+		if now.Sub(entry.updatedAt) > c.ttl {
+			delete(c.entries, orderID)
+		}
+	}
+	c.lastSweep = now
+}
+
+// Len возвращает число записей, включая еще не вычищенные устаревшие.
+func (c *OrderStateCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.entries)
+}
+
+func (c *OrderStateCache) Get(orderID string) (*domain.Order, bool) {
+	c.mu.RLock()
+	entry, exists := c.entries[orderID]
+	c.mu.RUnlock()
+
+	if !exists || time.Since(entry.updatedAt) > c.ttl {
+		return nil, false
+	}
+
+	order := entry.order
+	return &order, true
+}
+
+func (c *OrderStateCache) Delete(orderIDs ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, orderID := range orderIDs {
+		delete(c.entries, orderID)
+	}
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"cryptorg/internal/domain"
+	"cryptorg/internal/metrics"
+)
+
+// ResolveFillPrice подставляет в рыночный ордер среднюю цену исполнения. Ответ Bybit на
+// создание ордера не содержит цены или содержит "0", а от цены входа строятся TP и сетка.
+// Цена берется из состояния ордера (/v5/order/realtime), затем из списка исполнений; если
+// биржа еще не отдала исполнение, используется последняя цена символа.
+func (s *OrderService) ResolveFillPrice(ctx context.Context, order *domain.Order) error {
+	if price, _ := strconv.ParseFloat(order.Price, 64); price > 0 {
+		return nil
+	}
+
+	if updated, err := s.FetchOrderStatus(ctx, order.Symbol, order.BybitID); err != nil {
+		log.Printf("Failed to fetch order %s for fill price: %v", order.BybitID, err)
+	} else {
+		order.Status = updated.Status
+		order.ExecutedQty = updated.ExecutedQty
+		order.ExecutedValue = updated.ExecutedValue
+		order.Fee = updated.Fee
+		order.UpdatedAt = updated.UpdatedAt
+		if price := averageFillPrice(order.ExecutedQty, order.ExecutedValue); price > 0 {
+			s.setFillPrice(order, price, "realtime")
+			return nil
+		}
+	}
+
+	start := time.Now()
+	executions, err := s.exchangeClient.ListExecutions(ctx, order.Symbol, order.BybitID)
+	s.observeExchange("list_executions", start, err)
+	if err != nil {
+		log.Printf("Failed to list executions of order %s for fill price: %v", order.BybitID, err)
+	} else {
+		var qty, value float64
+		for _, execution := range executions {
+			execQty, _ := strconv.ParseFloat(execution.Qty, 64)
+			execValue, _ := strconv.ParseFloat(execution.Value, 64)
+			if execValue == 0 {
+				price, _ := strconv.ParseFloat(execution.Price, 64)
+				execValue = price * execQty
+			}
+			qty += execQty
+			value += execValue
+		}
+		if qty > 0 && value > 0 {
+			s.setFillPrice(order, value/qty, "executions")
+			return nil
+		}
+	}
+
+	price, err := s.FetchLastPrice(ctx, order.Symbol)
+	if err != nil {
+		return fmt.Errorf("failed to resolve fill price of order %s: %w", order.BybitID, err)
+	}
+	log.Printf("WARNING: no fill price for order %s on %s, using last price %.8f", order.BybitID, order.Symbol, price)
+	s.setFillPrice(order, price, "ticker")
+	return nil
+}
+
+func (s *OrderService) setFillPrice(order *domain.Order, price float64, source string) {
+	order.Price = fmt.Sprintf("%.8f", price)
+	s.metrics.IncCounter("fill_price_resolved_total", metrics.Labels{"source": source})
+}
+
+func averageFillPrice(executedQty, executedValue string) float64 {
+	qty, _ := strconv.ParseFloat(executedQty, 64)
+	value, _ := strconv.ParseFloat(executedValue, 64)
+	if qty <= 0 || value <= 0 {
+		return 0
+	}
+	return value / qty
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"cryptorg/internal/bybit"
+	"cryptorg/internal/domain"
+	"cryptorg/internal/metrics"
+	"cryptorg/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const fillOrderID = "1868412334150428000"
+
+func newMockOrderService(t *testing.T) (*OrderService, *bybit.MockClient) {
+	t.Helper()
+
+	precision, err := NewPrecisionOverrides(storage.NewMemoryPrecisionStore())
+	require.NoError(t, err)
+	client := &bybit.MockClient{}
+	t.Cleanup(func() { client.AssertExpectations(t) })
+	return NewOrderManager(client, NewOrderStateCache(0), metrics.NoopRecorder{}, precision, false), client
+}
+
+// placeMarketEntry выставляет рыночный вход; как у Bybit, ответ на создание содержит price "0".
+func placeMarketEntry(t *testing.T, orders *OrderService, client *bybit.MockClient) *domain.Order {
+	t.Helper()
+
+	client.On("GetInstrumentInfo", mock.Anything, "BTCUSDT").Return(nil, errors.New("no filters")).Once()
+	client.On("ExecuteOrder", mock.Anything, mock.Anything).Return(&bybit.ExchangeOrderResponse{
+		OrderID: fillOrderID,
+		Symbol:  "BTCUSDT",
+		Price:   "0",
+		Status:  "New",
+	}, nil).Once()
+
+	order, err := orders.ExecuteMarketOrder(context.Background(), domain.CreateOrderRequest{
+		Symbol:   "BTCUSDT",
+		Side:     domain.OrderSideBuy,
+		Type:     domain.OrderTypeMarket,
+		Quantity: "100",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "0", order.Price)
+	return order
+}
+
+func resolvedPrice(t *testing.T, order *domain.Order) float64 {
+	t.Helper()
+
+	price, err := strconv.ParseFloat(order.Price, 64)
+	require.NoError(t, err)
+	return price
+}
+
+func TestResolveFillPriceFromRealtime(t *testing.T) {
+	orders, client := newMockOrderService(t)
+	order := placeMarketEntry(t, orders, client)
+
+	client.On("FetchOrderInfo", mock.Anything, "BTCUSDT", fillOrderID).Return(&bybit.ExchangeOrderResponse{
+		OrderID:       fillOrderID,
+		Symbol:        "BTCUSDT",
+		Price:         "0",
+		Status:        "Filled",
+		ExecutedQty:   "0.001587",
+		ExecutedValue: "99.981",
+		ExecutedFee:   "0.000001587",
+	}, nil).Once()
+
+	require.NoError(t, orders.ResolveFillPrice(context.Background(), order))
+	assert.InDelta(t, 63000, resolvedPrice(t, order), 1e-6)
+	assert.Equal(t, domain.OrderStatusFilled, order.Status)
+	assert.Equal(t, "0.001587", order.ExecutedQty)
+	assert.Equal(t, "0.000001587", order.Fee)
+}
+
+func TestResolveFillPriceFromHistory(t *testing.T) {
+	orders, client := newMockOrderService(t)
+	order := placeMarketEntry(t, orders, client)
+
+	// Давно закрытого ордера нет среди текущих
+	client.On("FetchOrderInfo", mock.Anything, "BTCUSDT", fillOrderID).Return(nil, bybit.ErrOrderNotFound).Once()
+	client.On("FetchOrderHistory", mock.Anything, "BTCUSDT", fillOrderID).Return(&bybit.ExchangeOrderResponse{
+		OrderID:       fillOrderID,
+		Symbol:        "BTCUSDT",
+		Price:         "0",
+		Status:        "Filled",
+		ExecutedQty:   "0.002",
+		ExecutedValue: "126",
+	}, nil).Once()
+
+	require.NoError(t, orders.ResolveFillPrice(context.Background(), order))
+	assert.InDelta(t, 63000, resolvedPrice(t, order), 1e-6)
+}
+
+func TestResolveFillPriceFromExecutions(t *testing.T) {
+	tests := []struct {
+		name       string
+		executions []bybit.Execution
+		want       float64
+	}{
+		{
+			name: "with exec value",
+			executions: []bybit.Execution{
+				{ExecID: "1", Price: "62999.9", Qty: "0.001", Value: "62.9999"},
+				{ExecID: "2", Price: "63000.1", Qty: "0.000587", Value: "36.9810587"},
+			},
+			want: (62.9999 + 36.9810587) / 0.001587,
+		},
+		{
+			name: "without exec value",
+			executions: []bybit.Execution{
+				{ExecID: "1", Price: "63000", Qty: "0.001"},
+				{ExecID: "2", Price: "63010", Qty: "0.001"},
+			},
+			want: 63005,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders, client := newMockOrderService(t)
+			order := placeMarketEntry(t, orders, client)
+
+			// Биржа еще не отдала исполнение в состоянии ордера
+			client.On("FetchOrderInfo", mock.Anything, "BTCUSDT", fillOrderID).Return(&bybit.ExchangeOrderResponse{
+				OrderID: fillOrderID,
+				Symbol:  "BTCUSDT",
+				Price:   "0",
+				Status:  "New",
+			}, nil).Once()
+			client.On("ListExecutions", mock.Anything, "BTCUSDT", fillOrderID).Return(tt.executions, nil).Once()
+
+			require.NoError(t, orders.ResolveFillPrice(context.Background(), order))
+			assert.InDelta(t, tt.want, resolvedPrice(t, order), 1e-6)
+		})
+	}
+}
+
+func TestResolveFillPriceFromTicker(t *testing.T) {
+	orders, client := newMockOrderService(t)
+	order := placeMarketEntry(t, orders, client)
+
+	client.On("FetchOrderInfo", mock.Anything, "BTCUSDT", fillOrderID).Return(nil, errors.New("timeout")).Once()
+	client.On("ListExecutions", mock.Anything, "BTCUSDT", fillOrderID).Return([]bybit.Execution{}, nil).Once()
+	client.On("GetTicker", mock.Anything, "BTCUSDT").Return(&bybit.Ticker{Symbol: "BTCUSDT", LastPrice: "63010"}, nil).Once()
+
+	require.NoError(t, orders.ResolveFillPrice(context.Background(), order))
+	assert.Equal(t, "63010.00000000", order.Price)
+}
+
+func TestResolveFillPriceFailsWithoutSources(t *testing.T) {
+	orders, client := newMockOrderService(t)
+	order := placeMarketEntry(t, orders, client)
+
+	client.On("FetchOrderInfo", mock.Anything, "BTCUSDT", fillOrderID).Return(nil, errors.New("timeout")).Once()
+	client.On("ListExecutions", mock.Anything, "BTCUSDT", fillOrderID).Return(nil, errors.New("timeout")).Once()
+	client.On("GetTicker", mock.Anything, "BTCUSDT").Return(nil, errors.New("timeout")).Once()
+
+	assert.Error(t, orders.ResolveFillPrice(context.Background(), order))
+	assert.Equal(t, "0", order.Price)
+}
+
+func TestResolveFillPriceKeepsKnownPrice(t *testing.T) {
+	orders, _ := newMockOrderService(t)
+	order := &domain.Order{BybitID: fillOrderID, Symbol: "BTCUSDT", Price: "62000"}
+
+	// Цена уже известна - к бирже обращений нет
+	require.NoError(t, orders.ResolveFillPrice(context.Background(), order))
+	assert.Equal(t, "62000", order.Price)
+}
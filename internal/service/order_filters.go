@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"cryptorg/internal/bybit"
+	"cryptorg/internal/domain"
+	apperrors "cryptorg/pkg/errors"
+)
+
+// applyFilters приводит количество и цены запроса к фильтрам символа перед отправкой.
+// Количество округляется вниз до шага, цена покупки - вниз, продажи - вверх до шага цены,
+// чтобы ордер не стал хуже запрошенного. Ордер меньше минимума биржи отклоняется до
+// отправки. Если фильтры получить не удалось, запрос уходит как есть.
+func (s *OrderService) applyFilters(ctx context.Context, req *bybit.ExchangeOrderRequest) error {
+	info, err := s.InstrumentInfo(ctx, req.Symbol)
+	if err != nil {
+		log.Printf("Sending %s order for %s without exchange filters: %v", req.OrderType, req.Symbol, err)
+		return nil
+	}
+
+	sell := req.Side == string(domain.OrderSideSell)
+	req.Price = roundToStep(req.Price, info.TickSize, sell)
+	req.TriggerPrice = roundToStep(req.TriggerPrice, info.TickSize, sell)
+
+	// Рыночная покупка на Bybit по умолчанию задается суммой в котируемой валюте
+	quoteQty := req.OrderType == string(domain.OrderTypeMarket) &&
+		(req.MarketUnit == bybit.MarketUnitQuoteCoin || (!sell && req.MarketUnit == ""))
+	if quoteQty {
+		req.Qty = roundToStep(req.Qty, info.QuotePrecision, false)
+		amount, _ := strconv.ParseFloat(req.Qty, 64)
+		return checkMinAmount(info, req.Symbol, amount)
+	}
+
+	req.Qty = roundToStep(req.Qty, info.QtyStep, false)
+	qty, _ := strconv.ParseFloat(req.Qty, 64)
+	if minQty, err := strconv.ParseFloat(info.MinOrderQty, 64); err == nil && qty < minQty {
+		return apperrors.DomainError(
+			fmt.Sprintf("order quantity %s is below the minimum %s for %s", req.Qty, info.MinOrderQty, req.Symbol),
+			"ORDER_BELOW_MIN_QTY")
+	}
+	if price, err := strconv.ParseFloat(req.Price, 64); err == nil && price > 0 {
+		return checkMinAmount(info, req.Symbol, qty*price)
+	}
+	return nil
+}
+
+func checkMinAmount(info *bybit.InstrumentInfo, symbol string, amount float64) error {
+	minAmount, err := strconv.ParseFloat(info.MinOrderAmt, 64)
+	if err != nil || amount >= minAmount {
+		return nil
+	}
+	return apperrors.DomainError(
+		fmt.Sprintf("order amount %.8f is below the minimum %s for %s", amount, info.MinOrderAmt, symbol),
+		"ORDER_BELOW_MIN_AMOUNT")
+}
+
+// roundToStep округляет значение до кратного step вниз или вверх и форматирует с числом
+// знаков шага. Пустые значения и некорректный шаг остаются без изменений.
+func roundToStep(value, step string, up bool) string {
+	v, err := domain.ParseDecimal(value)
+	if err != nil {
+		return value
+	}
+	s, err := domain.ParseDecimal(step)
+	if err != nil || !s.IsPositive() {
+		return value
+	}
+
+	steps := v.Div(s).Floor()
+	if up {
+		steps = v.Div(s).Ceil()
+	}
+
+	decimals := 0
+	if idx := strings.IndexByte(step, '.'); idx >= 0 {
+		decimals = len(strings.TrimRight(step[idx+1:], "0"))
+	}
+	return steps.Mul(s).StringFixed(int32(decimals))
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"cryptorg/internal/bybit"
+	"cryptorg/internal/domain"
+)
+
+// orderLookupTimeout ограничивает поиск ордера после сбоя, даже если контекст заявки уже отменен.
+const orderLookupTimeout = 10 * time.Second
+
+// orderLinkID выводит orderLinkId из параметров заявки и nonce. Timestamp и nonce задаются
+// один раз на заявку, поэтому повтор той же заявки получает тот же идентификатор, а
+// одинаковые заявки разных сделок - разные.
+func orderLinkID(req bybit.ExchangeOrderRequest, nonce string) string {
+	return domain.OrderLinkIDFor(req.Symbol, req.Side, req.OrderType, req.Qty, req.Price,
+		req.TriggerPrice, req.MarketUnit, strconv.FormatInt(req.Timestamp, 10), nonce)
+}
+
+// placeOrder отправляет заявку. Если ответ не получен (таймаут, обрыв, 5xx), неизвестно,
+// принята ли она: сначала ищем ордер по orderLinkId и повторяем отправку, только если
+// биржа его не знает. Повтор идет с тем же orderLinkId, так что дубль биржа отклонит.
+func (s *OrderService) placeOrder(ctx context.Context, req bybit.ExchangeOrderRequest) (*bybit.ExchangeOrderResponse, error) {
+	start := time.Now()
+	resp, err := s.exchangeClient.ExecuteOrder(ctx, req)
+	s.observeExchange("create_order", start, err)
+
+	var apiErr *bybit.APIError
+	if err == nil || errors.As(err, &apiErr) {
+		return resp, err
+	}
+
+	lookupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), orderLookupTimeout)
+	defer cancel()
+
+	existing, lookupErr := s.exchangeClient.FetchOrderByLinkID(lookupCtx, req.Symbol, req.OrderLinkID)
+	switch {
+	case lookupErr == nil:
+		log.Printf("Order %s on %s was placed despite error: %v", req.OrderLinkID, req.Symbol, err)
+		s.metrics.IncCounter("orders_recovered_total", nil)
+		return existing, nil
+	case !errors.Is(lookupErr, bybit.ErrOrderNotFound):
+		return nil, fmt.Errorf("%w; order state unknown: %v", err, lookupErr)
+	case ctx.Err() != nil:
+		return nil, err
+	}
+
+	log.Printf("Order %s on %s not found after error, retrying: %v", req.OrderLinkID, req.Symbol, err)
+	start = time.Now()
+	resp, err = s.exchangeClient.ExecuteOrder(ctx, req)
+	s.observeExchange("create_order", start, err)
+	return resp, err
+}
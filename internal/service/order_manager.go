@@ -2,24 +2,133 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"sort"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"cryptorg/internal/bybit"
 	"cryptorg/internal/domain"
+	"cryptorg/internal/metrics"
+	"cryptorg/internal/tracing"
+	"cryptorg/pkg/pricing"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type OrderService struct {
-	exchangeClient *bybit.Client
+	exchangeClient ExchangeClient
+	orderCache     *OrderStateCache
+	metrics        metrics.Recorder
+	instruments    map[string]*bybit.InstrumentInfo
+	instrumentsMu  sync.RWMutex
+	precision      *PrecisionOverrides
+	rawPayloads    *rawPayloadStore // nil - исходные ответы биржи не сохраняются
+
+	// Случайная метка процесса и номер заявки различают orderLinkId одинаковых заявок
+	// разных сделок и реплик, отправленных в одну миллисекунду
+	linkSalt string
+	linkSeq  atomic.Uint64
 }
 
-func NewOrderManager(exchangeClient *bybit.Client) *OrderService {
-	return &OrderService{
+func NewOrderManager(exchangeClient ExchangeClient, orderCache *OrderStateCache, recorder metrics.Recorder, precision *PrecisionOverrides, keepRawPayloads bool) *OrderService {
+	service := &OrderService{
 		exchangeClient: exchangeClient,
+		orderCache:     orderCache,
+		metrics:        recorder,
+		precision:      precision,
+		instruments:    make(map[string]*bybit.InstrumentInfo),
+		linkSalt:       uuid.NewString(),
+	}
+	if keepRawPayloads {
+		service.rawPayloads = newRawPayloadStore()
+	}
+	return service
+}
+
+func (s *OrderService) observeExchange(operation string, start time.Time, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
 	}
+
+	s.metrics.IncCounter("exchange_requests_total", metrics.Labels{"operation": operation, "result": result})
+	s.metrics.ObserveHistogram("exchange_request_duration_seconds", metrics.Since(start), metrics.Labels{"operation": operation})
+}
+
+func (s *OrderService) executeOrder(ctx context.Context, req bybit.ExchangeOrderRequest) (*bybit.ExchangeOrderResponse, error) {
+	ctx, span := tracing.Start(ctx, "OrderService.executeOrder",
+		tracing.Symbol(req.Symbol),
+		attribute.String("order.type", req.OrderType),
+		attribute.String("order.side", req.Side),
+	)
+
+	if err := s.applyFilters(ctx, &req); err != nil {
+		tracing.End(span, err)
+		return nil, err
+	}
+	s.precision.Apply(&req)
+	nonce := s.linkSalt + "-" + strconv.FormatUint(s.linkSeq.Add(1), 10)
+	req.OrderLinkID = orderLinkID(req, nonce)
+
+	resp, err := s.placeOrder(ctx, req)
+
+	// Отказ из-за точности: запоминаем новую точность символа и повторяем один раз
+	var apiErr *bybit.APIError
+	if errors.As(err, &apiErr) && s.precision.Learn(req, apiErr) {
+		s.precision.Apply(&req)
+		req.OrderLinkID = orderLinkID(req, nonce)
+		span.AddEvent("retry after precision rejection")
+
+		resp, err = s.placeOrder(ctx, req)
+	}
+
+	if err == nil {
+		if resp.OrderLinkID == "" {
+			resp.OrderLinkID = req.OrderLinkID
+		}
+		fillFromRequest(resp, req)
+		span.SetAttributes(tracing.OrderID(resp.OrderID))
+		s.metrics.IncCounter("orders_placed_total", metrics.Labels{"type": req.OrderType, "side": req.Side})
+	}
+	tracing.End(span, err)
+	return resp, err
+}
+
+// fillFromRequest дополняет ответ на создание ордера параметрами запроса: Bybit возвращает
+// только orderId и orderLinkId. Берутся значения после фильтров и точности - именно с
+// ними ордер ушел на биржу.
+func fillFromRequest(resp *bybit.ExchangeOrderResponse, req bybit.ExchangeOrderRequest) {
+	if resp.Symbol == "" {
+		resp.Symbol = req.Symbol
+	}
+	if resp.Side == "" {
+		resp.Side = req.Side
+	}
+	if resp.OrderType == "" {
+		resp.OrderType = req.OrderType
+	}
+	if resp.Qty == "" {
+		resp.Qty = req.Qty
+	}
+	if resp.Price == "" {
+		resp.Price = req.Price
+	}
+}
+
+func (s *OrderService) terminateOrder(ctx context.Context, req bybit.ExchangeCancelRequest) error {
+	ctx, span := tracing.Start(ctx, "OrderService.terminateOrder", tracing.Symbol(req.Symbol), tracing.OrderID(req.OrderID))
+
+	start := time.Now()
+	err := s.exchangeClient.TerminateOrder(ctx, req)
+	s.observeExchange("cancel_order", start, err)
+	tracing.End(span, err)
+	return err
 }
 
 func (s *OrderService) ExecuteMarketOrder(ctx context.Context, req domain.CreateOrderRequest) (*domain.Order, error) {
@@ -30,13 +139,17 @@ func (s *OrderService) ExecuteMarketOrder(ctx context.Context, req domain.Create
 		Qty:       req.Quantity,
 		Timestamp: time.Now().UnixMilli(),
 	}
+	if req.QuoteQuantity && req.Side == domain.OrderSideSell {
+		exchangeReq.MarketUnit = bybit.MarketUnitQuoteCoin
+	}
 
-	exchangeResp, err := s.exchangeClient.ExecuteOrder(ctx, exchangeReq)
+	exchangeResp, err := s.executeOrder(ctx, exchangeReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute market order: %w", err)
 	}
 
 	order := s.buildOrderFromResponse(exchangeResp)
+	s.keepRaw(order, exchangeResp.Raw, rawSourceCreate)
 	return order, nil
 }
 
@@ -59,16 +172,64 @@ func (s *OrderService) ExecuteLimitOrder(ctx context.Context, req domain.CreateO
 		TimeInForce: domain.DefaultTimeInForce,
 		Timestamp:   time.Now().UnixMilli(),
 	}
+	if req.PostOnly {
+		exchangeReq.TimeInForce = domain.TimeInForcePostOnly
+	} else if req.ImmediateOrCancel {
+		exchangeReq.TimeInForce = domain.TimeInForceIOC
+	}
 
-	exchangeResp, err := s.exchangeClient.ExecuteOrder(ctx, exchangeReq)
+	exchangeResp, err := s.executeOrder(ctx, exchangeReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute limit order: %w", err)
 	}
 
 	order := s.buildOrderFromResponse(exchangeResp)
+	s.keepRaw(order, exchangeResp.Raw, rawSourceCreate)
+	return order, nil
+}
+
+func (s *OrderService) ExecuteStopLimitOrder(ctx context.Context, req domain.CreateOrderRequest) (*domain.Order, error) {
+	if req.Price == "" || req.TriggerPrice == "" {
+		return nil, fmt.Errorf("price and trigger price are required for stop-limit order")
+	}
+
+	exchangeReq := bybit.ExchangeOrderRequest{
+		Symbol:       req.Symbol,
+		Side:         string(req.Side),
+		OrderType:    string(domain.OrderTypeLimit),
+		Qty:          req.Quantity,
+		Price:        req.Price,
+		TriggerPrice: req.TriggerPrice,
+		OrderFilter:  bybit.OrderFilterStopOrder,
+		TimeInForce:  domain.DefaultTimeInForce,
+		Timestamp:    time.Now().UnixMilli(),
+	}
+
+	exchangeResp, err := s.executeOrder(ctx, exchangeReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute stop-limit order: %w", err)
+	}
+
+	order := s.buildOrderFromResponse(exchangeResp)
+	s.keepRaw(order, exchangeResp.Raw, rawSourceCreate)
 	return order, nil
 }
 
+func (s *OrderService) TerminateStopOrder(ctx context.Context, symbol string, orderID string) error {
+	cancelReq := bybit.ExchangeCancelRequest{
+		Symbol:      symbol,
+		OrderID:     orderID,
+		OrderFilter: bybit.OrderFilterStopOrder,
+		Timestamp:   time.Now().UnixMilli(),
+	}
+
+	if err := s.terminateOrder(ctx, cancelReq); err != nil {
+		return fmt.Errorf("failed to terminate stop order: %w", err)
+	}
+
+	return nil
+}
+
 func (s *OrderService) TerminateOrder(ctx context.Context, symbol string, orderID string) error {
 	cancelReq := bybit.ExchangeCancelRequest{
 		Symbol:    symbol,
@@ -76,7 +237,7 @@ func (s *OrderService) TerminateOrder(ctx context.Context, symbol string, orderI
 		Timestamp: time.Now().UnixMilli(),
 	}
 
-	if err := s.exchangeClient.TerminateOrder(ctx, cancelReq); err != nil {
+	if err := s.terminateOrder(ctx, cancelReq); err != nil {
 		return fmt.Errorf("failed to terminate order: %w", err)
 	}
 
@@ -84,15 +245,226 @@ func (s *OrderService) TerminateOrder(ctx context.Context, symbol string, orderI
 }
 
 func (s *OrderService) FetchOrderStatus(ctx context.Context, symbol string, orderID string) (*domain.Order, error) {
+	ctx, span := tracing.Start(ctx, "OrderService.FetchOrderStatus", tracing.Symbol(symbol), tracing.OrderID(orderID))
+
+	start := time.Now()
 	exchangeResp, err := s.exchangeClient.FetchOrderInfo(ctx, symbol, orderID)
+	s.observeExchange("get_order", start, err)
+	if errors.Is(err, bybit.ErrOrderNotFound) {
+		// Давно закрытого ордера уже нет среди текущих, он есть только в истории
+		start = time.Now()
+		exchangeResp, err = s.exchangeClient.FetchOrderHistory(ctx, symbol, orderID)
+		s.observeExchange("get_order_history", start, err)
+	}
+	tracing.End(span, err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch order status: %w", err)
 	}
 
 	order := s.buildOrderFromResponse(exchangeResp)
+	s.keepRaw(order, exchangeResp.Raw, rawSourceRealtime)
 	return order, nil
 }
 
+func (s *OrderService) FetchLastPrice(ctx context.Context, symbol string) (float64, error) {
+	start := time.Now()
+	ticker, err := s.exchangeClient.GetTicker(ctx, symbol)
+	s.observeExchange("get_ticker", start, err)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch ticker: %w", err)
+	}
+
+	price, err := strconv.ParseFloat(ticker.LastPrice, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid last price: %w", err)
+	}
+
+	return price, nil
+}
+
+func (s *OrderService) FetchOrderBook(ctx context.Context, symbol string, limit int) (*bybit.OrderBook, error) {
+	start := time.Now()
+	book, err := s.exchangeClient.GetOrderBook(ctx, symbol, limit)
+	s.observeExchange("get_order_book", start, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch order book: %w", err)
+	}
+	return book, nil
+}
+
+func (s *OrderService) ListTickers(ctx context.Context) ([]bybit.Ticker, error) {
+	start := time.Now()
+	tickers, err := s.exchangeClient.ListTickers(ctx)
+	s.observeExchange("list_tickers", start, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tickers: %w", err)
+	}
+
+	return tickers, nil
+}
+
+func (s *OrderService) FetchKlines(ctx context.Context, symbol, interval string, limit int) ([]bybit.Kline, error) {
+	start := time.Now()
+	klines, err := s.exchangeClient.GetKlines(ctx, symbol, interval, limit)
+	s.observeExchange("get_klines", start, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch klines: %w", err)
+	}
+
+	return klines, nil
+}
+
+func (s *OrderService) FetchKlinesRange(ctx context.Context, symbol, interval string, from, to time.Time) ([]bybit.Kline, error) {
+	start := time.Now()
+	klines, err := s.exchangeClient.GetKlinesRange(ctx, symbol, interval, from, to)
+	s.observeExchange("get_klines_range", start, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch klines: %w", err)
+	}
+
+	return klines, nil
+}
+
+func (s *OrderService) ListOpenOrders(ctx context.Context, symbol string) ([]*domain.Order, error) {
+	start := time.Now()
+	exchangeOrders, err := s.exchangeClient.ListOpenOrders(ctx, symbol)
+	s.observeExchange("list_open_orders", start, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open orders: %w", err)
+	}
+
+	orders := make([]*domain.Order, 0, len(exchangeOrders))
+	for i := range exchangeOrders {
+		orders = append(orders, s.buildOrderFromResponse(&exchangeOrders[i]))
+	}
+	return orders, nil
+}
+
+// WalletDelta суммирует изменения баланса coin от исполнений ордера orderID, созданного
+// не раньше since. found = false, если биржа еще не отразила исполнение в журнале.
+func (s *OrderService) WalletDelta(ctx context.Context, coin, orderID string, since time.Time) (delta float64, found bool, err error) {
+	start := time.Now()
+	changes, err := s.exchangeClient.ListWalletChanges(ctx, coin, since)
+	s.observeExchange("wallet_changes", start, err)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to list wallet changes: %w", err)
+	}
+
+	for _, change := range changes {
+		if change.OrderID != orderID {
+			continue
+		}
+		value, err := strconv.ParseFloat(change.Change, 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid wallet change %q: %w", change.Change, err)
+		}
+		delta += value
+		found = true
+	}
+	return delta, found, nil
+}
+
+// FetchBalance возвращает полный баланс монеты и его часть, заблокированную в ордерах.
+func (s *OrderService) FetchBalance(ctx context.Context, coin string) (total float64, locked float64, err error) {
+	start := time.Now()
+	balance, err := s.exchangeClient.GetBalance(ctx, coin)
+	s.observeExchange("get_balance", start, err)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to fetch balance: %w", err)
+	}
+
+	total, err = strconv.ParseFloat(balance.WalletBalance, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid wallet balance: %w", err)
+	}
+
+	if balance.Locked != "" {
+		locked, err = strconv.ParseFloat(balance.Locked, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid locked balance: %w", err)
+		}
+	}
+
+	return total, locked, nil
+}
+
+// FetchWalletBalance возвращает балансы всех монет счета, пропуская нулевые.
+func (s *OrderService) FetchWalletBalance(ctx context.Context) (*domain.AccountBalance, error) {
+	start := time.Now()
+	balances, err := s.exchangeClient.GetWalletBalance(ctx)
+	s.observeExchange("get_wallet_balance", start, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch wallet balance: %w", err)
+	}
+
+	account := &domain.AccountBalance{
+		Coins:     make([]domain.CoinBalance, 0, len(balances)),
+		Timestamp: time.Now(),
+	}
+	for _, balance := range balances {
+		total, err := domain.ParseDecimal(balance.WalletBalance)
+		if err != nil || total.IsZero() {
+			continue
+		}
+		locked, _ := domain.ParseDecimal(balance.Locked)
+		account.Coins = append(account.Coins, domain.CoinBalance{
+			Coin:   balance.Coin,
+			Total:  domain.FormatDecimal(total),
+			Locked: domain.FormatDecimal(locked),
+			Free:   domain.FormatDecimal(total.Sub(locked)),
+		})
+	}
+	sort.Slice(account.Coins, func(i, j int) bool { return account.Coins[i].Coin < account.Coins[j].Coin })
+	return account, nil
+}
+
+// InstrumentInfo возвращает фильтры символа; они меняются редко, поэтому кэшируются на время жизни процесса.
+func (s *OrderService) InstrumentInfo(ctx context.Context, symbol string) (*bybit.InstrumentInfo, error) {
+	s.instrumentsMu.RLock()
+	info, ok := s.instruments[symbol]
+	s.instrumentsMu.RUnlock()
+	if ok {
+		return info, nil
+	}
+
+	start := time.Now()
+	info, err := s.exchangeClient.GetInstrumentInfo(ctx, symbol)
+	s.observeExchange("get_instrument", start, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch instrument info: %w", err)
+	}
+
+	s.instrumentsMu.Lock()
+	s.instruments[symbol] = info
+	s.instrumentsMu.Unlock()
+
+	return info, nil
+}
+
+// CachedOrderStatus отдает состояние из кэша событий и идет в REST только если данных нет или они устарели.
+func (s *OrderService) CachedOrderStatus(ctx context.Context, symbol string, orderID string) (*domain.Order, error) {
+	if order, ok := s.orderCache.Get(orderID); ok {
+		return order, nil
+	}
+
+	order, err := s.FetchOrderStatus(ctx, symbol, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.orderCache.Update(*order)
+	return order, nil
+}
+
+func (s *OrderService) UpdateCachedOrder(order domain.Order) {
+	s.orderCache.Update(order)
+}
+
+// ForgetCachedOrders убирает из кэша ордера, события которых больше не нужны.
+func (s *OrderService) ForgetCachedOrders(orderIDs ...string) {
+	s.orderCache.Delete(orderIDs...)
+}
+
 func (s *OrderService) ComputeTakeProfitPrice(entryPrice string, profitPercent float64, side domain.OrderSide) (string, error) {
 	if entryPrice == "" {
 		return "", fmt.Errorf("entry price is required")
@@ -101,19 +473,12 @@ func (s *OrderService) ComputeTakeProfitPrice(entryPrice string, profitPercent f
 		return "", fmt.Errorf("profit percent must be positive")
 	}
 
-	price, err := strconv.ParseFloat(entryPrice, 64)
+	price, err := domain.ParseDecimal(entryPrice)
 	if err != nil {
 		return "", fmt.Errorf("invalid entry price: %w", err)
 	}
 
-	var tpPrice float64
-	if side == domain.OrderSideBuy {
-		tpPrice = price * (1 + profitPercent/100)
-	} else {
-		tpPrice = price * (1 - profitPercent/100)
-	}
-
-	return fmt.Sprintf("%.8f", tpPrice), nil
+	return domain.FormatDecimal(pricing.TakeProfit(price, profitPercent, side != domain.OrderSideBuy, nil)), nil
 }
 
 func (s *OrderService) ComputeDCAPrice(currentPrice string, stepPercent float64, side domain.OrderSide) (string, error) {
@@ -124,52 +489,84 @@ func (s *OrderService) ComputeDCAPrice(currentPrice string, stepPercent float64,
 		return "", fmt.Errorf("step percent must be positive")
 	}
 
-	price, err := strconv.ParseFloat(currentPrice, 64)
+	price, err := domain.ParseDecimal(currentPrice)
 	if err != nil {
 		return "", fmt.Errorf("invalid current price: %w", err)
 	}
 
-	var dcaPrice float64
-	if side == domain.OrderSideBuy {
-		dcaPrice = price * (1 - stepPercent/100)
-	} else {
-		dcaPrice = price * (1 + stepPercent/100)
-	}
-
-	return fmt.Sprintf("%.8f", dcaPrice), nil
+	return domain.FormatDecimal(pricing.DCAPrice(price, stepPercent, side != domain.OrderSideBuy)), nil
 }
 
 func (s *OrderService) calculateQuantityFromUSDT(usdtAmount, price string) (string, error) {
-	usdt, err := strconv.ParseFloat(usdtAmount, 64)
+	usdt, err := domain.ParseDecimal(usdtAmount)
 	if err != nil {
 		return "", fmt.Errorf("invalid USDT amount: %w", err)
 	}
 
-	priceFloat, err := strconv.ParseFloat(price, 64)
+	priceValue, err := domain.ParseDecimal(price)
 	if err != nil {
 		return "", fmt.Errorf("invalid price: %w", err)
 	}
 
-	if priceFloat <= 0 {
-		return "", fmt.Errorf("price must be positive")
+	quantity, err := pricing.QuantityFromQuote(usdt, priceValue)
+	if err != nil {
+		return "", err
 	}
-
-	quantity := usdt / priceFloat
-	return fmt.Sprintf("%.8f", quantity), nil
+	return domain.FormatDecimal(quantity), nil
 }
 
 func (s *OrderService) buildOrderFromResponse(resp *bybit.ExchangeOrderResponse) *domain.Order {
+	status := domain.ParseOrderStatus(resp.Status)
+	if status == domain.OrderStatusUnknown {
+		log.Printf("Unknown status %q of order %s on %s", resp.Status, resp.OrderID, resp.Symbol)
+		s.metrics.IncCounter("order_status_unknown_total", metrics.Labels{"status": resp.Status})
+	}
+
+	// Время ордера берется с биржи: исполнение, обработанное с опозданием, не сдвигает статистику
+	now := time.Now()
+	createdAt, updatedAt := now, now
+	if at, ok := s.parseExchangeTime(resp.CreatedTime); ok {
+		createdAt, updatedAt = at, at
+	}
+	if at, ok := s.parseExchangeTime(resp.UpdatedTime); ok {
+		updatedAt = at
+	}
+
 	return &domain.Order{
-		ID:          uuid.New(),
-		BybitID:     resp.OrderID,
-		Symbol:      resp.Symbol,
-		Side:        domain.OrderSide(resp.Side),
-		Type:        domain.OrderType(resp.OrderType),
-		Quantity:    resp.Qty,
-		Price:       resp.Price,
-		Status:      domain.OrderStatus(resp.Status),
-		ExecutedQty: resp.ExecutedQty,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+		ID:            uuid.New(),
+		BybitID:       resp.OrderID,
+		LinkID:        resp.OrderLinkID,
+		Symbol:        resp.Symbol,
+		Side:          domain.OrderSide(resp.Side),
+		Type:          domain.OrderType(resp.OrderType),
+		Quantity:      resp.Qty,
+		Price:         resp.Price,
+		Status:        status,
+		ExecutedQty:   resp.ExecutedQty,
+		ExecutedValue: resp.ExecutedValue,
+		Fee:           resp.ExecutedFee,
+		CreatedAt:     createdAt,
+		UpdatedAt:     updatedAt,
+	}
+}
+
+// exchangeTime переводит время биржи в миллисекундах в локальные часы с поправкой на
+// смещение часов биржи. Время из будущего после поправки ограничивается текущим.
+func (s *OrderService) exchangeTime(ms int64) (time.Time, bool) {
+	if ms <= 0 {
+		return time.Time{}, false
+	}
+	at := s.exchangeClient.Clock().ToLocal(time.UnixMilli(ms))
+	if now := time.Now(); at.After(now) {
+		at = now
+	}
+	return at, true
+}
+
+func (s *OrderService) parseExchangeTime(ms string) (time.Time, bool) {
+	value, err := strconv.ParseInt(ms, 10, 64)
+	if err != nil {
+		return time.Time{}, false
 	}
+	return s.exchangeTime(value)
 }
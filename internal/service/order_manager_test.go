@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cryptorg/internal/bybit"
+	"cryptorg/internal/domain"
+	"cryptorg/internal/metrics"
+	"cryptorg/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Ответ Bybit на создание ордера содержит только идентификаторы: остальное берется из запроса
+func TestExecuteLimitOrderFillsResponseFromRequest(t *testing.T) {
+	orders, client := newMockOrderService(t)
+
+	client.On("GetInstrumentInfo", mock.Anything, "BTCUSDT").Return(nil, errors.New("no filters")).Once()
+	client.On("ExecuteOrder", mock.Anything, mock.Anything).Return(&bybit.ExchangeOrderResponse{
+		OrderID:     fillOrderID,
+		OrderLinkID: "link-1",
+		Status:      "New",
+	}, nil).Once()
+
+	order, err := orders.ExecuteLimitOrder(context.Background(), domain.CreateOrderRequest{
+		Symbol:   "BTCUSDT",
+		Side:     domain.OrderSideSell,
+		Type:     domain.OrderTypeLimit,
+		Quantity: "101",
+		Price:    "101",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, fillOrderID, order.BybitID)
+	assert.Equal(t, "BTCUSDT", order.Symbol)
+	assert.Equal(t, domain.OrderSideSell, order.Side)
+	assert.Equal(t, domain.OrderTypeLimit, order.Type)
+	assert.Equal(t, "101", order.Price)
+	assert.InDelta(t, 1, parseFake(order.Quantity), 1e-8)
+}
+
+func TestStopLossIsSizedFromPosition(t *testing.T) {
+	exchange := newFakeExchange(map[string]float64{"BTCUSDT": 100})
+	trades := newFakeTradeService(t, exchange, storage.NewMemoryJournal(), storage.NewMemoryTradeRepository())
+
+	config := testTradeConfig()
+	config.StopLossPercent = 5
+	trade, err := trades.InitializeTrade(context.Background(), config)
+	require.NoError(t, err)
+
+	require.NotNil(t, trade.StopLossOrder)
+	// Количество проходит через точность символа, поэтому сравниваются числа
+	position := parseFake(trade.CurrentPositionQty)
+	assert.InDelta(t, position, parseFake(trade.StopLossOrder.Quantity), 1e-8)
+	assert.InDelta(t, position, parseFake(exchange.orders[trade.StopLossOrder.BybitID].Qty), 1e-8)
+}
+
+// Одинаковые заявки разных сделок в одну миллисекунду не должны совпадать по orderLinkId
+func TestIdenticalOrdersGetDistinctLinkIDs(t *testing.T) {
+	exchange := newFakeExchange(map[string]float64{"BTCUSDT": 100})
+	precision, err := NewPrecisionOverrides(storage.NewMemoryPrecisionStore())
+	require.NoError(t, err)
+	orders := NewOrderManager(exchange, NewOrderStateCache(0), metrics.NoopRecorder{}, precision, false)
+
+	req := domain.CreateOrderRequest{
+		Symbol:   "BTCUSDT",
+		Side:     domain.OrderSideBuy,
+		Type:     domain.OrderTypeLimit,
+		Quantity: "100",
+		Price:    "90",
+	}
+	links := make(map[string]bool)
+	for i := 0; i < 20; i++ {
+		order, err := orders.ExecuteLimitOrder(context.Background(), req)
+		require.NoError(t, err)
+		assert.True(t, domain.IsBotOrderLink(order.LinkID))
+		links[order.LinkID] = true
+	}
+	assert.Len(t, links, 20)
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+
+	"cryptorg/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+type polledOrder struct {
+	tradeID  uuid.UUID
+	exchange string // TradeConfig.Exchange сделки
+	orderID  string
+	role     string
+	executed float64 // Уже учтенное исполнение ордера
+}
+
+// OrderPollService - запасной путь к исполнениям на случай потерянного вебхука: по таймеру
+// запрашивает статусы открытых ордеров активных сделок и передает найденные исполнения
+// в ProcessOrderExecution. По одному символу за проход запрашивается не больше perSymbol
+// ордеров, остальные - на следующих проходах по кругу.
+type OrderPollService struct {
+	tradeManager *TradeService
+	interval     time.Duration
+	perSymbol    int
+	cursor       map[string]int // Символ -> первый ордер следующего прохода; только из Run
+}
+
+func NewOrderPollManager(tradeManager *TradeService, interval time.Duration, perSymbol int) *OrderPollService {
+	if perSymbol <= 0 {
+		perSymbol = 1
+	}
+	return &OrderPollService{
+		tradeManager: tradeManager,
+		interval:     interval,
+		perSymbol:    perSymbol,
+		cursor:       make(map[string]int),
+	}
+}
+
+func (s *OrderPollService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Poll(ctx); err != nil {
+				log.Printf("Order status poll failed: %v", err)
+			}
+		}
+	}
+}
+
+// Poll выполняет один проход по ордерам всех символов.
+func (s *OrderPollService) Poll(ctx context.Context) error {
+	var errs []error
+	for symbol, orders := range s.tradeManager.pollableOrders() {
+		start := s.cursor[symbol] % len(orders)
+		count := min(s.perSymbol, len(orders))
+		for i := 0; i < count; i++ {
+			if err := s.pollOrder(ctx, symbol, orders[(start+i)%len(orders)]); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		s.cursor[symbol] = start + count
+	}
+	return errors.Join(errs...)
+}
+
+func (s *OrderPollService) pollOrder(ctx context.Context, symbol string, item polledOrder) error {
+	orders := s.tradeManager.exchangeOrders(item.exchange)
+	actual, err := orders.FetchOrderStatus(ctx, symbol, item.orderID)
+	if err != nil {
+		return fmt.Errorf("%s order %s: %w", item.role, item.orderID, err)
+	}
+	// Частичное исполнение DCA учитывается, не дожидаясь остатка
+	executed, _ := strconv.ParseFloat(actual.ExecutedQty, 64)
+	partial := item.role == "dca" && actual.Status.IsPartial() && executed > item.executed
+	if !actual.Status.ClosedWithFills() && !partial {
+		return nil
+	}
+
+	log.Printf("Poller found %s order %s of trade %s %s on the exchange", item.role, item.orderID, item.tradeID, actual.Status)
+	s.tradeManager.metrics.IncCounter("order_poll_fills_total", nil)
+
+	// Обработка исполнения читает статус из кэша, поэтому сначала кладем туда ответ биржи
+	orders.UpdateCachedOrder(*actual)
+	if err := s.tradeManager.ProcessOrderExecution(ctx, item.tradeID, item.orderID, ""); err != nil {
+		return fmt.Errorf("%s order %s: %w", item.role, item.orderID, err)
+	}
+	return nil
+}
+
+// pollableOrders - неисполненные TP, SL и DCA активных сделок по символам. Порядок внутри
+// символа постоянный, чтобы курсор опроса проходил все ордера.
+func (s *TradeService) pollableOrders() map[string][]polledOrder {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[string][]polledOrder)
+	add := func(trade *domain.Trade, order *domain.Order, role string) {
+		if order == nil || order.BybitID == "" || order.Status.IsTerminal() {
+			return
+		}
+		executed, _ := strconv.ParseFloat(order.ExecutedQty, 64)
+		result[trade.Symbol] = append(result[trade.Symbol], polledOrder{tradeID: trade.ID, exchange: trade.Config.Exchange, orderID: order.BybitID, role: role, executed: executed})
+	}
+	for _, trade := range s.trades {
+		if trade.Status != domain.TradeStatusActive && trade.Status != domain.TradeStatusPaused {
+			continue
+		}
+		add(trade, trade.TakeProfitOrder, "take_profit")
+		add(trade, trade.StopLossOrder, "stop_loss")
+		for i := range trade.DCAOrders {
+			add(trade, &trade.DCAOrders[i], "dca")
+		}
+	}
+
+	for _, orders := range result {
+		sort.Slice(orders, func(i, j int) bool { return orders[i].orderID < orders[j].orderID })
+	}
+	return result
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"cryptorg/internal/domain"
+)
+
+// maxRawPayloads - сколько последних ордеров хранится в памяти для GET /api/orders/{id}/raw.
+const maxRawPayloads = 1000
+
+type rawPayloadSource int
+
+const (
+	rawSourceCreate rawPayloadSource = iota
+	rawSourceRealtime
+)
+
+// rawPayloadStore хранит сжатые ответы биржи по ID ордера, вытесняя самые старые.
+type rawPayloadStore struct {
+	mu       sync.Mutex
+	payloads map[string]*domain.RawOrderPayloads
+	order    []string
+}
+
+func newRawPayloadStore() *rawPayloadStore {
+	return &rawPayloadStore{
+		payloads: make(map[string]*domain.RawOrderPayloads),
+	}
+}
+
+// keepRaw сжимает ответ биржи и прикладывает его к ордеру вместе с ранее сохраненными
+// ответами того же ордера. Без включенного хранения ничего не делает.
+func (s *OrderService) keepRaw(order *domain.Order, raw json.RawMessage, source rawPayloadSource) {
+	if s.rawPayloads == nil || len(raw) == 0 || order.BybitID == "" {
+		return
+	}
+
+	compressed, err := compressPayload(raw)
+	if err != nil {
+		return
+	}
+
+	store := s.rawPayloads
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	payloads, ok := store.payloads[order.BybitID]
+	if !ok {
+		payloads = &domain.RawOrderPayloads{}
+		store.payloads[order.BybitID] = payloads
+		store.order = append(store.order, order.BybitID)
+		if len(store.order) > maxRawPayloads {
+			delete(store.payloads, store.order[0])
+			store.order = store.order[1:]
+		}
+	}
+
+	switch source {
+	case rawSourceCreate:
+		payloads.Create = compressed
+	case rawSourceRealtime:
+		payloads.Realtime = compressed
+	}
+
+	copied := *payloads
+	order.Raw = &copied
+}
+
+// RawPayloads возвращает распакованные ответы create и realtime по ID ордера на бирже.
+func (s *OrderService) RawPayloads(orderID string) (map[string]json.RawMessage, error) {
+	if s.rawPayloads == nil {
+		return nil, fmt.Errorf("raw payload storage is disabled")
+	}
+
+	s.rawPayloads.mu.Lock()
+	payloads, ok := s.rawPayloads.payloads[orderID]
+	var copied domain.RawOrderPayloads
+	if ok {
+		copied = *payloads
+	}
+	s.rawPayloads.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("no raw payloads for order %s", orderID)
+	}
+
+	result := make(map[string]json.RawMessage)
+	for name, data := range map[string][]byte{"create": copied.Create, "realtime": copied.Realtime} {
+		if len(data) == 0 {
+			continue
+		}
+		raw, err := decompressPayload(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress %s payload: %w", name, err)
+		}
+		result[name] = raw
+	}
+	return result, nil
+}
+
+func compressPayload(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decompressPayload(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
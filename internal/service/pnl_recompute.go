@@ -0,0 +1,233 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"cryptorg/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// PnLRecomputeService пересчитывает учет закрытых сделок по исполнениям из журнала после
+// исправлений в учете (комиссии, точность). Журнал и сделки не меняются: результат
+// сохраняется новой ревизией рядом с исходными показателями.
+type PnLRecomputeService struct {
+	journal   domain.EventJournal
+	revisions domain.PnLRevisionStore
+	mu        sync.Mutex
+}
+
+func NewPnLRecomputeManager(journal domain.EventJournal, revisions domain.PnLRevisionStore) *PnLRecomputeService {
+	return &PnLRecomputeService{
+		journal:   journal,
+		revisions: revisions,
+	}
+}
+
+// Recompute пересчитывает выбранные сделки. Исполнения берутся из всех снимков сделки:
+// для каждого ордера - последнее известное состояние. Сделки, которые еще открыты,
+// отсутствуют в журнале или чьи выходы не попали в снимки, пропускаются с причиной.
+func (s *PnLRecomputeService) Recompute(req domain.PnLRecomputeRequest) (*domain.PnLRecomputeResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events, err := s.journal.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	trades, executions, err := journalExecutions(events)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := req.TradeIDs
+	if len(ids) == 0 {
+		for id := range trades {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool { return trades[ids[i]].CreatedAt.Before(trades[ids[j]].CreatedAt) })
+	}
+
+	result := &domain.PnLRecomputeResult{
+		DryRun:    req.DryRun,
+		Revisions: make([]domain.PnLRevision, 0, len(ids)),
+	}
+	for _, id := range ids {
+		trade, exists := trades[id]
+		if !exists {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("%s: not found in journal", id))
+			continue
+		}
+		if _, _, closed := closedPnL(trade); !closed {
+			// Без явного списка открытые и неисполненные сделки не интересны
+			if len(req.TradeIDs) > 0 {
+				result.Skipped = append(result.Skipped, fmt.Sprintf("%s: trade is not closed or has no fills", id))
+			}
+			continue
+		}
+
+		revision, err := recomputeTrade(trade, executions[id])
+		if err != nil {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("%s: %v", id, err))
+			continue
+		}
+		revision.Reason = req.Reason
+
+		if !req.DryRun {
+			if err := s.revisions.Append(revision); err != nil {
+				return nil, fmt.Errorf("failed to save pnl revision: %w", err)
+			}
+		}
+		if revision.PnLDelta != fmt.Sprintf("%.8f", 0.0) {
+			result.Changed++
+		}
+		result.Revisions = append(result.Revisions, *revision)
+	}
+	return result, nil
+}
+
+// Revisions возвращает сохраненные ревизии, для tradeID - только ревизии этой сделки.
+func (s *PnLRecomputeService) Revisions(tradeID *uuid.UUID) ([]domain.PnLRevision, error) {
+	revisions, err := s.revisions.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pnl revisions: %w", err)
+	}
+	if tradeID == nil {
+		return revisions, nil
+	}
+
+	result := make([]domain.PnLRevision, 0)
+	for _, revision := range revisions {
+		if revision.TradeID == *tradeID {
+			result = append(result, revision)
+		}
+	}
+	return result, nil
+}
+
+// journalExecutions возвращает последний снимок каждой сделки и последнее известное
+// состояние каждого ее ордера с исполнением по всем снимкам журнала.
+func journalExecutions(events []domain.TradeEvent) (map[uuid.UUID]*domain.Trade, map[uuid.UUID][]domain.Order, error) {
+	trades, err := ReplayEvents(events, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	orders := make(map[uuid.UUID]map[string]domain.Order)
+	for _, event := range events {
+		if len(event.Snapshot) == 0 {
+			continue
+		}
+		var trade domain.Trade
+		if err := json.Unmarshal(event.Snapshot, &trade); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode snapshot of event %d: %w", event.Seq, err)
+		}
+
+		known, exists := orders[trade.ID]
+		if !exists {
+			known = make(map[string]domain.Order)
+			orders[trade.ID] = known
+		}
+		for _, order := range tradeOrders(&trade) {
+			key := order.BybitID
+			if key == "" {
+				key = order.ID.String()
+			}
+			known[key] = order
+		}
+	}
+
+	executions := make(map[uuid.UUID][]domain.Order, len(orders))
+	for tradeID, known := range orders {
+		filled := make([]domain.Order, 0, len(known))
+		for _, order := range known {
+			executed, _ := strconv.ParseFloat(order.ExecutedQty, 64)
+			if executed > 0 || order.Status.IsFilled() {
+				filled = append(filled, order)
+			}
+		}
+		sort.Slice(filled, func(i, j int) bool { return filled[i].CreatedAt.Before(filled[j].CreatedAt) })
+		executions[tradeID] = filled
+	}
+	return trades, executions, nil
+}
+
+func tradeOrders(trade *domain.Trade) []domain.Order {
+	orders := make([]domain.Order, 0, len(trade.DCAOrders)+3)
+	if trade.EntryOrder != nil {
+		orders = append(orders, *trade.EntryOrder)
+	}
+	orders = append(orders, trade.DCAOrders...)
+	if trade.TakeProfitOrder != nil {
+		orders = append(orders, *trade.TakeProfitOrder)
+	}
+	if trade.StopLossOrder != nil {
+		orders = append(orders, *trade.StopLossOrder)
+	}
+	return orders
+}
+
+// recomputeTrade заново проводит исполнения через recordFill на копии сделки.
+func recomputeTrade(original *domain.Trade, fills []domain.Order) (*domain.PnLRevision, error) {
+	trade := *original
+	trade.TotalInvested = ""
+	trade.CurrentPositionQty = ""
+	trade.FreedCapital = ""
+
+	entryQty, entryValue := 0.0, 0.0
+	for i := range fills {
+		order := &fills[i]
+		recordFill(&trade, order)
+
+		if order.Side == entrySide(trade.Config) {
+			executed, _ := strconv.ParseFloat(order.ExecutedQty, 64)
+			entryQty += executed
+			entryValue += orderValue(order)
+		}
+	}
+	if entryQty <= 0 {
+		return nil, fmt.Errorf("no entry fills in journal")
+	}
+	trade.AveragePrice = fmt.Sprintf("%.8f", entryValue/entryQty)
+
+	// Выходы, исполненные вне TP/SL (например, рыночный выход помощника), в снимки не попадают
+	originalFreed, _ := strconv.ParseFloat(original.FreedCapital, 64)
+	freed, _ := strconv.ParseFloat(trade.FreedCapital, 64)
+	if originalFreed > 0 && freed == 0 {
+		return nil, fmt.Errorf("exit fills are missing from journal snapshots")
+	}
+
+	originalPnL, _, _ := closedPnL(original)
+	recomputedPnL, _, _ := closedPnL(&trade)
+	delta := recomputedPnL - originalPnL
+	if math.Abs(delta) < 5e-9 {
+		delta = 0
+	}
+
+	return &domain.PnLRevision{
+		TradeID:    original.ID,
+		Symbol:     original.Symbol,
+		Status:     string(original.Status),
+		Fills:      len(fills),
+		Original:   pnlFigures(original, originalPnL),
+		Recomputed: pnlFigures(&trade, recomputedPnL),
+		PnLDelta:   fmt.Sprintf("%.8f", delta),
+		ComputedAt: time.Now(),
+	}, nil
+}
+
+func pnlFigures(trade *domain.Trade, pnl float64) domain.PnLFigures {
+	return domain.PnLFigures{
+		TotalInvested:      trade.TotalInvested,
+		CurrentPositionQty: trade.CurrentPositionQty,
+		FreedCapital:       trade.FreedCapital,
+		AveragePrice:       trade.AveragePrice,
+		RealizedPnL:        fmt.Sprintf("%.8f", pnl),
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cryptorg/internal/bybit"
+	"cryptorg/internal/domain"
+)
+
+// PrecisionOverrides хранит точность символов, выученную из отказов биржи, когда
+// данные instrument info оказались устаревшими. Переопределения переживают рестарт.
+type PrecisionOverrides struct {
+	mu        sync.RWMutex
+	store     domain.PrecisionStore
+	overrides map[string]domain.PrecisionOverride
+}
+
+func NewPrecisionOverrides(store domain.PrecisionStore) (*PrecisionOverrides, error) {
+	overrides, err := store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load precision overrides: %w", err)
+	}
+
+	return &PrecisionOverrides{
+		store:     store,
+		overrides: overrides,
+	}, nil
+}
+
+// Apply усекает количество и цены запроса до выученной точности символа.
+func (p *PrecisionOverrides) Apply(req *bybit.ExchangeOrderRequest) {
+	p.mu.RLock()
+	override, ok := p.overrides[req.Symbol]
+	p.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	if override.QtyDecimals != nil {
+		req.Qty = truncateDecimals(req.Qty, *override.QtyDecimals)
+	}
+	if override.PriceDecimals != nil {
+		req.Price = truncateDecimals(req.Price, *override.PriceDecimals)
+		req.TriggerPrice = truncateDecimals(req.TriggerPrice, *override.PriceDecimals)
+	}
+}
+
+// Learn разбирает отказ биржи и, если он вызван лишними знаками, уменьшает точность
+// символа на один знак относительно отправленного значения. Возвращает true, если
+// запрос имеет смысл повторить.
+func (p *PrecisionOverrides) Learn(req bybit.ExchangeOrderRequest, apiErr *bybit.APIError) bool {
+	p.mu.Lock()
+	override := p.overrides[req.Symbol]
+	override.Symbol = req.Symbol
+
+	switch apiErr.RetCode {
+	case bybit.RetCodeQtyDecimalTooLong, bybit.RetCodeAmountDecimalTooLong:
+		decimals := countDecimals(req.Qty) - 1
+		if decimals < 0 {
+			p.mu.Unlock()
+			return false
+		}
+		override.QtyDecimals = &decimals
+	case bybit.RetCodePriceDecimalTooLong:
+		price := req.Price
+		if price == "" {
+			price = req.TriggerPrice
+		}
+		decimals := countDecimals(price) - 1
+		if decimals < 0 {
+			p.mu.Unlock()
+			return false
+		}
+		override.PriceDecimals = &decimals
+	default:
+		p.mu.Unlock()
+		return false
+	}
+
+	override.UpdatedAt = time.Now()
+	p.overrides[req.Symbol] = override
+
+	snapshot := make(map[string]domain.PrecisionOverride, len(p.overrides))
+	for symbol, o := range p.overrides {
+		snapshot[symbol] = o
+	}
+	p.mu.Unlock()
+
+	log.Printf("Learned precision override for %s after retCode %d: %s", req.Symbol, apiErr.RetCode, apiErr.RetMsg)
+	if err := p.store.Save(snapshot); err != nil {
+		log.Printf("Failed to persist precision overrides: %v", err)
+	}
+	return true
+}
+
+func countDecimals(value string) int {
+	idx := strings.IndexByte(value, '.')
+	if idx < 0 {
+		return 0
+	}
+	return len(strings.TrimRight(value[idx+1:], "0"))
+}
+
+func truncateDecimals(value string, decimals int) string {
+	if value == "" || countDecimals(value) <= decimals {
+		return value
+	}
+
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return value
+	}
+
+	// Усечение вниз: округление вверх могло бы превысить доступный баланс
+	factor := 1.0
+	for i := 0; i < decimals; i++ {
+		factor *= 10
+	}
+	truncated := float64(int64(v*factor+1e-9)) / factor
+	return strconv.FormatFloat(truncated, 'f', decimals, 64)
+}
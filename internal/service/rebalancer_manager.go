@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"cryptorg/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// RebalancerService ведет портфели с целевым распределением и выравнивает доли
+// лимитными ордерами, когда отклонение превышает порог.
+type RebalancerService struct {
+	orderManager *OrderService
+	tradeManager *TradeService
+	exposure     *ExposureGuard
+	portfolios   map[uuid.UUID]*domain.Portfolio
+	mu           sync.RWMutex
+}
+
+func NewRebalancerManager(orderManager *OrderService, tradeManager *TradeService, exposure *ExposureGuard) *RebalancerService {
+	return &RebalancerService{
+		orderManager: orderManager,
+		tradeManager: tradeManager,
+		exposure:     exposure,
+		portfolios:   make(map[uuid.UUID]*domain.Portfolio),
+	}
+}
+
+func (s *RebalancerService) CreatePortfolio(ctx context.Context, config domain.PortfolioConfig) (*domain.Portfolio, error) {
+	symbols := portfolioSymbols(config)
+	if !config.AllowOppositeExposure {
+		if conflicts := s.tradeManager.OpenTradesBySymbol(symbols); len(conflicts) > 0 {
+			return nil, oppositeTradesError(conflicts)
+		}
+	}
+
+	portfolio := &domain.Portfolio{
+		ID:         uuid.New(),
+		Config:     config,
+		Status:     domain.PortfolioStatusActive,
+		OpenOrders: make([]domain.Order, 0),
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+
+	if err := s.valuate(ctx, portfolio); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.portfolios[portfolio.ID] = portfolio
+	s.mu.Unlock()
+	s.exposure.ClaimPortfolio(portfolio.ID, symbols)
+
+	return portfolio, nil
+}
+
+func (s *RebalancerService) GetPortfolio(id uuid.UUID) (*domain.Portfolio, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	portfolio, exists := s.portfolios[id]
+	if !exists {
+		return nil, fmt.Errorf("portfolio not found: %s", id)
+	}
+	return portfolio, nil
+}
+
+func (s *RebalancerService) GetAllPortfolios() []*domain.Portfolio {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]*domain.Portfolio, 0, len(s.portfolios))
+	for _, portfolio := range s.portfolios {
+		result = append(result, portfolio)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt) })
+	return result
+}
+
+func (s *RebalancerService) StopPortfolio(ctx context.Context, id uuid.UUID) error {
+	portfolio, err := s.GetPortfolio(id)
+	if err != nil {
+		return err
+	}
+
+	s.cancelOpenOrders(ctx, portfolio)
+
+	s.mu.Lock()
+	portfolio.Status = domain.PortfolioStatusStopped
+	portfolio.UpdatedAt = time.Now()
+	s.mu.Unlock()
+	s.exposure.ReleasePortfolio(id)
+
+	return nil
+}
+
+// RebalanceAll проходит по активным портфелям, у которых наступил период ребалансировки;
+// используется планировщиком.
+func (s *RebalancerService) RebalanceAll(ctx context.Context) error {
+	now := time.Now()
+
+	s.mu.RLock()
+	active := make([]uuid.UUID, 0)
+	for id, portfolio := range s.portfolios {
+		if portfolio.Status != domain.PortfolioStatusActive {
+			continue
+		}
+		interval := time.Duration(portfolio.Config.IntervalMinutes) * time.Minute
+		if portfolio.LastRebalanceAt == nil || now.Sub(*portfolio.LastRebalanceAt) >= interval {
+			active = append(active, id)
+		}
+	}
+	s.mu.RUnlock()
+
+	var errs []error
+	for _, id := range active {
+		if _, err := s.Rebalance(ctx, id); err != nil {
+			errs = append(errs, fmt.Errorf("portfolio %s: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Rebalance пересчитывает доли и, если какая-то ушла за порог, снимает старые ордера
+// ребалансировки и выставляет новые: сначала продажи, затем покупки на вырученное.
+func (s *RebalancerService) Rebalance(ctx context.Context, id uuid.UUID) (*domain.Portfolio, error) {
+	portfolio, err := s.GetPortfolio(id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cancelOpenOrders(ctx, portfolio)
+
+	if err := s.valuate(ctx, portfolio); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !needsRebalance(portfolio) {
+		s.mu.Lock()
+		portfolio.LastRebalanceAt = &now
+		s.mu.Unlock()
+		return portfolio, nil
+	}
+
+	minVolume, _ := strconv.ParseFloat(portfolio.Config.MinOrderVolume, 64)
+
+	holdings := append([]domain.AssetHolding(nil), portfolio.Holdings...)
+	sort.Slice(holdings, func(i, j int) bool { return holdings[i].DriftPercent > holdings[j].DriftPercent })
+
+	orders := make([]domain.Order, 0)
+	var errs []error
+	for _, holding := range holdings {
+		delta := holding.DriftPercent / 100 * portfolio.TotalValue
+		volume := math.Min(math.Abs(delta), domain.MaxPositionValue)
+		if volume < minVolume || volume == 0 {
+			continue
+		}
+
+		side := domain.OrderSideSell
+		if delta < 0 {
+			side = domain.OrderSideBuy
+		}
+
+		order, err := s.orderManager.ExecuteLimitOrder(ctx, domain.CreateOrderRequest{
+			Symbol:   holding.Asset + portfolio.Config.QuoteAsset,
+			Side:     side,
+			Type:     domain.OrderTypeLimit,
+			Quantity: fmt.Sprintf("%.8f", volume),
+			Price:    fmt.Sprintf("%.8f", holding.Price),
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", side, holding.Asset, err))
+			continue
+		}
+		orders = append(orders, *order)
+	}
+
+	s.mu.Lock()
+	portfolio.OpenOrders = orders
+	portfolio.LastRebalanceAt = &now
+	portfolio.UpdatedAt = now
+	s.mu.Unlock()
+
+	return portfolio, errors.Join(errs...)
+}
+
+// valuate обновляет количества, цены и доли активов по балансам биржи.
+func (s *RebalancerService) valuate(ctx context.Context, portfolio *domain.Portfolio) error {
+	holdings := make([]domain.AssetHolding, 0, len(portfolio.Config.Allocations))
+	total := 0.0
+
+	for asset, target := range portfolio.Config.Allocations {
+		quantity, _, err := s.orderManager.FetchBalance(ctx, asset)
+		if err != nil {
+			return err
+		}
+
+		price, err := s.orderManager.FetchLastPrice(ctx, asset+portfolio.Config.QuoteAsset)
+		if err != nil {
+			return err
+		}
+
+		value := quantity * price
+		total += value
+		holdings = append(holdings, domain.AssetHolding{
+			Asset:         asset,
+			Quantity:      quantity,
+			Price:         price,
+			Value:         value,
+			TargetPercent: target,
+		})
+	}
+
+	for i := range holdings {
+		if total > 0 {
+			holdings[i].WeightPercent = holdings[i].Value / total * 100
+		}
+		holdings[i].DriftPercent = holdings[i].WeightPercent - holdings[i].TargetPercent
+	}
+	sort.Slice(holdings, func(i, j int) bool { return holdings[i].Asset < holdings[j].Asset })
+
+	s.mu.Lock()
+	portfolio.Holdings = holdings
+	portfolio.TotalValue = total
+	portfolio.UpdatedAt = time.Now()
+	s.mu.Unlock()
+
+	return nil
+}
+
+func needsRebalance(portfolio *domain.Portfolio) bool {
+	if portfolio.TotalValue <= 0 {
+		return false
+	}
+	for _, holding := range portfolio.Holdings {
+		if math.Abs(holding.DriftPercent) > portfolio.Config.DriftThresholdPercent {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *RebalancerService) cancelOpenOrders(ctx context.Context, portfolio *domain.Portfolio) {
+	s.mu.RLock()
+	orders := append([]domain.Order(nil), portfolio.OpenOrders...)
+	s.mu.RUnlock()
+
+	for _, order := range orders {
+		if err := s.orderManager.TerminateOrder(ctx, order.Symbol, order.BybitID); err != nil {
+		}
+	}
+
+	s.mu.Lock()
+	portfolio.OpenOrders = make([]domain.Order, 0)
+	s.mu.Unlock()
+}
+
+// PlanStop возвращает ордера, которые снимет StopPortfolio. Остановка активы не продает.
+func (s *RebalancerService) PlanStop(id uuid.UUID) (*domain.DryRunPlan, error) {
+	portfolio, err := s.GetPortfolio(id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	plan := &domain.DryRunPlan{
+		Action:         "stop_portfolio",
+		OrdersToCancel: make([]domain.PlannedCancel, 0, len(portfolio.OpenOrders)),
+		Sales:          make([]domain.PlannedSale, 0),
+	}
+	for i := range portfolio.OpenOrders {
+		plan.OrdersToCancel = append(plan.OrdersToCancel, plannedCancel(&portfolio.OpenOrders[i], "rebalance"))
+	}
+	return plan, nil
+}
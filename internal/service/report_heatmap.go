@@ -0,0 +1,103 @@
+package service
+
+import (
+	"time"
+
+	"cryptorg/internal/domain"
+)
+
+type heatmapBucket struct {
+	trades int
+	fills  int
+	wins   int
+	cycle  time.Duration
+}
+
+func (b *heatmapBucket) add(fills int, win bool, cycle time.Duration) {
+	b.trades++
+	b.fills += fills
+	b.cycle += cycle
+	if win {
+		b.wins++
+	}
+}
+
+func (b *heatmapBucket) merge(other heatmapBucket) {
+	b.trades += other.trades
+	b.fills += other.fills
+	b.wins += other.wins
+	b.cycle += other.cycle
+}
+
+func (b *heatmapBucket) cell(weekday string, hour int) domain.HeatmapCell {
+	cell := domain.HeatmapCell{Weekday: weekday, Hour: hour, Trades: b.trades, Fills: b.fills}
+	if b.trades > 0 {
+		cell.WinRate = float64(b.wins) / float64(b.trades)
+		cell.AvgCycleDuration = (b.cycle / time.Duration(b.trades)).Round(time.Second).String()
+	}
+	return cell
+}
+
+// TimeHeatmap группирует завершенные (по TP или остановленные) сделки, открытые начиная с since,
+// по часу и дню недели открытия в часовом поясе отчетов. Нулевой since означает всю историю.
+func (s *ReportService) TimeHeatmap(since time.Time) *domain.TimeHeatmap {
+	var cells [7][24]heatmapBucket
+	total := 0
+
+	for _, trade := range s.tradeManager.GetAllTrades() {
+		if trade.Status != domain.TradeStatusCompleted && trade.Status != domain.TradeStatusStopped {
+			continue
+		}
+		if trade.CreatedAt.Before(since) {
+			continue
+		}
+
+		opened := trade.CreatedAt.In(s.location)
+		win := trade.Status == domain.TradeStatusCompleted && realizedProfit(trade) > 0
+		cells[opened.Weekday()][opened.Hour()].add(countFills(trade), win, tradeClosedAt(trade).Sub(trade.CreatedAt))
+		total++
+	}
+
+	heatmap := &domain.TimeHeatmap{
+		Timezone:  s.location.String(),
+		Trades:    total,
+		Cells:     make([]domain.HeatmapCell, 0),
+		ByHour:    make([]domain.HeatmapCell, 0, 24),
+		ByWeekday: make([]domain.HeatmapCell, 0, 7),
+	}
+
+	var byHour [24]heatmapBucket
+	// Неделя начинается с понедельника
+	for i := 1; i <= 7; i++ {
+		weekday := time.Weekday(i % 7)
+		var day heatmapBucket
+		for hour := 0; hour < 24; hour++ {
+			bucket := cells[weekday][hour]
+			if bucket.trades == 0 {
+				continue
+			}
+			heatmap.Cells = append(heatmap.Cells, bucket.cell(weekday.String(), hour))
+			day.merge(bucket)
+			byHour[hour].merge(bucket)
+		}
+		heatmap.ByWeekday = append(heatmap.ByWeekday, day.cell(weekday.String(), -1))
+	}
+	for hour := range byHour {
+		heatmap.ByHour = append(heatmap.ByHour, byHour[hour].cell("", hour))
+	}
+
+	return heatmap
+}
+
+func countFills(trade *domain.Trade) int {
+	fills := 0
+	if trade.EntryOrder != nil && isFilledStatus(trade.EntryOrder.Status) {
+		fills++
+	}
+	for _, order := range trade.DCAOrders {
+		if isFilledStatus(order.Status) {
+			fills++
+		}
+	}
+	return fills
+}
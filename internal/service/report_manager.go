@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cryptorg/internal/bybit"
+	"cryptorg/internal/domain"
+	"cryptorg/internal/notify"
+)
+
+const reportDateLayout = "2006-01-02"
+
+// ReportService агрегирует сделки по дням. Границы дней считаются в настроенном
+// часовом поясе, а не по полуночи UTC.
+type ReportService struct {
+	tradeManager  *TradeService
+	notifier      notify.Notifier
+	location      *time.Location
+	deliveryHour  int    // Час (в часовом поясе отчетов), после которого отправляется отчет за вчера
+	lastDelivered string // Дата последнего отправленного отчета
+	mu            sync.Mutex
+}
+
+func NewReportManager(tradeManager *TradeService, notifier notify.Notifier, location *time.Location, deliveryHour int) *ReportService {
+	return &ReportService{
+		tradeManager: tradeManager,
+		notifier:     notifier,
+		location:     location,
+		deliveryHour: deliveryHour,
+	}
+}
+
+func (s *ReportService) Location() *time.Location {
+	return s.location
+}
+
+// Summary возвращает отчет по дням с from по to включительно (даты в часовом поясе отчетов).
+func (s *ReportService) Summary(from, to time.Time) *domain.ReportSummary {
+	from = startOfDay(from.In(s.location))
+	to = startOfDay(to.In(s.location))
+
+	days := make([]domain.DailyReport, 0)
+	index := make(map[string]int)
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format(reportDateLayout)
+		index[date] = len(days)
+		days = append(days, domain.DailyReport{Date: date})
+	}
+
+	profits := make([]map[string]float64, len(days))
+	for i := range profits {
+		profits[i] = make(map[string]float64)
+	}
+	total := make(map[string]float64)
+	for _, trade := range s.tradeManager.GetAllTrades() {
+		if i, ok := index[trade.CreatedAt.In(s.location).Format(reportDateLayout)]; ok {
+			days[i].Opened++
+		}
+
+		i, ok := index[tradeClosedAt(trade).In(s.location).Format(reportDateLayout)]
+		if !ok {
+			continue
+		}
+		switch trade.Status {
+		case domain.TradeStatusCompleted:
+			days[i].Completed++
+			// Прибыль в разных котируемых валютах не складывается
+			quote := bybit.QuoteAsset(trade.Symbol)
+			profit := realizedProfit(trade)
+			profits[i][quote] += profit
+			total[quote] += profit
+		case domain.TradeStatusStopped:
+			days[i].Stopped++
+		case domain.TradeStatusCancelled:
+			days[i].Cancelled++
+		}
+	}
+
+	for i := range days {
+		days[i].RealizedProfit = formatByQuote(profits[i])
+	}
+
+	return &domain.ReportSummary{
+		Timezone:            s.location.String(),
+		From:                from.Format(reportDateLayout),
+		To:                  to.Format(reportDateLayout),
+		Days:                days,
+		TotalRealizedProfit: formatByQuote(total),
+	}
+}
+
+// DeliverDailyReport отправляет отчет за вчерашний день один раз в сутки,
+// как только в часовом поясе отчетов наступает час доставки.
+func (s *ReportService) DeliverDailyReport(ctx context.Context) error {
+	now := time.Now().In(s.location)
+	if now.Hour() < s.deliveryHour {
+		return nil
+	}
+
+	yesterday := now.AddDate(0, 0, -1)
+	date := yesterday.Format(reportDateLayout)
+
+	s.mu.Lock()
+	if s.lastDelivered == date {
+		s.mu.Unlock()
+		return nil
+	}
+	s.lastDelivered = date
+	s.mu.Unlock()
+
+	day := s.Summary(yesterday, yesterday).Days[0]
+	quotes := make([]string, 0, len(day.RealizedProfit))
+	for quote, profit := range day.RealizedProfit {
+		quotes = append(quotes, profit+" "+quote)
+	}
+	sort.Strings(quotes)
+
+	return s.notifier.Notify(ctx, notify.Localized(notify.LevelInfo, "Daily report",
+		"%s (%s): opened %d, completed %d, stopped %d, cancelled %d, realized profit %s",
+		day.Date, s.location, day.Opened, day.Completed, day.Stopped, day.Cancelled, strings.Join(quotes, ", ")))
+}
+
+func formatByQuote(amounts map[string]float64) map[string]string {
+	result := make(map[string]string, len(amounts))
+	for quote, amount := range amounts {
+		result[quote] = fmt.Sprintf("%.8f", amount)
+	}
+	return result
+}
+
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// realizedProfit оценивает прибыль сделки, закрытой по TP: (цена TP - средняя цена) * объем TP.
+func realizedProfit(trade *domain.Trade) float64 {
+	if trade.TakeProfitOrder == nil {
+		return 0
+	}
+
+	tpPrice, err := strconv.ParseFloat(trade.TakeProfitOrder.Price, 64)
+	if err != nil {
+		return 0
+	}
+	averagePrice, err := strconv.ParseFloat(trade.AveragePrice, 64)
+	if err != nil && trade.EntryOrder != nil {
+		averagePrice, err = strconv.ParseFloat(trade.EntryOrder.Price, 64)
+	}
+	if err != nil {
+		return 0
+	}
+
+	quantity, err := strconv.ParseFloat(trade.TakeProfitOrder.ExecutedQty, 64)
+	if err != nil || quantity == 0 {
+		quantity, _ = strconv.ParseFloat(trade.TakeProfitOrder.Quantity, 64)
+	}
+
+	profit := sideSign(trade.Config) * (tpPrice - averagePrice) * quantity
+	// Со своими комиссиями в конфиге прибыль считается чистой
+	if entryFee, exitFee, ok := tradeFees(trade.Config); ok {
+		profit -= averagePrice*quantity*entryFee + tpPrice*quantity*exitFee
+	}
+	return profit
+}
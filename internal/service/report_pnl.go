@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"cryptorg/internal/bybit"
+	"cryptorg/internal/domain"
+)
+
+// PnLStats агрегирует реализованный PnL закрытых сделок по дням или неделям с from по to
+// включительно и добавляет нереализованный PnL активных сделок по последним ценам.
+func (s *ReportService) PnLStats(ctx context.Context, period string, from, to time.Time) (*domain.PnLStats, error) {
+	bucketStart := startOfDay
+	step := func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }
+	if period == domain.PnLPeriodWeek {
+		bucketStart = startOfWeek
+		step = func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }
+	}
+	from = bucketStart(from.In(s.location))
+	to = bucketStart(to.In(s.location))
+
+	buckets := make([]domain.PnLBucket, 0)
+	index := make(map[string]int)
+	for start := from; !start.After(to); start = step(start) {
+		date := start.Format(reportDateLayout)
+		index[date] = len(buckets)
+		buckets = append(buckets, domain.PnLBucket{Start: date})
+	}
+
+	realized := make([]map[string]float64, len(buckets))
+	fees := make([]map[string]float64, len(buckets))
+	for i := range buckets {
+		realized[i] = make(map[string]float64)
+		fees[i] = make(map[string]float64)
+	}
+	total := make(map[string]float64)
+	for _, trade := range s.tradeManager.GetAllTrades() {
+		if trade.Status != domain.TradeStatusCompleted && trade.Status != domain.TradeStatusStopped {
+			continue
+		}
+		i, ok := index[bucketStart(tradeClosedAt(trade).In(s.location)).Format(reportDateLayout)]
+		if !ok {
+			continue
+		}
+		profit, ok := tradeRealizedPnL(trade)
+		if !ok {
+			continue
+		}
+
+		quote := bybit.QuoteAsset(trade.Symbol)
+		buckets[i].Closed++
+		realized[i][quote] += profit
+		total[quote] += profit
+		if paid, err := strconv.ParseFloat(trade.Fees, 64); err == nil {
+			fees[i][quote] += paid
+		} else {
+			fees[i][quote] += tradeFeesPaid(trade)
+		}
+	}
+
+	for i := range buckets {
+		buckets[i].Realized = formatByQuote(realized[i])
+		buckets[i].Fees = formatByQuote(fees[i])
+	}
+
+	unrealized, active, err := s.tradeManager.UnrealizedPnL(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.PnLStats{
+		Timezone:     s.location.String(),
+		Period:       period,
+		From:         from.Format(reportDateLayout),
+		To:           to.Format(reportDateLayout),
+		Buckets:      buckets,
+		Realized:     formatByQuote(total),
+		Unrealized:   formatByQuote(unrealized),
+		ActiveTrades: active,
+		CalculatedAt: time.Now(),
+	}, nil
+}
+
+// startOfWeek - полночь понедельника недели, в которую попадает t.
+func startOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return startOfDay(t).AddDate(0, 0, -offset)
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"cryptorg/internal/domain"
+)
+
+// PublicStats собирает статистику для публичной страницы. Прибыль выражается в процентах
+// от капитала закрытых сделок, чтобы не раскрывать размер счета.
+func (s *ReportService) PublicStats() *domain.PublicStats {
+	stats := &domain.PublicStats{
+		Timezone: s.location.String(),
+		Curve:    make([]domain.PublicPnLPoint, 0),
+	}
+
+	type dayTotals struct {
+		trades   int
+		profit   float64
+		invested float64
+	}
+	days := make(map[string]*dayTotals)
+	wins := 0
+
+	for _, trade := range s.tradeManager.GetAllTrades() {
+		if trade.Status == domain.TradeStatusActive || trade.Status == domain.TradeStatusPaused {
+			stats.ActiveTrades++
+			continue
+		}
+
+		profit, invested, ok := closedPnL(trade)
+		if !ok {
+			continue
+		}
+		stats.ClosedTrades++
+		if profit > 0 {
+			wins++
+		}
+
+		date := trade.UpdatedAt.In(s.location).Format(reportDateLayout)
+		day, exists := days[date]
+		if !exists {
+			day = &dayTotals{}
+			days[date] = day
+		}
+		day.trades++
+		day.profit += profit
+		day.invested += invested
+	}
+
+	dates := make([]string, 0, len(days))
+	for date := range days {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	profit, invested := 0.0, 0.0
+	for _, date := range dates {
+		profit += days[date].profit
+		invested += days[date].invested
+		stats.Curve = append(stats.Curve, domain.PublicPnLPoint{
+			Date:      date,
+			Trades:    days[date].trades,
+			ReturnPct: roundPercent(profit / invested * 100),
+		})
+	}
+
+	if stats.ClosedTrades > 0 {
+		stats.WinRate = float64(wins) / float64(stats.ClosedTrades)
+		stats.ReturnPct = roundPercent(profit / invested * 100)
+	}
+	return stats
+}
+
+// closedPnL - прибыль и вложенный капитал закрытой сделки. Если продажи учтены в FreedCapital,
+// берется разница с TotalInvested, иначе оценка по TP. Сделки без продаж не учитываются.
+func closedPnL(trade *domain.Trade) (profit, invested float64, ok bool) {
+	if trade.Status != domain.TradeStatusCompleted && trade.Status != domain.TradeStatusStopped &&
+		trade.Status != domain.TradeStatusCancelled {
+		return 0, 0, false
+	}
+
+	invested, _ = strconv.ParseFloat(trade.TotalInvested, 64)
+	if invested <= 0 {
+		return 0, 0, false
+	}
+
+	if freed, _ := strconv.ParseFloat(trade.FreedCapital, 64); freed > 0 {
+		return positionPnL(trade, 0), invested, true
+	}
+	if trade.Status == domain.TradeStatusCompleted {
+		return realizedProfit(trade), invested, true
+	}
+	return 0, 0, false
+}
+
+func roundPercent(value float64) float64 {
+	return math.Round(value*100) / 100
+}
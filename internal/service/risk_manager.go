@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"cryptorg/internal/bybit"
+	"cryptorg/internal/domain"
+)
+
+const (
+	volatilityInterval = "D"
+	volatilityLookback = 30
+)
+
+type RiskService struct {
+	exchangeClient ExchangeClient
+	exchanges      map[string]ExchangeClient // Биржи по имени для TradeConfig.Exchange; заполняется при сборке
+	quoteBudgets   map[string]float64        // Бюджет по котируемой валюте; без записи - общий лимит MaxPositionValue
+	symbols        *SymbolLists
+	cooldowns      *SymbolCooldowns
+	lossLimits     map[string]float64 // Допустимый убыток за 24 часа по котируемой валюте
+	approvalLimits map[string]float64 // Капитал сделки, выше которого нужно подтверждение оператора
+
+	lossMu     sync.Mutex
+	suspension domain.TradingSuspension
+	lastPnL    map[string]float64
+	baseline   map[string]float64
+}
+
+func NewRiskManager(exchangeClient ExchangeClient, quoteBudgets map[string]float64, symbols *SymbolLists, cooldowns *SymbolCooldowns, lossLimits map[string]float64, approvalLimits map[string]float64) *RiskService {
+	return &RiskService{
+		exchangeClient: exchangeClient,
+		quoteBudgets:   quoteBudgets,
+		symbols:        symbols,
+		cooldowns:      cooldowns,
+		lossLimits:     lossLimits,
+		approvalLimits: approvalLimits,
+	}
+}
+
+// AddExchange регистрирует клиент биржи, которую можно выбрать в конфиге сделки.
+func (s *RiskService) AddExchange(name string, client ExchangeClient) {
+	if s.exchanges == nil {
+		s.exchanges = make(map[string]ExchangeClient)
+	}
+	s.exchanges[name] = client
+}
+
+// clientFor возвращает клиент биржи сделки; пустое или неизвестное имя - биржа по умолчанию.
+func (s *RiskService) clientFor(config domain.TradeConfig) ExchangeClient {
+	if client, ok := s.exchanges[config.Exchange]; ok {
+		return client
+	}
+	return s.exchangeClient
+}
+
+func (s *RiskService) SymbolLists() *SymbolLists {
+	return s.symbols
+}
+
+func (s *RiskService) Cooldowns() *SymbolCooldowns {
+	return s.cooldowns
+}
+
+// QuoteBudget возвращает бюджет котируемой валюты и признак того, что он задан явно.
+func (s *RiskService) QuoteBudget(quote string) (float64, bool) {
+	budget, ok := s.quoteBudgets[quote]
+	if !ok {
+		return domain.MaxPositionValue, false
+	}
+	return budget, true
+}
+
+// ApprovalThreshold возвращает порог подтверждения котируемой валюты, если он задан.
+func (s *RiskService) ApprovalThreshold(quote string) (float64, bool) {
+	threshold, ok := s.approvalLimits[quote]
+	return threshold, ok && threshold > 0
+}
+
+func (s *RiskService) QuoteBudgets() map[string]float64 {
+	return s.quoteBudgets
+}
+
+func (s *RiskService) AssessTrade(ctx context.Context, config domain.TradeConfig, entryPrice domain.Decimal) *domain.RiskAssessment {
+	grid := BuildGrid(config, entryPrice)
+	return s.assessGrid(ctx, config, grid)
+}
+
+func (s *RiskService) assessGrid(ctx context.Context, config domain.TradeConfig, grid []domain.GridLevel) *domain.RiskAssessment {
+	assessment := &domain.RiskAssessment{
+		RequiredCapital: fmt.Sprintf("%.8f", requiredCapital(config, grid)),
+		Warnings:        make([]string, 0),
+	}
+
+	if len(grid) > 0 {
+		assessment.MaxDeviationPercent = grid[len(grid)-1].DeviationPercent
+
+		firstVolume, _ := strconv.ParseFloat(grid[0].Volume, 64)
+		lastVolume, _ := strconv.ParseFloat(grid[len(grid)-1].Volume, 64)
+		if firstVolume > 0 {
+			assessment.MartingaleSteepness = lastVolume / firstVolume
+		}
+	}
+
+	client := s.clientFor(config)
+	volatility, err := averageDailyRange(ctx, client, config.Symbol)
+	if err == nil {
+		assessment.VolatilityPercent = &volatility
+	}
+
+	score := 0
+
+	move := "drop"
+	if config.IsShort() {
+		move = "rise"
+	}
+	if config.Strategy != domain.StrategyTimeBased {
+		if volatility > 0 && assessment.MaxDeviationPercent < volatility*2 {
+			score += 40
+			assessment.Warnings = append(assessment.Warnings, fmt.Sprintf(
+				"this grid only covers a %.1f%% %s while the average daily range is %.1f%%",
+				assessment.MaxDeviationPercent, move, volatility))
+		} else if assessment.MaxDeviationPercent < 10 {
+			score += 25
+			assessment.Warnings = append(assessment.Warnings, fmt.Sprintf(
+				"this grid only covers a %.1f%% %s", assessment.MaxDeviationPercent, move))
+		}
+	}
+
+	if assessment.MartingaleSteepness > 10 {
+		score += 30
+		assessment.Warnings = append(assessment.Warnings, fmt.Sprintf(
+			"the last DCA level is %.1fx the first one, deep fills will dominate the position",
+			assessment.MartingaleSteepness))
+	} else if assessment.MartingaleSteepness > 3 {
+		score += 15
+	}
+
+	quote := bybit.QuoteAsset(config.Symbol)
+	limit, _ := s.QuoteBudget(quote)
+	if capital, _ := strconv.ParseFloat(assessment.RequiredCapital, 64); capital > limit {
+		score += 30
+		assessment.Warnings = append(assessment.Warnings, fmt.Sprintf(
+			"required capital %.2f %s exceeds the %s limit %.2f", capital, quote, quote, limit))
+	}
+
+	if !config.IsShort() {
+		if available, err := freeBalance(ctx, client, quote); err == nil {
+			formatted := fmt.Sprintf("%.8f", available)
+			assessment.AvailableBalance = &formatted
+			if capital, _ := strconv.ParseFloat(assessment.RequiredCapital, 64); capital > available {
+				score += 30
+				assessment.Warnings = append(assessment.Warnings, fmt.Sprintf(
+					"required capital %.2f %s exceeds the free balance %.2f %s", capital, quote, available, quote))
+			}
+		}
+	}
+
+	if config.StopLossPercent <= 0 {
+		score += 10
+	}
+
+	if entryFee, exitFee, ok := tradeFees(config); ok && config.TakeProfitPercent <= (entryFee+exitFee)*100 {
+		assessment.Warnings = append(assessment.Warnings, fmt.Sprintf(
+			"take profit %.2f%% is below the %.2f%% round-trip fees, TP is moved to cover them",
+			config.TakeProfitPercent, (entryFee+exitFee)*100))
+	}
+
+	if score > 100 {
+		score = 100
+	}
+	assessment.Score = score
+	assessment.Level = riskLevel(score)
+
+	return assessment
+}
+
+func averageDailyRange(ctx context.Context, client ExchangeClient, symbol string) (float64, error) {
+	klines, err := client.GetKlines(ctx, symbol, volatilityInterval, volatilityLookback)
+	if err != nil {
+		return 0, err
+	}
+	if len(klines) == 0 {
+		return 0, fmt.Errorf("no klines for %s", symbol)
+	}
+
+	total := 0.0
+	for _, k := range klines {
+		if k.Close > 0 {
+			total += (k.High - k.Low) / k.Close * 100
+		}
+	}
+
+	return total / float64(len(klines)), nil
+}
+
+// freeBalance возвращает свободный остаток монеты на счете. Шорт продает базовую монету,
+// поэтому сверяется только котируемая валюта лонга.
+func freeBalance(ctx context.Context, client ExchangeClient, coin string) (float64, error) {
+	balance, err := client.GetBalance(ctx, coin)
+	if err != nil {
+		return 0, err
+	}
+
+	total, err := strconv.ParseFloat(balance.WalletBalance, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid wallet balance: %w", err)
+	}
+	locked := 0.0
+	if balance.Locked != "" {
+		if locked, err = strconv.ParseFloat(balance.Locked, 64); err != nil {
+			return 0, fmt.Errorf("invalid locked balance: %w", err)
+		}
+	}
+	return total - locked, nil
+}
+
+func riskLevel(score int) domain.RiskLevel {
+	switch {
+	case score < 30:
+		return domain.RiskLevelLow
+	case score < 60:
+		return domain.RiskLevelMedium
+	default:
+		return domain.RiskLevelHigh
+	}
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+type Job func(ctx context.Context) error
+
+type scheduledJob struct {
+	name string
+	run  Job
+}
+
+type Scheduler struct {
+	interval time.Duration
+	jobs     []scheduledJob
+}
+
+func NewScheduler(interval time.Duration) *Scheduler {
+	return &Scheduler{
+		interval: interval,
+		jobs:     make([]scheduledJob, 0),
+	}
+}
+
+func (s *Scheduler) Register(name string, job Job) {
+	s.jobs = append(s.jobs, scheduledJob{name: name, run: job})
+}
+
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, job := range s.jobs {
+				if err := job.run(ctx); err != nil {
+					log.Printf("Scheduled job %s failed: %v", job.name, err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrSignalCooldown = errors.New("signal is within symbol cooldown")
+	ErrSignalInFlight = errors.New("identical signal is being processed")
+)
+
+type signalRecord struct {
+	tradeID    uuid.UUID
+	receivedAt time.Time
+}
+
+// SignalGate отсекает повторные сигналы внешних алертов (TradingView и т.п.):
+// идентичные payload в пределах окна дедупликации возвращают уже открытую сделку,
+// а любой новый вход по символу в пределах cooldown отклоняется.
+type SignalGate struct {
+	mu              sync.Mutex
+	cooldown        time.Duration
+	symbolCooldowns map[string]time.Duration
+	dedupWindow     time.Duration
+	lastEntry       map[string]time.Time    // symbol -> время последнего принятого сигнала
+	processed       map[string]signalRecord // ключ payload -> результат
+}
+
+func NewSignalGate(cooldown, dedupWindow time.Duration, symbolCooldowns map[string]time.Duration) *SignalGate {
+	return &SignalGate{
+		cooldown:        cooldown,
+		symbolCooldowns: symbolCooldowns,
+		dedupWindow:     dedupWindow,
+		lastEntry:       make(map[string]time.Time),
+		processed:       make(map[string]signalRecord),
+	}
+}
+
+func (g *SignalGate) cooldownFor(symbol string) time.Duration {
+	if cooldown, ok := g.symbolCooldowns[symbol]; ok {
+		return cooldown
+	}
+	return g.cooldown
+}
+
+// Admit резервирует вход по сигналу. Для дубликата возвращает ID ранее открытой сделки.
+// Ошибка ErrSignalCooldown сопровождается оставшимся временем ожидания.
+func (g *SignalGate) Admit(symbol, key string) (duplicateOf *uuid.UUID, retryAfter time.Duration, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	for k, record := range g.processed {
+		if now.Sub(record.receivedAt) > g.dedupWindow {
+			delete(g.processed, k)
+		}
+	}
+
+	if record, ok := g.processed[key]; ok {
+		if record.tradeID == uuid.Nil {
+			return nil, 0, ErrSignalInFlight
+		}
+		tradeID := record.tradeID
+		return &tradeID, 0, nil
+	}
+
+	if last, ok := g.lastEntry[symbol]; ok {
+		if elapsed := now.Sub(last); elapsed < g.cooldownFor(symbol) {
+			return nil, g.cooldownFor(symbol) - elapsed, ErrSignalCooldown
+		}
+	}
+
+	g.lastEntry[symbol] = now
+	g.processed[key] = signalRecord{receivedAt: now}
+	return nil, 0, nil
+}
+
+// Complete связывает принятый сигнал с открытой по нему сделкой.
+func (g *SignalGate) Complete(key string, tradeID uuid.UUID) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if record, ok := g.processed[key]; ok {
+		record.tradeID = tradeID
+		g.processed[key] = record
+	}
+}
+
+// Release снимает резерв, если сделку открыть не удалось, чтобы повтор алерта не был отброшен.
+func (g *SignalGate) Release(symbol, key string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	delete(g.processed, key)
+	delete(g.lastEntry, symbol)
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"cryptorg/internal/domain"
+	apperrors "cryptorg/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// SymbolCooldowns - паузы по символам после выхода по стоп-лоссу: защита от входа в падающий
+// рынок сразу после стопа. Паузы переживают рестарт; нулевая длительность их выключает.
+type SymbolCooldowns struct {
+	mu        sync.RWMutex
+	store     domain.CooldownStore
+	duration  time.Duration
+	cooldowns map[string]domain.SymbolCooldown
+}
+
+func NewSymbolCooldowns(store domain.CooldownStore, duration time.Duration) (*SymbolCooldowns, error) {
+	cooldowns, err := store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load symbol cooldowns: %w", err)
+	}
+
+	return &SymbolCooldowns{
+		store:     store,
+		duration:  duration,
+		cooldowns: cooldowns,
+	}, nil
+}
+
+// Start ставит символ на паузу от момента стопа. Более длинная действующая пауза сохраняется.
+// Возвращает false, если паузы выключены.
+func (c *SymbolCooldowns) Start(symbol string, tradeID uuid.UUID, reason string, at time.Time) (domain.SymbolCooldown, bool, error) {
+	if c.duration <= 0 {
+		return domain.SymbolCooldown{}, false, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cooldown := domain.SymbolCooldown{
+		Symbol:    symbol,
+		TradeID:   tradeID,
+		Reason:    reason,
+		StartedAt: at,
+		Until:     at.Add(c.duration),
+	}
+	if existing, ok := c.cooldowns[symbol]; ok && existing.Until.After(cooldown.Until) {
+		return existing, true, nil
+	}
+
+	now := time.Now()
+	for other, existing := range c.cooldowns {
+		if !existing.Until.After(now) {
+			delete(c.cooldowns, other)
+		}
+	}
+	c.cooldowns[symbol] = cooldown
+
+	if err := c.store.Save(c.cooldowns); err != nil {
+		return cooldown, true, fmt.Errorf("failed to save symbol cooldowns: %w", err)
+	}
+	return cooldown, true, nil
+}
+
+// Get возвращает действующую паузу символа.
+func (c *SymbolCooldowns) Get(symbol string) (domain.SymbolCooldown, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	cooldown, ok := c.cooldowns[symbol]
+	if !ok || !cooldown.Until.After(time.Now()) {
+		return domain.SymbolCooldown{}, false
+	}
+	return cooldown, true
+}
+
+// Check возвращает доменную ошибку SYMBOL_COOLDOWN, пока символ на паузе.
+func (c *SymbolCooldowns) Check(symbol string) error {
+	cooldown, ok := c.Get(symbol)
+	if !ok {
+		return nil
+	}
+	appErr := apperrors.DomainError(
+		fmt.Sprintf("symbol %s is cooling down after a stop-out until %s", symbol, cooldown.Until.UTC().Format(time.RFC3339)),
+		"SYMBOL_COOLDOWN",
+	)
+	appErr.Details = map[string]interface{}{"symbol": symbol, "until": cooldown.Until, "trade_id": cooldown.TradeID}
+	return appErr
+}
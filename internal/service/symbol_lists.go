@@ -0,0 +1,103 @@
+package service
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	apperrors "cryptorg/pkg/errors"
+)
+
+// SymbolLists - списки разрешенных и запрещенных символов. Элементы - шаблоны path.Match
+// (USDC*, *DAI*). Запрет важнее разрешения; пустой список разрешений разрешает все.
+// Начальные списки берутся из окружения, дальше меняются через API до перезапуска.
+type SymbolLists struct {
+	mu    sync.RWMutex
+	allow []string
+	deny  []string
+}
+
+func NewSymbolLists(allow, deny []string) (*SymbolLists, error) {
+	lists := &SymbolLists{}
+	if err := lists.Set(allow, deny); err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+// Set заменяет оба списка; некорректный шаблон отклоняет изменение целиком.
+func (l *SymbolLists) Set(allow, deny []string) error {
+	allow, err := normalizePatterns(allow)
+	if err != nil {
+		return err
+	}
+	deny, err = normalizePatterns(deny)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	l.allow, l.deny = allow, deny
+	l.mu.Unlock()
+	return nil
+}
+
+func (l *SymbolLists) Lists() (allow, deny []string) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return append([]string(nil), l.allow...), append([]string(nil), l.deny...)
+}
+
+// Check возвращает доменную ошибку SYMBOL_NOT_ALLOWED, если символ торговать нельзя.
+func (l *SymbolLists) Check(symbol string) error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	symbol = strings.ToUpper(symbol)
+	if pattern, ok := matchSymbol(l.deny, symbol); ok {
+		return symbolNotAllowed(symbol, fmt.Sprintf("symbol %s is denied by %s", symbol, pattern))
+	}
+	if len(l.allow) > 0 {
+		if _, ok := matchSymbol(l.allow, symbol); !ok {
+			return symbolNotAllowed(symbol, fmt.Sprintf("symbol %s is not in the allow list", symbol))
+		}
+	}
+	return nil
+}
+
+func (l *SymbolLists) Allowed(symbol string) bool {
+	return l.Check(symbol) == nil
+}
+
+func symbolNotAllowed(symbol, message string) error {
+	appErr := apperrors.DomainError(message, "SYMBOL_NOT_ALLOWED")
+	appErr.Details = map[string]interface{}{"symbol": symbol}
+	return appErr
+}
+
+func matchSymbol(patterns []string, symbol string) (string, bool) {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, symbol); ok {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+func normalizePatterns(patterns []string) ([]string, error) {
+	result := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		pattern = strings.ToUpper(strings.TrimSpace(pattern))
+		if pattern == "" {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid symbol pattern %q: %w", pattern, err)
+		}
+		result = append(result, pattern)
+	}
+	sort.Strings(result)
+	return result, nil
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"cryptorg/internal/bybit"
+	"cryptorg/internal/domain"
+	apperrors "cryptorg/pkg/errors"
+)
+
+// checkAccountActivity отказывает в открытии сделки, если по символу есть ордера,
+// выставленные не ботом, или баланс монеты, купленной вручную. Иначе TP бота
+// продал бы монеты, которые пользователь держит долгосрочно. Шорт продает монеты
+// со счета намеренно, для него проверяются только ордера.
+func (s *TradeService) checkAccountActivity(ctx context.Context, config domain.TradeConfig) error {
+	symbol := config.Symbol
+	openOrders, err := s.ordersFor(config).ListOpenOrders(ctx, symbol)
+	if err != nil {
+		return fmt.Errorf("failed to check open orders: %w", err)
+	}
+
+	s.mu.RLock()
+	manualOrders := make([]string, 0)
+	for _, order := range openOrders {
+		if _, ok := s.orderIndex[order.BybitID]; !ok {
+			manualOrders = append(manualOrders, order.BybitID)
+		}
+	}
+
+	botQty := 0.0
+	for _, trade := range s.trades {
+		if trade.Symbol == symbol && s.exchangeKey(trade.Config.Exchange) == s.exchangeKey(config.Exchange) && (trade.Status == domain.TradeStatusActive || trade.Status == domain.TradeStatusPaused) && !trade.Config.IsShort() {
+			botQty += positionQty(trade)
+		}
+	}
+	s.mu.RUnlock()
+
+	if len(manualOrders) > 0 {
+		appErr := apperrors.DomainError(
+			fmt.Sprintf("symbol %s has open orders not managed by the bot: %s", symbol, strings.Join(manualOrders, ", ")),
+			"MANUAL_ORDERS_PRESENT",
+		)
+		appErr.Details = map[string]interface{}{"symbol": symbol, "order_ids": manualOrders}
+		return appErr
+	}
+
+	if config.IsShort() {
+		return nil
+	}
+
+	coin := bybit.BaseAsset(symbol)
+	balance, _, err := s.ordersFor(config).FetchBalance(ctx, coin)
+	if err != nil {
+		return fmt.Errorf("failed to check balance: %w", err)
+	}
+
+	if manualQty := balance - botQty; manualQty >= domain.MinOrderSize {
+		appErr := apperrors.DomainError(
+			fmt.Sprintf("account holds %.8f %s not managed by the bot", manualQty, coin),
+			"MANUAL_BALANCE_PRESENT",
+		)
+		appErr.Details = map[string]interface{}{"symbol": symbol, "coin": coin, "quantity": manualQty}
+		return appErr
+	}
+
+	return nil
+}
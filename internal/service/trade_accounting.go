@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	"cryptorg/internal/domain"
+)
+
+// orderValue - исполненный объем ордера в котируемой валюте. Если биржа не вернула
+// cumExecValue, объем оценивается по исполненному количеству и цене, а для рыночной
+// покупки без данных об исполнении - по заявленной сумме.
+func orderValue(order *domain.Order) float64 {
+	if value, err := strconv.ParseFloat(order.ExecutedValue, 64); err == nil && value > 0 {
+		return value
+	}
+
+	executed, _ := strconv.ParseFloat(order.ExecutedQty, 64)
+	price, _ := strconv.ParseFloat(order.Price, 64)
+	if executed > 0 && price > 0 {
+		return executed * price
+	}
+
+	if order.Type == domain.OrderTypeMarket && order.Side == domain.OrderSideBuy {
+		quoteAmount, _ := strconv.ParseFloat(order.Quantity, 64)
+		return quoteAmount
+	}
+	return 0
+}
+
+// fillDelta - исполнение updated сверх уже учтенного в previous. Ордер с частичным
+// исполнением учитывается по мере исполнения, каждый раз только приращение; false - нового
+// исполнения нет.
+func fillDelta(previous, updated *domain.Order) (domain.Order, bool) {
+	previousQty, _ := strconv.ParseFloat(previous.ExecutedQty, 64)
+	executed, _ := strconv.ParseFloat(updated.ExecutedQty, 64)
+	if executed <= previousQty {
+		return domain.Order{}, false
+	}
+
+	var previousValue, previousFee float64
+	if previousQty > 0 {
+		previousValue = orderValue(previous)
+		previousFee, _ = strconv.ParseFloat(previous.Fee, 64)
+	}
+	fee, _ := strconv.ParseFloat(updated.Fee, 64)
+
+	delta := *updated
+	delta.ExecutedQty = fmt.Sprintf("%.8f", executed-previousQty)
+	delta.ExecutedValue = fmt.Sprintf("%.8f", max(orderValue(updated)-previousValue, 0))
+	delta.Fee = fmt.Sprintf("%.8f", max(fee-previousFee, 0))
+	return delta, true
+}
+
+// recordFill учитывает исполнение ордера в TotalInvested, CurrentPositionQty и FreedCapital.
+// На споте Bybit комиссия покупки удерживается в базовой монете, продажи - в котируемой.
+// У шорта TotalInvested - выручка от продаж за вычетом комиссии, CurrentPositionQty - проданные
+// и еще не откупленные монеты, FreedCapital - котируемая валюта, потраченная на откуп.
+func recordFill(trade *domain.Trade, order *domain.Order) {
+	invested, _ := strconv.ParseFloat(trade.TotalInvested, 64)
+	position, _ := strconv.ParseFloat(trade.CurrentPositionQty, 64)
+	freed, _ := strconv.ParseFloat(trade.FreedCapital, 64)
+
+	executed, _ := strconv.ParseFloat(order.ExecutedQty, 64)
+	fee, _ := strconv.ParseFloat(order.Fee, 64)
+	value := orderValue(order)
+
+	switch {
+	case trade.Config.IsShort() && order.Side == domain.OrderSideSell:
+		invested += value - fee
+		position += executed
+	case trade.Config.IsShort():
+		freed += value
+		position -= executed - fee
+	case order.Side == domain.OrderSideSell:
+		freed += value - fee
+		position -= executed
+	default:
+		invested += value
+		position += executed - fee
+	}
+	if position < 0 {
+		position = 0
+	}
+
+	trade.TotalInvested = fmt.Sprintf("%.8f", invested)
+	trade.CurrentPositionQty = fmt.Sprintf("%.8f", position)
+	trade.FreedCapital = fmt.Sprintf("%.8f", freed)
+}
+
+// positionPnL - результат сделки в котируемой валюте по учтенным исполнениям, остаток
+// позиции оценивается по price. С price = 0 остаток не учитывается.
+func positionPnL(trade *domain.Trade, price float64) float64 {
+	invested, _ := strconv.ParseFloat(trade.TotalInvested, 64)
+	freed, _ := strconv.ParseFloat(trade.FreedCapital, 64)
+	position, _ := strconv.ParseFloat(trade.CurrentPositionQty, 64)
+
+	if trade.Config.IsShort() {
+		return invested - freed - position*price
+	}
+	return freed + position*price - invested
+}
+
+// recordExitFill подтягивает с биржи итог исполнения TP или SL и учитывает его.
+func (s *TradeService) recordExitFill(ctx context.Context, trade *domain.Trade, order *domain.Order) {
+	updated, err := s.ordersFor(trade.Config).CachedOrderStatus(ctx, order.Symbol, order.BybitID)
+	if err != nil {
+		log.Printf("Failed to fetch exit order %s of trade %s: %v", order.BybitID, trade.ID, err)
+		return
+	}
+
+	*order = *updated
+	recordFill(trade, order)
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"cryptorg/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// ForceStatus переводит сделку в указанный статус без обращений к бирже.
+// Используется оператором, когда состояние бота разошлось с биржей.
+func (s *TradeService) ForceStatus(tradeID uuid.UUID, status domain.TradeStatus, reason string) (*domain.Trade, error) {
+	s.mu.Lock()
+	trade, exists := s.trades[tradeID]
+	if !exists {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("trade not found: %s", tradeID)
+	}
+
+	previous := trade.Status
+	trade.Status = status
+	trade.UpdatedAt = time.Now()
+	// Ручной статус снимает ожидание отмен: оставшиеся ордера оператор разбирает сам
+	if status != domain.TradeStatusClosing {
+		trade.ClosingStatus = ""
+		trade.PendingCancels = nil
+	}
+
+	// Ордера неактивной сделки не должны находиться по вебхукам
+	if status == domain.TradeStatusActive || status == domain.TradeStatusPaused {
+		s.indexOrders(trade)
+	} else {
+		s.unindexOrders(trade)
+	}
+	s.mu.Unlock()
+
+	message := fmt.Sprintf("%s -> %s: %s", previous, status, reason)
+	log.Printf("AUDIT: trade %s status forced %s", tradeID, message)
+	s.recordEvent(trade, domain.TradeEventStatusForced, nil, message)
+
+	return trade, nil
+}
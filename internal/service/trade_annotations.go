@@ -0,0 +1,88 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"cryptorg/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// AnnotateTrade добавляет к сделке пометку внешней системы и пишет ее в журнал,
+// чтобы она попала в хронологию событий сделки.
+func (s *TradeService) AnnotateTrade(tradeID uuid.UUID, annotation domain.TradeAnnotation) (*domain.Trade, error) {
+	if annotation.Timestamp.IsZero() {
+		annotation.Timestamp = time.Now()
+	}
+
+	s.mu.Lock()
+	trade, exists := s.trades[tradeID]
+	if !exists {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("trade not found: %s", tradeID)
+	}
+
+	trade.Annotations = append(trade.Annotations, annotation)
+	trade.UpdatedAt = time.Now()
+	s.mu.Unlock()
+
+	message := fmt.Sprintf("%s=%s", annotation.Key, annotation.Value)
+	if annotation.Source != "" {
+		message = annotation.Source + ": " + message
+	}
+	s.recordEvent(trade, domain.TradeEventAnnotated, nil, message)
+
+	return trade, nil
+}
+
+// EventsSince возвращает до limit событий журнала с Seq больше sinceSeq. Снимки состояния
+// включаются только по запросу: они занимают большую часть журнала.
+func (s *TradeService) EventsSince(sinceSeq uint64, limit int, withSnapshots bool) (*domain.EventPage, error) {
+	events, err := s.journal.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	// Журнал упорядочен по Seq
+	start := sort.Search(len(events), func(i int) bool { return events[i].Seq > sinceSeq })
+	end := start + limit
+	if end > len(events) {
+		end = len(events)
+	}
+
+	page := &domain.EventPage{
+		Events:  make([]domain.TradeEvent, 0, end-start),
+		NextSeq: sinceSeq,
+		HasMore: end < len(events),
+	}
+	for _, event := range events[start:end] {
+		if !withSnapshots {
+			event.Snapshot = nil
+		}
+		page.Events = append(page.Events, event)
+		page.NextSeq = event.Seq
+	}
+	if len(events) > 0 {
+		page.LastSeq = events[len(events)-1].Seq
+	}
+	return page, nil
+}
+
+// TradeEvents возвращает хронологию событий сделки из журнала без снимков состояния.
+func (s *TradeService) TradeEvents(tradeID uuid.UUID) ([]domain.TradeEvent, error) {
+	events, err := s.journal.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	timeline := make([]domain.TradeEvent, 0)
+	for _, event := range events {
+		if event.TradeID == tradeID {
+			event.Snapshot = nil
+			timeline = append(timeline, event)
+		}
+	}
+	return timeline, nil
+}
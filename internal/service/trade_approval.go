@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"cryptorg/internal/bybit"
+	"cryptorg/internal/domain"
+	"cryptorg/internal/i18n"
+	"cryptorg/internal/notify"
+	apperrors "cryptorg/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// approvalRequired возвращает запрос подтверждения, если капитал сделки выше порога
+// ее котируемой валюты. Без порога подтверждение не требуется.
+func (s *TradeService) approvalRequired(config domain.TradeConfig) *domain.TradeApproval {
+	quote := bybit.QuoteAsset(config.Symbol)
+	threshold, ok := s.riskManager.ApprovalThreshold(quote)
+	if !ok {
+		return nil
+	}
+
+	required := requiredCapital(config, BuildGrid(config, domain.DecimalOne))
+	if required <= threshold {
+		return nil
+	}
+
+	return &domain.TradeApproval{
+		Quote:           quote,
+		RequiredCapital: fmt.Sprintf("%.8f", required),
+		Threshold:       fmt.Sprintf("%.8f", threshold),
+		RequestedAt:     time.Now(),
+	}
+}
+
+// requestApproval переводит сделку в PENDING_APPROVAL и отправляет уведомление с кнопкой подтверждения.
+func (s *TradeService) requestApproval(ctx context.Context, trade *domain.Trade, approval *domain.TradeApproval) {
+	trade.Status = domain.TradeStatusPendingApproval
+	trade.Approval = approval
+
+	s.mu.Lock()
+	s.trades[trade.ID] = trade
+	s.mu.Unlock()
+
+	s.recordEvent(trade, domain.TradeEventApprovalAsked, nil,
+		fmt.Sprintf("required capital %s %s exceeds approval threshold %s", approval.RequiredCapital, approval.Quote, approval.Threshold))
+	s.metrics.IncCounter("trades_pending_approval_total", nil)
+
+	lang := i18n.Default()
+	notification := notify.Localized(notify.LevelWarning, "Trade approval required",
+		"Trade %s on %s needs %s %s, above the approval threshold %s",
+		trade.ID, trade.Symbol, approval.RequiredCapital, approval.Quote, approval.Threshold)
+	notification.Actions = []notify.Action{{Text: i18n.T(lang, "Approve"), Data: domain.ApprovalCallbackPrefix + trade.ID.String()}}
+	if err := s.notifier.Notify(ctx, notification); err != nil {
+		log.Printf("Failed to notify about approval of trade %s: %v", trade.ID, err)
+	}
+}
+
+// ApproveTrade подтверждает сделку в PENDING_APPROVAL и запускает ее так же, как новую:
+// ставит в ожидание условия старта или выставляет вход. При ошибке входа сделка
+// переводится в FAILED, как и при срабатывании условия старта.
+func (s *TradeService) ApproveTrade(ctx context.Context, tradeID uuid.UUID, approvedBy string) (*domain.Trade, error) {
+	unlock, err := s.locker.Lock(ctx, tradeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock trade: %w", err)
+	}
+	defer unlock()
+
+	s.mu.Lock()
+	trade, exists := s.trades[tradeID]
+	if !exists {
+		s.mu.Unlock()
+		return nil, apperrors.NotFoundError("trade", tradeID.String())
+	}
+	if trade.Status != domain.TradeStatusPendingApproval || trade.Approval == nil {
+		s.mu.Unlock()
+		return nil, apperrors.DomainError(fmt.Sprintf("trade %s is not pending approval", tradeID), "TRADE_NOT_PENDING_APPROVAL")
+	}
+	now := time.Now()
+	trade.Approval.ApprovedAt = &now
+	trade.Approval.ApprovedBy = approvedBy
+	trade.UpdatedAt = now
+	s.mu.Unlock()
+
+	s.recordEvent(trade, domain.TradeEventApproved, nil, approvedBy)
+
+	if !trade.Config.Force {
+		err = s.checkAccountActivity(ctx, trade.Config)
+	}
+	if err == nil {
+		err = s.checkFreeBalance(ctx, trade.Config)
+	}
+	if err == nil {
+		err = s.startTrade(ctx, trade)
+	}
+	if err == nil {
+		return trade, nil
+	}
+
+	// Вход без позиции openTrade уже перевел в FAILED
+	if trade.Status != domain.TradeStatusFailed {
+		s.mu.Lock()
+		trade.Status = domain.TradeStatusFailed
+		trade.UpdatedAt = time.Now()
+		s.mu.Unlock()
+
+		s.recordEvent(trade, domain.TradeEventFinalized, nil, string(domain.TradeStatusFailed))
+	}
+	return nil, fmt.Errorf("failed to start approved trade: %w", err)
+}
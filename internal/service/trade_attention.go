@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"cryptorg/internal/domain"
+	"cryptorg/internal/i18n"
+	"cryptorg/internal/notify"
+
+	"github.com/google/uuid"
+)
+
+// FlagAttention помечает сделку после паники в ее обработке: сверяет ордера сделки с биржей
+// без исправлений, сохраняет найденные расхождения в пометке и отправляет критическое
+// уведомление. Пометка снимается только вручную через AcknowledgeAttention.
+func (s *TradeService) FlagAttention(ctx context.Context, tradeID uuid.UUID, reason string) {
+	s.mu.RLock()
+	trade, exists := s.trades[tradeID]
+	s.mu.RUnlock()
+
+	if !exists {
+		return
+	}
+
+	attention := &domain.TradeAttention{
+		Reason:    reason,
+		FlaggedAt: time.Now(),
+		Issues:    make([]domain.ConsistencyIssue, 0),
+	}
+
+	issues, err := s.checkSingleTrade(ctx, trade)
+	if err != nil {
+		attention.Error = err.Error()
+	}
+	attention.Issues = append(attention.Issues, issues...)
+
+	s.mu.Lock()
+	trade.Attention = attention
+	trade.UpdatedAt = time.Now()
+	s.mu.Unlock()
+
+	s.recordEvent(trade, domain.TradeEventAttention, nil, reason)
+	s.metrics.IncCounter("trades_attention_total", nil)
+
+	lang := i18n.Default()
+	message := i18n.Sprintf(lang, "Trade %s on %s was interrupted: %s", trade.ID, trade.Symbol, reason)
+	if len(attention.Issues) > 0 {
+		kinds := make([]string, 0, len(attention.Issues))
+		for _, issue := range attention.Issues {
+			kinds = append(kinds, string(issue.Kind))
+		}
+		message += "\n" + i18n.Sprintf(lang, "Exchange check found: %s", strings.Join(kinds, ", "))
+	} else if attention.Error != "" {
+		message += "\n" + i18n.Sprintf(lang, "Exchange check failed: %s", attention.Error)
+	}
+	if err := s.notifier.Notify(ctx, notify.New(notify.LevelCritical, i18n.T(lang, "Trade requires attention"), message)); err != nil {
+	}
+}
+
+// checkSingleTrade сверяет с биржей ордера одной активной сделки.
+func (s *TradeService) checkSingleTrade(ctx context.Context, trade *domain.Trade) ([]domain.ConsistencyIssue, error) {
+	if trade.Status != domain.TradeStatusActive || trade.EntryOrder == nil {
+		return nil, nil
+	}
+
+	openOrders, err := s.ordersFor(trade.Config).ListOpenOrders(ctx, trade.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open orders: %w", err)
+	}
+	open := make(map[string]bool, len(openOrders))
+	for _, order := range openOrders {
+		open[order.BybitID] = true
+	}
+
+	return s.checkTradeConsistency(ctx, trade, open, false)
+}
+
+// AttentionTrades возвращает сделки с неснятой пометкой.
+func (s *TradeService) AttentionTrades() []*domain.Trade {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	trades := make([]*domain.Trade, 0)
+	for _, trade := range s.trades {
+		if trade.Attention != nil {
+			trades = append(trades, trade)
+		}
+	}
+	return trades
+}
+
+// AcknowledgeAttention снимает пометку после ручной проверки сделки.
+func (s *TradeService) AcknowledgeAttention(tradeID uuid.UUID, note string) (*domain.Trade, error) {
+	s.mu.Lock()
+	trade, exists := s.trades[tradeID]
+	if !exists {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("trade not found: %s", tradeID)
+	}
+	if trade.Attention == nil {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("trade %s has no attention flag", tradeID)
+	}
+	trade.Attention = nil
+	trade.UpdatedAt = time.Now()
+	s.mu.Unlock()
+
+	s.recordEvent(trade, domain.TradeEventAcknowledged, nil, note)
+	return trade, nil
+}
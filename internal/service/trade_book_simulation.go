@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"cryptorg/internal/bybit"
+	"cryptorg/internal/domain"
+	apperrors "cryptorg/pkg/errors"
+)
+
+// wantsBookSimulation сообщает, что перед открытием нужен прогон по стакану.
+func wantsBookSimulation(config domain.TradeConfig) bool {
+	return config.SimulateBook || config.MaxSlippagePercent > 0
+}
+
+// checkBookSimulation прогоняет вход по стакану и отказывает, если проскальзывание
+// больше MaxSlippagePercent или видимого стакана не хватает на вход. Без лимита
+// ошибка получения стакана не мешает открытию.
+func (s *TradeService) checkBookSimulation(ctx context.Context, trade *domain.Trade) error {
+	config := trade.Config
+	sim, err := s.simulateBook(ctx, config)
+	if err != nil {
+		if config.MaxSlippagePercent > 0 {
+			return fmt.Errorf("failed to simulate entry against order book: %w", err)
+		}
+		log.Printf("Book simulation for %s skipped: %v", config.Symbol, err)
+		return nil
+	}
+	trade.BookSimulation = sim
+
+	if config.MaxSlippagePercent <= 0 {
+		return nil
+	}
+	var appErr *apperrors.AppError
+	switch {
+	case !sim.Covered:
+		appErr = apperrors.DomainError(fmt.Sprintf("visible order book covers only %s of %s entry volume",
+			sim.FilledVolume, sim.EntryVolume), "INSUFFICIENT_BOOK_DEPTH")
+	case sim.SlippagePercent > config.MaxSlippagePercent:
+		appErr = apperrors.DomainError(fmt.Sprintf("expected entry slippage %.2f%% exceeds max %.2f%%",
+			sim.SlippagePercent, config.MaxSlippagePercent), "SLIPPAGE_TOO_HIGH")
+	default:
+		return nil
+	}
+	appErr.Details = map[string]interface{}{"simulation": sim}
+	return appErr
+}
+
+// simulateBook прогоняет рыночный вход по встречной стороне стакана и
+// располагает уровни DCA относительно видимой глубины своей стороны.
+func (s *TradeService) simulateBook(ctx context.Context, config domain.TradeConfig) (*domain.BookSimulation, error) {
+	entryVolume, err := strconv.ParseFloat(config.EntryVolume, 64)
+	if err != nil || entryVolume <= 0 {
+		return nil, fmt.Errorf("invalid entry volume: %s", config.EntryVolume)
+	}
+
+	book, err := s.ordersFor(config).FetchOrderBook(ctx, config.Symbol, bybit.OrderBookMaxDepth)
+	if err != nil {
+		return nil, err
+	}
+
+	// Лонг покупает по аскам, сетка DCA стоит среди бидов; шорт наоборот
+	taker, maker := book.Asks, book.Bids
+	if config.IsShort() {
+		taker, maker = book.Bids, book.Asks
+	}
+	if len(taker) == 0 {
+		return nil, fmt.Errorf("order book for %s has no liquidity on the entry side", config.Symbol)
+	}
+
+	best := taker[0].Price
+	filled, qty, consumed := 0.0, 0.0, 0
+	for _, level := range taker {
+		if filled >= entryVolume {
+			break
+		}
+		take := math.Min(level.Price*level.Size, entryVolume-filled)
+		filled += take
+		qty += take / level.Price
+		consumed++
+	}
+	if qty <= 0 {
+		return nil, fmt.Errorf("order book for %s has no liquidity on the entry side", config.Symbol)
+	}
+	avg := filled / qty
+	slippage := math.Abs(avg-best) / best * 100
+
+	sim := &domain.BookSimulation{
+		Side:             entrySide(config),
+		BestPrice:        fmt.Sprintf("%.8f", best),
+		ExpectedAvgPrice: fmt.Sprintf("%.8f", avg),
+		SlippagePercent:  math.Round(slippage*10000) / 10000,
+		EntryVolume:      fmt.Sprintf("%.8f", entryVolume),
+		FilledVolume:     fmt.Sprintf("%.8f", filled),
+		Covered:          filled >= entryVolume,
+		LevelsConsumed:   consumed,
+		BookDepth:        len(taker),
+		Grid:             make([]domain.GridDepth, 0, config.DCACount),
+		Warnings:         make([]string, 0),
+		SimulatedAt:      time.Now(),
+	}
+
+	if !sim.Covered {
+		sim.Warnings = append(sim.Warnings, fmt.Sprintf(
+			"visible book covers only %.2f of %.2f entry volume", filled, entryVolume))
+	}
+	if slippage >= domain.DefaultSlippageWarnPercent {
+		sim.Warnings = append(sim.Warnings, fmt.Sprintf(
+			"market entry is expected to slip %.2f%% from the best price", slippage))
+	}
+	if config.MakerOnly {
+		sim.Warnings = append(sim.Warnings, "maker only entry rests at the edge of the book, taker cost is shown for comparison")
+	}
+
+	var outside int
+	for _, level := range BuildGrid(config, domain.DecimalFromFloat(avg)) {
+		price, err := strconv.ParseFloat(level.Price, 64)
+		if err != nil {
+			continue
+		}
+		depth := gridDepth(level, price, maker, best, config.IsShort())
+		if depth.Crosses {
+			sim.Warnings = append(sim.Warnings, fmt.Sprintf(
+				"DCA level %d at %s is past the best price and would fill immediately", level.Index, level.Price))
+		}
+		if !depth.WithinBook {
+			outside++
+		}
+		sim.Grid = append(sim.Grid, depth)
+	}
+	if outside > 0 && len(maker) > 0 {
+		sim.Warnings = append(sim.Warnings, fmt.Sprintf(
+			"%d of %d DCA levels are beyond the visible %d levels of depth", outside, len(sim.Grid), len(maker)))
+	}
+
+	return sim, nil
+}
+
+// gridDepth считает видимый объем, который стоит перед уровнем DCA в очереди своей стороны.
+func gridDepth(level domain.GridLevel, price float64, side []bybit.BookLevel, best float64, short bool) domain.GridDepth {
+	var ahead float64
+	for _, book := range side {
+		if (!short && book.Price < price) || (short && book.Price > price) {
+			break
+		}
+		ahead += book.Price * book.Size
+	}
+
+	within := false
+	if len(side) > 0 {
+		last := side[len(side)-1].Price
+		within = (!short && price >= last) || (short && price <= last)
+	}
+
+	return domain.GridDepth{
+		Index:       level.Index,
+		Price:       level.Price,
+		VolumeAhead: fmt.Sprintf("%.8f", ahead),
+		WithinBook:  within,
+		Crosses:     (!short && price >= best) || (short && price <= best),
+	}
+}
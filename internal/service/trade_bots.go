@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+
+	"cryptorg/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// StartBotTrade открывает сделку от имени бота. Пометка ставится до регистрации сделки,
+// поэтому она сразу учитывается в открытых сделках бота.
+func (s *TradeService) StartBotTrade(ctx context.Context, botID uuid.UUID, config domain.TradeConfig) (*domain.Trade, error) {
+	return s.initializeTrade(ctx, config, &botID)
+}
+
+// OnTradeClosed подписывает listener на финализацию сделок. Подписка делается при
+// сборке приложения, до обработки исполнений.
+func (s *TradeService) OnTradeClosed(listener func(ctx context.Context, trade *domain.Trade)) {
+	s.closed = append(s.closed, listener)
+}
+
+// PendingBotTrades - сделки бота, которые еще не вошли в позицию (WAITING и PENDING_APPROVAL).
+func (s *TradeService) PendingBotTrades(botID uuid.UUID) []uuid.UUID {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var ids []uuid.UUID
+	for id, trade := range s.trades {
+		if trade.BotID != nil && *trade.BotID == botID &&
+			(trade.Status == domain.TradeStatusWaiting || trade.Status == domain.TradeStatusPendingApproval) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// BotTradesByStatus - сделки бота в указанном статусе.
+func (s *TradeService) BotTradesByStatus(botID uuid.UUID, status domain.TradeStatus) []uuid.UUID {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var ids []uuid.UUID
+	for id, trade := range s.trades {
+		if trade.BotID != nil && *trade.BotID == botID && trade.Status == status {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// OpenTradesByBot считает открытые (ACTIVE и WAITING) сделки каждого бота.
+func (s *TradeService) OpenTradesByBot() map[uuid.UUID]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[uuid.UUID]int)
+	for _, trade := range s.trades {
+		if trade.BotID != nil && trade.Status.IsOpen() {
+			result[*trade.BotID]++
+		}
+	}
+	return result
+}
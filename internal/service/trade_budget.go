@@ -0,0 +1,148 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"cryptorg/internal/bybit"
+	"cryptorg/internal/domain"
+	apperrors "cryptorg/pkg/errors"
+)
+
+// committedCapital - капитал, зарезервированный активными и ожидающими сделками, по котируемым валютам.
+func (s *TradeService) committedCapital() map[string]float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	committed := make(map[string]float64)
+	for _, trade := range s.trades {
+		if !trade.Status.IsOpen() {
+			continue
+		}
+		quote := bybit.QuoteAsset(trade.Symbol)
+		committed[quote] += requiredCapital(trade.Config, BuildGrid(trade.Config, domain.DecimalOne))
+	}
+	return committed
+}
+
+// checkQuoteBudget отказывает в сделке, если вместе с уже открытыми по той же котируемой
+// валюте она выходит за бюджет этой валюты. Без заданного бюджета проверка не выполняется.
+// При BudgetPolicy truncate или scale сетка вместо отказа ужимается до свободного остатка,
+// а описание изменений возвращается для ответа клиенту.
+func (s *TradeService) checkQuoteBudget(config *domain.TradeConfig) (*domain.BudgetAdjustment, error) {
+	quote := bybit.QuoteAsset(config.Symbol)
+	budget, ok := s.riskManager.QuoteBudget(quote)
+	if !ok {
+		return nil, nil
+	}
+
+	committed := s.committedCapital()[quote]
+	required := requiredCapital(*config, BuildGrid(*config, domain.DecimalOne))
+	if committed+required <= budget {
+		return nil, nil
+	}
+
+	if adjustment := fitGridToBudget(config, budget-committed); adjustment != nil {
+		return adjustment, nil
+	}
+
+	appErr := apperrors.DomainError(
+		fmt.Sprintf("%s budget %.2f exceeded: %.2f committed, %.2f required", quote, budget, committed, required),
+		"QUOTE_BUDGET_EXCEEDED",
+	)
+	appErr.Details = map[string]interface{}{"quote": quote, "budget": budget, "committed": committed, "required": required}
+	return nil, appErr
+}
+
+// fitGridToBudget ужимает сетку price_step под available по BudgetPolicy. truncate отбрасывает
+// самые глубокие уровни; scale сначала снижает мартингейл (глубокие уровни уменьшаются
+// сильнее), а если и без него не помещается - пропорционально уменьшает DCAVolume.
+// Вход не уменьшается; nil - если уложиться хотя бы с одним уровнем не удалось.
+func fitGridToBudget(config *domain.TradeConfig, available float64) *domain.BudgetAdjustment {
+	if config.Strategy == domain.StrategyTimeBased || config.DCACount < 1 {
+		return nil
+	}
+	if config.BudgetPolicy != domain.BudgetPolicyTruncate && config.BudgetPolicy != domain.BudgetPolicyScale {
+		return nil
+	}
+
+	fits := func(c domain.TradeConfig) bool {
+		return requiredCapital(c, BuildGrid(c, domain.DecimalOne)) <= available
+	}
+
+	adjusted := *config
+	switch config.BudgetPolicy {
+	case domain.BudgetPolicyTruncate:
+		for adjusted.DCACount > 1 && !fits(adjusted) {
+			adjusted.DCACount--
+		}
+
+	case domain.BudgetPolicyScale:
+		if adjusted.Martingale > 1 {
+			flat := adjusted
+			flat.Martingale = 1
+			if fits(flat) {
+				// Наибольший мартингейл с шагом 0.01, при котором сетка помещается
+				low, high := 1.0, adjusted.Martingale
+				for high-low > 0.005 {
+					flat.Martingale = (low + high) / 2
+					if fits(flat) {
+						low = flat.Martingale
+					} else {
+						high = flat.Martingale
+					}
+				}
+				adjusted.Martingale = math.Floor(low*100) / 100
+			} else {
+				adjusted.Martingale = 1
+			}
+		}
+
+		if !fits(adjusted) {
+			entryVolume, _ := strconv.ParseFloat(adjusted.EntryVolume, 64)
+			dcaVolume, _ := strconv.ParseFloat(adjusted.DCAVolume, 64)
+			gridCapital := requiredCapital(adjusted, BuildGrid(adjusted, domain.DecimalOne)) - entryVolume
+			if gridCapital <= 0 || available <= entryVolume {
+				return nil
+			}
+			ratio := (available - entryVolume) / gridCapital
+			adjusted.DCAVolume = strconv.FormatFloat(math.Floor(dcaVolume*ratio*1e8)/1e8, 'f', -1, 64)
+		}
+	}
+
+	if !fits(adjusted) {
+		return nil
+	}
+
+	adjustment := &domain.BudgetAdjustment{
+		Policy:              config.BudgetPolicy,
+		Available:           fmt.Sprintf("%.8f", available),
+		RequestedCapital:    fmt.Sprintf("%.8f", requiredCapital(*config, BuildGrid(*config, domain.DecimalOne))),
+		AdjustedCapital:     fmt.Sprintf("%.8f", requiredCapital(adjusted, BuildGrid(adjusted, domain.DecimalOne))),
+		RequestedDCACount:   config.DCACount,
+		DCACount:            adjusted.DCACount,
+		RequestedMartingale: config.Martingale,
+		Martingale:          adjusted.Martingale,
+		RequestedDCAVolume:  config.DCAVolume,
+		DCAVolume:           adjusted.DCAVolume,
+	}
+	*config = adjusted
+	return adjustment
+}
+
+// BudgetUsage возвращает бюджет и занятый капитал по каждой котируемой валюте.
+func (s *TradeService) BudgetUsage() map[string]map[string]float64 {
+	committed := s.committedCapital()
+
+	usage := make(map[string]map[string]float64)
+	for quote, budget := range s.riskManager.QuoteBudgets() {
+		usage[quote] = map[string]float64{"budget": budget, "committed": committed[quote]}
+	}
+	for quote, amount := range committed {
+		if _, ok := usage[quote]; !ok {
+			usage[quote] = map[string]float64{"committed": amount}
+		}
+	}
+	return usage
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"cryptorg/internal/bybit"
+	"cryptorg/internal/domain"
+)
+
+const defaultScreenerLimit = 10
+
+// BulkCreate создает по сделке на каждый символ из списка или скринера. В режиме DryRun
+// ордера не выставляются, а в ответе возвращается требуемый капитал по каждому символу.
+func (s *TradeService) BulkCreate(ctx context.Context, req domain.BulkTradeRequest) (*domain.BulkTradeResult, error) {
+	symbols := req.Symbols
+	if req.Screener != nil {
+		screened, err := s.screenSymbols(ctx, req.Preset, *req.Screener)
+		if err != nil {
+			return nil, err
+		}
+		symbols = append(symbols, screened...)
+	}
+	symbols = uniqueSymbols(symbols)
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("no symbols selected")
+	}
+
+	preset := req.Preset
+	if req.TotalBudget != "" {
+		budget, err := strconv.ParseFloat(req.TotalBudget, 64)
+		if err != nil || budget <= 0 {
+			return nil, fmt.Errorf("invalid total budget: %s", req.TotalBudget)
+		}
+		presetCapital := requiredCapital(preset, BuildGrid(preset, domain.DecimalOne))
+		if presetCapital <= 0 {
+			return nil, fmt.Errorf("preset requires no capital")
+		}
+		preset = scaleConfigVolumes(preset, domain.DecimalFromFloat(budget/float64(len(symbols))/presetCapital))
+	}
+
+	result := &domain.BulkTradeResult{
+		DryRun: req.DryRun,
+		Items:  make([]domain.BulkTradeItem, 0, len(symbols)),
+	}
+
+	total := 0.0
+	for _, symbol := range symbols {
+		config := preset
+		config.Symbol = symbol
+		item := domain.BulkTradeItem{Symbol: symbol}
+
+		if err := s.checkSymbol(config); err != nil {
+			item.Config = config
+			item.Error = err.Error()
+			result.Items = append(result.Items, item)
+			continue
+		}
+
+		if err := s.enforceMinNotional(ctx, &config); err != nil {
+			item.Config = config
+			item.Error = err.Error()
+			result.Items = append(result.Items, item)
+			continue
+		}
+
+		capital := requiredCapital(config, BuildGrid(config, domain.DecimalOne))
+		item.Config = config
+		item.RequiredCapital = fmt.Sprintf("%.8f", capital)
+		total += capital
+
+		if !req.DryRun {
+			trade, err := s.InitializeTrade(ctx, config)
+			if err != nil {
+				item.Error = err.Error()
+			}
+			item.Trade = trade
+		}
+
+		result.Items = append(result.Items, item)
+	}
+
+	result.TotalRequiredCapital = fmt.Sprintf("%.8f", total)
+	return result, nil
+}
+
+// screenSymbols возвращает символы котируемой валюты с наибольшим оборотом за 24 часа
+// на бирже шаблона.
+func (s *TradeService) screenSymbols(ctx context.Context, preset domain.TradeConfig, screener domain.BulkScreener) ([]string, error) {
+	quote := screener.QuoteAsset
+	if quote == "" {
+		quote = "USDT"
+	}
+	limit := screener.Limit
+	if limit <= 0 {
+		limit = defaultScreenerLimit
+	}
+
+	tickers, err := s.ordersFor(preset).ListTickers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	type candidate struct {
+		symbol   string
+		turnover float64
+	}
+	candidates := make([]candidate, 0)
+	for _, ticker := range tickers {
+		if bybit.QuoteAsset(ticker.Symbol) != quote || !s.riskManager.SymbolLists().Allowed(ticker.Symbol) {
+			continue
+		}
+		turnover, _ := strconv.ParseFloat(ticker.Turnover, 64)
+		if turnover >= screener.MinTurnover {
+			candidates = append(candidates, candidate{symbol: ticker.Symbol, turnover: turnover})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].turnover > candidates[j].turnover })
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	symbols := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		symbols = append(symbols, c.symbol)
+	}
+	return symbols, nil
+}
+
+func uniqueSymbols(symbols []string) []string {
+	seen := make(map[string]bool, len(symbols))
+	result := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		if symbol != "" && !seen[symbol] {
+			seen[symbol] = true
+			result = append(result, symbol)
+		}
+	}
+	return result
+}
+
+// scaleConfigVolumes умножает объемы входа и уровней DCA на ratio.
+func scaleConfigVolumes(config domain.TradeConfig, ratio domain.Decimal) domain.TradeConfig {
+	entryVolume, _ := domain.ParseDecimal(config.EntryVolume)
+	dcaVolume, _ := domain.ParseDecimal(config.DCAVolume)
+
+	config.EntryVolume = domain.FormatDecimal(entryVolume.Mul(ratio))
+	config.DCAVolume = domain.FormatDecimal(dcaVolume.Mul(ratio))
+	return config
+}
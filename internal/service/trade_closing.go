@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"cryptorg/internal/domain"
+	"cryptorg/internal/notify"
+)
+
+const (
+	cancelRetryBaseDelay = 10 * time.Second
+	cancelRetryMaxDelay  = 5 * time.Minute
+)
+
+// cancelRemainingOrders снимает TP, SL и открытые DCA ордера закрытой сделки. Если часть
+// отмен не прошла, сделка переводится в CLOSING до подтверждения, а status сохраняется
+// в ClosingStatus. Возвращает true, если сделка осталась в CLOSING.
+func (s *TradeService) cancelRemainingOrders(ctx context.Context, trade *domain.Trade, status domain.TradeStatus, filledOrderID string) bool {
+	targets := make([]domain.PendingCancel, 0)
+	if trade.StopLossOrder != nil && trade.StopLossOrder.BybitID != filledOrderID {
+		targets = append(targets, domain.PendingCancel{OrderID: trade.StopLossOrder.BybitID, Role: "stop_loss", Stop: true})
+	}
+	if trade.TakeProfitOrder != nil && trade.TakeProfitOrder.BybitID != filledOrderID {
+		targets = append(targets, domain.PendingCancel{OrderID: trade.TakeProfitOrder.BybitID, Role: "take_profit"})
+	}
+	for _, order := range s.dcaOrders(trade) {
+		if order.Status.IsOpen() {
+			targets = append(targets, domain.PendingCancel{OrderID: order.BybitID, Role: "dca"})
+		}
+	}
+
+	now := time.Now()
+	pending := make([]domain.PendingCancel, 0)
+	for _, target := range targets {
+		if err := s.terminatePending(ctx, trade, target); err != nil {
+			s.recordCancelFailure(trade, findTradeOrder(trade, target.OrderID), err)
+			target.Attempts = 1
+			target.LastError = err.Error()
+			target.NextAttemptAt = now.Add(cancelRetryBaseDelay)
+			pending = append(pending, target)
+		}
+	}
+	if len(pending) == 0 {
+		return false
+	}
+
+	s.mu.Lock()
+	trade.ClosingStatus = status
+	trade.Status = domain.TradeStatusClosing
+	trade.PendingCancels = pending
+	s.mu.Unlock()
+
+	s.recordEvent(trade, domain.TradeEventClosing, nil, fmt.Sprintf("%d orders left to cancel", len(pending)))
+	s.metrics.IncCounter("trades_closing_total", nil)
+	notification := notify.Localized(notify.LevelWarning, "Trade closing stalled",
+		"Trade %s on %s closed as %s, but %d orders are still on the exchange; retrying cancels", trade.ID, trade.Symbol, status, len(pending))
+	if err := s.notifier.Notify(ctx, notification); err != nil {
+		log.Printf("Failed to notify about closing trade %s: %v", trade.ID, err)
+	}
+	return true
+}
+
+func (s *TradeService) terminatePending(ctx context.Context, trade *domain.Trade, pending domain.PendingCancel) error {
+	if pending.Stop {
+		return s.ordersFor(trade.Config).TerminateStopOrder(ctx, trade.Symbol, pending.OrderID)
+	}
+	return s.ordersFor(trade.Config).TerminateOrder(ctx, trade.Symbol, pending.OrderID)
+}
+
+// RetryPendingCancels повторяет неудавшиеся отмены сделок в CLOSING с экспоненциальной
+// задержкой. Ордер считается снятым, когда его нет среди открытых ордеров символа;
+// когда сняты все, сделка получает итоговый статус.
+func (s *TradeService) RetryPendingCancels(ctx context.Context) error {
+	s.mu.RLock()
+	closing := make([]*domain.Trade, 0)
+	for _, trade := range s.trades {
+		if trade.Status == domain.TradeStatusClosing {
+			closing = append(closing, trade)
+		}
+	}
+	s.mu.RUnlock()
+
+	var errs []error
+	for _, trade := range closing {
+		if err := s.retryTradeCancels(ctx, trade, time.Now()); err != nil {
+			errs = append(errs, fmt.Errorf("trade %s: %w", trade.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *TradeService) retryTradeCancels(ctx context.Context, trade *domain.Trade, now time.Time) error {
+	unlock, err := s.locker.Lock(ctx, trade.ID)
+	if err != nil {
+		return fmt.Errorf("failed to lock trade: %w", err)
+	}
+	defer unlock()
+
+	if trade.Status != domain.TradeStatusClosing {
+		return nil
+	}
+
+	open, err := s.ordersFor(trade.Config).ListOpenOrders(ctx, trade.Symbol)
+	if err != nil {
+		return err
+	}
+	live := make(map[string]bool, len(open))
+	for _, order := range open {
+		live[order.BybitID] = true
+	}
+
+	var errs []error
+	remaining := make([]domain.PendingCancel, 0, len(trade.PendingCancels))
+	for _, pending := range trade.PendingCancels {
+		stillLive, err := s.pendingLive(ctx, trade, pending, live)
+		if err != nil {
+			errs = append(errs, err)
+			remaining = append(remaining, pending)
+			continue
+		}
+		if !stillLive {
+			continue
+		}
+		if pending.NextAttemptAt.After(now) {
+			remaining = append(remaining, pending)
+			continue
+		}
+
+		pending.Attempts++
+		if err := s.terminatePending(ctx, trade, pending); err != nil {
+			pending.LastError = err.Error()
+			pending.NextAttemptAt = now.Add(cancelRetryDelay(pending.Attempts))
+			errs = append(errs, fmt.Errorf("%s order %s: %w", pending.Role, pending.OrderID, err))
+		} else {
+			// Успешный ответ еще не подтверждение: следующий проход сверит открытые ордера
+			pending.LastError = ""
+			pending.NextAttemptAt = now
+		}
+		remaining = append(remaining, pending)
+	}
+
+	if len(remaining) > 0 {
+		s.mu.Lock()
+		trade.PendingCancels = remaining
+		s.mu.Unlock()
+		return errors.Join(errs...)
+	}
+
+	s.mu.Lock()
+	trade.Status = trade.ClosingStatus
+	trade.ClosingStatus = ""
+	trade.PendingCancels = nil
+	trade.UpdatedAt = now
+	s.mu.Unlock()
+
+	s.recordEvent(trade, domain.TradeEventCancelsDone, nil, string(trade.Status))
+	if trade.Config.AutoRestart {
+		s.completeCycle(ctx, trade)
+	}
+	return nil
+}
+
+// pendingLive проверяет, стоит ли ордер еще на бирже. Условные ордера не попадают в список
+// открытых, поэтому их статус запрашивается отдельно.
+func (s *TradeService) pendingLive(ctx context.Context, trade *domain.Trade, pending domain.PendingCancel, live map[string]bool) (bool, error) {
+	if !pending.Stop {
+		return live[pending.OrderID], nil
+	}
+
+	order, err := s.ordersFor(trade.Config).FetchOrderStatus(ctx, trade.Symbol, pending.OrderID)
+	if err != nil {
+		return false, fmt.Errorf("%s order %s: %w", pending.Role, pending.OrderID, err)
+	}
+	return !order.Status.IsTerminal(), nil
+}
+
+func cancelRetryDelay(attempts int) time.Duration {
+	delay := cancelRetryBaseDelay
+	for i := 1; i < attempts && delay < cancelRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > cancelRetryMaxDelay {
+		delay = cancelRetryMaxDelay
+	}
+	return delay
+}
@@ -0,0 +1,309 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cryptorg/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// staleTakeProfitTolerance - относительное расхождение средней цены, при котором TP
+// считается выставленным для другой позиции; меньшие отличия дает округление.
+const staleTakeProfitTolerance = 1e-6
+
+// orphanGracePeriod - только что выставленный ордер может быть еще не привязан к сделке.
+const orphanGracePeriod = time.Minute
+
+// CheckConsistency сверяет активные сделки с открытыми ордерами биржи и индекс ордеров
+// со сделками. При repair исправляются простые случаи: пропущенное исполнение
+// обрабатывается как обычное, пропавший или устаревший TP выставляется заново, висячая
+// запись индекса удаляется, ордер бота без сделки отменяется, прерванное открытие сделки
+// доводится до конца. Ошибки запросов к бирже возвращаются вместе с частичным отчетом.
+func (s *TradeService) CheckConsistency(ctx context.Context, repair bool) (*domain.ConsistencyReport, error) {
+	report := &domain.ConsistencyReport{
+		CheckedAt:  time.Now(),
+		AutoRepair: repair,
+		Issues:     make([]domain.ConsistencyIssue, 0),
+	}
+
+	s.mu.RLock()
+	dangling := make(map[string]uuid.UUID)
+	for orderID, tradeID := range s.orderIndex {
+		if _, ok := s.trades[tradeID]; !ok {
+			dangling[orderID] = tradeID
+		}
+	}
+	bySymbol := make(map[exchangeSymbol][]*domain.Trade)
+	for _, trade := range s.trades {
+		if trade.Status == domain.TradeStatusActive && trade.EntryOrder != nil {
+			key := exchangeSymbol{exchange: s.exchangeKey(trade.Config.Exchange), symbol: trade.Symbol}
+			bySymbol[key] = append(bySymbol[key], trade)
+		}
+	}
+	s.mu.RUnlock()
+
+	for orderID, tradeID := range dangling {
+		issue := domain.ConsistencyIssue{
+			Kind:    domain.IssueDanglingIndex,
+			TradeID: tradeID.String(),
+			OrderID: orderID,
+			Detail:  "order index points at a missing trade",
+		}
+		if repair {
+			s.mu.Lock()
+			if _, ok := s.trades[tradeID]; !ok {
+				delete(s.orderIndex, orderID)
+			}
+			s.mu.Unlock()
+			issue.Repaired = true
+		}
+		report.Issues = append(report.Issues, issue)
+	}
+
+	var errs []error
+	for name, orders := range s.exchangeGroups() {
+		orphans, err := s.checkOrphanOrders(ctx, orders, repair)
+		if err != nil {
+			errs = append(errs, exchangeError(name, err))
+		}
+		report.Issues = append(report.Issues, orphans...)
+	}
+
+	for key, trades := range bySymbol {
+		openOrders, err := s.exchangeOrders(key.exchange).ListOpenOrders(ctx, key.symbol)
+		if err != nil {
+			errs = append(errs, exchangeError(key.exchange, fmt.Errorf("%s: %w", key.symbol, err)))
+			continue
+		}
+		open := make(map[string]bool, len(openOrders))
+		for _, order := range openOrders {
+			open[order.BybitID] = true
+		}
+
+		for _, trade := range trades {
+			report.TradesChecked++
+			issues, err := s.checkTradeConsistency(ctx, trade, open, repair)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("trade %s: %w", trade.ID, err))
+			}
+			report.Issues = append(report.Issues, issues...)
+		}
+	}
+
+	return report, errors.Join(errs...)
+}
+
+// checkOrphanOrders ищет открытые на бирже ордера бота (по префиксу orderLinkId), которых
+// нет в индексе: их сделка закрыта или не сохранилась до сбоя. Ручные ордера не трогаются.
+func (s *TradeService) checkOrphanOrders(ctx context.Context, orders *OrderService, repair bool) ([]domain.ConsistencyIssue, error) {
+	openOrders, err := orders.ListOpenOrders(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open orders: %w", err)
+	}
+
+	s.mu.RLock()
+	orphans := make([]*domain.Order, 0)
+	for _, order := range openOrders {
+		if _, ok := s.orderIndex[order.BybitID]; ok || !domain.IsBotOrderLink(order.LinkID) {
+			continue
+		}
+		if time.Since(order.CreatedAt) < orphanGracePeriod {
+			continue
+		}
+		orphans = append(orphans, order)
+	}
+	s.mu.RUnlock()
+
+	issues := make([]domain.ConsistencyIssue, 0, len(orphans))
+	for _, order := range orphans {
+		issue := domain.ConsistencyIssue{
+			Kind:    domain.IssueOrphanOrder,
+			OrderID: order.BybitID,
+			Symbol:  order.Symbol,
+			Detail:  fmt.Sprintf("bot order %s is open on the exchange but belongs to no active trade", order.LinkID),
+		}
+		if repair {
+			if err := orders.TerminateOrder(ctx, order.Symbol, order.BybitID); err != nil {
+				issue.Error = err.Error()
+			} else {
+				issue.Repaired = true
+			}
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// checkTradeConsistency проверяет ордера одной сделки, которых нет среди открытых на бирже.
+// Пропущенные исполнения обрабатываются после снятия блокировки сделки:
+// ProcessOrderExecution берет ее сам.
+func (s *TradeService) checkTradeConsistency(ctx context.Context, trade *domain.Trade, open map[string]bool, repair bool) ([]domain.ConsistencyIssue, error) {
+	unlock, err := s.locker.Lock(ctx, trade.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock trade: %w", err)
+	}
+
+	if trade.Status != domain.TradeStatusActive {
+		unlock()
+		return nil, nil
+	}
+
+	if trade.Opening {
+		// Остальные проверки имеют смысл только для открытой до конца сделки
+		issue := domain.ConsistencyIssue{
+			Kind:    domain.IssueIncompleteOpen,
+			TradeID: trade.ID.String(),
+			Symbol:  trade.Symbol,
+			Detail:  "trade entry is filled but its take profit and grid were not placed",
+		}
+		if repair {
+			if err := s.completeOpen(ctx, trade); err != nil {
+				issue.Error = err.Error()
+			} else {
+				issue.Repaired = true
+			}
+		}
+		unlock()
+		return []domain.ConsistencyIssue{issue}, nil
+	}
+
+	type roleOrder struct {
+		role  string
+		order *domain.Order
+	}
+	local := make([]roleOrder, 0, len(trade.DCAOrders)+2)
+	if trade.TakeProfitOrder != nil && isOpenOrder(*trade.TakeProfitOrder) {
+		local = append(local, roleOrder{"take_profit", trade.TakeProfitOrder})
+	}
+	if trade.StopLossOrder != nil && isOpenOrder(*trade.StopLossOrder) {
+		local = append(local, roleOrder{"stop_loss", trade.StopLossOrder})
+	}
+	for i := range trade.DCAOrders {
+		if isOpenOrder(trade.DCAOrders[i]) {
+			local = append(local, roleOrder{"dca", &trade.DCAOrders[i]})
+		}
+	}
+
+	var errs []error
+	issues := make([]domain.ConsistencyIssue, 0)
+	missedFills := make([]*domain.Order, 0)
+	tpLive := false
+	for _, item := range local {
+		if open[item.order.BybitID] {
+			tpLive = tpLive || item.role == "take_profit"
+			continue
+		}
+
+		// Исполнение пропущено, пока бот был недоступен: время и цены берутся из исполнений
+		actual, err := s.ordersFor(trade.Config).BackfillOrder(ctx, trade.Symbol, item.order.BybitID)
+		if err != nil {
+			// Статус неизвестен - TP не считается пропавшим
+			tpLive = tpLive || item.role == "take_profit"
+			errs = append(errs, err)
+			continue
+		}
+
+		issue := domain.ConsistencyIssue{TradeID: trade.ID.String(), OrderID: item.order.BybitID, Symbol: trade.Symbol}
+		switch actual.Status.Normalize() {
+		case domain.OrderStatusFilled, domain.OrderStatusPartiallyCanceled:
+			// Частичное исполнение с отменой остатка обрабатывается тем же путем, что и полное
+			tpLive = tpLive || item.role == "take_profit"
+			issue.Kind = domain.IssueMissedFill
+			issue.Detail = fmt.Sprintf("%s order is %s locally but %s on the exchange", item.role, item.order.Status, actual.Status)
+			missedFills = append(missedFills, actual)
+		case domain.OrderStatusNew, domain.OrderStatusPartially, domain.OrderStatusUntriggered, domain.OrderStatusTriggered:
+			// Ордер выставлен или исполнился частично между запросами
+			tpLive = tpLive || item.role == "take_profit"
+			continue
+		case domain.OrderStatusCanceled, domain.OrderStatusRejected, domain.OrderStatusDeactivated:
+			issue.Kind = domain.IssueOrderGone
+			issue.Detail = fmt.Sprintf("%s order is %s locally but %s on the exchange", item.role, item.order.Status, actual.Status)
+		default:
+			// Состояние ордера неизвестно - TP не считается пропавшим, ордер не переставляется
+			tpLive = tpLive || item.role == "take_profit"
+			issue.Kind = domain.IssueUnknownStatus
+			issue.Detail = fmt.Sprintf("%s order is %s locally, exchange status is not recognized", item.role, item.order.Status)
+		}
+		issues = append(issues, issue)
+	}
+
+	// Пропущенное исполнение DCA переставит TP само при обработке
+	if tpLive && len(missedFills) == 0 && trade.TPDeferredAt == nil && s.takeProfitStale(trade) {
+		issue := domain.ConsistencyIssue{
+			Kind:    domain.IssueStaleTakeProfit,
+			TradeID: trade.ID.String(),
+			OrderID: trade.TakeProfitOrder.BybitID,
+			Symbol:  trade.Symbol,
+			Detail:  fmt.Sprintf("take profit was placed for average price %s, position has changed since", trade.AveragePrice),
+		}
+		if repair {
+			if err := s.updateTakeProfitOrder(ctx, trade); err != nil {
+				issue.Error = err.Error()
+			} else {
+				issue.Repaired = true
+				trade.UpdatedAt = time.Now()
+			}
+		}
+		issues = append(issues, issue)
+	}
+
+	if !tpLive && trade.TPDeferredAt == nil {
+		issue := domain.ConsistencyIssue{
+			Kind:    domain.IssueMissingTakeProfit,
+			TradeID: trade.ID.String(),
+			Symbol:  trade.Symbol,
+			Detail:  "active trade has no open take profit on the exchange",
+		}
+		if repair {
+			if err := s.updateTakeProfitOrder(ctx, trade); err != nil {
+				issue.Error = err.Error()
+			} else {
+				issue.Repaired = true
+				trade.UpdatedAt = time.Now()
+				s.invalidateSnapshot()
+			}
+		}
+		issues = append(issues, issue)
+	}
+	unlock()
+
+	if repair {
+		for _, order := range missedFills {
+			for i := range issues {
+				if issues[i].Kind != domain.IssueMissedFill || issues[i].OrderID != order.BybitID {
+					continue
+				}
+				// Обработка исполнения читает статус из кэша, поэтому сначала кладем туда ответ биржи
+				s.ordersFor(trade.Config).UpdateCachedOrder(*order)
+				if err := s.ProcessOrderExecution(ctx, trade.ID, order.BybitID, ""); err != nil {
+					issues[i].Error = err.Error()
+				} else {
+					issues[i].Repaired = true
+				}
+			}
+		}
+	}
+
+	return issues, errors.Join(errs...)
+}
+
+// takeProfitStale - позиция изменилась после выставления TP: исполнение DCA учтено, а TP
+// не переставлен из-за сбоя между ними. Средняя цена сделки меняется только вместе с TP.
+func (s *TradeService) takeProfitStale(trade *domain.Trade) bool {
+	if trade.TakeProfitOrder == nil {
+		return false
+	}
+	current, err := domain.ParseDecimal(trade.AveragePrice)
+	if err != nil || !current.IsPositive() {
+		return false
+	}
+	average, _, err := s.calculateNewAveragePrice(trade)
+	if err != nil {
+		return false
+	}
+	return average.Sub(current).Abs().Div(current).InexactFloat64() > staleTakeProfitTolerance
+}
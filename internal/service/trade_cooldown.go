@@ -0,0 +1,25 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"cryptorg/internal/domain"
+)
+
+// startCooldown ставит символ сделки, закрытой по стопу, на паузу от времени выхода.
+func (s *TradeService) startCooldown(trade *domain.Trade, closedAt time.Time) {
+	reason := fmt.Sprintf("trade %s stopped out", trade.ID)
+	cooldown, started, err := s.riskManager.Cooldowns().Start(trade.Symbol, trade.ID, reason, closedAt)
+	if err != nil {
+		log.Printf("Failed to persist cooldown of %s: %v", trade.Symbol, err)
+	}
+	if !started {
+		return
+	}
+
+	log.Printf("AUDIT: %s cooling down until %s after %s", trade.Symbol, cooldown.Until.Format(time.RFC3339), reason)
+	s.metrics.IncCounter("symbol_cooldowns_total", nil)
+	s.recordEvent(trade, domain.TradeEventCooldown, nil, fmt.Sprintf("new trades on %s paused until %s", trade.Symbol, cooldown.Until.UTC().Format(time.RFC3339)))
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cryptorg/internal/domain"
+	"cryptorg/internal/notify"
+
+	"github.com/google/uuid"
+)
+
+// completeCycle решает судьбу автоперезапускаемой сделки после финализации: открывает
+// следующий цикл или выводит сделку из работы по MaxCycles / StopAfterLoss с уведомлением.
+// Ручное закрытие и ошибки не перезапускают сделку.
+func (s *TradeService) completeCycle(ctx context.Context, trade *domain.Trade) {
+	config := trade.Config
+
+	var reason string
+	switch {
+	case trade.Status == domain.TradeStatusStopped && config.StopAfterLoss:
+		reason = fmt.Sprintf("cycle %d stopped out", trade.Cycle)
+	case trade.Status != domain.TradeStatusCompleted && trade.Status != domain.TradeStatusStopped:
+		return
+	case config.MaxCycles > 0 && trade.Cycle >= config.MaxCycles:
+		reason = fmt.Sprintf("reached max cycles %d", config.MaxCycles)
+	}
+
+	if reason != "" {
+		s.recordEvent(trade, domain.TradeEventRetired, nil, reason)
+		notification := notify.Localized(notify.LevelInfo, "Trade retired",
+			"Trade %s on %s retired: %s", trade.ID, trade.Symbol, reason)
+		if err := s.notifier.Notify(ctx, notification); err != nil {
+		}
+		return
+	}
+
+	next, err := s.restartTrade(ctx, trade)
+	if err != nil {
+		notification := notify.Localized(notify.LevelWarning, "Trade restart failed",
+			"Trade %s on %s failed to start cycle %d: %v", trade.ID, trade.Symbol, trade.Cycle+1, err)
+		if err := s.notifier.Notify(ctx, notification); err != nil {
+		}
+		return
+	}
+
+	s.recordEvent(trade, domain.TradeEventRestarted, nil, fmt.Sprintf("restarted as %s", next.ID))
+}
+
+func (s *TradeService) restartTrade(ctx context.Context, previous *domain.Trade) (*domain.Trade, error) {
+	previousID := previous.ID
+	trade := &domain.Trade{
+		ID:              uuid.New(),
+		Symbol:          previous.Symbol,
+		Config:          previous.Config,
+		DCAOrders:       make([]domain.Order, 0),
+		Cycle:           previous.Cycle + 1,
+		PreviousTradeID: &previousID,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+
+	// Цикл после стопа ждет конца паузы символа
+	_, cooling := s.riskManager.Cooldowns().Get(trade.Symbol)
+	if cooling || trade.Config.StartPrice != "" || s.outsidePriceBounds(ctx, trade.Config) {
+		s.queueTrade(trade)
+		return trade, nil
+	}
+
+	unlock, err := s.locker.Lock(ctx, trade.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock trade: %w", err)
+	}
+	defer unlock()
+
+	if err := s.openTrade(ctx, trade); err != nil {
+		return nil, err
+	}
+	return trade, nil
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"slices"
+
+	"cryptorg/internal/domain"
+)
+
+// Срез DCAOrders сделки не меняется на месте: запись под s.mu подменяет его копией.
+// Поэтому срез, прочитанный через dcaOrders, можно обходить без блокировки, пока
+// другая горутина доставляет или переставляет ордера.
+
+// dcaOrders - текущий срез DCA ордеров сделки. Вызывающий не должен держать s.mu.
+func (s *TradeService) dcaOrders(trade *domain.Trade) []domain.Order {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return trade.DCAOrders
+}
+
+// appendDCAOrder добавляет ордер в конец сетки. Clip заставляет append выделить новый массив.
+func (s *TradeService) appendDCAOrder(trade *domain.Trade, order domain.Order) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	trade.DCAOrders = append(slices.Clip(trade.DCAOrders), order)
+}
+
+// setDCAOrder заменяет i-й ордер сетки.
+func (s *TradeService) setDCAOrder(trade *domain.Trade, i int, order domain.Order) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	orders := slices.Clone(trade.DCAOrders)
+	orders[i] = order
+	trade.DCAOrders = orders
+}
+
+// replaceDCAOrders подменяет сетку целиком; orders после вызова не изменяются.
+func (s *TradeService) replaceDCAOrders(trade *domain.Trade, orders []domain.Order) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	trade.DCAOrders = orders
+}
+
+// mutableTradeOrder - как findTradeOrder, но DCA ордер возвращается из свежей копии сетки,
+// и его можно менять. Вызывающий держит s.mu на запись.
+func mutableTradeOrder(trade *domain.Trade, orderID string) *domain.Order {
+	for i := range trade.DCAOrders {
+		if trade.DCAOrders[i].BybitID == orderID {
+			trade.DCAOrders = slices.Clone(trade.DCAOrders)
+			return &trade.DCAOrders[i]
+		}
+	}
+	return findTradeOrder(trade, orderID)
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"fmt"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+
+	"cryptorg/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dcaGrid(prefix string, count int) []domain.Order {
+	orders := make([]domain.Order, count)
+	for i := range orders {
+		orders[i] = domain.Order{
+			BybitID:  fmt.Sprintf("%s-%d", prefix, i),
+			Symbol:   "BTCUSDT",
+			Side:     domain.OrderSideBuy,
+			Type:     domain.OrderTypeLimit,
+			Quantity: "100",
+			Price:    fmt.Sprintf("%d", 98-i),
+			Status:   domain.OrderStatusNew,
+		}
+	}
+	return orders
+}
+
+// Срезы, прочитанные через dcaOrders, не меняются, пока другие горутины доставляют,
+// переставляют и дописывают ордера. Гонки ловит go test -race.
+func TestDCAOrdersSnapshotsAreImmutable(t *testing.T) {
+	const (
+		writers    = 4
+		readers    = 4
+		iterations = 300
+		gridSize   = 5
+	)
+
+	s := &TradeService{}
+	trade := &domain.Trade{DCAOrders: dcaGrid("initial", gridSize)}
+
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				switch i % 4 {
+				case 0:
+					// Исполнение уровня
+					order := dcaGrid(fmt.Sprintf("fill-%d-%d", w, i), 1)[0]
+					order.Status = domain.OrderStatusFilled
+					s.setDCAOrder(trade, i%gridSize, order)
+				case 1:
+					// Перестановка сетки
+					s.replaceDCAOrders(trade, dcaGrid(fmt.Sprintf("reanchor-%d-%d", w, i), gridSize))
+				case 2:
+					// Доставка частичного исполнения меняет ордер на месте под s.mu
+					s.mu.Lock()
+					if order := mutableTradeOrder(trade, trade.DCAOrders[0].BybitID); order != nil {
+						order.Status = domain.OrderStatusPartially
+						order.ExecutedQty = fmt.Sprintf("%d", i)
+					}
+					s.mu.Unlock()
+				case 3:
+					// Новый уровень и возврат к исходному размеру сетки
+					s.appendDCAOrder(trade, dcaGrid(fmt.Sprintf("append-%d-%d", w, i), 1)[0])
+					s.mu.Lock()
+					trade.DCAOrders = slices.Clone(trade.DCAOrders[:gridSize])
+					s.mu.Unlock()
+				}
+			}
+		}(w)
+	}
+
+	// Читатели ведут себя как сверка: берут срез, долго его обходят и проверяют,
+	// что за это время он не изменился
+	for r := 0; r < readers; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				snapshot := s.dcaOrders(trade)
+				want := slices.Clone(snapshot)
+
+				for range snapshot {
+					runtime.Gosched()
+				}
+
+				if !assert.Equal(t, want, snapshot, "snapshot changed while reading") {
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	require.Len(t, s.dcaOrders(trade), gridSize)
+}
+
+func TestDCAOrdersWritesDoNotLeakIntoOldSnapshots(t *testing.T) {
+	s := &TradeService{}
+	trade := &domain.Trade{DCAOrders: dcaGrid("initial", 3)}
+
+	before := s.dcaOrders(trade)
+
+	filled := before[1]
+	filled.Status = domain.OrderStatusFilled
+	s.setDCAOrder(trade, 1, filled)
+	s.appendDCAOrder(trade, dcaGrid("append", 1)[0])
+	s.mu.Lock()
+	mutableTradeOrder(trade, "initial-0").Status = domain.OrderStatusCanceled
+	s.mu.Unlock()
+
+	assert.Equal(t, dcaGrid("initial", 3), before)
+
+	after := s.dcaOrders(trade)
+	require.Len(t, after, 4)
+	assert.Equal(t, domain.OrderStatusCanceled, after[0].Status)
+	assert.Equal(t, domain.OrderStatusFilled, after[1].Status)
+	assert.Equal(t, "append-0", after[3].BybitID)
+
+	// Переданный в replaceDCAOrders срез становится сеткой без копирования
+	replacement := dcaGrid("replacement", 2)
+	s.replaceDCAOrders(trade, replacement)
+	assert.Equal(t, replacement, s.dcaOrders(trade))
+	assert.Len(t, after, 4)
+}
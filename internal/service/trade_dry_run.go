@@ -0,0 +1,52 @@
+package service
+
+import (
+	"fmt"
+
+	"cryptorg/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// PlanClose возвращает ордера, которые снимет CloseTrade. Условия повторяют finalizeTrade:
+// TP и SL снимаются всегда, DCA - только в статусе NEW. Закрытие позицию не продает.
+func (s *TradeService) PlanClose(tradeID uuid.UUID) (*domain.DryRunPlan, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	trade, exists := s.trades[tradeID]
+	if !exists {
+		return nil, fmt.Errorf("trade not found: %s", tradeID)
+	}
+
+	plan := &domain.DryRunPlan{
+		Action:         "close_trade",
+		OrdersToCancel: make([]domain.PlannedCancel, 0),
+		Sales:          make([]domain.PlannedSale, 0),
+	}
+
+	if trade.StopLossOrder != nil {
+		plan.OrdersToCancel = append(plan.OrdersToCancel, plannedCancel(trade.StopLossOrder, "stop_loss"))
+	}
+	if trade.TakeProfitOrder != nil {
+		plan.OrdersToCancel = append(plan.OrdersToCancel, plannedCancel(trade.TakeProfitOrder, "take_profit"))
+	}
+	for i := range trade.DCAOrders {
+		if trade.DCAOrders[i].Status.IsOpen() {
+			plan.OrdersToCancel = append(plan.OrdersToCancel, plannedCancel(&trade.DCAOrders[i], "dca"))
+		}
+	}
+
+	return plan, nil
+}
+
+func plannedCancel(order *domain.Order, role string) domain.PlannedCancel {
+	return domain.PlannedCancel{
+		Symbol:   order.Symbol,
+		OrderID:  order.BybitID,
+		Role:     role,
+		Side:     order.Side,
+		Price:    order.Price,
+		Quantity: order.Quantity,
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"fmt"
+
+	"cryptorg/internal/domain"
+	apperrors "cryptorg/pkg/errors"
+)
+
+// AddExchange регистрирует биржу, которую можно выбрать в конфиге сделки или бота.
+// Регистрация делается при сборке приложения, до восстановления сделок.
+func (s *TradeService) AddExchange(name string, orders *OrderService) {
+	if s.exchanges == nil {
+		s.exchanges = make(map[string]*OrderService)
+	}
+	s.exchanges[name] = orders
+}
+
+// ordersFor возвращает клиент ордеров биржи сделки; пустое имя - биржа по умолчанию.
+func (s *TradeService) ordersFor(config domain.TradeConfig) *OrderService {
+	return s.exchangeOrders(config.Exchange)
+}
+
+func (s *TradeService) exchangeOrders(name string) *OrderService {
+	if orders, ok := s.exchanges[name]; ok && name != "" {
+		return orders
+	}
+	return s.orderManager
+}
+
+// checkExchange отказывает в сделке на бирже, которая не подключена.
+func (s *TradeService) checkExchange(name string) error {
+	if name == "" {
+		return nil
+	}
+	if _, ok := s.exchanges[name]; !ok {
+		return apperrors.DomainError(fmt.Sprintf("exchange %s is not configured", name), "EXCHANGE_NOT_CONFIGURED")
+	}
+	return nil
+}
+
+// exchangeKey сводит имя биржи сделки к ключу exchangeGroups: биржа по умолчанию - пустое имя.
+func (s *TradeService) exchangeKey(name string) string {
+	if orders, ok := s.exchanges[name]; !ok || orders == s.orderManager {
+		return ""
+	}
+	return name
+}
+
+// exchangeGroups - клиенты всех подключенных бирж без повторов, по имени в конфиге сделки.
+func (s *TradeService) exchangeGroups() map[string]*OrderService {
+	groups := map[string]*OrderService{"": s.orderManager}
+	for name, orders := range s.exchanges {
+		if orders != s.orderManager {
+			groups[name] = orders
+		}
+	}
+	return groups
+}
+
+// exchangeSymbol - символ на конкретной бирже: одинаковые пары разных бирж сверяются отдельно.
+type exchangeSymbol struct {
+	exchange string
+	symbol   string
+}
+
+func (s *TradeService) symbolKey(trade *domain.Trade) exchangeSymbol {
+	return exchangeSymbol{exchange: s.exchangeKey(trade.Config.Exchange), symbol: trade.Symbol}
+}
+
+// exchangeError добавляет к ошибке имя биржи, если это не биржа по умолчанию.
+func exchangeError(name string, err error) error {
+	if name == "" {
+		return err
+	}
+	return fmt.Errorf("%s: %w", name, err)
+}
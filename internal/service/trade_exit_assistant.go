@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"cryptorg/internal/domain"
+	"cryptorg/internal/indicator"
+	"cryptorg/internal/notify"
+	"cryptorg/pkg/pricing"
+)
+
+// exitAssistantLookback - сколько свечей запрашивать; последняя еще формируется и отбрасывается.
+const exitAssistantLookback = 5
+
+// RunExitAssistant проверяет сделки с настроенным помощником выхода: если позиция в прибыли
+// и на закрытых свечах появилась разворотная фигура, TP подтягивается к цене или позиция
+// закрывается по рынку. Помощник срабатывает для сделки один раз.
+func (s *TradeService) RunExitAssistant(ctx context.Context) error {
+	s.mu.RLock()
+	candidates := make([]*domain.Trade, 0)
+	for _, trade := range s.trades {
+		if trade.Status == domain.TradeStatusActive && trade.Config.ExitAssistant != nil &&
+			trade.ExitSignalAt == nil && trade.TakeProfitOrder != nil {
+			candidates = append(candidates, trade)
+		}
+	}
+	s.mu.RUnlock()
+
+	var errs []error
+	for _, trade := range candidates {
+		if err := s.assistExit(ctx, trade); err != nil {
+			errs = append(errs, fmt.Errorf("trade %s: %w", trade.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *TradeService) assistExit(ctx context.Context, trade *domain.Trade) error {
+	assistant := trade.Config.ExitAssistant
+
+	averagePrice, totalVolume, err := s.calculateNewAveragePrice(trade)
+	if err != nil {
+		return err
+	}
+
+	lastPrice, err := s.ordersFor(trade.Config).FetchLastPrice(ctx, trade.Symbol)
+	if err != nil {
+		return err
+	}
+
+	minExitPrice := averagePrice.Mul(pricing.Factor(assistant.MinProfitPercent)).InexactFloat64()
+	if lastPrice < minExitPrice {
+		return nil
+	}
+
+	detected, err := s.detectReversal(ctx, trade, assistant)
+	if err != nil || detected == "" {
+		return err
+	}
+
+	now := time.Now()
+	trade.ExitSignalAt = &now
+
+	switch assistant.Action {
+	case domain.ExitActionMarket:
+		err = s.exitAtMarket(ctx, trade, totalVolume)
+	default:
+		err = s.tightenTakeProfit(ctx, trade, totalVolume, math.Max(minExitPrice, lastPrice))
+	}
+	if err != nil {
+		trade.ExitSignalAt = nil
+		return err
+	}
+
+	message := fmt.Sprintf("%s detected on %s at %.8f, action: %s", detected, trade.Symbol, lastPrice, assistant.Action)
+	s.recordEvent(trade, domain.TradeEventExitAssist, nil, message)
+	notification := notify.Localized(notify.LevelInfo, "Exit assistant",
+		"%s detected on %s at %.8f, action: %s", detected, trade.Symbol, lastPrice, assistant.Action)
+	if err := s.notifier.Notify(ctx, notification); err != nil {
+	}
+	return nil
+}
+
+// detectReversal возвращает "<interval>:<pattern>" первой найденной фигуры или пустую строку.
+func (s *TradeService) detectReversal(ctx context.Context, trade *domain.Trade, assistant *domain.ExitAssistantConfig) (string, error) {
+	for _, interval := range assistant.Intervals {
+		klines, err := s.ordersFor(trade.Config).FetchKlines(ctx, trade.Symbol, interval, exitAssistantLookback)
+		if err != nil {
+			return "", err
+		}
+		if len(klines) < 2 {
+			continue
+		}
+		closed := klines[:len(klines)-1]
+
+		for _, name := range assistant.Patterns {
+			pattern, ok := indicator.Lookup(name)
+			if ok && pattern.Detect(closed) {
+				return strings.Join([]string{interval, name}, ":"), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+func (s *TradeService) tightenTakeProfit(ctx context.Context, trade *domain.Trade, totalVolume string, price float64) error {
+	if err := s.ordersFor(trade.Config).TerminateOrder(ctx, trade.Symbol, trade.TakeProfitOrder.BybitID); err != nil {
+		return fmt.Errorf("failed to cancel take profit order: %w", err)
+	}
+
+	tpOrder, err := s.ordersFor(trade.Config).ExecuteLimitOrder(ctx, domain.CreateOrderRequest{
+		Symbol:   trade.Symbol,
+		Side:     domain.OrderSideSell,
+		Type:     domain.OrderTypeLimit,
+		Quantity: totalVolume,
+		Price:    fmt.Sprintf("%.8f", price),
+		PostOnly: trade.Config.MakerOnly,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create tightened take profit order: %w", err)
+	}
+
+	trade.TakeProfitOrder = tpOrder
+	trade.UpdatedAt = time.Now()
+	s.recordEvent(trade, domain.TradeEventTPReplaced, tpOrder, "")
+
+	s.mu.Lock()
+	s.indexOrders(trade)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *TradeService) exitAtMarket(ctx context.Context, trade *domain.Trade, totalVolume string) error {
+	if err := s.ordersFor(trade.Config).TerminateOrder(ctx, trade.Symbol, trade.TakeProfitOrder.BybitID); err != nil {
+		return fmt.Errorf("failed to cancel take profit order: %w", err)
+	}
+
+	exitOrder, err := s.ordersFor(trade.Config).ExecuteMarketOrder(ctx, domain.CreateOrderRequest{
+		Symbol:   trade.Symbol,
+		Side:     domain.OrderSideSell,
+		Type:     domain.OrderTypeMarket,
+		Quantity: totalVolume,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute market exit: %w", err)
+	}
+	recordFill(trade, exitOrder)
+
+	// TP уже отменен выше, повторно его снимать не нужно
+	return s.finalizeTrade(ctx, trade.ID, domain.TradeStatusCompleted, trade.TakeProfitOrder.BybitID)
+}
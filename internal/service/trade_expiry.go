@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"cryptorg/internal/domain"
+)
+
+func stampExpiry(trade *domain.Trade, order *domain.Order) {
+	if trade.Config.DCAOrderTTLMinutes <= 0 {
+		return
+	}
+
+	expiresAt := order.CreatedAt.Add(time.Duration(trade.Config.DCAOrderTTLMinutes) * time.Minute)
+	order.ExpiresAt = &expiresAt
+}
+
+// ExpireDCAOrders снимает DCA ордера с истекшим сроком жизни и, если включено,
+// перевыставляет их от текущей цены.
+func (s *TradeService) ExpireDCAOrders(ctx context.Context) error {
+	now := time.Now()
+
+	s.mu.RLock()
+	candidates := make([]*domain.Trade, 0)
+	for _, trade := range s.trades {
+		if trade.Status == domain.TradeStatusActive && trade.Config.DCAOrderTTLMinutes > 0 {
+			candidates = append(candidates, trade)
+		}
+	}
+	s.mu.RUnlock()
+
+	var errs []error
+	for _, trade := range candidates {
+		if err := s.expireTradeOrders(ctx, trade, now); err != nil {
+			errs = append(errs, fmt.Errorf("trade %s: %w", trade.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *TradeService) expireTradeOrders(ctx context.Context, trade *domain.Trade, now time.Time) error {
+	unlock, err := s.locker.Lock(ctx, trade.ID)
+	if err != nil {
+		return fmt.Errorf("failed to lock trade: %w", err)
+	}
+	defer unlock()
+
+	// Сделка могла закрыться, пока ждали блокировку
+	if trade.Status != domain.TradeStatusActive {
+		return nil
+	}
+
+	expiredIdx := make([]int, 0)
+	for i, order := range trade.DCAOrders {
+		if isOpenOrder(order) && order.ExpiresAt != nil && !order.ExpiresAt.After(now) {
+			expiredIdx = append(expiredIdx, i)
+		}
+	}
+	if len(expiredIdx) == 0 {
+		return nil
+	}
+
+	if trade.Config.ReplaceExpired {
+		lastPrice, err := s.ordersFor(trade.Config).FetchLastPrice(ctx, trade.Symbol)
+		if err != nil {
+			return err
+		}
+		return s.reanchorOpenOrders(ctx, trade, expiredIdx, domain.DecimalFromFloat(lastPrice))
+	}
+
+	for _, i := range expiredIdx {
+		order := trade.DCAOrders[i]
+		if err := s.ordersFor(trade.Config).TerminateOrder(ctx, order.Symbol, order.BybitID); err != nil {
+			return fmt.Errorf("failed to cancel expired DCA order %s: %w", order.BybitID, err)
+		}
+		order.Status = domain.OrderStatusCanceled
+		if executed, _ := strconv.ParseFloat(order.ExecutedQty, 64); executed > 0 {
+			// Учтенное частичное исполнение остается в средней цене и объеме TP
+			order.Status = domain.OrderStatusPartiallyCanceled
+		}
+		order.UpdatedAt = now
+		s.setDCAOrder(trade, i, order)
+
+		s.mu.Lock()
+		delete(s.orderIndex, order.BybitID)
+		s.mu.Unlock()
+	}
+
+	trade.UpdatedAt = now
+	s.recordEvent(trade, domain.TradeEventOrdersExpired, nil, fmt.Sprintf("cancelled %d expired DCA orders", len(expiredIdx)))
+	return nil
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"cryptorg/internal/domain"
+)
+
+// exportTrade передает итог сделки, закрытой по TP или SL, во внешний журнал, если он настроен.
+func (s *TradeService) exportTrade(ctx context.Context, trade *domain.Trade, exitOrderID string) {
+	if s.exporter == nil {
+		return
+	}
+	if trade.Status != domain.TradeStatusCompleted && trade.Status != domain.TradeStatusStopped {
+		return
+	}
+
+	if err := s.exporter.ExportTrade(ctx, buildTradeExport(trade, exitOrderID)); err != nil {
+		log.Printf("Failed to export trade %s: %v", trade.ID, err)
+		s.metrics.IncCounter("trade_exports_failed_total", nil)
+	}
+}
+
+func buildTradeExport(trade *domain.Trade, exitOrderID string) domain.TradeExport {
+	closedAt := tradeClosedAt(trade)
+	export := domain.TradeExport{
+		TradeID:  trade.ID,
+		Symbol:   trade.Symbol,
+		Side:     entrySide(trade.Config),
+		Status:   trade.Status,
+		PnL:      trade.RealizedPnL,
+		Fees:     trade.Fees,
+		OpenedAt: trade.CreatedAt,
+		ClosedAt: closedAt,
+		Duration: closedAt.Sub(trade.CreatedAt).Truncate(time.Second),
+	}
+
+	if trade.EntryOrder != nil {
+		export.EntryPrice = fmt.Sprintf("%.8f", fillPrice(trade.EntryOrder))
+	}
+	for i := range trade.DCAOrders {
+		if trade.DCAOrders[i].Status.ClosedWithFills() {
+			export.DCACount++
+		}
+	}
+	for _, exit := range []*domain.Order{trade.TakeProfitOrder, trade.StopLossOrder} {
+		if exit != nil && exit.BybitID == exitOrderID {
+			export.ExitPrice = fmt.Sprintf("%.8f", fillPrice(exit))
+		}
+	}
+
+	if export.PnL == "" {
+		profit, _ := tradeRealizedPnL(trade)
+		export.PnL = fmt.Sprintf("%.8f", profit)
+	}
+	if export.Fees == "" {
+		export.Fees = fmt.Sprintf("%.8f", tradeFeesPaid(trade))
+	}
+	return export
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+
+	"cryptorg/internal/domain"
+	apperrors "cryptorg/pkg/errors"
+)
+
+// checkSymbol проверяет, можно ли открыть сделку: остановку по лимиту убытка, списки символов,
+// паузу после стопа, пересечение с портфелями и встречные сделки по символу. BulkCreate вызывает ее и в dry run.
+func (s *TradeService) checkSymbol(config domain.TradeConfig) error {
+	if err := s.riskManager.checkSuspended(); err != nil {
+		return err
+	}
+	if err := s.riskManager.SymbolLists().Check(config.Symbol); err != nil {
+		return err
+	}
+	if err := s.riskManager.Cooldowns().Check(config.Symbol); err != nil {
+		return err
+	}
+	if err := s.exposure.CheckTrade(config); err != nil {
+		return err
+	}
+	return s.checkOppositeSide(config)
+}
+
+// checkOppositeSide отказывает в лонге по символу, где открыт шорт, и наоборот: встречные
+// сетки покупают и продают одну монету друг у друга.
+func (s *TradeService) checkOppositeSide(config domain.TradeConfig) error {
+	if config.AllowOppositeExposure {
+		return nil
+	}
+
+	s.mu.RLock()
+	conflicts := make([]string, 0)
+	for _, trade := range s.trades {
+		if trade.Symbol == config.Symbol && trade.Status.IsOpen() && trade.Config.IsShort() != config.IsShort() {
+			conflicts = append(conflicts, trade.ID.String())
+		}
+	}
+	s.mu.RUnlock()
+
+	if len(conflicts) == 0 {
+		return nil
+	}
+	sort.Strings(conflicts)
+
+	appErr := apperrors.DomainError(
+		fmt.Sprintf("symbol %s has open trades on the opposite side; set allow_opposite_exposure to open the trade anyway", config.Symbol),
+		"OPPOSITE_EXPOSURE",
+	)
+	appErr.Details = map[string]interface{}{"symbol": config.Symbol, "trades": conflicts}
+	return appErr
+}
+
+// OpenTradesBySymbol возвращает ID активных и ожидающих старта сделок по каждому из symbols.
+func (s *TradeService) OpenTradesBySymbol(symbols []string) map[string][]string {
+	wanted := make(map[string]bool, len(symbols))
+	for _, symbol := range symbols {
+		wanted[symbol] = true
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[string][]string)
+	for _, trade := range s.trades {
+		if !wanted[trade.Symbol] {
+			continue
+		}
+		if trade.Status.IsOpen() {
+			result[trade.Symbol] = append(result[trade.Symbol], trade.ID.String())
+		}
+	}
+	return result
+}
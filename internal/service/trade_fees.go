@@ -0,0 +1,52 @@
+package service
+
+import (
+	"cryptorg/internal/domain"
+	"cryptorg/pkg/pricing"
+)
+
+// tradeFees возвращает комиссии входа и выхода сделки в долях и признак того,
+// что в конфиге заданы свои ставки. Без них TP и PnL считаются без учета комиссий.
+// Вход и DCA идут по тейкерской ставке (в MakerOnly - по мейкерской), TP всегда мейкерский.
+func tradeFees(config domain.TradeConfig) (entry, exit float64, ok bool) {
+	if config.MakerFeePercent == nil && config.TakerFeePercent == nil {
+		return 0, 0, false
+	}
+
+	maker, taker := domain.DefaultMakerFeePercent, domain.DefaultTakerFeePercent
+	if config.MakerFeePercent != nil {
+		maker = *config.MakerFeePercent
+	}
+	if config.TakerFeePercent != nil {
+		taker = *config.TakerFeePercent
+	}
+
+	entry = taker
+	if config.MakerOnly {
+		entry = maker
+	}
+	return entry / 100, maker / 100, true
+}
+
+// takeProfitPrice - цена TP от средней цены: выше нее для лонга, ниже для шорта. Со своими
+// комиссиями цена отодвигается так, чтобы TakeProfitPercent остался чистой прибылью.
+func takeProfitPrice(config domain.TradeConfig, averagePrice domain.Decimal) domain.Decimal {
+	var fees *pricing.Fees
+	if entry, exit, ok := tradeFees(config); ok {
+		fees = &pricing.Fees{Entry: domain.DecimalFromFloat(entry), Exit: domain.DecimalFromFloat(exit)}
+	}
+	return pricing.TakeProfit(averagePrice, config.TakeProfitPercent, config.IsShort(), fees)
+}
+
+// breakEvenPrice - цена выхода без прибыли и убытка с учетом комиссий; без своих ставок
+// берутся комиссии биржи по умолчанию.
+func breakEvenPrice(config domain.TradeConfig, averagePrice domain.Decimal) domain.Decimal {
+	entry, exit, ok := tradeFees(config)
+	if !ok {
+		maker := domain.DefaultMakerFeePercent
+		config.MakerFeePercent = &maker
+		entry, exit, _ = tradeFees(config)
+	}
+	fees := pricing.Fees{Entry: domain.DecimalFromFloat(entry), Exit: domain.DecimalFromFloat(exit)}
+	return pricing.BreakEven(averagePrice, config.IsShort(), fees)
+}
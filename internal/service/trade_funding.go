@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"cryptorg/internal/bybit"
+	"cryptorg/internal/domain"
+	"cryptorg/internal/notify"
+	apperrors "cryptorg/pkg/errors"
+)
+
+// nextLevelCost возвращает объем в котируемой валюте, который понадобится сделке
+// для следующей покупки, еще не обеспеченной ордером на бирже.
+func nextLevelCost(trade *domain.Trade) float64 {
+	if isTimeBased(trade) {
+		if trade.NextBuyAt == nil {
+			return 0
+		}
+		volume, _ := strconv.ParseFloat(trade.Config.DCAVolume, 64)
+		return volume
+	}
+
+	entryPrice, err := domain.ParseDecimal(trade.EntryOrder.Price)
+	if err != nil {
+		return 0
+	}
+
+	// Выставленные и исполненные уровни уже оплачены; следующий - первый без ордера
+	backed := 0
+	for i := range trade.DCAOrders {
+		if trade.DCAOrders[i].Status.IsOpen() || trade.DCAOrders[i].Status.ClosedWithFills() {
+			backed++
+		}
+	}
+
+	grid := BuildGrid(trade.Config, entryPrice)
+	if backed >= len(grid) {
+		return 0
+	}
+	volume, _ := strconv.ParseFloat(grid[backed].Volume, 64)
+	return volume
+}
+
+// checkFreeBalance отказывает в сделке, если свободного остатка котируемой валюты не хватает
+// на вход и всю сетку DCA с мартингейлом. Шорт продает базовую монету и не проверяется.
+func (s *TradeService) checkFreeBalance(ctx context.Context, config domain.TradeConfig) error {
+	if config.IsShort() {
+		return nil
+	}
+
+	quote := bybit.QuoteAsset(config.Symbol)
+	total, locked, err := s.ordersFor(config).FetchBalance(ctx, quote)
+	if err != nil {
+		return fmt.Errorf("failed to check balance: %w", err)
+	}
+
+	available := total - locked
+	required := requiredCapital(config, BuildGrid(config, domain.DecimalOne))
+	if required <= available {
+		return nil
+	}
+
+	appErr := apperrors.DomainError(
+		fmt.Sprintf("insufficient %s balance: %.2f required, %.2f available", quote, required, available),
+		"INSUFFICIENT_BALANCE",
+	)
+	appErr.Details = map[string]interface{}{"coin": quote, "required": required, "available": available}
+	return appErr
+}
+
+// CheckFunding сравнивает свободный остаток котируемой валюты с потребностью активных
+// сделок в следующих уровнях DCA. Сделки, на которые не хватает средств, помечаются
+// underfunded в порядке открытия; о каждом новом дефиците отправляется уведомление.
+// Шорты продают базовую монету и здесь не проверяются.
+func (s *TradeService) CheckFunding(ctx context.Context) error {
+	s.mu.RLock()
+	byQuote := make(map[exchangeSymbol][]*domain.Trade) // symbol - котируемая валюта
+	for _, trade := range s.trades {
+		if trade.Status == domain.TradeStatusActive && trade.EntryOrder != nil && !trade.Config.IsShort() {
+			key := exchangeSymbol{exchange: s.exchangeKey(trade.Config.Exchange), symbol: bybit.QuoteAsset(trade.Symbol)}
+			byQuote[key] = append(byQuote[key], trade)
+		}
+	}
+	s.mu.RUnlock()
+
+	var errs []error
+	for key, trades := range byQuote {
+		if err := s.checkQuoteFunding(ctx, s.exchangeOrders(key.exchange), key.symbol, trades); err != nil {
+			errs = append(errs, exchangeError(key.exchange, fmt.Errorf("%s: %w", key.symbol, err)))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *TradeService) checkQuoteFunding(ctx context.Context, orders *OrderService, quote string, trades []*domain.Trade) error {
+	total, locked, err := orders.FetchBalance(ctx, quote)
+	if err != nil {
+		return err
+	}
+	free := total - locked
+
+	sort.Slice(trades, func(i, j int) bool { return trades[i].CreatedAt.Before(trades[j].CreatedAt) })
+
+	required := 0.0
+	newlyUnderfunded := make([]string, 0)
+
+	s.mu.Lock()
+	for _, trade := range trades {
+		required += nextLevelCost(trade)
+		underfunded := required > free
+		if underfunded && !trade.Underfunded {
+			newlyUnderfunded = append(newlyUnderfunded, trade.ID.String())
+		}
+		trade.Underfunded = underfunded
+	}
+	s.mu.Unlock()
+	s.invalidateSnapshot()
+
+	if len(newlyUnderfunded) == 0 {
+		return nil
+	}
+
+	return s.notifier.Notify(ctx, notify.Localized(notify.LevelWarning, "Low balance",
+		"Free %s balance %.2f does not cover next DCA levels (%.2f required). Underfunded trades: %v",
+		quote, free, required, newlyUnderfunded))
+}
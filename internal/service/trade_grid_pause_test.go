@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cryptorg/internal/bybit"
+	"cryptorg/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Ответ фейковой биржи на создание ордера совпадает по полям с записанным ответом Bybit
+// (testdata/replay/POST_v5_order_create_*); orderStatus подставляет клиент.
+func TestFakeCreateResponseMatchesRecordedShape(t *testing.T) {
+	paths, err := filepath.Glob("../bybit/testdata/replay/POST_v5_order_create_*.json")
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+	data, err := os.ReadFile(paths[0])
+	require.NoError(t, err)
+
+	var fixture struct {
+		Response struct {
+			Result map[string]json.RawMessage `json:"result"`
+		} `json:"response"`
+	}
+	require.NoError(t, json.Unmarshal(data, &fixture))
+	recorded := []string{"orderStatus"}
+	for field := range fixture.Response.Result {
+		recorded = append(recorded, field)
+	}
+
+	exchange := newFakeExchange(map[string]float64{"BTCUSDT": 100})
+	for _, req := range []bybit.ExchangeOrderRequest{
+		{Symbol: "BTCUSDT", Side: "Buy", OrderType: "Limit", Qty: "1", Price: "90", OrderLinkID: "limit"},
+		{Symbol: "BTCUSDT", Side: "Buy", OrderType: "Market", Qty: "100", OrderLinkID: "market"},
+	} {
+		resp, err := exchange.ExecuteOrder(context.Background(), req)
+		require.NoError(t, err)
+
+		data, err := json.Marshal(resp)
+		require.NoError(t, err)
+		var fields map[string]string
+		require.NoError(t, json.Unmarshal(data, &fields))
+		present := make([]string, 0, len(fields))
+		for field, value := range fields {
+			if value != "" {
+				present = append(present, field)
+			}
+		}
+		assert.ElementsMatch(t, recorded, present, req.OrderType)
+	}
+}
+
+func TestRefreshStaleGridWithIDOnlyCreateResponse(t *testing.T) {
+	exchange := newFakeExchange(map[string]float64{"BTCUSDT": 100})
+	trades := newFakeTradeService(t, exchange, storage.NewMemoryJournal(), storage.NewMemoryTradeRepository())
+
+	config := testTradeConfig()
+	config.GridRefreshPercent = 5
+	config.TakeProfitPercent = 20
+	trade, err := trades.InitializeTrade(context.Background(), config)
+	require.NoError(t, err)
+	require.Len(t, trade.DCAOrders, 3)
+	assert.InDelta(t, 98, parseFake(trade.DCAOrders[0].Price), 1e-6)
+
+	// Цена ушла вверх на 10%: сетка переставляется от новой цены с прежними объемами
+	exchange.setPrice("BTCUSDT", 110)
+	require.NoError(t, trades.RefreshStaleGrids(context.Background()))
+
+	got, err := trades.GetTrade(trade.ID)
+	require.NoError(t, err)
+	require.Len(t, got.DCAOrders, 3)
+	assert.InDelta(t, 107.8, parseFake(got.DCAOrders[0].Price), 1e-6)
+	assert.InDelta(t, 100/107.8, parseFake(got.DCAOrders[0].Quantity), 1e-6)
+	assert.Len(t, exchange.open("BTCUSDT"), 4)
+}
+
+func TestPauseAndResumeWithIDOnlyCreateResponse(t *testing.T) {
+	exchange := newFakeExchange(map[string]float64{"BTCUSDT": 100})
+	trades := newFakeTradeService(t, exchange, storage.NewMemoryJournal(), storage.NewMemoryTradeRepository())
+
+	trade, err := trades.InitializeTrade(context.Background(), testTradeConfig())
+	require.NoError(t, err)
+	levels := make(map[string]float64, len(trade.DCAOrders))
+	for _, order := range trade.DCAOrders {
+		levels[order.Price] = parseFake(order.Quantity)
+	}
+
+	paused, err := trades.PauseTrade(context.Background(), trade.ID, "news")
+	require.NoError(t, err)
+	require.Len(t, paused.PausedGrid, 3)
+	assert.Empty(t, exchange.open("BTCUSDT"))
+
+	// Уровни возвращаются по прежним ценам и с прежним количеством в базовой валюте
+	resumed, err := trades.ResumeTrade(context.Background(), trade.ID)
+	require.NoError(t, err)
+	require.Len(t, resumed.DCAOrders, 3)
+	for _, order := range resumed.DCAOrders {
+		require.Contains(t, levels, order.Price)
+		assert.InDelta(t, levels[order.Price], parseFake(order.Quantity), 1e-6)
+		placed, ok := exchange.order(order.BybitID)
+		require.True(t, ok)
+		assert.InDelta(t, levels[order.Price], parseFake(placed.Qty), 1e-6)
+	}
+	assert.NotNil(t, resumed.TakeProfitOrder)
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"cryptorg/internal/domain"
+)
+
+// isOpenOrder - ордер стоит на бирже без исполнения: его можно переставить или отменить,
+// не теряя учтенный объем. Частично исполненные ордера сюда не входят.
+func isOpenOrder(order domain.Order) bool {
+	switch order.Status.Normalize() {
+	case domain.OrderStatusNew, domain.OrderStatusUntriggered, domain.OrderStatusTriggered:
+		return true
+	}
+	return false
+}
+
+// RefreshStaleGrids переставляет неисполненные DCA ордера ближе к цене, если после движения
+// в пользу сделки ближайший из них оказался дальше GridRefreshPercent от текущей цены.
+func (s *TradeService) RefreshStaleGrids(ctx context.Context) error {
+	s.mu.RLock()
+	candidates := make([]*domain.Trade, 0)
+	for _, trade := range s.trades {
+		if trade.Status == domain.TradeStatusActive && trade.Config.GridRefreshPercent > 0 && !isTimeBased(trade) {
+			candidates = append(candidates, trade)
+		}
+	}
+	s.mu.RUnlock()
+
+	var errs []error
+	for _, trade := range candidates {
+		if err := s.refreshTradeGrid(ctx, trade); err != nil {
+			errs = append(errs, fmt.Errorf("trade %s: %w", trade.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *TradeService) refreshTradeGrid(ctx context.Context, trade *domain.Trade) error {
+	unlock, err := s.locker.Lock(ctx, trade.ID)
+	if err != nil {
+		return fmt.Errorf("failed to lock trade: %w", err)
+	}
+	defer unlock()
+
+	// Сделка могла закрыться, пока ждали блокировку
+	if trade.Status != domain.TradeStatusActive {
+		return nil
+	}
+
+	sign := domain.DecimalFromFloat(sideSign(trade.Config))
+	openIdx := make([]int, 0)
+	nearest := domain.DecimalZero
+	for i, order := range trade.DCAOrders {
+		if !isOpenOrder(order) {
+			continue
+		}
+		openIdx = append(openIdx, i)
+		// Ближайший к цене уровень: самый высокий для лонга, самый низкий для шорта
+		if price, err := domain.ParseDecimal(order.Price); err == nil && (nearest.IsZero() || price.Sub(nearest).Mul(sign).IsPositive()) {
+			nearest = price
+		}
+	}
+	if len(openIdx) == 0 || !nearest.IsPositive() {
+		return nil
+	}
+
+	last, err := s.ordersFor(trade.Config).FetchLastPrice(ctx, trade.Symbol)
+	if err != nil {
+		return err
+	}
+	lastPrice := domain.DecimalFromFloat(last)
+	if !lastPrice.IsPositive() {
+		return nil
+	}
+
+	drift := lastPrice.Sub(nearest).Mul(sign).Div(lastPrice).Mul(domain.DecimalFromFloat(100))
+	if drift.LessThanOrEqual(domain.DecimalFromFloat(trade.Config.GridRefreshPercent)) {
+		return nil
+	}
+
+	return s.reanchorOpenOrders(ctx, trade, openIdx, lastPrice)
+}
+
+// reanchorOpenOrders отменяет указанные DCA ордера и выставляет их заново от anchorPrice,
+// сохраняя объемы уровней исходной сетки. Ордер, который не удалось отменить, остается на
+// месте; переносятся только отмененные, ошибки отмен возвращаются после переноса.
+func (s *TradeService) reanchorOpenOrders(ctx context.Context, trade *domain.Trade, openIdx []int, anchorPrice domain.Decimal) error {
+	entryPrice, err := domain.ParseDecimal(trade.EntryOrder.Price)
+	if err != nil {
+		return fmt.Errorf("invalid entry price: %w", err)
+	}
+
+	filledCount := len(trade.DCAOrders) - len(openIdx)
+	originalGrid := BuildGrid(trade.Config, entryPrice)
+
+	var errs []error
+	cancelled := make(map[int]bool, len(openIdx))
+	// Уровни исходной сетки отмененных ордеров: k-й открытый ордер стоит на уровне filledCount+k
+	levels := make([]int, 0, len(openIdx))
+	for k, i := range openIdx {
+		order := trade.DCAOrders[i]
+		if err := s.ordersFor(trade.Config).TerminateOrder(ctx, order.Symbol, order.BybitID); err != nil {
+			s.recordCancelFailure(trade, &order, err)
+			errs = append(errs, fmt.Errorf("failed to cancel stale DCA order %s: %w", order.BybitID, err))
+			continue
+		}
+		cancelled[i] = true
+		levels = append(levels, filledCount+k)
+
+		s.mu.Lock()
+		delete(s.orderIndex, order.BybitID)
+		s.mu.Unlock()
+	}
+	if len(cancelled) == 0 {
+		return errors.Join(errs...)
+	}
+
+	kept := make([]domain.Order, 0, len(trade.DCAOrders))
+	for i, order := range trade.DCAOrders {
+		if !cancelled[i] {
+			kept = append(kept, order)
+			continue
+		}
+		// Учтенное частичное исполнение остается в средней цене и объеме TP
+		if executed, _ := domain.ParseDecimal(order.ExecutedQty); executed.IsPositive() {
+			order.Status = domain.OrderStatusPartiallyCanceled
+			kept = append(kept, order)
+		}
+	}
+	s.replaceDCAOrders(trade, kept)
+
+	// Приостановленные уровни идут в исходной сетке после всех открытых
+	for n := 0; n < trade.PausedLevels; n++ {
+		levels = append(levels, filledCount+len(openIdx)+n)
+	}
+
+	anchoredConfig := trade.Config
+	// Приостановленные уровни тоже переносятся: после переноса они могут оказаться в границах
+	anchoredConfig.DCACount = len(levels)
+	anchoredGrid := BuildGrid(anchoredConfig, anchorPrice)
+
+	trade.PausedLevels = 0
+	for n, level := range anchoredGrid {
+		if price, err := domain.ParseDecimal(level.Price); err == nil && !withinPriceBounds(trade.Config, price) {
+			trade.PausedLevels++
+			continue
+		}
+
+		volume := level.Volume
+		if n < len(levels) && levels[n] < len(originalGrid) {
+			volume = originalGrid[levels[n]].Volume
+		}
+
+		dcaOrderReq := domain.CreateOrderRequest{
+			Symbol:   trade.Config.Symbol,
+			Side:     entrySide(trade.Config),
+			Type:     domain.OrderTypeLimit,
+			Quantity: volume,
+			Price:    level.Price,
+			PostOnly: trade.Config.MakerOnly,
+		}
+
+		dcaOrder, err := s.ordersFor(trade.Config).ExecuteLimitOrder(ctx, dcaOrderReq)
+		if err != nil {
+			continue
+		}
+
+		stampExpiry(trade, dcaOrder)
+		s.appendDCAOrder(trade, *dcaOrder)
+	}
+
+	s.mu.Lock()
+	s.indexOrders(trade)
+	s.mu.Unlock()
+
+	s.recordEvent(trade, domain.TradeEventGridRefreshed, nil, fmt.Sprintf("re-anchored %d of %d levels at %s", len(cancelled), len(openIdx), domain.FormatDecimal(anchorPrice)))
+	return errors.Join(errs...)
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"cryptorg/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func (s *TradeService) recordEvent(trade *domain.Trade, eventType domain.TradeEventType, order *domain.Order, message string) {
+	snapshot, err := json.Marshal(trade)
+	if err != nil {
+		log.Printf("Failed to snapshot trade %s for journal: %v", trade.ID, err)
+		return
+	}
+
+	event := &domain.TradeEvent{
+		Type:      eventType,
+		TradeID:   trade.ID,
+		Message:   message,
+		Snapshot:  snapshot,
+		Timestamp: time.Now(),
+	}
+	if order != nil {
+		event.OrderID = order.BybitID
+		event.Price = order.Price
+		event.Quantity = order.Quantity
+	}
+
+	if err := s.journal.Append(event); err != nil {
+		log.Printf("Failed to append %s event for trade %s: %v", eventType, trade.ID, err)
+	}
+	if err := s.repository.Save(trade); err != nil {
+		log.Printf("Failed to persist trade %s after %s: %v", trade.ID, eventType, err)
+	}
+	s.invalidateSnapshot()
+}
+
+// recordCancelFailure фиксирует неудачную отмену, которая не прерывает обработку сделки.
+func (s *TradeService) recordCancelFailure(trade *domain.Trade, order *domain.Order, err error) {
+	log.Printf("Failed to cancel order of trade %s: %v", trade.ID, err)
+	s.recordEvent(trade, domain.TradeEventCancelFailed, order, err.Error())
+}
+
+// ReplayEvents восстанавливает состояние сделок из журнала до события untilSeq включительно
+// (0 - до конца журнала).
+func ReplayEvents(events []domain.TradeEvent, untilSeq uint64) (map[uuid.UUID]*domain.Trade, error) {
+	trades := make(map[uuid.UUID]*domain.Trade)
+
+	var lastSeq uint64
+	for _, event := range events {
+		if untilSeq > 0 && event.Seq > untilSeq {
+			break
+		}
+		if event.Seq <= lastSeq {
+			return nil, fmt.Errorf("journal sequence is not monotonic: %d after %d", event.Seq, lastSeq)
+		}
+		lastSeq = event.Seq
+
+		if len(event.Snapshot) == 0 {
+			continue
+		}
+
+		var trade domain.Trade
+		if err := json.Unmarshal(event.Snapshot, &trade); err != nil {
+			return nil, fmt.Errorf("failed to decode snapshot of event %d: %w", event.Seq, err)
+		}
+		trades[trade.ID] = &trade
+	}
+
+	return trades, nil
+}
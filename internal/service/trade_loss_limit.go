@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"cryptorg/internal/bybit"
+	"cryptorg/internal/domain"
+	"cryptorg/internal/notify"
+)
+
+// CheckLossLimit считает PnL за скользящие 24 часа и при превышении дневного лимита
+// убытка останавливает открытие новых циклов. Используется планировщиком.
+func (s *TradeService) CheckLossLimit(ctx context.Context) error {
+	if len(s.riskManager.DailyLossLimits()) == 0 {
+		return nil
+	}
+
+	pnl, err := s.dailyPnL(ctx)
+	if err != nil {
+		return err
+	}
+
+	quote, loss, breached := s.riskManager.evaluateLoss(pnl)
+	if !breached {
+		return nil
+	}
+
+	limit := s.riskManager.DailyLossLimits()[quote]
+	reason := fmt.Sprintf("%s loss %.2f over 24h exceeds limit %.2f", quote, loss, limit)
+	if !s.riskManager.suspend(reason) {
+		return nil
+	}
+
+	log.Printf("AUDIT: trading suspended: %s", reason)
+	return s.notifier.Notify(ctx, notify.Localized(notify.LevelCritical, "Trading suspended",
+		"%s loss %.2f over 24h exceeds limit %.2f. Open trades are still managed; resume with POST /api/emergency/resume",
+		quote, loss, limit))
+}
+
+// dailyPnL - реализованный PnL сделок, закрытых за последние 24 часа, плюс переоценка
+// открытых позиций по последней цене, по котируемым валютам.
+func (s *TradeService) dailyPnL(ctx context.Context) (map[string]float64, error) {
+	prices, err := s.lastPrices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	since := time.Now().Add(-lossWindow)
+	pnl := make(map[string]float64)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, trade := range s.trades {
+		invested, _ := strconv.ParseFloat(trade.TotalInvested, 64)
+		freed, _ := strconv.ParseFloat(trade.FreedCapital, 64)
+		quote := bybit.QuoteAsset(trade.Symbol)
+
+		switch trade.Status {
+		case domain.TradeStatusActive, domain.TradeStatusPaused:
+			if price := prices[s.symbolKey(trade)]; price > 0 && invested > 0 {
+				pnl[quote] += positionPnL(trade, price)
+			}
+		case domain.TradeStatusCompleted, domain.TradeStatusStopped, domain.TradeStatusCancelled:
+			// Отмененная сделка без выхода оставила позицию на счете, это не убыток
+			if trade.UpdatedAt.After(since) && freed > 0 {
+				pnl[quote] += positionPnL(trade, 0)
+			}
+		}
+	}
+	return pnl, nil
+}
+
+func (s *TradeService) TradingSuspension() domain.TradingSuspension {
+	return s.riskManager.Suspension()
+}
+
+func (s *TradeService) ResumeTrading() error {
+	return s.riskManager.Resume()
+}
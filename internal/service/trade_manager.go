@@ -2,100 +2,515 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strconv"
+	"log"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"cryptorg/internal/chaos"
 	"cryptorg/internal/domain"
+	"cryptorg/internal/metrics"
+	"cryptorg/internal/notify"
+	"cryptorg/internal/tracing"
+	"cryptorg/pkg/pricing"
 
 	"github.com/google/uuid"
 )
 
+// Открытие, не завершенное после исполнения входа, сразу повторяется несколько раз: ошибка
+// цены входа обычно временная, а сверка с исправлением по умолчанию выключена.
+const (
+	openRetryAttempts  = 3
+	openRetryBaseDelay = 500 * time.Millisecond
+)
+
 type TradeService struct {
-	orderManager *OrderService
-	trades       map[uuid.UUID]*domain.Trade
-	orderIndex   map[string]uuid.UUID // orderID -> tradeID для быстрого поиска
-	mu           sync.RWMutex
+	orderManager  *OrderService
+	exchanges     map[string]*OrderService // Биржи по имени для TradeConfig.Exchange; заполняется при сборке
+	riskManager   *RiskService
+	journal       domain.EventJournal
+	repository    domain.TradeRepository
+	executions    domain.ExecutionStore
+	metrics       metrics.Recorder
+	trades        map[uuid.UUID]*domain.Trade
+	orderIndex    map[string]uuid.UUID // orderID -> tradeID для быстрого поиска
+	lastFillAt    time.Time
+	mu            sync.RWMutex
+	snapshot      atomic.Pointer[tradeSnapshot]
+	snapshotDirty atomic.Bool
+	notifier      notify.Notifier
+	locker        domain.TradeLocker
+	shared        domain.TradeLoader // nil - хранилище сделок не общее с другими репликами
+	exposure      *ExposureGuard
+	exporter      domain.TradeExporter // nil - закрытые сделки никуда не выгружаются
+	closed        []func(ctx context.Context, trade *domain.Trade)
+	creditChecks  []*creditCheck // Под mu
 }
 
-func NewTradeManager(orderManager *OrderService) *TradeService {
+func NewTradeManager(orderManager *OrderService, riskManager *RiskService, journal domain.EventJournal, repository domain.TradeRepository, executions domain.ExecutionStore, recorder metrics.Recorder, notifier notify.Notifier, locker domain.TradeLocker, exposure *ExposureGuard, exporter domain.TradeExporter) *TradeService {
 	return &TradeService{
 		orderManager: orderManager,
+		riskManager:  riskManager,
+		journal:      journal,
+		repository:   repository,
+		executions:   executions,
+		metrics:      recorder,
+		notifier:     notifier,
+		locker:       locker,
+		exposure:     exposure,
+		exporter:     exporter,
 		trades:       make(map[uuid.UUID]*domain.Trade),
 		orderIndex:   make(map[string]uuid.UUID),
 	}
 }
 
 func (s *TradeService) InitializeTrade(ctx context.Context, config domain.TradeConfig) (*domain.Trade, error) {
-	entryOrderReq := domain.CreateOrderRequest{
-		Symbol:   config.Symbol,
-		Side:     domain.OrderSideBuy,
-		Type:     domain.OrderTypeMarket,
-		Quantity: config.EntryVolume,
+	return s.initializeTrade(ctx, config, nil)
+}
+
+func (s *TradeService) initializeTrade(ctx context.Context, config domain.TradeConfig, botID *uuid.UUID) (_ *domain.Trade, err error) {
+	ctx, span := tracing.Start(ctx, "TradeService.InitializeTrade", tracing.Symbol(config.Symbol))
+	defer func() { tracing.End(span, err) }()
+
+	if err := s.checkExchange(config.Exchange); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkSymbol(config); err != nil {
+		return nil, err
 	}
 
-	entryOrder, err := s.orderManager.ExecuteMarketOrder(ctx, entryOrderReq)
+	if !config.Force {
+		if err := s.checkAccountActivity(ctx, config); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.enforceMinNotional(ctx, &config); err != nil {
+		return nil, err
+	}
+
+	adjustment, err := s.checkQuoteBudget(&config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute entry order: %w", err)
+		return nil, err
+	}
+	if adjustment != nil {
+		// Уменьшенные уровни снова сверяются с минимальным ордером биржи
+		if err := s.enforceMinNotional(ctx, &config); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.checkFreeBalance(ctx, config); err != nil {
+		return nil, err
 	}
 
 	trade := &domain.Trade{
-		ID:            uuid.New(),
-		Symbol:        config.Symbol,
-		Config:        config,
-		EntryOrder:    entryOrder,
-		DCAOrders:     make([]domain.Order, 0),
-		Status:        domain.TradeStatusActive,
-		TotalInvested: config.EntryVolume,
-		AveragePrice:  entryOrder.Price,
-		CurrentPrice:  entryOrder.Price,
-		CreatedAt:     time.Now(),
-		UpdatedAt:     time.Now(),
+		ID:        uuid.New(),
+		Symbol:    config.Symbol,
+		Config:    config,
+		DCAOrders: make([]domain.Order, 0),
+		Cycle:     1,
+		BotID:     botID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+
+		BudgetAdjustment: adjustment,
 	}
+	span.SetAttributes(tracing.TradeID(trade.ID.String()))
 
-	if err := s.setupTakeProfitOrder(ctx, trade); err != nil {
+	if approval := s.approvalRequired(config); approval != nil {
+		s.requestApproval(ctx, trade, approval)
+		return trade, nil
 	}
 
-	if err := s.setupDCAOrders(ctx, trade); err != nil {
+	// Сделка видна сверке с момента исполнения входа, открытие идет под ее блокировкой
+	unlock, err := s.locker.Lock(ctx, trade.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock trade: %w", err)
 	}
+	defer unlock()
+
+	if err := s.startTrade(ctx, trade); err != nil {
+		return nil, err
+	}
+	if trade.EntryOrder != nil {
+		span.SetAttributes(tracing.OrderID(trade.EntryOrder.BybitID))
+	}
+	return trade, nil
+}
+
+// startTrade ставит сделку в ожидание условия старта или сразу выставляет вход и сетку.
+func (s *TradeService) startTrade(ctx context.Context, trade *domain.Trade) error {
+	config := trade.Config
+	if config.StartPrice != "" || s.outsidePriceBounds(ctx, config) {
+		s.queueTrade(trade)
+		return nil
+	}
+
+	if wantsBookSimulation(config) {
+		if err := s.checkBookSimulation(ctx, trade); err != nil {
+			return err
+		}
+	}
+
+	if err := s.openTrade(ctx, trade); err != nil {
+		return err
+	}
+	if trade.BudgetAdjustment != nil {
+		if entryPrice, err := domain.ParseDecimal(trade.EntryOrder.Price); err == nil {
+			trade.BudgetAdjustment.Grid = BuildGrid(trade.Config, entryPrice)
+		}
+	}
+	return nil
+}
+
+// openTrade выставляет вход, TP, SL и сетку для подготовленной сделки и регистрирует ее.
+func (s *TradeService) openTrade(ctx context.Context, trade *domain.Trade) error {
+	config := trade.Config
+
+	// Списки могли измениться, пока сделка ждала условия старта или следующего цикла
+	if err := s.riskManager.SymbolLists().Check(config.Symbol); err != nil {
+		return err
+	}
+	if err := s.riskManager.checkSuspended(); err != nil {
+		return err
+	}
+	if err := s.riskManager.Cooldowns().Check(config.Symbol); err != nil {
+		return err
+	}
+
+	entryOrderReq := domain.CreateOrderRequest{
+		Symbol:        config.Symbol,
+		Side:          entrySide(config),
+		Type:          domain.OrderTypeMarket,
+		Quantity:      config.EntryVolume,
+		QuoteQuantity: config.IsShort(),
+	}
+
+	entryOrder, err := s.executeEntryOrder(ctx, config, entryOrderReq)
+	if err != nil {
+		return fmt.Errorf("failed to execute entry order: %w", err)
+	}
+	if entryFailed(entryOrder) {
+		return fmt.Errorf("entry order %s is %s on the exchange", entryOrder.BybitID, entryOrder.Status)
+	}
+
+	// Вход исполнен: сделка сохраняется до TP и сетки, чтобы сбой дальше не оставил позицию
+	// без учета. Незавершенное открытие доводит сверка, см. completeOpen
+	trade.EntryOrder = entryOrder
+	trade.Status = domain.TradeStatusActive
+	trade.Opening = true
+	trade.UpdatedAt = time.Now()
 
 	s.mu.Lock()
 	s.trades[trade.ID] = trade
 	s.indexOrders(trade)
 	s.mu.Unlock()
 
-	return trade, nil
+	s.recordEvent(trade, domain.TradeEventEntryPlaced, entryOrder, "")
+
+	err = chaos.Inject(chaos.PointAfterEntry)
+	if err == nil {
+		err = s.completeOpenWithRetry(ctx, trade)
+	}
+	if err != nil && trade.Opening {
+		// Позиция уже набрана: ошибка не отменяет сделку, открытие доведет сверка
+		log.Printf("Trade %s on %s opened without take profit or grid, left for reconciliation: %v", trade.ID, trade.Symbol, err)
+		notification := notify.Localized(notify.LevelWarning, "Trade open incomplete",
+			"Trade %s on %s entered the position but failed to place its orders: %v", trade.ID, trade.Symbol, err)
+		if err := s.notifier.Notify(ctx, notification); err != nil {
+			log.Printf("Failed to notify about incomplete open of trade %s: %v", trade.ID, err)
+		}
+		return nil
+	}
+	return err
+}
+
+// completeOpen доводит открытие после исполнения входа: цена входа, TP, SL и сетка.
+// Повторный вызов после сбоя выставляет только то, чего у сделки еще нет. Если вход
+// не дал позиции, сделка переводится в FAILED.
+func (s *TradeService) completeOpen(ctx context.Context, trade *domain.Trade) error {
+	entryOrder := trade.EntryOrder
+
+	// Учет входа делается один раз: средняя цена появляется только после него
+	if trade.AveragePrice == "" {
+		// Цена рыночного входа нужна до построения TP и сетки
+		if err := s.ordersFor(trade.Config).ResolveFillPrice(ctx, entryOrder); err != nil {
+			return err
+		}
+		if entryFailed(entryOrder) {
+			s.failOpen(trade)
+			return fmt.Errorf("entry order %s is %s on the exchange", entryOrder.BybitID, entryOrder.Status)
+		}
+
+		config := trade.Config
+		if isPartiallyFilled(entryOrder) {
+			var err error
+			config, err = s.handlePartialEntry(ctx, config, entryOrder)
+			if err != nil {
+				// Позиция возвращена или не набрана - сделки нет
+				if partialFillPolicy(trade.Config) == domain.PartialFillPolicyAbort {
+					s.failOpen(trade)
+				}
+				return err
+			}
+		}
+
+		trade.Config = config
+		recordFill(trade, entryOrder)
+		trade.AveragePrice = entryOrder.Price
+		trade.CurrentPrice = entryOrder.Price
+		trade.UpdatedAt = time.Now()
+
+		if entryPrice, err := domain.ParseDecimal(entryOrder.Price); err == nil {
+			trade.Risk = s.riskManager.AssessTrade(ctx, config, entryPrice)
+		}
+	}
+
+	if err := chaos.Inject(chaos.PointBeforeTakeProfit); err != nil {
+		return err
+	}
+
+	// Пропавший TP после открытия выставит сверка
+	if trade.TakeProfitOrder == nil {
+		if err := s.setupTakeProfitOrder(ctx, trade); err != nil {
+			log.Printf("Failed to set up take profit of trade %s: %v", trade.ID, err)
+		}
+	}
+
+	if trade.StopLossOrder == nil {
+		if err := s.setupStopLossOrder(ctx, trade); err != nil {
+			log.Printf("Failed to set up stop loss of trade %s: %v", trade.ID, err)
+		}
+	}
+
+	if err := chaos.Inject(chaos.PointBeforeDCAGrid); err != nil {
+		return err
+	}
+
+	switch {
+	case isTimeBased(trade):
+		if trade.NextBuyAt == nil {
+			s.scheduleNextBuy(trade, trade.CreatedAt)
+		}
+	case len(trade.DCAOrders) == 0:
+		if err := s.setupDCAOrders(ctx, trade); err != nil {
+			log.Printf("Failed to set up DCA grid of trade %s: %v", trade.ID, err)
+		}
+	}
+
+	s.mu.Lock()
+	trade.Opening = false
+	trade.UpdatedAt = time.Now()
+	s.indexOrders(trade)
+	s.mu.Unlock()
+
+	s.recordEvent(trade, domain.TradeEventOpened, entryOrder, "")
+	s.metrics.IncCounter("trades_opened_total", metrics.Labels{"strategy": string(trade.Config.Strategy)})
+
+	return nil
+}
+
+// completeOpenWithRetry повторяет completeOpen с растущей паузой, пока открытие не завершено.
+// Внедренный сбой имитирует падение процесса и не повторяется.
+func (s *TradeService) completeOpenWithRetry(ctx context.Context, trade *domain.Trade) error {
+	delay := openRetryBaseDelay
+	for attempt := 1; ; attempt++ {
+		err := s.completeOpen(ctx, trade)
+		if err == nil || !trade.Opening || attempt == openRetryAttempts || errors.Is(err, chaos.ErrInjected) {
+			return err
+		}
+
+		log.Printf("Failed to complete open of trade %s (attempt %d/%d), retrying in %s: %v", trade.ID, attempt, openRetryAttempts, delay, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// entryFailed - вход закрыт биржей без позиции.
+func entryFailed(order *domain.Order) bool {
+	switch order.Status.Normalize() {
+	case domain.OrderStatusRejected, domain.OrderStatusCanceled, domain.OrderStatusDeactivated:
+		return true
+	}
+	return false
+}
+
+// failOpen переводит в FAILED сделку, вход которой не дал позиции.
+func (s *TradeService) failOpen(trade *domain.Trade) {
+	s.mu.Lock()
+	trade.Status = domain.TradeStatusFailed
+	trade.Opening = false
+	trade.UpdatedAt = time.Now()
+	s.unindexOrders(trade)
+	s.mu.Unlock()
+
+	s.recordEvent(trade, domain.TradeEventFinalized, trade.EntryOrder, string(domain.TradeStatusFailed))
+}
+
+func (s *TradeService) PreviewTrade(ctx context.Context, config domain.TradeConfig) (*domain.TradePreview, error) {
+	if err := s.checkExchange(config.Exchange); err != nil {
+		return nil, err
+	}
+
+	if err := s.enforceMinNotional(ctx, &config); err != nil {
+		return nil, err
+	}
+
+	lastPrice, err := s.ordersFor(config).FetchLastPrice(ctx, config.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch entry price: %w", err)
+	}
+
+	entryPrice := domain.DecimalFromFloat(lastPrice)
+	grid := BuildGrid(config, entryPrice)
+
+	preview := &domain.TradePreview{
+		Config:          config,
+		EntryPrice:      domain.FormatDecimal(entryPrice),
+		TakeProfitPrice: domain.FormatDecimal(takeProfitPrice(config, entryPrice)),
+		BreakEvenPrice:  domain.FormatDecimal(breakEvenPrice(config, entryPrice)),
+		Grid:            grid,
+		Risk:            s.riskManager.assessGrid(ctx, config, grid),
+	}
+	if wantsBookSimulation(config) {
+		sim, err := s.simulateBook(ctx, config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to simulate entry against order book: %w", err)
+		}
+		preview.BookSimulation = sim
+	}
+	return preview, nil
+}
+
+// handlePartialEntry применяет PartialFillPolicy к частично исполненному входу. Вход задается
+// суммой в котируемой валюте, поэтому доля исполнения - набранная стоимость к этой сумме.
+func (s *TradeService) handlePartialEntry(ctx context.Context, config domain.TradeConfig, entryOrder *domain.Order) (domain.TradeConfig, error) {
+	requested, err := domain.ParseDecimal(entryOrder.Quantity)
+	if err != nil {
+		return config, fmt.Errorf("invalid entry quantity: %w", err)
+	}
+	executed, err := domain.ParseDecimal(entryOrder.ExecutedQty)
+	if err != nil {
+		return config, fmt.Errorf("invalid entry executed quantity: %w", err)
+	}
+	filled := domain.DecimalFromFloat(orderValue(entryOrder))
+	if !requested.IsPositive() || filled.GreaterThanOrEqual(requested) {
+		return config, nil
+	}
+
+	policy := partialFillPolicy(config)
+	switch policy {
+	case domain.PartialFillPolicyResubmit:
+		remainderReq := domain.CreateOrderRequest{
+			Symbol:        config.Symbol,
+			Side:          entrySide(config),
+			Type:          domain.OrderTypeMarket,
+			Quantity:      domain.FormatDecimal(requested.Sub(filled)),
+			QuoteQuantity: config.IsShort(),
+		}
+		remainder, err := s.executeEntryOrder(ctx, config, remainderReq)
+		if err != nil {
+			return config, fmt.Errorf("failed to resubmit entry remainder: %w", err)
+		}
+		// Ответ на создание не содержит исполнения: его приносит статус ордера
+		if err := s.ordersFor(config).ResolveFillPrice(ctx, remainder); err != nil {
+			log.Printf("Failed to resolve fill of entry remainder %s: %v", remainder.BybitID, err)
+		}
+		remainderExecuted, _ := domain.ParseDecimal(remainder.ExecutedQty)
+		filled = filled.Add(domain.DecimalFromFloat(orderValue(remainder)))
+		entryOrder.ExecutedValue = domain.FormatDecimal(filled)
+		entryOrder.ExecutedQty = domain.FormatDecimal(executed.Add(remainderExecuted))
+		if filled.GreaterThanOrEqual(requested) {
+			entryOrder.Status = domain.OrderStatusFilled
+			return config, nil
+		}
+		return scaleConfigToFill(config, filled.Div(requested)), nil
+
+	case domain.PartialFillPolicyFilledOnly:
+		return scaleConfigToFill(config, filled.Div(requested)), nil
+
+	case domain.PartialFillPolicyAbort:
+		if executed.IsPositive() {
+			// Возврат при отмене сделки всегда по рынку, в том числе в режиме MakerOnly
+			refundReq := domain.CreateOrderRequest{
+				Symbol:   config.Symbol,
+				Side:     exitSide(config),
+				Type:     domain.OrderTypeMarket,
+				Quantity: entryOrder.ExecutedQty,
+			}
+			if config.IsShort() {
+				// Рыночная покупка задается суммой в котируемой валюте
+				refundReq.Quantity = fmt.Sprintf("%.8f", orderValue(entryOrder))
+			}
+			if _, err := s.ordersFor(config).ExecuteMarketOrder(ctx, refundReq); err != nil {
+				return config, fmt.Errorf("entry partially filled, failed to refund %s: %w", entryOrder.ExecutedQty, err)
+			}
+		}
+		return config, fmt.Errorf("entry order partially filled (%s of %s), trade aborted", entryOrder.ExecutedQty, entryOrder.Quantity)
+	}
+
+	return config, fmt.Errorf("unknown partial fill policy: %s", policy)
+}
+
+func partialFillPolicy(config domain.TradeConfig) domain.PartialFillPolicy {
+	if config.PartialFillPolicy == "" {
+		return domain.DefaultPartialFill
+	}
+	return config.PartialFillPolicy
+}
+
+// executeEntryOrder исполняет вход по рынку или, в режиме MakerOnly, мейкерской лимиткой с погоней.
+func (s *TradeService) executeEntryOrder(ctx context.Context, config domain.TradeConfig, req domain.CreateOrderRequest) (*domain.Order, error) {
+	if config.MakerOnly {
+		return s.ordersFor(config).ExecuteMakerOrder(ctx, req)
+	}
+	return s.ordersFor(config).ExecuteMarketOrder(ctx, req)
+}
+
+// scaleConfigToFill уменьшает объем входа до исполненной доли ratio. TP и SL считаются от
+// набранной позиции; уровни DCA сохраняют настроенный объем.
+func scaleConfigToFill(config domain.TradeConfig, ratio domain.Decimal) domain.TradeConfig {
+	entryVolume, _ := domain.ParseDecimal(config.EntryVolume)
+	config.EntryVolume = domain.FormatDecimal(entryVolume.Mul(ratio))
+	return config
+}
+
+func isPartiallyFilled(order *domain.Order) bool {
+	return order.Status.IsPartial()
 }
 
 func (s *TradeService) setupTakeProfitOrder(ctx context.Context, trade *domain.Trade) error {
-	entryPrice, err := strconv.ParseFloat(trade.EntryOrder.Price, 64)
+	entryPrice, err := domain.ParseDecimal(trade.EntryOrder.Price)
 	if err != nil {
 		return fmt.Errorf("invalid entry price: %w", err)
 	}
 
-	tpPrice := entryPrice * (1 + trade.Config.TakeProfitPercent/100)
-	tpPriceStr := fmt.Sprintf("%.8f", tpPrice)
+	tpPrice := takeProfitPrice(trade.Config, entryPrice)
+	tpPriceStr := domain.FormatDecimal(tpPrice)
 
-	totalVolume := trade.Config.EntryVolume
-	if trade.Config.Martingale > 0 {
-		for i := 0; i < trade.Config.DCACount; i++ {
-			dcaVolume, _ := strconv.ParseFloat(trade.Config.DCAVolume, 64)
-			totalVolumeFloat, _ := strconv.ParseFloat(totalVolume, 64)
-			totalVolumeFloat += dcaVolume * trade.Config.Martingale
-			totalVolume = fmt.Sprintf("%.8f", totalVolumeFloat)
-		}
+	amount, err := takeProfitAmount(trade, tpPrice)
+	if err != nil {
+		return err
 	}
 
 	tpOrderReq := domain.CreateOrderRequest{
 		Symbol:   trade.Config.Symbol,
-		Side:     domain.OrderSideSell,
+		Side:     exitSide(trade.Config),
 		Type:     domain.OrderTypeLimit,
-		Quantity: totalVolume,
+		Quantity: amount,
 		Price:    tpPriceStr,
+		PostOnly: trade.Config.MakerOnly,
 	}
 
-	tpOrder, err := s.orderManager.ExecuteLimitOrder(ctx, tpOrderReq)
+	tpOrder, err := s.ordersFor(trade.Config).ExecuteLimitOrder(ctx, tpOrderReq)
 	if err != nil {
 		return fmt.Errorf("failed to create take profit order: %w", err)
 	}
@@ -104,47 +519,46 @@ func (s *TradeService) setupTakeProfitOrder(ctx context.Context, trade *domain.T
 	return nil
 }
 
+// takeProfitAmount - сумма TP в котируемой валюте по цене tpPrice: TP закрывает набранную
+// позицию за вычетом комиссий. Неисполненные уровни DCA в TP не входят - продать монеты,
+// которых еще нет, спот не даст; TP переставляется после каждого исполнения DCA.
+func takeProfitAmount(trade *domain.Trade, tpPrice domain.Decimal) (string, error) {
+	position, err := domain.ParseDecimal(trade.CurrentPositionQty)
+	if err != nil || !position.IsPositive() {
+		return "", fmt.Errorf("trade %s has no position to take profit on", trade.ID)
+	}
+	return domain.FormatDecimal(position.Mul(tpPrice)), nil
+}
+
 func (s *TradeService) setupDCAOrders(ctx context.Context, trade *domain.Trade) error {
-	entryPrice, err := strconv.ParseFloat(trade.EntryOrder.Price, 64)
+	entryPrice, err := domain.ParseDecimal(trade.EntryOrder.Price)
 	if err != nil {
 		return fmt.Errorf("invalid entry price: %w", err)
 	}
 
-	currentPrice := entryPrice
-	currentVolume := trade.Config.DCAVolume
-
-	for i := 0; i < trade.Config.DCACount; i++ {
-		if trade.Config.DynamicStep {
-			stepPercent := trade.Config.DCAStepPercent * float64(i+1)
-			dcaPrice := currentPrice * (1 - stepPercent/100)
-			currentPrice = dcaPrice
-		} else {
-			dcaPrice := currentPrice * (1 - trade.Config.DCAStepPercent/100)
-			currentPrice = dcaPrice
-		}
-
-		dcaPriceStr := fmt.Sprintf("%.8f", currentPrice)
-
-		if trade.Config.Martingale > 0 {
-			volumeFloat, _ := strconv.ParseFloat(currentVolume, 64)
-			volumeFloat *= trade.Config.Martingale
-			currentVolume = fmt.Sprintf("%.8f", volumeFloat)
+	trade.PausedLevels = 0
+	for _, level := range BuildGrid(trade.Config, entryPrice) {
+		if price, err := domain.ParseDecimal(level.Price); err == nil && !withinPriceBounds(trade.Config, price) {
+			trade.PausedLevels++
+			continue
 		}
 
 		dcaOrderReq := domain.CreateOrderRequest{
 			Symbol:   trade.Config.Symbol,
-			Side:     domain.OrderSideBuy,
+			Side:     entrySide(trade.Config),
 			Type:     domain.OrderTypeLimit,
-			Quantity: currentVolume,
-			Price:    dcaPriceStr,
+			Quantity: level.Volume,
+			Price:    level.Price,
+			PostOnly: trade.Config.MakerOnly,
 		}
 
-		dcaOrder, err := s.orderManager.ExecuteLimitOrder(ctx, dcaOrderReq)
+		dcaOrder, err := s.ordersFor(trade.Config).ExecuteLimitOrder(ctx, dcaOrderReq)
 		if err != nil {
 			continue
 		}
 
-		trade.DCAOrders = append(trade.DCAOrders, *dcaOrder)
+		stampExpiry(trade, dcaOrder)
+		s.appendDCAOrder(trade, *dcaOrder)
 	}
 
 	return nil
@@ -159,6 +573,10 @@ func (s *TradeService) indexOrders(trade *domain.Trade) {
 		s.orderIndex[trade.TakeProfitOrder.BybitID] = trade.ID
 	}
 
+	if trade.StopLossOrder != nil {
+		s.orderIndex[trade.StopLossOrder.BybitID] = trade.ID
+	}
+
 	for _, dcaOrder := range trade.DCAOrders {
 		s.orderIndex[dcaOrder.BybitID] = trade.ID
 	}
@@ -173,11 +591,29 @@ func (s *TradeService) unindexOrders(trade *domain.Trade) {
 		delete(s.orderIndex, trade.TakeProfitOrder.BybitID)
 	}
 
+	if trade.StopLossOrder != nil {
+		delete(s.orderIndex, trade.StopLossOrder.BybitID)
+	}
+
 	for _, dcaOrder := range trade.DCAOrders {
 		delete(s.orderIndex, dcaOrder.BybitID)
 	}
 }
 
+// tradeOrderIDs - ID всех ордеров сделки; вызывается под mu.
+func tradeOrderIDs(trade *domain.Trade) []string {
+	ids := make([]string, 0, len(trade.DCAOrders)+3)
+	for _, order := range []*domain.Order{trade.EntryOrder, trade.TakeProfitOrder, trade.StopLossOrder} {
+		if order != nil {
+			ids = append(ids, order.BybitID)
+		}
+	}
+	for _, dcaOrder := range trade.DCAOrders {
+		ids = append(ids, dcaOrder.BybitID)
+	}
+	return ids
+}
+
 func (s *TradeService) FindTradeByOrderID(orderID string) (*domain.Trade, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -195,7 +631,89 @@ func (s *TradeService) FindTradeByOrderID(orderID string) (*domain.Trade, error)
 	return trade, nil
 }
 
-func (s *TradeService) ProcessOrderExecution(ctx context.Context, tradeID uuid.UUID, orderID string) error {
+// ApplyOrderUpdate переносит данные об исполнении из события биржи в кэш состояния ордеров.
+func (s *TradeService) ApplyOrderUpdate(update domain.OrderUpdate) {
+	trade, err := s.FindTradeByOrderID(update.OrderID)
+	if err != nil {
+		return
+	}
+
+	order := findTradeOrder(trade, update.OrderID)
+	if order == nil {
+		return
+	}
+
+	updated := *order
+	// Неизвестный статус не затирает последнее известное состояние
+	if update.Status != domain.OrderStatusUnknown {
+		updated.Status = update.Status
+	}
+	if update.ExecutedQty != "" && update.ExecutedQty != order.ExecutedQty {
+		updated.ExecutedQty = update.ExecutedQty
+		// Сумма прежнего исполнения к новому объему не относится: без суммы от биржи
+		// она считается по средней цене
+		updated.ExecutedValue = update.ExecutedValue
+		if update.Fee != "" {
+			updated.Fee = update.Fee
+		}
+	}
+	if update.Price != "" {
+		updated.Price = update.Price
+	}
+	updated.UpdatedAt = time.Now()
+	if at, ok := s.ordersFor(trade.Config).exchangeTime(update.Timestamp); ok {
+		updated.UpdatedAt = at
+	}
+
+	s.ordersFor(trade.Config).UpdateCachedOrder(updated)
+}
+
+func findTradeOrder(trade *domain.Trade, orderID string) *domain.Order {
+	if trade.EntryOrder != nil && trade.EntryOrder.BybitID == orderID {
+		return trade.EntryOrder
+	}
+	if trade.TakeProfitOrder != nil && trade.TakeProfitOrder.BybitID == orderID {
+		return trade.TakeProfitOrder
+	}
+	if trade.StopLossOrder != nil && trade.StopLossOrder.BybitID == orderID {
+		return trade.StopLossOrder
+	}
+	for i := range trade.DCAOrders {
+		if trade.DCAOrders[i].BybitID == orderID {
+			return &trade.DCAOrders[i]
+		}
+	}
+	return nil
+}
+
+// ProcessOrderExecution применяет исполнение ордера к сделке. Исполнение с уже обработанным
+// execID (повторная доставка после переподключения) пропускается; пустой execID не проверяется.
+func (s *TradeService) ProcessOrderExecution(ctx context.Context, tradeID uuid.UUID, orderID, execID string) (err error) {
+	ctx, span := tracing.Start(ctx, "TradeService.ProcessOrderExecution", tracing.TradeID(tradeID.String()), tracing.OrderID(orderID))
+	defer func() { tracing.End(span, err) }()
+
+	unlock, err := s.locker.Lock(ctx, tradeID)
+	if err != nil {
+		return fmt.Errorf("failed to lock trade: %w", err)
+	}
+	defer unlock()
+
+	if execID != "" {
+		if seen, err := s.executions.Seen(execID); err == nil && seen {
+			s.metrics.IncCounter("executions_duplicate_total", nil)
+			return nil
+		}
+		// Запоминаем только успешно примененные исполнения, чтобы повторная доставка после ошибки прошла
+		defer func() {
+			if err != nil {
+				return
+			}
+			if err := s.executions.Mark(execID, time.Now()); err != nil {
+				log.Printf("Failed to remember execution %s of trade %s: %v", execID, tradeID, err)
+			}
+		}()
+	}
+
 	s.mu.Lock()
 	trade, exists := s.trades[tradeID]
 	s.mu.Unlock()
@@ -203,13 +721,33 @@ func (s *TradeService) ProcessOrderExecution(ctx context.Context, tradeID uuid.U
 	if !exists {
 		return fmt.Errorf("trade not found: %s", tradeID)
 	}
+	if err := s.syncTrade(trade); err != nil {
+		return err
+	}
+
+	// Повторное уведомление об уже обработанном исполнении ничего не меняет. На паузе
+	// исполняются только ордера, которые не удалось снять
+	if trade.Status != domain.TradeStatusActive && trade.Status != domain.TradeStatusPaused {
+		return nil
+	}
+
+	s.mu.Lock()
+	s.lastFillAt = time.Now()
+	s.mu.Unlock()
 
 	if trade.TakeProfitOrder != nil && trade.TakeProfitOrder.BybitID == orderID {
-		return s.finalizeTrade(ctx, tradeID, domain.TradeStatusCompleted)
+		return s.handleExitExecution(ctx, trade, trade.TakeProfitOrder, domain.TradeStatusCompleted)
+	}
+
+	if trade.StopLossOrder != nil && trade.StopLossOrder.BybitID == orderID {
+		return s.handleExitExecution(ctx, trade, trade.StopLossOrder, domain.TradeStatusStopped)
 	}
 
 	for i, dcaOrder := range trade.DCAOrders {
 		if dcaOrder.BybitID == orderID {
+			if dcaOrder.Status.ClosedWithFills() {
+				return nil
+			}
 			return s.handleDCAExecution(ctx, trade, i)
 		}
 	}
@@ -218,97 +756,185 @@ func (s *TradeService) ProcessOrderExecution(ctx context.Context, tradeID uuid.U
 }
 
 func (s *TradeService) handleDCAExecution(ctx context.Context, trade *domain.Trade, dcaOrderIndex int) error {
-	dcaOrder := &trade.DCAOrders[dcaOrderIndex]
+	dcaOrder := trade.DCAOrders[dcaOrderIndex]
 
-	updatedOrder, err := s.orderManager.FetchOrderStatus(ctx, dcaOrder.Symbol, dcaOrder.BybitID)
+	updatedOrder, err := s.ordersFor(trade.Config).CachedOrderStatus(ctx, dcaOrder.Symbol, dcaOrder.BybitID)
 	if err != nil {
 		return fmt.Errorf("failed to get updated DCA order status: %w", err)
 	}
+	if !updatedOrder.Status.ClosedWithFills() && !updatedOrder.Status.IsPartial() {
+		return fmt.Errorf("DCA order %s is %s, not filled", dcaOrder.BybitID, updatedOrder.Status)
+	}
 
-	trade.DCAOrders[dcaOrderIndex] = *updatedOrder
+	// Частично исполненный ордер обрабатывается при каждом исполнении: в позицию идет
+	// только приращение к уже учтенному объему
+	fill, filled := fillDelta(&dcaOrder, updatedOrder)
+	s.setDCAOrder(trade, dcaOrderIndex, *updatedOrder)
+	if !filled {
+		return nil
+	}
+	recordFill(trade, &fill)
 
-	if err := s.updateTakeProfitOrder(ctx, trade); err != nil {
+	if updatedOrder.Status.ClosedWithFills() {
+		s.recordEvent(trade, domain.TradeEventDCAFilled, updatedOrder, "")
+	} else {
+		s.recordEvent(trade, domain.TradeEventDCAPartial, updatedOrder, fmt.Sprintf("%s of %s filled", updatedOrder.ExecutedQty, updatedOrder.Quantity))
+	}
+
+	if err := chaos.Inject(chaos.PointAfterDCAFill); err != nil {
+		return err
 	}
 
+	// На паузе TP не выставляется: его выставит ResumeTrade от итоговой средней цены
+	if trade.Status == domain.TradeStatusPaused {
+		trade.UpdatedAt = time.Now()
+		return nil
+	}
+
+	// Исполнение уже учтено; TP без замены найдет и переставит сверка
 	trade.UpdatedAt = time.Now()
+	if err := s.updateTakeProfitOrder(ctx, trade); err != nil {
+		return fmt.Errorf("failed to update take profit order: %w", err)
+	}
 	return nil
 }
 
 func (s *TradeService) updateTakeProfitOrder(ctx context.Context, trade *domain.Trade) error {
-	if trade.TakeProfitOrder != nil {
-		if err := s.orderManager.TerminateOrder(ctx, trade.Symbol, trade.TakeProfitOrder.BybitID); err != nil {
-		}
+	if err := chaos.Inject(chaos.PointBeforeTPReplace); err != nil {
+		return err
 	}
 
-	newAveragePrice, totalVolume, err := s.calculateNewAveragePrice(trade)
+	newAveragePrice, _, err := s.calculateNewAveragePrice(trade)
 	if err != nil {
 		return fmt.Errorf("failed to calculate new average price: %w", err)
 	}
 
-	tpPrice := newAveragePrice * (1 + trade.Config.TakeProfitPercent/100)
-	tpPriceStr := fmt.Sprintf("%.8f", tpPrice)
+	tpPrice, deferred, note := s.guardTakeProfitPrice(ctx, trade, takeProfitPrice(trade.Config, newAveragePrice))
+	if deferred {
+		// Старый TP остается на месте до возврата цены или истечения отсрочки
+		s.deferTakeProfit(ctx, trade, note)
+		return nil
+	}
+
+	// Объем TP - чистая позиция: комиссии в базовой монете уже удержаны из исполнений
+	amount, err := takeProfitAmount(trade, tpPrice)
+	if err != nil {
+		return err
+	}
+
+	if err := s.cancelTakeProfit(ctx, trade); err != nil {
+		return err
+	}
+
+	tpPriceStr := domain.FormatDecimal(tpPrice)
 
 	tpOrderReq := domain.CreateOrderRequest{
 		Symbol:   trade.Config.Symbol,
-		Side:     domain.OrderSideSell,
+		Side:     exitSide(trade.Config),
 		Type:     domain.OrderTypeLimit,
-		Quantity: totalVolume,
+		Quantity: amount,
 		Price:    tpPriceStr,
+		PostOnly: trade.Config.MakerOnly,
 	}
 
-	tpOrder, err := s.orderManager.ExecuteLimitOrder(ctx, tpOrderReq)
+	tpOrder, err := s.ordersFor(trade.Config).ExecuteLimitOrder(ctx, tpOrderReq)
 	if err != nil {
+		// Старый TP уже снят: сделка остается без TP, сверка выставит его заново
+		if cancelled := trade.TakeProfitOrder; cancelled != nil {
+			s.mu.Lock()
+			trade.TakeProfitOrder = nil
+			s.mu.Unlock()
+			s.recordEvent(trade, domain.TradeEventTPCancelled, cancelled, err.Error())
+		}
 		return fmt.Errorf("failed to create new take profit order: %w", err)
 	}
 
 	trade.TakeProfitOrder = tpOrder
-	trade.AveragePrice = fmt.Sprintf("%.8f", newAveragePrice)
+	trade.AveragePrice = domain.FormatDecimal(newAveragePrice)
+	trade.TPDeferredAt = nil
+	s.recordEvent(trade, domain.TradeEventTPReplaced, tpOrder, note)
+
+	if err := s.replaceStopLossOrder(ctx, trade); err != nil {
+		log.Printf("Failed to replace stop loss of trade %s: %v", trade.ID, err)
+	}
+
+	s.mu.Lock()
+	s.indexOrders(trade)
+	s.mu.Unlock()
 	return nil
 }
 
-func (s *TradeService) calculateNewAveragePrice(trade *domain.Trade) (float64, string, error) {
-	totalVolume := 0.0
-	totalCost := 0.0
-
-	entryVolume, err := strconv.ParseFloat(trade.EntryOrder.Quantity, 64)
-	if err != nil {
-		return 0, "", fmt.Errorf("invalid entry volume: %w", err)
+// cancelTakeProfit снимает текущий TP перед выставлением нового: на споте монеты заблокированы
+// старым ордером. Неудачная отмена допустима, только если старый TP уже закрыт биржей без
+// исполнения - иначе новый ордер стал бы вторым выходом на те же монеты.
+func (s *TradeService) cancelTakeProfit(ctx context.Context, trade *domain.Trade) error {
+	if trade.TakeProfitOrder == nil {
+		return nil
 	}
-	entryPrice, err := strconv.ParseFloat(trade.EntryOrder.Price, 64)
-	if err != nil {
-		return 0, "", fmt.Errorf("invalid entry price: %w", err)
+
+	orders := s.ordersFor(trade.Config)
+	orderID := trade.TakeProfitOrder.BybitID
+	cancelErr := orders.TerminateOrder(ctx, trade.Symbol, orderID)
+	if cancelErr == nil {
+		return nil
 	}
 
-	totalVolume += entryVolume
-	totalCost += entryVolume * entryPrice
+	current, err := orders.FetchOrderStatus(ctx, trade.Symbol, orderID)
+	if err == nil && current.Status.IsTerminal() && !current.Status.ClosedWithFills() {
+		return nil
+	}
 
-	for _, dcaOrder := range trade.DCAOrders {
-		if dcaOrder.Status == domain.OrderStatusFilled {
-			dcaVolume, err := strconv.ParseFloat(dcaOrder.ExecutedQty, 64)
-			if err != nil {
-				continue
-			}
-			dcaPrice, err := strconv.ParseFloat(dcaOrder.Price, 64)
-			if err != nil {
-				continue
-			}
+	// TP еще стоит, исполнился или его статус неизвестен - исполнение обработает поток
+	// ордеров, а замену повторит сверка
+	s.recordCancelFailure(trade, trade.TakeProfitOrder, cancelErr)
+	return fmt.Errorf("failed to cancel take profit order %s: %w", orderID, cancelErr)
+}
 
-			totalVolume += dcaVolume
-			totalCost += dcaVolume * dcaPrice
+func (s *TradeService) calculateNewAveragePrice(trade *domain.Trade) (domain.Decimal, string, error) {
+	// Объем входа - фактически исполненный; у рыночной покупки Quantity задан в котируемой валюте
+	entryVolume, err := domain.ParseDecimal(trade.EntryOrder.ExecutedQty)
+	if err != nil || !entryVolume.IsPositive() {
+		entryVolume, err = domain.ParseDecimal(trade.EntryOrder.Quantity)
+		if err != nil {
+			return domain.DecimalZero, "", fmt.Errorf("invalid entry volume: %w", err)
 		}
 	}
-
-	if totalVolume == 0 {
-		return 0, "", fmt.Errorf("total volume is zero")
+	entryPrice, err := domain.ParseDecimal(trade.EntryOrder.Price)
+	if err != nil {
+		return domain.DecimalZero, "", fmt.Errorf("invalid entry price: %w", err)
 	}
 
-	averagePrice := totalCost / totalVolume
-	totalVolumeStr := fmt.Sprintf("%.8f", totalVolume)
+	fills := []pricing.Fill{{Price: entryPrice, Quantity: entryVolume}}
+	// Учитываются все исполнения DCA, включая частичные у ордеров, которые еще стоят
+	for _, dcaOrder := range trade.DCAOrders {
+		dcaVolume, err := domain.ParseDecimal(dcaOrder.ExecutedQty)
+		if err != nil || !dcaVolume.IsPositive() {
+			continue
+		}
+		dcaPrice, err := domain.ParseDecimal(dcaOrder.Price)
+		// Средняя цена исполнения точнее лимитной цены ордера
+		if value, valueErr := domain.ParseDecimal(dcaOrder.ExecutedValue); valueErr == nil && value.IsPositive() {
+			dcaPrice, err = value.Div(dcaVolume), nil
+		}
+		if err != nil {
+			continue
+		}
 
-	return averagePrice, totalVolumeStr, nil
+		fills = append(fills, pricing.Fill{Price: dcaPrice, Quantity: dcaVolume})
+	}
+
+	averagePrice, totalVolume, err := pricing.AveragePrice(fills)
+	if err != nil {
+		return domain.DecimalZero, "", err
+	}
+	return averagePrice, domain.FormatDecimal(totalVolume), nil
 }
 
-func (s *TradeService) finalizeTrade(ctx context.Context, tradeID uuid.UUID, status domain.TradeStatus) error {
+func (s *TradeService) finalizeTrade(ctx context.Context, tradeID uuid.UUID, status domain.TradeStatus, filledOrderID string) error {
+	if err := chaos.Inject(chaos.PointBeforeFinalize); err != nil {
+		return err
+	}
+
 	s.mu.Lock()
 	trade, exists := s.trades[tradeID]
 	if !exists {
@@ -318,17 +944,53 @@ func (s *TradeService) finalizeTrade(ctx context.Context, tradeID uuid.UUID, sta
 
 	trade.Status = status
 	trade.UpdatedAt = time.Now()
+	closedAt := trade.UpdatedAt
+	// Время закрытия - исполнение выхода на бирже, а не момент обработки
+	if exit := exitOrder(trade, filledOrderID); exit != nil && !exit.UpdatedAt.IsZero() && exit.UpdatedAt.Before(closedAt) {
+		closedAt = exit.UpdatedAt
+	}
+	settlePnL(trade, closedAt)
 
 	s.unindexOrders(trade)
+	orderIDs := tradeOrderIDs(trade)
 	s.mu.Unlock()
 
-	for _, dcaOrder := range trade.DCAOrders {
-		if dcaOrder.Status == domain.OrderStatusNew {
-			if err := s.orderManager.TerminateOrder(ctx, dcaOrder.Symbol, dcaOrder.BybitID); err != nil {
-			}
-		}
+	// Поздние события ордеров закрытой сделки не обрабатываются, кэш их состояния не нужен
+	s.ordersFor(trade.Config).ForgetCachedOrders(orderIDs...)
+
+	if status == domain.TradeStatusStopped {
+		s.startCooldown(trade, closedAt)
 	}
 
+	s.recordEvent(trade, domain.TradeEventFinalized, exitOrder(trade, filledOrderID), string(status))
+	s.metrics.IncCounter("trades_finalized_total", metrics.Labels{"status": string(status)})
+	s.exportTrade(ctx, trade, filledOrderID)
+	for _, listener := range s.closed {
+		listener(ctx, trade)
+	}
+
+	// Новый цикл начнется после подтверждения отмен, см. retryTradeCancels
+	if s.cancelRemainingOrders(ctx, trade, status, filledOrderID) {
+		return nil
+	}
+
+	if trade.Config.AutoRestart {
+		s.completeCycle(ctx, trade)
+	}
+
+	return nil
+}
+
+// exitOrder - TP или SL, исполнение которого закрыло сделку; nil при закрытии без выхода.
+func exitOrder(trade *domain.Trade, filledOrderID string) *domain.Order {
+	if filledOrderID == "" {
+		return nil
+	}
+	for _, order := range []*domain.Order{trade.TakeProfitOrder, trade.StopLossOrder} {
+		if order != nil && order.BybitID == filledOrderID {
+			return order
+		}
+	}
 	return nil
 }
 
@@ -344,19 +1006,41 @@ func (s *TradeService) GetTrade(tradeID uuid.UUID) (*domain.Trade, error) {
 	return trade, nil
 }
 
-func (s *TradeService) GetAllTrades() map[uuid.UUID]*domain.Trade {
+func (s *TradeService) LastFillAt() time.Time {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	result := make(map[uuid.UUID]*domain.Trade)
-	for id, trade := range s.trades {
-		result[id] = trade
-	}
+	return s.lastFillAt
+}
+
+// StorageStatus возвращает тип хранилища сделок и результат проверки записи.
+func (s *TradeService) StorageStatus() (string, error) {
+	return s.repository.Driver(), s.repository.Ping()
+}
 
-	return result
+func (s *TradeService) CountActiveTrades() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	count := 0
+	for _, trade := range s.trades {
+		if trade.Status == domain.TradeStatusActive || trade.Status == domain.TradeStatusPaused {
+			count++
+		}
+	}
+	return count
 }
 
-func (s *TradeService) CloseTrade(ctx context.Context, tradeID uuid.UUID, reason string) error {
+func (s *TradeService) CloseTrade(ctx context.Context, tradeID uuid.UUID, reason string) (err error) {
+	ctx, span := tracing.Start(ctx, "TradeService.CloseTrade", tracing.TradeID(tradeID.String()))
+	defer func() { tracing.End(span, err) }()
+
+	unlock, err := s.locker.Lock(ctx, tradeID)
+	if err != nil {
+		return fmt.Errorf("failed to lock trade: %w", err)
+	}
+	defer unlock()
+
 	s.mu.RLock()
 	_, exists := s.trades[tradeID]
 	s.mu.RUnlock()
@@ -365,5 +1049,5 @@ func (s *TradeService) CloseTrade(ctx context.Context, tradeID uuid.UUID, reason
 		return fmt.Errorf("trade not found: %s", tradeID)
 	}
 
-	return s.finalizeTrade(ctx, tradeID, domain.TradeStatusCancelled)
+	return s.finalizeTrade(ctx, tradeID, domain.TradeStatusCancelled, "")
 }
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"cryptorg/internal/domain"
+	apperrors "cryptorg/pkg/errors"
+)
+
+// enforceMinNotional проверяет объемы входа и уровней против minOrderAmt символа.
+// Глубокие уровни мартингейла со слишком малым объемом биржа отклоняет, и сетка
+// молча сокращается. В режиме bump объемы поднимаются до минимума, иначе сделка отклоняется.
+func (s *TradeService) enforceMinNotional(ctx context.Context, config *domain.TradeConfig) error {
+	info, err := s.ordersFor(*config).InstrumentInfo(ctx, config.Symbol)
+	if err != nil {
+		return fmt.Errorf("failed to load instrument info: %w", err)
+	}
+
+	minAmount, _ := strconv.ParseFloat(info.MinOrderAmt, 64)
+	if minAmount <= 0 {
+		return nil
+	}
+	bump := config.MinNotionalPolicy == domain.MinNotionalPolicyBump
+	minVolume := strconv.FormatFloat(minAmount, 'f', -1, 64)
+
+	below := make([]string, 0)
+	if volume, _ := strconv.ParseFloat(config.EntryVolume, 64); volume < minAmount {
+		if bump {
+			config.EntryVolume = minVolume
+		} else {
+			below = append(below, "entry")
+		}
+	}
+
+	if config.Strategy == domain.StrategyTimeBased {
+		if volume, _ := strconv.ParseFloat(config.DCAVolume, 64); volume < minAmount {
+			if bump {
+				config.DCAVolume = minVolume
+			} else {
+				below = append(below, "scheduled buy")
+			}
+		}
+	} else {
+		// Объемы уровней не зависят от цены входа
+		for _, level := range BuildGrid(*config, domain.DecimalOne) {
+			if volume, _ := strconv.ParseFloat(level.Volume, 64); volume < minAmount {
+				below = append(below, fmt.Sprintf("level %d", level.Index))
+			}
+		}
+		if bump && len(below) > 0 {
+			config.MinLevelVolume = minVolume
+			below = below[:0]
+		}
+	}
+
+	if len(below) == 0 {
+		return nil
+	}
+
+	appErr := apperrors.DomainError(
+		fmt.Sprintf("%s volume is below the minimum order amount %s for %s", strings.Join(below, ", "), minVolume, config.Symbol),
+		"BELOW_MIN_NOTIONAL",
+	)
+	appErr.Details = map[string]interface{}{"symbol": config.Symbol, "min_order_amount": minVolume, "orders": below}
+	return appErr
+}
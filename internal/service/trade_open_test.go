@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"cryptorg/internal/bybit"
+	"cryptorg/internal/domain"
+	"cryptorg/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errPriceOutage = errors.New("price sources are down")
+
+// priceOutageExchange - фейковая биржа, у которой недоступны все источники цены исполнения.
+// Каждая попытка ResolveFillPrice заканчивается запросом тикера и расходует одну попытку сбоя.
+type priceOutageExchange struct {
+	*fakeExchange
+
+	mu       sync.Mutex
+	outages  int
+	resolves int
+}
+
+func (e *priceOutageExchange) down() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.outages > 0
+}
+
+func (e *priceOutageExchange) setOutages(n int) {
+	e.mu.Lock()
+	e.outages = n
+	e.mu.Unlock()
+}
+
+func (e *priceOutageExchange) FetchOrderInfo(ctx context.Context, symbol string, orderID string) (*bybit.ExchangeOrderResponse, error) {
+	if e.down() {
+		return nil, errPriceOutage
+	}
+	return e.fakeExchange.FetchOrderInfo(ctx, symbol, orderID)
+}
+
+func (e *priceOutageExchange) ListExecutions(ctx context.Context, symbol string, orderID string) ([]bybit.Execution, error) {
+	if e.down() {
+		return nil, errPriceOutage
+	}
+	return e.fakeExchange.ListExecutions(ctx, symbol, orderID)
+}
+
+func (e *priceOutageExchange) GetTicker(ctx context.Context, symbol string) (*bybit.Ticker, error) {
+	e.mu.Lock()
+	e.resolves++
+	if e.outages > 0 {
+		e.outages--
+		e.mu.Unlock()
+		return nil, errPriceOutage
+	}
+	e.mu.Unlock()
+	return e.fakeExchange.GetTicker(ctx, symbol)
+}
+
+func TestOpenRetriesFillPriceAfterEntry(t *testing.T) {
+	exchange := &priceOutageExchange{fakeExchange: newFakeExchange(map[string]float64{"BTCUSDT": 100})}
+	trades := newFakeTradeService(t, exchange, storage.NewMemoryJournal(), storage.NewMemoryTradeRepository())
+
+	exchange.setOutages(1)
+	trade, err := trades.InitializeTrade(context.Background(), testTradeConfig())
+	require.NoError(t, err)
+
+	// Первая попытка упала, повтор открыл сделку полностью
+	assert.False(t, trade.Opening)
+	assert.Equal(t, domain.TradeStatusActive, trade.Status)
+	assert.Equal(t, "100.00000000", trade.AveragePrice)
+	require.NotNil(t, trade.TakeProfitOrder)
+	assert.Len(t, trade.DCAOrders, 3)
+}
+
+// assertPlacedAs проверяет, что цена и количество ордера сделки совпадают с ордером на бирже:
+// фейковая биржа, как Bybit, отвечает на создание только идентификаторами.
+func assertPlacedAs(t *testing.T, exchange *fakeExchange, order *domain.Order) {
+	t.Helper()
+
+	placed, ok := exchange.order(order.BybitID)
+	require.True(t, ok, order.BybitID)
+	require.NotEmpty(t, order.Price)
+	require.NotEmpty(t, order.Quantity)
+	assert.InDelta(t, parseFake(placed.Price), parseFake(order.Price), 1e-8)
+	assert.InDelta(t, parseFake(placed.Qty), parseFake(order.Quantity), 1e-8)
+}
+
+func TestOpenRetryTakesOrderParamsFromRequests(t *testing.T) {
+	exchange := &priceOutageExchange{fakeExchange: newFakeExchange(map[string]float64{"BTCUSDT": 100})}
+	trades := newFakeTradeService(t, exchange, storage.NewMemoryJournal(), storage.NewMemoryTradeRepository())
+
+	config := testTradeConfig()
+	config.StopLossPercent = 5
+	exchange.setOutages(1)
+	trade, err := trades.InitializeTrade(context.Background(), config)
+	require.NoError(t, err)
+	require.False(t, trade.Opening)
+
+	require.NotNil(t, trade.TakeProfitOrder)
+	assertPlacedAs(t, exchange.fakeExchange, trade.TakeProfitOrder)
+	assert.InDelta(t, 101, parseFake(trade.TakeProfitOrder.Price), 1e-8)
+	require.NotNil(t, trade.StopLossOrder)
+	assertPlacedAs(t, exchange.fakeExchange, trade.StopLossOrder)
+	assert.InDelta(t, parseFake(trade.CurrentPositionQty), parseFake(trade.StopLossOrder.Quantity), 1e-8)
+
+	require.Len(t, trade.DCAOrders, 3)
+	for i := range trade.DCAOrders {
+		assertPlacedAs(t, exchange.fakeExchange, &trade.DCAOrders[i])
+	}
+}
+
+func TestOpenPersistsEntryWhenFillPriceIsUnavailable(t *testing.T) {
+	exchange := &priceOutageExchange{fakeExchange: newFakeExchange(map[string]float64{"BTCUSDT": 100})}
+	journal := storage.NewMemoryJournal()
+	repository := storage.NewMemoryTradeRepository()
+	trades := newFakeTradeService(t, exchange, journal, repository)
+
+	exchange.setOutages(openRetryAttempts)
+	trade, err := trades.InitializeTrade(context.Background(), testTradeConfig())
+	require.NoError(t, err)
+	assert.Equal(t, openRetryAttempts, exchange.resolves)
+
+	// Вход исполнен, но цены нет: сделка сохранена незавершенной, ордеров выхода и сетки нет
+	assert.True(t, trade.Opening)
+	assert.Empty(t, trade.AveragePrice)
+	assert.Nil(t, trade.TakeProfitOrder)
+	assert.Empty(t, trade.DCAOrders)
+
+	saved, err := repository.LoadActive()
+	require.NoError(t, err)
+	require.Len(t, saved, 1)
+	assert.True(t, saved[0].Opening)
+	assert.Equal(t, trade.EntryOrder.BybitID, saved[0].EntryOrder.BybitID)
+
+	events, err := journal.ReadAll()
+	require.NoError(t, err)
+	require.NotEmpty(t, events)
+	assert.Equal(t, domain.TradeEventEntryPlaced, events[len(events)-1].Type)
+
+	// Источники цены вернулись - сверка доводит открытие
+	report, err := trades.CheckConsistency(context.Background(), true)
+	require.NoError(t, err)
+	require.Len(t, report.Issues, 1)
+	assert.Equal(t, domain.IssueIncompleteOpen, report.Issues[0].Kind)
+	assert.True(t, report.Issues[0].Repaired, report.Issues[0].Error)
+
+	got, err := trades.GetTrade(trade.ID)
+	require.NoError(t, err)
+	assert.False(t, got.Opening)
+	assert.Equal(t, "100.00000000", got.AveragePrice)
+	require.NotNil(t, got.TakeProfitOrder)
+	assertPlacedAs(t, exchange.fakeExchange, got.TakeProfitOrder)
+	require.Len(t, got.DCAOrders, 3)
+	for i := range got.DCAOrders {
+		assertPlacedAs(t, exchange.fakeExchange, &got.DCAOrders[i])
+	}
+}
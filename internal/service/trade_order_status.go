@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"cryptorg/internal/domain"
+	"cryptorg/internal/metrics"
+)
+
+// handleExitExecution применяет исполнение TP или SL. Полное исполнение закрывает сделку.
+// Если биржа закрыла ордер с частичным исполнением (PartiallyFilledCanceled), исполненная
+// часть учитывается, а на остаток позиции заново выставляются TP по прежней цене и SL.
+func (s *TradeService) handleExitExecution(ctx context.Context, trade *domain.Trade, order *domain.Order, final domain.TradeStatus) error {
+	orderID := order.BybitID
+	held, _ := strconv.ParseFloat(trade.CurrentPositionQty, 64)
+	s.recordExitFill(ctx, trade, order)
+
+	remaining, _ := domain.ParseDecimal(trade.CurrentPositionQty)
+	if order.Status.Normalize() != domain.OrderStatusPartiallyCanceled || !remaining.IsPositive() {
+		if final == domain.TradeStatusCompleted {
+			s.queueCreditCheck(trade, *order, held)
+		}
+		return s.finalizeTrade(ctx, trade.ID, final, orderID)
+	}
+
+	tpPrice, err := domain.ParseDecimal(trade.TakeProfitOrder.Price)
+	if err != nil {
+		return fmt.Errorf("invalid take profit price: %w", err)
+	}
+
+	s.mu.Lock()
+	s.unindexOrders(trade)
+	s.mu.Unlock()
+
+	// Закрытый биржей ордер не отменяется; второй ордер пары снимается перед перестановкой
+	if trade.StopLossOrder != nil && trade.StopLossOrder.BybitID == orderID {
+		trade.StopLossOrder = nil
+		if err := s.ordersFor(trade.Config).TerminateOrder(ctx, trade.Symbol, trade.TakeProfitOrder.BybitID); err != nil {
+			s.recordCancelFailure(trade, trade.TakeProfitOrder, err)
+		}
+	}
+
+	s.metrics.IncCounter("exit_partial_cancels_total", metrics.Labels{"status": string(final)})
+	note := fmt.Sprintf("exit order %s closed with %s of %s filled, %s left in position", orderID, order.ExecutedQty, order.Quantity, domain.FormatDecimal(remaining))
+	return s.restoreTakeProfit(ctx, trade, remaining, tpPrice, note)
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"cryptorg/internal/bybit"
+	"cryptorg/internal/domain"
+	"cryptorg/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// partialEntryExchange - фейковая биржа, которая исполняет первый рыночный ордер только на
+// долю fraction и закрывает его как PartiallyFilledCanceled, как Bybit при тонкой книге.
+type partialEntryExchange struct {
+	*fakeExchange
+
+	fraction float64
+	done     bool
+}
+
+func (e *partialEntryExchange) ExecuteOrder(ctx context.Context, req bybit.ExchangeOrderRequest) (*bybit.ExchangeOrderResponse, error) {
+	resp, err := e.fakeExchange.ExecuteOrder(ctx, req)
+	if err != nil || e.done || req.OrderType != string(domain.OrderTypeMarket) {
+		return resp, err
+	}
+	e.done = true
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	order := e.orders[resp.OrderID]
+	order.ExecutedQty = formatFake(parseFake(order.ExecutedQty) * e.fraction)
+	order.ExecutedValue = formatFake(parseFake(order.ExecutedValue) * e.fraction)
+	order.Status = string(domain.OrderStatusBybitPartiallyFilledCanceled)
+	e.executions[order.OrderID][0].Qty = order.ExecutedQty
+	e.executions[order.OrderID][0].Value = order.ExecutedValue
+	return resp, nil
+}
+
+func TestPartialEntryScalesOnlyEntryVolume(t *testing.T) {
+	exchange := &partialEntryExchange{fakeExchange: newFakeExchange(map[string]float64{"BTCUSDT": 100}), fraction: 0.4}
+	trades := newFakeTradeService(t, exchange, storage.NewMemoryJournal(), storage.NewMemoryTradeRepository())
+
+	config := testTradeConfig()
+	config.PartialFillPolicy = domain.PartialFillPolicyFilledOnly
+	trade, err := trades.InitializeTrade(context.Background(), config)
+	require.NoError(t, err)
+
+	// 0.4 BTC на 40 из 100 USDT: доля считается по стоимости, а не монетами к сумме
+	assert.InDelta(t, 40, parseFake(trade.Config.EntryVolume), 1e-8)
+	assert.Equal(t, "100", trade.Config.DCAVolume)
+	assert.InDelta(t, 0.4, parseFake(trade.CurrentPositionQty), 1e-8)
+
+	require.Len(t, trade.DCAOrders, 3)
+	first, ok := exchange.order(trade.DCAOrders[0].BybitID)
+	require.True(t, ok)
+	assert.InDelta(t, 100, parseFake(first.Qty)*parseFake(first.Price), 1e-3)
+
+	require.NotNil(t, trade.TakeProfitOrder)
+	tp, ok := exchange.order(trade.TakeProfitOrder.BybitID)
+	require.True(t, ok)
+	assert.InDelta(t, 0.4, parseFake(tp.Qty), 1e-8)
+}
+
+func TestPartialEntryResubmitsQuoteRemainder(t *testing.T) {
+	exchange := &partialEntryExchange{fakeExchange: newFakeExchange(map[string]float64{"BTCUSDT": 100}), fraction: 0.4}
+	trades := newFakeTradeService(t, exchange, storage.NewMemoryJournal(), storage.NewMemoryTradeRepository())
+
+	config := testTradeConfig()
+	config.PartialFillPolicy = domain.PartialFillPolicyResubmit
+	trade, err := trades.InitializeTrade(context.Background(), config)
+	require.NoError(t, err)
+
+	// Остаток дозаявлен суммой 60 USDT, вход набран целиком
+	remainder, ok := exchange.order(exchange.placed[1])
+	require.True(t, ok)
+	assert.Equal(t, string(domain.OrderTypeMarket), remainder.OrderType)
+	assert.InDelta(t, 0.6, parseFake(remainder.ExecutedQty), 1e-8)
+
+	assert.Equal(t, domain.OrderStatusFilled, trade.EntryOrder.Status)
+	assert.Equal(t, "100", trade.Config.EntryVolume)
+	assert.InDelta(t, 1, parseFake(trade.CurrentPositionQty), 1e-8)
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"cryptorg/internal/domain"
+	apperrors "cryptorg/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// PauseTrade снимает TP и открытые DCA ордера активной сделки, оставляя позицию и стоп-лосс:
+// сетка не докупает на новостях, но позиция не продается. Исполнения, успевшие пройти до
+// отмены, учитываются в позиции. Если TP снять не удалось (мог исполниться), пауза не ставится.
+func (s *TradeService) PauseTrade(ctx context.Context, tradeID uuid.UUID, reason string) (*domain.Trade, error) {
+	unlock, err := s.locker.Lock(ctx, tradeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock trade: %w", err)
+	}
+	defer unlock()
+
+	s.mu.RLock()
+	trade, exists := s.trades[tradeID]
+	s.mu.RUnlock()
+	if !exists {
+		return nil, apperrors.NotFoundError("trade", tradeID.String())
+	}
+	if trade.Status != domain.TradeStatusActive {
+		return nil, apperrors.DomainError(fmt.Sprintf("trade %s is %s, only active trades can be paused", tradeID, trade.Status), "TRADE_NOT_ACTIVE")
+	}
+
+	if tp := trade.TakeProfitOrder; tp != nil {
+		if err := s.ordersFor(trade.Config).TerminateOrder(ctx, trade.Symbol, tp.BybitID); err != nil {
+			return nil, fmt.Errorf("failed to cancel take profit %s: %w", tp.BybitID, err)
+		}
+		s.settleCancelledOrder(ctx, trade, tp)
+	}
+
+	kept := make([]domain.Order, 0, len(trade.DCAOrders))
+	pausedGrid := make([]domain.Order, 0)
+	for _, order := range s.dcaOrders(trade) {
+		if !order.Status.IsOpen() {
+			kept = append(kept, order)
+			continue
+		}
+		if err := s.ordersFor(trade.Config).TerminateOrder(ctx, order.Symbol, order.BybitID); err != nil {
+			// Ордер остается в сетке: его исполнение обработается и на паузе
+			s.recordCancelFailure(trade, &order, err)
+			kept = append(kept, order)
+			continue
+		}
+		s.settleCancelledOrder(ctx, trade, &order)
+
+		remaining := remainingQty(order)
+		if executed, _ := domain.ParseDecimal(order.ExecutedQty); executed.IsPositive() {
+			kept = append(kept, order)
+		}
+		if remaining.IsPositive() {
+			level := order
+			level.Quantity = domain.FormatDecimal(remaining)
+			pausedGrid = append(pausedGrid, level)
+		}
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	s.unindexOrders(trade)
+	trade.TakeProfitOrder = nil
+	trade.DCAOrders = kept
+	trade.PausedGrid = pausedGrid
+	trade.Status = domain.TradeStatusPaused
+	trade.PausedAt = &now
+	trade.UpdatedAt = now
+	s.indexOrders(trade)
+	s.mu.Unlock()
+
+	message := fmt.Sprintf("%d DCA levels lifted: %s", len(pausedGrid), reason)
+	log.Printf("AUDIT: trade %s paused, %s", tradeID, message)
+	s.recordEvent(trade, domain.TradeEventPaused, nil, message)
+	return trade, nil
+}
+
+// ResumeTrade выставляет снятые паузой уровни DCA по прежним ценам и TP от текущей средней
+// цены позиции. Уровни за пределами MinPrice/MaxPrice не выставляются, как и при открытии.
+func (s *TradeService) ResumeTrade(ctx context.Context, tradeID uuid.UUID) (*domain.Trade, error) {
+	unlock, err := s.locker.Lock(ctx, tradeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock trade: %w", err)
+	}
+	defer unlock()
+
+	s.mu.RLock()
+	trade, exists := s.trades[tradeID]
+	s.mu.RUnlock()
+	if !exists {
+		return nil, apperrors.NotFoundError("trade", tradeID.String())
+	}
+	if trade.Status != domain.TradeStatusPaused {
+		return nil, apperrors.DomainError(fmt.Sprintf("trade %s is %s, not paused", tradeID, trade.Status), "TRADE_NOT_PAUSED")
+	}
+
+	placed := 0
+	for _, level := range trade.PausedGrid {
+		price, err := domain.ParseDecimal(level.Price)
+		if err != nil {
+			log.Printf("Invalid price %q of paused DCA level of trade %s: %v", level.Price, tradeID, err)
+			continue
+		}
+		if !withinPriceBounds(trade.Config, price) {
+			trade.PausedLevels++
+			continue
+		}
+
+		// Уровень хранит остаток в базовой валюте, лимитный ордер задается суммой в котируемой
+		quantity, _ := domain.ParseDecimal(level.Quantity)
+		dcaOrder, err := s.ordersFor(trade.Config).ExecuteLimitOrder(ctx, domain.CreateOrderRequest{
+			Symbol:   trade.Config.Symbol,
+			Side:     entrySide(trade.Config),
+			Type:     domain.OrderTypeLimit,
+			Quantity: domain.FormatDecimal(quantity.Mul(price)),
+			Price:    level.Price,
+			PostOnly: trade.Config.MakerOnly,
+		})
+		if err != nil {
+			log.Printf("Failed to restore DCA level %s of trade %s: %v", level.Price, tradeID, err)
+			continue
+		}
+
+		stampExpiry(trade, dcaOrder)
+		s.appendDCAOrder(trade, *dcaOrder)
+		placed++
+	}
+
+	s.mu.Lock()
+	trade.Status = domain.TradeStatusActive
+	trade.PausedAt = nil
+	trade.PausedGrid = nil
+	trade.UpdatedAt = time.Now()
+	s.indexOrders(trade)
+	s.mu.Unlock()
+
+	if err := s.updateTakeProfitOrder(ctx, trade); err != nil {
+		// Сделка уже активна: пропавший TP выставит сверка с биржей
+		log.Printf("Failed to restore take profit of trade %s: %v", tradeID, err)
+	}
+
+	message := fmt.Sprintf("%d DCA levels restored", placed)
+	log.Printf("AUDIT: trade %s resumed, %s", tradeID, message)
+	s.recordEvent(trade, domain.TradeEventResumed, nil, message)
+	return trade, nil
+}
+
+// settleCancelledOrder перечитывает снятый ордер и учитывает в позиции исполнение,
+// прошедшее до отмены и еще не учтенное.
+func (s *TradeService) settleCancelledOrder(ctx context.Context, trade *domain.Trade, order *domain.Order) {
+	updated, err := s.ordersFor(trade.Config).FetchOrderStatus(ctx, trade.Symbol, order.BybitID)
+	if err != nil {
+		log.Printf("Failed to fetch cancelled order %s of trade %s: %v", order.BybitID, trade.ID, err)
+		return
+	}
+	previous := *order
+	*order = *updated
+	if fill, filled := fillDelta(&previous, order); filled {
+		recordFill(trade, &fill)
+	}
+}
+
+func remainingQty(order domain.Order) domain.Decimal {
+	quantity, _ := domain.ParseDecimal(order.Quantity)
+	executed, _ := domain.ParseDecimal(order.ExecutedQty)
+	return quantity.Sub(executed)
+}
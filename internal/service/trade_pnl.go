@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"cryptorg/internal/bybit"
+	"cryptorg/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// settlePnL фиксирует итог сделки при финализации: время закрытия, комиссии и
+// реализованный PnL, если по сделке был выход.
+func settlePnL(trade *domain.Trade, closedAt time.Time) {
+	trade.ClosedAt = &closedAt
+	trade.Fees = fmt.Sprintf("%.8f", tradeFeesPaid(trade))
+	if profit, _, ok := closedPnL(trade); ok {
+		trade.RealizedPnL = fmt.Sprintf("%.8f", profit)
+	}
+}
+
+// tradeRealizedPnL - зафиксированный PnL закрытой сделки; для сделок, закрытых до его
+// учета, считается по исполнениям так же, как при закрытии.
+func tradeRealizedPnL(trade *domain.Trade) (float64, bool) {
+	if trade.RealizedPnL != "" {
+		profit, err := strconv.ParseFloat(trade.RealizedPnL, 64)
+		return profit, err == nil
+	}
+	profit, _, ok := closedPnL(trade)
+	return profit, ok
+}
+
+// tradeClosedAt - время закрытия; у старых сделок это последнее обновление.
+func tradeClosedAt(trade *domain.Trade) time.Time {
+	if trade.ClosedAt != nil {
+		return *trade.ClosedAt
+	}
+	return trade.UpdatedAt
+}
+
+// tradeFeesPaid - комиссии всех исполненных ордеров сделки в котируемой валюте.
+func tradeFeesPaid(trade *domain.Trade) float64 {
+	orders := make([]*domain.Order, 0, len(trade.DCAOrders)+3)
+	if trade.EntryOrder != nil {
+		orders = append(orders, trade.EntryOrder)
+	}
+	for i := range trade.DCAOrders {
+		orders = append(orders, &trade.DCAOrders[i])
+	}
+	orders = append(orders, trade.TakeProfitOrder, trade.StopLossOrder)
+
+	fees := 0.0
+	for _, order := range orders {
+		if order == nil {
+			continue
+		}
+		if executed, _ := strconv.ParseFloat(order.ExecutedQty, 64); executed > 0 {
+			fees += feeInQuote(order)
+		}
+	}
+	return fees
+}
+
+// fillPrice - средняя цена исполнения ордера, без исполнения - цена ордера.
+func fillPrice(order *domain.Order) float64 {
+	executed, _ := strconv.ParseFloat(order.ExecutedQty, 64)
+	if value := orderValue(order); executed > 0 && value > 0 {
+		return value / executed
+	}
+	price, _ := strconv.ParseFloat(order.Price, 64)
+	return price
+}
+
+// feeInQuote переводит комиссию ордера в котируемую валюту: у покупок она в базовой монете.
+func feeInQuote(order *domain.Order) float64 {
+	fee, _ := strconv.ParseFloat(order.Fee, 64)
+	if order.Side == domain.OrderSideBuy {
+		return fee * fillPrice(order)
+	}
+	return fee
+}
+
+// TradePnL считает PnL сделки; для активной сделки запрашивает последнюю цену символа.
+func (s *TradeService) TradePnL(ctx context.Context, tradeID uuid.UUID) (*domain.TradePnL, error) {
+	s.mu.RLock()
+	trade, exists := s.trades[tradeID]
+	var snapshot domain.Trade
+	if exists {
+		snapshot = *trade
+	}
+	s.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("trade not found: %s", tradeID)
+	}
+	trade = &snapshot
+
+	result := &domain.TradePnL{
+		TradeID:       trade.ID,
+		Symbol:        trade.Symbol,
+		Quote:         bybit.QuoteAsset(trade.Symbol),
+		Status:        trade.Status,
+		TotalInvested: trade.TotalInvested,
+		PositionQty:   trade.CurrentPositionQty,
+		AveragePrice:  trade.AveragePrice,
+		UnrealizedPnL: fmt.Sprintf("%.8f", 0.0),
+		RealizedPnL:   fmt.Sprintf("%.8f", 0.0),
+		Fees:          fmt.Sprintf("%.8f", tradeFeesPaid(trade)),
+		ClosedAt:      trade.ClosedAt,
+		CalculatedAt:  time.Now(),
+	}
+
+	if trade.Status == domain.TradeStatusActive || trade.Status == domain.TradeStatusPaused {
+		price, err := s.ordersFor(trade.Config).FetchLastPrice(ctx, trade.Symbol)
+		if err != nil {
+			return nil, err
+		}
+		unrealized := positionPnL(trade, price)
+		result.CurrentPrice = fmt.Sprintf("%.8f", price)
+		result.UnrealizedPnL = fmt.Sprintf("%.8f", unrealized)
+		if invested, _ := strconv.ParseFloat(trade.TotalInvested, 64); invested > 0 {
+			result.UnrealizedPercent = roundPercent(unrealized / invested * 100)
+		}
+		return result, nil
+	}
+
+	if trade.Fees != "" {
+		result.Fees = trade.Fees
+	}
+	if profit, ok := tradeRealizedPnL(trade); ok {
+		result.RealizedPnL = fmt.Sprintf("%.8f", profit)
+	}
+	return result, nil
+}
+
+// UnrealizedPnL оценивает активные сделки по последним ценам, по котируемым валютам.
+func (s *TradeService) UnrealizedPnL(ctx context.Context) (map[string]float64, int, error) {
+	prices, err := s.lastPrices(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	pnl := make(map[string]float64)
+	active := 0
+	for _, trade := range s.trades {
+		if trade.Status != domain.TradeStatusActive && trade.Status != domain.TradeStatusPaused {
+			continue
+		}
+		active++
+		invested, _ := strconv.ParseFloat(trade.TotalInvested, 64)
+		if price := prices[s.symbolKey(trade)]; price > 0 && invested > 0 {
+			pnl[bybit.QuoteAsset(trade.Symbol)] += positionPnL(trade, price)
+		}
+	}
+	return pnl, active, nil
+}
+
+// lastPrices - последние цены всех спотовых символов, одним запросом на биржу.
+func (s *TradeService) lastPrices(ctx context.Context) (map[exchangeSymbol]float64, error) {
+	prices := make(map[exchangeSymbol]float64)
+	for name, orders := range s.exchangeGroups() {
+		tickers, err := orders.ListTickers(ctx)
+		if err != nil {
+			return nil, exchangeError(name, err)
+		}
+		for _, ticker := range tickers {
+			prices[exchangeSymbol{exchange: name, symbol: ticker.Symbol}], _ = strconv.ParseFloat(ticker.LastPrice, 64)
+		}
+	}
+	return prices, nil
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+
+	"cryptorg/internal/domain"
+)
+
+// priceBounds возвращает границы MinPrice/MaxPrice; 0 означает, что граница не задана.
+func priceBounds(config domain.TradeConfig) (minPrice, maxPrice domain.Decimal) {
+	minPrice, _ = domain.ParseDecimal(config.MinPrice)
+	maxPrice, _ = domain.ParseDecimal(config.MaxPrice)
+	return minPrice, maxPrice
+}
+
+func hasPriceBounds(config domain.TradeConfig) bool {
+	minPrice, maxPrice := priceBounds(config)
+	return minPrice.IsPositive() || maxPrice.IsPositive()
+}
+
+func withinPriceBounds(config domain.TradeConfig, price domain.Decimal) bool {
+	minPrice, maxPrice := priceBounds(config)
+	if minPrice.IsPositive() && price.LessThan(minPrice) {
+		return false
+	}
+	if maxPrice.IsPositive() && price.GreaterThan(maxPrice) {
+		return false
+	}
+	return true
+}
+
+// outsidePriceBounds сообщает, что символ торгуется вне границ конфига и новый цикл
+// нужно отложить. Если цену получить не удалось, решение остается за входом.
+func (s *TradeService) outsidePriceBounds(ctx context.Context, config domain.TradeConfig) bool {
+	if !hasPriceBounds(config) {
+		return false
+	}
+
+	lastPrice, err := s.ordersFor(config).FetchLastPrice(ctx, config.Symbol)
+	if err != nil {
+		return false
+	}
+	return !withinPriceBounds(config, domain.DecimalFromFloat(lastPrice))
+}
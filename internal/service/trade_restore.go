@@ -0,0 +1,36 @@
+package service
+
+import (
+	"fmt"
+
+	"cryptorg/internal/domain"
+)
+
+// LoadTrades поднимает из репозитория незавершенные сделки и индекс их ордеров.
+// Вызывается один раз при старте, до обработки исполнений и запуска планировщика;
+// расхождения с биржей за время простоя находит проверка согласованности.
+func (s *TradeService) LoadTrades() (int, error) {
+	trades, err := s.repository.LoadActive()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load trades: %w", err)
+	}
+
+	for _, trade := range trades {
+		if err := s.checkExchange(trade.Config.Exchange); err != nil {
+			return 0, fmt.Errorf("trade %s: %w", trade.ID, err)
+		}
+	}
+
+	s.mu.Lock()
+	for _, trade := range trades {
+		s.trades[trade.ID] = trade
+		// Ордера сделки в CLOSING уже не ведутся, их только снимают
+		if trade.Status != domain.TradeStatusClosing {
+			s.indexOrders(trade)
+		}
+	}
+	s.mu.Unlock()
+
+	s.invalidateSnapshot()
+	return len(trades), nil
+}
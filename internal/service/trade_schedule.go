@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"cryptorg/internal/domain"
+)
+
+func isTimeBased(trade *domain.Trade) bool {
+	return trade.Config.Strategy == domain.StrategyTimeBased
+}
+
+func (s *TradeService) scheduleNextBuy(trade *domain.Trade, from time.Time) {
+	if s.accumulationReached(trade) {
+		trade.NextBuyAt = nil
+		return
+	}
+
+	next := from.Add(time.Duration(trade.Config.BuyIntervalHours) * time.Hour)
+	trade.NextBuyAt = &next
+}
+
+func (s *TradeService) accumulationReached(trade *domain.Trade) bool {
+	if trade.Config.MaxBudget != "" {
+		budget, _ := strconv.ParseFloat(trade.Config.MaxBudget, 64)
+		invested, _ := strconv.ParseFloat(trade.TotalInvested, 64)
+		volume, _ := strconv.ParseFloat(trade.Config.DCAVolume, 64)
+		if invested+volume > budget {
+			return true
+		}
+	}
+
+	if trade.Config.TargetPositionQty != "" {
+		target, _ := strconv.ParseFloat(trade.Config.TargetPositionQty, 64)
+		if positionQty(trade) >= target {
+			return true
+		}
+	}
+
+	return false
+}
+
+func positionQty(trade *domain.Trade) float64 {
+	qty := 0.0
+	if trade.EntryOrder != nil {
+		executed, _ := strconv.ParseFloat(trade.EntryOrder.ExecutedQty, 64)
+		qty += executed
+	}
+	for _, order := range trade.DCAOrders {
+		if order.Status.ClosedWithFills() {
+			executed, _ := strconv.ParseFloat(order.ExecutedQty, 64)
+			qty += executed
+		}
+	}
+	return qty
+}
+
+// ExecuteScheduledBuys выполняет плановые покупки для сделок со стратегией time_based.
+func (s *TradeService) ExecuteScheduledBuys(ctx context.Context) error {
+	now := time.Now()
+
+	s.mu.RLock()
+	due := make([]*domain.Trade, 0)
+	for _, trade := range s.trades {
+		if trade.Status == domain.TradeStatusActive && isTimeBased(trade) &&
+			trade.NextBuyAt != nil && !trade.NextBuyAt.After(now) {
+			due = append(due, trade)
+		}
+	}
+	s.mu.RUnlock()
+
+	var errs []error
+	for _, trade := range due {
+		if err := s.executeScheduledBuy(ctx, trade, now); err != nil {
+			errs = append(errs, fmt.Errorf("trade %s: %w", trade.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *TradeService) executeScheduledBuy(ctx context.Context, trade *domain.Trade, now time.Time) error {
+	unlock, err := s.locker.Lock(ctx, trade.ID)
+	if err != nil {
+		return fmt.Errorf("failed to lock trade: %w", err)
+	}
+	defer unlock()
+
+	// Сделка могла закрыться, пока ждали блокировку
+	if trade.Status != domain.TradeStatusActive {
+		return nil
+	}
+
+	if s.accumulationReached(trade) {
+		trade.NextBuyAt = nil
+		return nil
+	}
+
+	// Вне границ цены покупка пропускается до следующего планового времени
+	if s.outsidePriceBounds(ctx, trade.Config) {
+		s.scheduleNextBuy(trade, now)
+		return nil
+	}
+
+	buyReq := domain.CreateOrderRequest{
+		Symbol:   trade.Config.Symbol,
+		Side:     domain.OrderSideBuy,
+		Type:     domain.OrderTypeMarket,
+		Quantity: trade.Config.DCAVolume,
+	}
+
+	order, err := s.executeEntryOrder(ctx, trade.Config, buyReq)
+	if err != nil {
+		return fmt.Errorf("failed to execute scheduled buy: %w", err)
+	}
+	order.Status = domain.OrderStatusFilled
+
+	s.appendDCAOrder(trade, *order)
+	recordFill(trade, order)
+	trade.UpdatedAt = now
+	s.scheduleNextBuy(trade, now)
+	s.recordEvent(trade, domain.TradeEventScheduledBuy, order, "")
+
+	if err := s.updateTakeProfitOrder(ctx, trade); err != nil {
+		return fmt.Errorf("failed to update take profit after scheduled buy: %w", err)
+	}
+
+	return nil
+}
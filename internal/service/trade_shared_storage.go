@@ -0,0 +1,42 @@
+package service
+
+import (
+	"fmt"
+
+	"cryptorg/internal/domain"
+)
+
+// UseSharedStorage включает работу нескольких реплик с общей базой сделок: под блокировкой
+// исполнения сделка перечитывается из loader. Вызывается при сборке приложения вместе
+// с блокировками на той же базе.
+func (s *TradeService) UseSharedStorage(loader domain.TradeLoader) {
+	s.shared = loader
+}
+
+// syncTrade заменяет состояние сделки в памяти сохраненным в общей базе: пока блокировку
+// держала другая реплика, она могла уже применить то же исполнение. Вызывается под
+// блокировкой сделки.
+func (s *TradeService) syncTrade(trade *domain.Trade) error {
+	if s.shared == nil {
+		return nil
+	}
+
+	stored, err := s.shared.Load(trade.ID)
+	if err != nil {
+		return fmt.Errorf("failed to reload trade: %w", err)
+	}
+	if stored == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	s.unindexOrders(trade)
+	*trade = *stored
+	// Как и при восстановлении, ордера сделки в CLOSING уже не ведутся
+	if trade.Status != domain.TradeStatusClosing {
+		s.indexOrders(trade)
+	}
+	s.mu.Unlock()
+	s.invalidateSnapshot()
+	return nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"cryptorg/internal/domain"
+	"cryptorg/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newReplica - экземпляр бота на общей базе сделок. Общий MemoryTradeLocker заменяет
+// блокировку в PostgreSQL: обе реплики ждут друг друга так же.
+func newReplica(t *testing.T, exchange ExchangeClient, repository *storage.SQLTradeRepository, locker domain.TradeLocker) *TradeService {
+	t.Helper()
+
+	replica := newFakeTradeService(t, exchange, storage.NewMemoryJournal(), repository)
+	replica.locker = locker
+	replica.UseSharedStorage(repository)
+	return replica
+}
+
+func TestSharedStorageAppliesFillOnce(t *testing.T) {
+	ctx := context.Background()
+	db, err := storage.OpenTradeDatabase(storage.DriverSQLite, filepath.Join(t.TempDir(), "trades.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	repository := storage.NewSQLTradeRepository(db, storage.DriverSQLite)
+	exchange := newFakeExchange(map[string]float64{"BTCUSDT": 100})
+	locker := storage.NewMemoryTradeLocker()
+
+	first := newReplica(t, exchange, repository, locker)
+	trade, err := first.InitializeTrade(ctx, testTradeConfig())
+	require.NoError(t, err)
+	second := newReplica(t, exchange, repository, locker)
+	loaded, err := second.LoadTrades()
+	require.NoError(t, err)
+	require.Equal(t, 1, loaded)
+
+	// Сделка в памяти первой реплики меняется при обработке, поэтому нужное берется заранее
+	tradeID := trade.ID
+	dca := trade.DCAOrders[0]
+
+	// Исполнение уровня DCA доставлено обеим репликам одновременно
+	updates := exchange.setPrice("BTCUSDT", parseFake(dca.Price))
+	require.Len(t, updates, 1)
+	update := updates[0]
+
+	var wg sync.WaitGroup
+	for _, replica := range []*TradeService{first, second} {
+		wg.Add(1)
+		go func(replica *TradeService) {
+			defer wg.Done()
+			replica.ApplyOrderUpdate(update)
+			assert.NoError(t, replica.ProcessOrderExecution(ctx, tradeID, update.OrderID, update.OrderID+"-exec"))
+		}(replica)
+	}
+	wg.Wait()
+
+	// Позиция выросла на один уровень, а не на два, и TP на бирже один
+	want := 1 + parseFake(dca.Quantity)
+	stored, err := repository.Load(tradeID)
+	require.NoError(t, err)
+	assert.InDelta(t, want, parseFake(stored.CurrentPositionQty), 1e-8)
+	for _, replica := range []*TradeService{first, second} {
+		got, err := replica.GetTrade(tradeID)
+		require.NoError(t, err)
+		assert.InDelta(t, want, parseFake(got.CurrentPositionQty), 1e-8)
+	}
+
+	sells := openSells(exchange)
+	require.Len(t, sells, 1)
+	assert.Equal(t, stored.TakeProfitOrder.BybitID, sells[0].OrderID)
+}
@@ -0,0 +1,28 @@
+package service
+
+import "cryptorg/internal/domain"
+
+// entrySide - сторона входа и DCA ордеров: покупка для лонга, продажа для шорта.
+func entrySide(config domain.TradeConfig) domain.OrderSide {
+	if config.IsShort() {
+		return domain.OrderSideSell
+	}
+	return domain.OrderSideBuy
+}
+
+// exitSide - сторона TP и SL, обратная входу.
+func exitSide(config domain.TradeConfig) domain.OrderSide {
+	if config.IsShort() {
+		return domain.OrderSideBuy
+	}
+	return domain.OrderSideSell
+}
+
+// sideSign - направление цены в пользу сделки: +1 для лонга, -1 для шорта.
+// Уровни DCA лежат против этого направления, TP и прибыль - по нему.
+func sideSign(config domain.TradeConfig) float64 {
+	if config.IsShort() {
+		return -1
+	}
+	return 1
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"cryptorg/internal/domain"
+)
+
+// tradeSnapshot - неизменяемая копия всех сделок для read-эндпоинтов.
+// Читатели получают ее без блокировки s.mu и не конкурируют с обработкой исполнений.
+type tradeSnapshot struct {
+	trades []*domain.Trade
+}
+
+// invalidateSnapshot помечает снимок устаревшим; он будет пересобран при следующем чтении.
+func (s *TradeService) invalidateSnapshot() {
+	s.snapshotDirty.Store(true)
+}
+
+// GetAllTrades возвращает сделки, отсортированные по времени создания.
+// Результат разделяется между вызовами и не должен изменяться.
+func (s *TradeService) GetAllTrades() []*domain.Trade {
+	if snapshot := s.snapshot.Load(); snapshot != nil && !s.snapshotDirty.Load() {
+		return snapshot.trades
+	}
+	return s.rebuildSnapshot().trades
+}
+
+// RefreshSnapshot пересобирает снимок по расписанию, подхватывая изменения
+// ордеров, которые не проходят через журнал событий.
+func (s *TradeService) RefreshSnapshot(ctx context.Context) error {
+	s.rebuildSnapshot()
+	return nil
+}
+
+func (s *TradeService) rebuildSnapshot() *tradeSnapshot {
+	// Флаг сбрасывается до копирования: изменение во время сборки снова пометит снимок
+	s.snapshotDirty.Store(false)
+
+	s.mu.RLock()
+	trades := make([]*domain.Trade, 0, len(s.trades))
+	for _, trade := range s.trades {
+		trades = append(trades, cloneTrade(trade))
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(trades, func(i, j int) bool { return trades[i].CreatedAt.Before(trades[j].CreatedAt) })
+
+	snapshot := &tradeSnapshot{trades: trades}
+	s.snapshot.Store(snapshot)
+	return snapshot
+}
+
+func cloneTrade(trade *domain.Trade) *domain.Trade {
+	clone := *trade
+	clone.EntryOrder = cloneOrder(trade.EntryOrder)
+	clone.TakeProfitOrder = cloneOrder(trade.TakeProfitOrder)
+	clone.StopLossOrder = cloneOrder(trade.StopLossOrder)
+	clone.DCAOrders = append([]domain.Order(nil), trade.DCAOrders...)
+	clone.PausedGrid = append([]domain.Order(nil), trade.PausedGrid...)
+	return &clone
+}
+
+func cloneOrder(order *domain.Order) *domain.Order {
+	if order == nil {
+		return nil
+	}
+	clone := *order
+	return &clone
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"cryptorg/internal/domain"
+	"cryptorg/internal/notify"
+)
+
+// queueTrade регистрирует сделку в статусе WAITING без ордеров на бирже.
+func (s *TradeService) queueTrade(trade *domain.Trade) {
+	trade.Status = domain.TradeStatusWaiting
+
+	s.mu.Lock()
+	s.trades[trade.ID] = trade
+	s.mu.Unlock()
+
+	message := fmt.Sprintf("waiting for price %s %s", trade.Config.StartDirection, trade.Config.StartPrice)
+	if trade.Config.StartPrice == "" {
+		message = fmt.Sprintf("waiting for price within bounds [%s, %s]", trade.Config.MinPrice, trade.Config.MaxPrice)
+	}
+	if cooldown, ok := s.riskManager.Cooldowns().Get(trade.Symbol); ok {
+		message = fmt.Sprintf("waiting for symbol cooldown until %s", cooldown.Until.UTC().Format(time.RFC3339))
+	}
+	s.recordEvent(trade, domain.TradeEventQueued, nil, message)
+}
+
+// TriggerWaitingTrades открывает ожидающие сделки, цена символа которых пересекла StartPrice
+// и находится в границах MinPrice/MaxPrice. Пока торговля остановлена по лимиту убытка
+// или символ на паузе после стопа, сделки остаются в ожидании.
+func (s *TradeService) TriggerWaitingTrades(ctx context.Context) error {
+	if s.riskManager.checkSuspended() != nil {
+		return nil
+	}
+
+	s.mu.RLock()
+	bySymbol := make(map[exchangeSymbol][]*domain.Trade)
+	for _, trade := range s.trades {
+		if trade.Status != domain.TradeStatusWaiting {
+			continue
+		}
+		if _, cooling := s.riskManager.Cooldowns().Get(trade.Symbol); !cooling {
+			key := s.symbolKey(trade)
+			bySymbol[key] = append(bySymbol[key], trade)
+		}
+	}
+	s.mu.RUnlock()
+
+	var errs []error
+	for key, trades := range bySymbol {
+		lastPrice, err := s.exchangeOrders(key.exchange).FetchLastPrice(ctx, key.symbol)
+		if err != nil {
+			errs = append(errs, exchangeError(key.exchange, fmt.Errorf("%s: %w", key.symbol, err)))
+			continue
+		}
+
+		for _, trade := range trades {
+			if !startConditionMet(trade.Config, lastPrice) {
+				continue
+			}
+			if err := s.activateTrade(ctx, trade); err != nil {
+				errs = append(errs, fmt.Errorf("trade %s: %w", trade.ID, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func startConditionMet(config domain.TradeConfig, lastPrice float64) bool {
+	if !withinPriceBounds(config, domain.DecimalFromFloat(lastPrice)) {
+		return false
+	}
+	if config.StartPrice == "" {
+		return true
+	}
+
+	startPrice, err := strconv.ParseFloat(config.StartPrice, 64)
+	if err != nil {
+		return false
+	}
+
+	if config.StartDirection == domain.StartDirectionAbove {
+		return lastPrice >= startPrice
+	}
+	return lastPrice <= startPrice
+}
+
+// activateTrade выставляет вход и сетку ожидающей сделки. При ошибке сделка
+// переводится в FAILED, чтобы не повторять вход на каждом тике.
+func (s *TradeService) activateTrade(ctx context.Context, trade *domain.Trade) error {
+	unlock, err := s.locker.Lock(ctx, trade.ID)
+	if err != nil {
+		return fmt.Errorf("failed to lock trade: %w", err)
+	}
+	defer unlock()
+
+	if trade.Status != domain.TradeStatusWaiting {
+		return nil
+	}
+
+	if !trade.Config.Force {
+		err = s.checkAccountActivity(ctx, trade.Config)
+	}
+	if err == nil {
+		err = s.openTrade(ctx, trade)
+	}
+	if err == nil {
+		return nil
+	}
+
+	// Вход без позиции openTrade уже перевел в FAILED
+	if trade.Status != domain.TradeStatusFailed {
+		s.mu.Lock()
+		trade.Status = domain.TradeStatusFailed
+		trade.UpdatedAt = time.Now()
+		s.mu.Unlock()
+
+		s.recordEvent(trade, domain.TradeEventFinalized, nil, string(domain.TradeStatusFailed))
+	}
+	notification := notify.Localized(notify.LevelWarning, "Trade start failed",
+		"Trade %s on %s failed to open at start price %s: %v", trade.ID, trade.Symbol, trade.Config.StartPrice, err)
+	if err := s.notifier.Notify(ctx, notification); err != nil {
+	}
+	return err
+}
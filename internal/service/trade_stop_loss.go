@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"cryptorg/internal/domain"
+	"cryptorg/pkg/pricing"
+)
+
+// Эмуляция OCO: TP и SL живут парой, исполнение одного сразу снимает другой.
+
+func (s *TradeService) setupStopLossOrder(ctx context.Context, trade *domain.Trade) error {
+	if trade.Config.StopLossPercent <= 0 || trade.TakeProfitOrder == nil {
+		return nil
+	}
+
+	averagePrice, err := domain.ParseDecimal(trade.AveragePrice)
+	if err != nil {
+		return fmt.Errorf("invalid average price: %w", err)
+	}
+
+	// Для шорта SL - покупка выше средней цены, лимит еще выше триггера
+	sign := sideSign(trade.Config)
+	triggerPrice := averagePrice.Mul(pricing.Factor(-sign * trade.Config.StopLossPercent))
+	limitPrice := triggerPrice.Mul(pricing.Factor(-sign * domain.StopLimitSlippagePercent))
+
+	slOrderReq := domain.CreateOrderRequest{
+		Symbol:       trade.Config.Symbol,
+		Side:         exitSide(trade.Config),
+		Type:         domain.OrderTypeLimit,
+		Quantity:     trade.CurrentPositionQty,
+		Price:        domain.FormatDecimal(limitPrice),
+		TriggerPrice: domain.FormatDecimal(triggerPrice),
+	}
+
+	slOrder, err := s.ordersFor(trade.Config).ExecuteStopLimitOrder(ctx, slOrderReq)
+	if err != nil {
+		return fmt.Errorf("failed to create stop loss order: %w", err)
+	}
+
+	trade.StopLossOrder = slOrder
+	return nil
+}
+
+func (s *TradeService) replaceStopLossOrder(ctx context.Context, trade *domain.Trade) error {
+	if trade.StopLossOrder != nil {
+		if err := s.ordersFor(trade.Config).TerminateStopOrder(ctx, trade.Symbol, trade.StopLossOrder.BybitID); err != nil {
+			return fmt.Errorf("failed to cancel previous stop loss order: %w", err)
+		}
+		trade.StopLossOrder = nil
+	}
+
+	if err := s.setupStopLossOrder(ctx, trade); err != nil {
+		return err
+	}
+
+	if trade.StopLossOrder != nil {
+		s.recordEvent(trade, domain.TradeEventSLReplaced, trade.StopLossOrder, "")
+	}
+	return nil
+}
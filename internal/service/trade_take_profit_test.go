@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"cryptorg/internal/bybit"
+	"cryptorg/internal/domain"
+	"cryptorg/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errExchangeRefused = errors.New("exchange refused the request")
+
+// refusingExchange - фейковая биржа, которая по флагам отказывает в отмене ордеров
+// и в выставлении лимитных продаж.
+type refusingExchange struct {
+	*fakeExchange
+
+	mu            sync.Mutex
+	refuseCancels bool
+	refuseSells   bool
+}
+
+func (e *refusingExchange) refuse(cancels, sells bool) {
+	e.mu.Lock()
+	e.refuseCancels = cancels
+	e.refuseSells = sells
+	e.mu.Unlock()
+}
+
+func (e *refusingExchange) TerminateOrder(ctx context.Context, req bybit.ExchangeCancelRequest) error {
+	e.mu.Lock()
+	refuse := e.refuseCancels
+	e.mu.Unlock()
+	if refuse {
+		return errExchangeRefused
+	}
+	return e.fakeExchange.TerminateOrder(ctx, req)
+}
+
+func (e *refusingExchange) ExecuteOrder(ctx context.Context, req bybit.ExchangeOrderRequest) (*bybit.ExchangeOrderResponse, error) {
+	e.mu.Lock()
+	refuse := e.refuseSells && req.Side == string(domain.OrderSideSell) && req.OrderType == string(domain.OrderTypeLimit)
+	e.mu.Unlock()
+	if refuse {
+		return nil, errExchangeRefused
+	}
+	return e.fakeExchange.ExecuteOrder(ctx, req)
+}
+
+// openSells - открытые продажи символа: у лонга это TP.
+func openSells(exchange *fakeExchange) []bybit.ExchangeOrderResponse {
+	sells := make([]bybit.ExchangeOrderResponse, 0)
+	for _, order := range exchange.open("BTCUSDT") {
+		if order.Side == string(domain.OrderSideSell) {
+			sells = append(sells, order)
+		}
+	}
+	return sells
+}
+
+// fillFirstDCA доводит цену до первого уровня сетки и возвращает ошибку обработки исполнения.
+func fillFirstDCA(t *testing.T, trades *TradeService, exchange *fakeExchange, trade *domain.Trade, fee string) error {
+	t.Helper()
+
+	updates := exchange.setPrice("BTCUSDT", parseFake(trade.DCAOrders[0].Price))
+	require.Len(t, updates, 1)
+	update := updates[0]
+	update.Fee = fee
+	exchange.mu.Lock()
+	exchange.orders[update.OrderID].ExecutedFee = fee
+	exchange.mu.Unlock()
+	trades.ApplyOrderUpdate(update)
+	return trades.ProcessOrderExecution(context.Background(), trade.ID, update.OrderID, update.OrderID+"-exec")
+}
+
+func TestTakeProfitReplacementUsesNetPosition(t *testing.T) {
+	exchange := newFakeExchange(map[string]float64{"BTCUSDT": 100})
+	trades := newFakeTradeService(t, exchange, storage.NewMemoryJournal(), storage.NewMemoryTradeRepository())
+
+	trade, err := trades.InitializeTrade(context.Background(), testTradeConfig())
+	require.NoError(t, err)
+	dca := trade.DCAOrders[0]
+
+	// Комиссия покупки удержана в базовой монете
+	require.NoError(t, fillFirstDCA(t, trades, exchange, trade, "0.001"))
+
+	got, err := trades.GetTrade(trade.ID)
+	require.NoError(t, err)
+	position := parseFake(got.CurrentPositionQty)
+	assert.InDelta(t, 1+parseFake(dca.Quantity)-0.001, position, 1e-8)
+
+	sells := openSells(exchange)
+	require.Len(t, sells, 1)
+	assert.Equal(t, got.TakeProfitOrder.BybitID, sells[0].OrderID)
+	assert.InDelta(t, position, parseFake(sells[0].Qty), 1e-6)
+}
+
+func TestTakeProfitIsNotDuplicatedWhenCancelFails(t *testing.T) {
+	ctx := context.Background()
+	exchange := &refusingExchange{fakeExchange: newFakeExchange(map[string]float64{"BTCUSDT": 100})}
+	trades := newFakeTradeService(t, exchange, storage.NewMemoryJournal(), storage.NewMemoryTradeRepository())
+
+	trade, err := trades.InitializeTrade(ctx, testTradeConfig())
+	require.NoError(t, err)
+	oldTP := trade.TakeProfitOrder.BybitID
+
+	exchange.refuse(true, false)
+	assert.ErrorIs(t, fillFirstDCA(t, trades, exchange.fakeExchange, trade, "0"), errExchangeRefused)
+
+	// Старый TP не снят - второй на те же монеты не выставлен
+	sells := openSells(exchange.fakeExchange)
+	require.Len(t, sells, 1)
+	assert.Equal(t, oldTP, sells[0].OrderID)
+	got, err := trades.GetTrade(trade.ID)
+	require.NoError(t, err)
+	assert.Equal(t, oldTP, got.TakeProfitOrder.BybitID)
+
+	// Биржа снова принимает отмены - сверка переставляет устаревший TP
+	exchange.refuse(false, false)
+	report, err := trades.CheckConsistency(ctx, true)
+	require.NoError(t, err)
+	require.Len(t, report.Issues, 1)
+	assert.Equal(t, domain.IssueStaleTakeProfit, report.Issues[0].Kind)
+	assert.True(t, report.Issues[0].Repaired, report.Issues[0].Error)
+
+	got, err = trades.GetTrade(trade.ID)
+	require.NoError(t, err)
+	sells = openSells(exchange.fakeExchange)
+	require.Len(t, sells, 1)
+	assert.Equal(t, got.TakeProfitOrder.BybitID, sells[0].OrderID)
+	assert.InDelta(t, parseFake(got.CurrentPositionQty), parseFake(sells[0].Qty), 1e-6)
+}
+
+func TestTakeProfitIsClearedWhenReplacementFails(t *testing.T) {
+	ctx := context.Background()
+	exchange := &refusingExchange{fakeExchange: newFakeExchange(map[string]float64{"BTCUSDT": 100})}
+	journal := storage.NewMemoryJournal()
+	trades := newFakeTradeService(t, exchange, journal, storage.NewMemoryTradeRepository())
+
+	trade, err := trades.InitializeTrade(ctx, testTradeConfig())
+	require.NoError(t, err)
+	oldTP := trade.TakeProfitOrder.BybitID
+
+	exchange.refuse(false, true)
+	assert.ErrorIs(t, fillFirstDCA(t, trades, exchange.fakeExchange, trade, "0"), errExchangeRefused)
+
+	// Старый TP снят, новый не выставлен: сделка не ссылается на снятый ордер
+	assert.Empty(t, openSells(exchange.fakeExchange))
+	got, err := trades.GetTrade(trade.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got.TakeProfitOrder)
+
+	events, err := journal.ReadAll()
+	require.NoError(t, err)
+	last := events[len(events)-1]
+	assert.Equal(t, domain.TradeEventTPCancelled, last.Type)
+	assert.Equal(t, oldTP, last.OrderID)
+
+	// Сверка находит сделку без TP и выставляет его
+	exchange.refuse(false, false)
+	report, err := trades.CheckConsistency(ctx, true)
+	require.NoError(t, err)
+	require.Len(t, report.Issues, 1)
+	assert.Equal(t, domain.IssueMissingTakeProfit, report.Issues[0].Kind)
+	assert.True(t, report.Issues[0].Repaired, report.Issues[0].Error)
+
+	sells := openSells(exchange.fakeExchange)
+	require.Len(t, sells, 1)
+	assert.InDelta(t, parseFake(got.CurrentPositionQty), parseFake(sells[0].Qty), 1e-6)
+}
+
+func TestMissingTakeProfitIsReplacedAfterExternalCancel(t *testing.T) {
+	ctx := context.Background()
+	exchange := newFakeExchange(map[string]float64{"BTCUSDT": 100})
+	trades := newFakeTradeService(t, exchange, storage.NewMemoryJournal(), storage.NewMemoryTradeRepository())
+
+	trade, err := trades.InitializeTrade(ctx, testTradeConfig())
+	require.NoError(t, err)
+	oldTP := trade.TakeProfitOrder.BybitID
+
+	// TP снят вручную: отмена при замене не проходит, но ордер уже закрыт без исполнения
+	require.NoError(t, exchange.TerminateOrder(ctx, bybit.ExchangeCancelRequest{Symbol: "BTCUSDT", OrderID: oldTP}))
+
+	report, err := trades.CheckConsistency(ctx, true)
+	require.NoError(t, err)
+	var missing *domain.ConsistencyIssue
+	for i := range report.Issues {
+		if report.Issues[i].Kind == domain.IssueMissingTakeProfit {
+			missing = &report.Issues[i]
+		}
+	}
+	require.NotNil(t, missing)
+	assert.True(t, missing.Repaired, missing.Error)
+
+	sells := openSells(exchange)
+	require.Len(t, sells, 1)
+	assert.NotEqual(t, oldTP, sells[0].OrderID)
+}
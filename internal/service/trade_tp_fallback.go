@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cryptorg/internal/domain"
+	"cryptorg/internal/metrics"
+	"cryptorg/internal/notify"
+)
+
+// RunTPFallback следит за TP сделок с настроенным TPFallback: если цена дольше
+// TPFallbackSeconds держится за TP (выше для лонга, ниже для шорта), а он не исполнен,
+// остаток TP закрывается IOC лимиткой по цене TP или по рынку.
+func (s *TradeService) RunTPFallback(ctx context.Context) error {
+	s.mu.RLock()
+	bySymbol := make(map[exchangeSymbol][]*domain.Trade)
+	for _, trade := range s.trades {
+		if trade.Status == domain.TradeStatusActive && trade.Config.TPFallback != "" &&
+			trade.TakeProfitOrder != nil && trade.TPDeferredAt == nil {
+			key := s.symbolKey(trade)
+			bySymbol[key] = append(bySymbol[key], trade)
+		}
+	}
+	s.mu.RUnlock()
+
+	var errs []error
+	for key, trades := range bySymbol {
+		lastPrice, err := s.exchangeOrders(key.exchange).FetchLastPrice(ctx, key.symbol)
+		if err != nil {
+			errs = append(errs, exchangeError(key.exchange, fmt.Errorf("%s: %w", key.symbol, err)))
+			continue
+		}
+
+		for _, trade := range trades {
+			if err := s.watchTakeProfit(ctx, trade, domain.DecimalFromFloat(lastPrice)); err != nil {
+				errs = append(errs, fmt.Errorf("trade %s: %w", trade.ID, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *TradeService) watchTakeProfit(ctx context.Context, trade *domain.Trade, lastPrice domain.Decimal) error {
+	unlock, err := s.locker.Lock(ctx, trade.ID)
+	if err != nil {
+		return fmt.Errorf("failed to lock trade: %w", err)
+	}
+	defer unlock()
+
+	if trade.Status != domain.TradeStatusActive || trade.TakeProfitOrder == nil {
+		return nil
+	}
+
+	tpPrice, err := domain.ParseDecimal(trade.TakeProfitOrder.Price)
+	if err != nil || !tpPrice.IsPositive() {
+		return nil
+	}
+
+	if !lastPrice.Sub(tpPrice).Mul(domain.DecimalFromFloat(sideSign(trade.Config))).IsPositive() {
+		// Цена вернулась за TP - отсчет начнется заново
+		trade.TPCrossedAt = nil
+		return nil
+	}
+
+	now := time.Now()
+	if trade.TPCrossedAt == nil {
+		trade.TPCrossedAt = &now
+		return nil
+	}
+	if now.Sub(*trade.TPCrossedAt) < time.Duration(trade.Config.TPFallbackSeconds)*time.Second {
+		return nil
+	}
+
+	return s.replaceStuckTakeProfit(ctx, trade, tpPrice, lastPrice)
+}
+
+// replaceStuckTakeProfit снимает TP и закрывает его неисполненный остаток по TPFallback.
+// Если IOC исполнилась не полностью, на остаток снова выставляется TP по прежней цене.
+func (s *TradeService) replaceStuckTakeProfit(ctx context.Context, trade *domain.Trade, tpPrice, lastPrice domain.Decimal) error {
+	tp := trade.TakeProfitOrder
+	crossedFor := time.Since(*trade.TPCrossedAt).Round(time.Second)
+
+	// Если TP успел исполниться, отмена не пройдет, а исполнение обработает вебхук
+	if err := s.ordersFor(trade.Config).TerminateOrder(ctx, trade.Symbol, tp.BybitID); err != nil {
+		return fmt.Errorf("failed to cancel take profit order: %w", err)
+	}
+	trade.TPCrossedAt = nil
+
+	status, err := s.ordersFor(trade.Config).FetchOrderStatus(ctx, trade.Symbol, tp.BybitID)
+	if err != nil {
+		status = tp
+	}
+	quantity, _ := domain.ParseDecimal(status.Quantity)
+	executed, _ := domain.ParseDecimal(status.ExecutedQty)
+	if executed.IsPositive() {
+		recordFill(trade, status)
+	}
+
+	s.mu.Lock()
+	delete(s.orderIndex, tp.BybitID)
+	s.mu.Unlock()
+
+	remaining := quantity.Sub(executed)
+	if !remaining.IsPositive() {
+		trade.TakeProfitOrder = status
+		return s.finalizeTrade(ctx, trade.ID, domain.TradeStatusCompleted, tp.BybitID)
+	}
+
+	fallback, err := s.executeTPFallback(ctx, trade, remaining, tpPrice, lastPrice)
+	if err != nil {
+		// Позиция не должна остаться без TP
+		if restoreErr := s.restoreTakeProfit(ctx, trade, remaining, tpPrice, "restored after TP fallback"); restoreErr != nil {
+			return fmt.Errorf("failed to execute %s fallback: %w; failed to restore take profit: %v", trade.Config.TPFallback, err, restoreErr)
+		}
+		return fmt.Errorf("failed to execute %s fallback: %w", trade.Config.TPFallback, err)
+	}
+	if updated, err := s.ordersFor(trade.Config).FetchOrderStatus(ctx, trade.Symbol, fallback.BybitID); err == nil {
+		fallback = updated
+	}
+
+	filled, _ := domain.ParseDecimal(fallback.ExecutedQty)
+	if filled.IsPositive() {
+		recordFill(trade, fallback)
+	}
+	s.metrics.IncCounter("tp_fallbacks_total", metrics.Labels{"type": string(trade.Config.TPFallback)})
+
+	message := fmt.Sprintf("price %s held past TP %s for %s, %s fallback filled %s of %s",
+		domain.FormatDecimal(lastPrice), domain.FormatDecimal(tpPrice), crossedFor, trade.Config.TPFallback,
+		domain.FormatDecimal(filled), domain.FormatDecimal(remaining))
+	notification := notify.Localized(notify.LevelInfo, "Take profit fallback",
+		"%s: price %s held past TP %s for %s, %s fallback filled %s of %s",
+		trade.Symbol, domain.FormatDecimal(lastPrice), domain.FormatDecimal(tpPrice), crossedFor, trade.Config.TPFallback,
+		domain.FormatDecimal(filled), domain.FormatDecimal(remaining))
+	if err := s.notifier.Notify(ctx, notification); err != nil {
+	}
+
+	if isFilledStatus(fallback.Status) || filled.GreaterThanOrEqual(remaining) {
+		trade.TakeProfitOrder = fallback
+		s.recordEvent(trade, domain.TradeEventTPFallback, fallback, message)
+		return s.finalizeTrade(ctx, trade.ID, domain.TradeStatusCompleted, fallback.BybitID)
+	}
+
+	if err := s.restoreTakeProfit(ctx, trade, remaining.Sub(filled), tpPrice, "restored after TP fallback"); err != nil {
+		return err
+	}
+	s.recordEvent(trade, domain.TradeEventTPFallback, fallback, message)
+	return nil
+}
+
+func (s *TradeService) executeTPFallback(ctx context.Context, trade *domain.Trade, remaining, tpPrice, lastPrice domain.Decimal) (*domain.Order, error) {
+	req := domain.CreateOrderRequest{
+		Symbol: trade.Symbol,
+		Side:   exitSide(trade.Config),
+	}
+
+	if trade.Config.TPFallback == domain.TPFallbackMarket {
+		req.Type = domain.OrderTypeMarket
+		req.Quantity = domain.FormatDecimal(remaining)
+		if trade.Config.IsShort() {
+			// Рыночная покупка задается суммой в котируемой валюте
+			req.Quantity = domain.FormatDecimal(remaining.Mul(lastPrice))
+		}
+		return s.ordersFor(trade.Config).ExecuteMarketOrder(ctx, req)
+	}
+
+	// Объем лимитки задается в котируемой валюте и пересчитывается по цене
+	req.Type = domain.OrderTypeLimit
+	req.Price = domain.FormatDecimal(tpPrice)
+	req.Quantity = domain.FormatDecimal(remaining.Mul(tpPrice))
+	req.ImmediateOrCancel = true
+	return s.ordersFor(trade.Config).ExecuteLimitOrder(ctx, req)
+}
+
+// restoreTakeProfit выставляет обычный TP на quantity монет по цене tpPrice и переставляет SL.
+func (s *TradeService) restoreTakeProfit(ctx context.Context, trade *domain.Trade, quantity, tpPrice domain.Decimal, note string) error {
+	tpOrder, err := s.ordersFor(trade.Config).ExecuteLimitOrder(ctx, domain.CreateOrderRequest{
+		Symbol:   trade.Symbol,
+		Side:     exitSide(trade.Config),
+		Type:     domain.OrderTypeLimit,
+		Quantity: domain.FormatDecimal(quantity.Mul(tpPrice)),
+		Price:    domain.FormatDecimal(tpPrice),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to restore take profit order: %w", err)
+	}
+
+	trade.TakeProfitOrder = tpOrder
+	trade.UpdatedAt = time.Now()
+	s.recordEvent(trade, domain.TradeEventTPReplaced, tpOrder, note)
+
+	if err := s.replaceStopLossOrder(ctx, trade); err != nil {
+	}
+
+	s.mu.Lock()
+	s.indexOrders(trade)
+	s.mu.Unlock()
+	return nil
+}
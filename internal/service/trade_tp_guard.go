@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cryptorg/internal/domain"
+	"cryptorg/internal/notify"
+	"cryptorg/pkg/pricing"
+)
+
+// guardTakeProfitPrice сверяет рассчитанную цену TP с текущим тикером. Если TP оказался по ту
+// сторону рынка (ниже цены для лонга, выше для шорта), он ставится чуть за ценой. Если TP
+// дальше порога от цены (резкое движение между исполнением DCA и перестановкой), возвращается
+// deferred = true, пока не истек TPMaxDeferral. Без тикера цена не меняется.
+func (s *TradeService) guardTakeProfitPrice(ctx context.Context, trade *domain.Trade, tpPrice domain.Decimal) (price domain.Decimal, deferred bool, note string) {
+	last, err := s.ordersFor(trade.Config).FetchLastPrice(ctx, trade.Symbol)
+	if err != nil || last <= 0 {
+		return tpPrice, false, ""
+	}
+
+	lastPrice := domain.DecimalFromFloat(last)
+	sign := sideSign(trade.Config)
+	behind, ahead := "below", "above"
+	if trade.Config.IsShort() {
+		behind, ahead = ahead, behind
+	}
+
+	gap := tpPrice.Sub(lastPrice).Mul(domain.DecimalFromFloat(sign))
+	if !gap.IsPositive() {
+		adjusted := lastPrice.Mul(pricing.Factor(sign * domain.TPAboveMarketPercent))
+		return adjusted, false, fmt.Sprintf("TP %s %s market %s, placed at %s",
+			domain.FormatDecimal(tpPrice), behind, domain.FormatDecimal(lastPrice), domain.FormatDecimal(adjusted))
+	}
+
+	maxDeviation := trade.Config.TPMaxDeviationPercent
+	if maxDeviation <= 0 {
+		maxDeviation = domain.DefaultTPMaxDeviationPercent
+	}
+
+	deviation := gap.Div(lastPrice).InexactFloat64() * 100
+	if deviation <= maxDeviation {
+		return tpPrice, false, ""
+	}
+
+	if trade.TPDeferredAt != nil && time.Since(*trade.TPDeferredAt) >= domain.TPMaxDeferral {
+		return tpPrice, false, fmt.Sprintf("TP %.2f%% %s market, placed after %s deferral", deviation, ahead, domain.TPMaxDeferral)
+	}
+
+	return tpPrice, true, fmt.Sprintf("TP %s is %.2f%% %s market %s",
+		domain.FormatDecimal(tpPrice), deviation, ahead, domain.FormatDecimal(lastPrice))
+}
+
+func (s *TradeService) deferTakeProfit(ctx context.Context, trade *domain.Trade, note string) {
+	if trade.TPDeferredAt != nil {
+		return
+	}
+
+	now := time.Now()
+	trade.TPDeferredAt = &now
+	trade.UpdatedAt = now
+	s.recordEvent(trade, domain.TradeEventTPDeferred, nil, note)
+
+	notification := notify.Localized(notify.LevelWarning, "Take profit deferred",
+		"Take profit replacement for %s deferred: %s", trade.Symbol, note)
+	if err := s.notifier.Notify(ctx, notification); err != nil {
+	}
+}
+
+// RetryDeferredTakeProfits повторяет отложенные перестановки TP: после возврата цены
+// или по истечении TPMaxDeferral TP выставляется.
+func (s *TradeService) RetryDeferredTakeProfits(ctx context.Context) error {
+	s.mu.RLock()
+	deferred := make([]*domain.Trade, 0)
+	for _, trade := range s.trades {
+		if trade.Status == domain.TradeStatusActive && trade.TPDeferredAt != nil {
+			deferred = append(deferred, trade)
+		}
+	}
+	s.mu.RUnlock()
+
+	var errs []error
+	for _, trade := range deferred {
+		if err := s.retryTakeProfit(ctx, trade); err != nil {
+			errs = append(errs, fmt.Errorf("trade %s: %w", trade.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *TradeService) retryTakeProfit(ctx context.Context, trade *domain.Trade) error {
+	unlock, err := s.locker.Lock(ctx, trade.ID)
+	if err != nil {
+		return fmt.Errorf("failed to lock trade: %w", err)
+	}
+	defer unlock()
+
+	if trade.Status != domain.TradeStatusActive || trade.TPDeferredAt == nil {
+		return nil
+	}
+
+	return s.updateTakeProfitOrder(ctx, trade)
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"cryptorg/internal/bybit"
+	"cryptorg/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+const (
+	creditCheckDelay    = 30 * time.Second // Журнал баланса биржи отстает от исполнения
+	creditCheckAttempts = 5
+	creditTolerance     = 0.005 // Допустимое расхождение, доля ожидаемой суммы
+)
+
+// creditCheck - сверка прихода на баланс после исполнения TP.
+type creditCheck struct {
+	tradeID  uuid.UUID
+	exchange string // TradeConfig.Exchange сделки
+	order    domain.Order
+	held     float64 // Позиция сделки перед исполнением TP
+	dueAt    time.Time
+	attempts int
+}
+
+// queueCreditCheck ставит сверку баланса после TP, закрывшего лонговую сделку: приход
+// котируемой валюты должен совпасть с продажей всей позиции за вычетом комиссии.
+func (s *TradeService) queueCreditCheck(trade *domain.Trade, order domain.Order, held float64) {
+	if trade.Config.IsShort() || held <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.creditChecks = append(s.creditChecks, &creditCheck{
+		tradeID:  trade.ID,
+		exchange: trade.Config.Exchange,
+		order:    order,
+		held:     held,
+		dueAt:    time.Now().Add(creditCheckDelay),
+	})
+}
+
+// VerifyTPCredits сверяет приход на баланс по исполненным TP. Если биржа еще не отразила
+// исполнение, сверка повторяется до creditCheckAttempts раз; расхождения пишутся в журнал сделки.
+func (s *TradeService) VerifyTPCredits(ctx context.Context) error {
+	now := time.Now()
+
+	s.mu.Lock()
+	due := make([]*creditCheck, 0)
+	pending := s.creditChecks[:0]
+	for _, check := range s.creditChecks {
+		if check.dueAt.After(now) {
+			pending = append(pending, check)
+		} else {
+			due = append(due, check)
+		}
+	}
+	s.creditChecks = pending
+	s.mu.Unlock()
+
+	var errs []error
+	for _, check := range due {
+		done, err := s.verifyCredit(ctx, check)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("trade %s: %w", check.tradeID, err))
+		}
+		if done {
+			continue
+		}
+
+		check.attempts++
+		if check.attempts >= creditCheckAttempts {
+			s.flagCredit(check, fmt.Sprintf("no wallet change found for order %s after %d checks", check.order.BybitID, check.attempts))
+			continue
+		}
+		check.dueAt = now.Add(creditCheckDelay)
+		s.mu.Lock()
+		s.creditChecks = append(s.creditChecks, check)
+		s.mu.Unlock()
+	}
+
+	return errors.Join(errs...)
+}
+
+// verifyCredit возвращает false, если сверку нужно повторить.
+func (s *TradeService) verifyCredit(ctx context.Context, check *creditCheck) (bool, error) {
+	order := check.order
+	received, found, err := s.exchangeOrders(check.exchange).WalletDelta(ctx, bybit.QuoteAsset(order.Symbol), order.BybitID, order.CreatedAt)
+	if err != nil || !found {
+		return false, err
+	}
+
+	issues := make([]string, 0, 2)
+	expected := check.held*fillPrice(&order) - feeInQuote(&order)
+	if math.Abs(received-expected) > expected*creditTolerance {
+		issues = append(issues, fmt.Sprintf("quote credit %.8f, expected %.8f", received, expected))
+	}
+	if executed, _ := strconv.ParseFloat(order.ExecutedQty, 64); math.Abs(executed-check.held) > check.held*creditTolerance {
+		issues = append(issues, fmt.Sprintf("take profit sold %.8f of %.8f held", executed, check.held))
+	}
+
+	if len(issues) > 0 {
+		s.flagCredit(check, strings.Join(issues, "; "))
+	}
+	return true, nil
+}
+
+func (s *TradeService) flagCredit(check *creditCheck, message string) {
+	s.mu.RLock()
+	trade, exists := s.trades[check.tradeID]
+	s.mu.RUnlock()
+
+	log.Printf("Wallet credit mismatch for trade %s: %s", check.tradeID, message)
+	s.metrics.IncCounter("wallet_credit_mismatch_total", nil)
+	if exists {
+		s.recordEvent(trade, domain.TradeEventCreditGap, &check.order, message)
+	}
+}
@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"cryptorg/internal/domain"
+)
+
+// WriteBackupFile записывает копию атомарно через rename.
+func WriteBackupFile(path string, backup *domain.StorageBackup) error {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create backup directory: %w", err)
+		}
+	}
+
+	data, err := json.Marshal(backup)
+	if err != nil {
+		return fmt.Errorf("failed to encode backup: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+func ReadBackupFile(path string) (*domain.StorageBackup, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	var backup domain.StorageBackup
+	if err := json.Unmarshal(data, &backup); err != nil {
+		return nil, fmt.Errorf("failed to decode backup %s: %w", path, err)
+	}
+	if backup.Version != domain.BackupFormatVersion {
+		return nil, fmt.Errorf("unsupported backup version %d in %s", backup.Version, path)
+	}
+	return &backup, nil
+}
+
+// RestoreBackups восстанавливает журнал, переопределения точности и ботов из цепочки копий:
+// первая копия должна быть полной, каждая следующая начинаться с LastSeq предыдущей.
+// Точность и боты берутся из последней копии. Существующие файлы перезаписываются;
+// пустой путь - файл не восстанавливается.
+func RestoreBackups(backups []*domain.StorageBackup, journalPath, precisionPath, botsPath string) error {
+	if len(backups) == 0 {
+		return fmt.Errorf("no backups to restore")
+	}
+	if !backups[0].IsFull() {
+		return fmt.Errorf("first backup must be full, got incremental from seq %d", backups[0].FromSeq)
+	}
+
+	events := make([]domain.TradeEvent, 0)
+	var lastSeq uint64
+	for i, backup := range backups {
+		if backup.FromSeq != lastSeq {
+			return fmt.Errorf("backup %d starts after seq %d, expected %d", i+1, backup.FromSeq, lastSeq)
+		}
+		for _, event := range backup.Events {
+			if event.Seq != lastSeq+1 {
+				return fmt.Errorf("backup %d has gap in events: seq %d after %d", i+1, event.Seq, lastSeq)
+			}
+			events = append(events, event)
+			lastSeq = event.Seq
+		}
+	}
+
+	if err := writeJournalFile(journalPath, events); err != nil {
+		return err
+	}
+
+	if precisionPath != "" {
+		store, err := NewFilePrecisionStore(precisionPath)
+		if err != nil {
+			return err
+		}
+		overrides := backups[len(backups)-1].PrecisionOverrides
+		if overrides == nil {
+			overrides = make(map[string]domain.PrecisionOverride)
+		}
+		if err := store.Save(overrides); err != nil {
+			return err
+		}
+	}
+
+	// Копии старого формата ботов не содержат, файл ботов тогда не трогаем
+	if bots := backups[len(backups)-1].Bots; botsPath != "" && bots != nil {
+		store, err := NewFileBotStore(botsPath)
+		if err != nil {
+			return err
+		}
+		if err := store.Save(bots); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeJournalFile(path string, events []domain.TradeEvent) error {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create journal directory: %w", err)
+		}
+	}
+
+	tmp := path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to create journal: %w", err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	for _, event := range events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("failed to marshal event: %w", err)
+		}
+		if _, err := writer.Write(append(data, '\n')); err != nil {
+			return fmt.Errorf("failed to write event: %w", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync journal: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close journal: %w", err)
+	}
+
+	return os.Rename(tmp, path)
+}
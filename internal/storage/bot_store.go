@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"cryptorg/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+type MemoryBotStore struct {
+	mu   sync.Mutex
+	bots map[uuid.UUID]domain.Bot
+}
+
+func NewMemoryBotStore() *MemoryBotStore {
+	return &MemoryBotStore{
+		bots: make(map[uuid.UUID]domain.Bot),
+	}
+}
+
+func (s *MemoryBotStore) Load() (map[uuid.UUID]domain.Bot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make(map[uuid.UUID]domain.Bot, len(s.bots))
+	for id, bot := range s.bots {
+		result[id] = bot
+	}
+	return result, nil
+}
+
+func (s *MemoryBotStore) Save(bots map[uuid.UUID]domain.Bot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.bots = make(map[uuid.UUID]domain.Bot, len(bots))
+	for id, bot := range bots {
+		s.bots[id] = bot
+	}
+	return nil
+}
+
+// FileBotStore хранит ботов одним JSON файлом; запись атомарна через rename.
+type FileBotStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileBotStore(path string) (*FileBotStore, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create bot store directory: %w", err)
+		}
+	}
+	return &FileBotStore{path: path}, nil
+}
+
+func (s *FileBotStore) Load() (map[uuid.UUID]domain.Bot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bots := make(map[uuid.UUID]domain.Bot)
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return bots, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bots: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &bots); err != nil {
+		return nil, fmt.Errorf("failed to decode bots: %w", err)
+	}
+	return bots, nil
+}
+
+func (s *FileBotStore) Save(bots map[uuid.UUID]domain.Bot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.MarshalIndent(bots, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode bots: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write bots: %w", err)
+	}
+	return os.Rename(tmp, s.path)
+}
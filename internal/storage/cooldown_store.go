@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"cryptorg/internal/domain"
+)
+
+type MemoryCooldownStore struct {
+	mu        sync.Mutex
+	cooldowns map[string]domain.SymbolCooldown
+}
+
+func NewMemoryCooldownStore() *MemoryCooldownStore {
+	return &MemoryCooldownStore{
+		cooldowns: make(map[string]domain.SymbolCooldown),
+	}
+}
+
+func (s *MemoryCooldownStore) Load() (map[string]domain.SymbolCooldown, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make(map[string]domain.SymbolCooldown, len(s.cooldowns))
+	for symbol, cooldown := range s.cooldowns {
+		result[symbol] = cooldown
+	}
+	return result, nil
+}
+
+func (s *MemoryCooldownStore) Save(cooldowns map[string]domain.SymbolCooldown) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cooldowns = make(map[string]domain.SymbolCooldown, len(cooldowns))
+	for symbol, cooldown := range cooldowns {
+		s.cooldowns[symbol] = cooldown
+	}
+	return nil
+}
+
+// FileCooldownStore хранит паузы символов одним JSON файлом; запись атомарна через rename.
+type FileCooldownStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileCooldownStore(path string) (*FileCooldownStore, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create cooldown store directory: %w", err)
+		}
+	}
+	return &FileCooldownStore{path: path}, nil
+}
+
+func (s *FileCooldownStore) Load() (map[string]domain.SymbolCooldown, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cooldowns := make(map[string]domain.SymbolCooldown)
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return cooldowns, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read symbol cooldowns: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &cooldowns); err != nil {
+		return nil, fmt.Errorf("failed to decode symbol cooldowns: %w", err)
+	}
+	return cooldowns, nil
+}
+
+func (s *FileCooldownStore) Save(cooldowns map[string]domain.SymbolCooldown) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.MarshalIndent(cooldowns, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode symbol cooldowns: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write symbol cooldowns: %w", err)
+	}
+	return os.Rename(tmp, s.path)
+}
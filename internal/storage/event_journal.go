@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"cryptorg/internal/domain"
+)
+
+type MemoryJournal struct {
+	mu     sync.Mutex
+	events []domain.TradeEvent
+}
+
+func NewMemoryJournal() *MemoryJournal {
+	return &MemoryJournal{
+		events: make([]domain.TradeEvent, 0),
+	}
+}
+
+func (j *MemoryJournal) Append(event *domain.TradeEvent) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	event.Seq = uint64(len(j.events)) + 1
+	j.events = append(j.events, *event)
+	return nil
+}
+
+func (j *MemoryJournal) ReadAll() ([]domain.TradeEvent, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	result := make([]domain.TradeEvent, len(j.events))
+	copy(result, j.events)
+	return result, nil
+}
+
+// FileJournal хранит события в JSON Lines, по одному на строку, в порядке Seq.
+type FileJournal struct {
+	mu      sync.Mutex
+	path    string
+	file    *os.File
+	lastSeq uint64
+}
+
+func NewFileJournal(path string) (*FileJournal, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create journal directory: %w", err)
+		}
+	}
+
+	j := &FileJournal{path: path}
+
+	events, err := j.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(events) > 0 {
+		j.lastSeq = events[len(events)-1].Seq
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	j.file = file
+
+	return j, nil
+}
+
+func (j *FileJournal) Append(event *domain.TradeEvent) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	event.Seq = j.lastSeq + 1
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	if _, err := j.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write event: %w", err)
+	}
+	if err := j.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync journal: %w", err)
+	}
+
+	j.lastSeq = event.Seq
+	return nil
+}
+
+// ReadAll держит блокировку, чтобы не прочитать строку, которую Append дописывает в этот момент.
+func (j *FileJournal) ReadAll() ([]domain.TradeEvent, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return ReadJournalFile(j.path)
+}
+
+func (j *FileJournal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.file.Close()
+}
+
+func ReadJournalFile(path string) ([]domain.TradeEvent, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return []domain.TradeEvent{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer file.Close()
+
+	events := make([]domain.TradeEvent, 0)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var event domain.TradeEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, fmt.Errorf("corrupted journal entry at line %d: %w", line, err)
+		}
+		events = append(events, event)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// MemoryExecutionStore помнит исполнения только до перезапуска.
+type MemoryExecutionStore struct {
+	mu   sync.Mutex
+	ttl  time.Duration
+	seen map[string]time.Time
+}
+
+func NewMemoryExecutionStore(ttl time.Duration) *MemoryExecutionStore {
+	return &MemoryExecutionStore{
+		ttl:  ttl,
+		seen: make(map[string]time.Time),
+	}
+}
+
+func (s *MemoryExecutionStore) Seen(execID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	at, ok := s.seen[execID]
+	return ok && time.Since(at) < s.ttl, nil
+}
+
+func (s *MemoryExecutionStore) Mark(execID string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seen[execID] = at
+	pruneExecutions(s.seen, at.Add(-s.ttl))
+	return nil
+}
+
+// FileExecutionStore хранит ID исполнений JSON файлом, чтобы повторная доставка
+// после перезапуска бота тоже отбрасывалась.
+type FileExecutionStore struct {
+	mu   sync.Mutex
+	path string
+	ttl  time.Duration
+	seen map[string]time.Time
+}
+
+func NewFileExecutionStore(path string, ttl time.Duration) (*FileExecutionStore, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create executions directory: %w", err)
+		}
+	}
+
+	s := &FileExecutionStore{
+		path: path,
+		ttl:  ttl,
+		seen: make(map[string]time.Time),
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read executions file: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.seen); err != nil {
+		return nil, fmt.Errorf("corrupted executions file: %w", err)
+	}
+	pruneExecutions(s.seen, time.Now().Add(-ttl))
+
+	return s, nil
+}
+
+func (s *FileExecutionStore) Seen(execID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	at, ok := s.seen[execID]
+	return ok && time.Since(at) < s.ttl, nil
+}
+
+func (s *FileExecutionStore) Mark(execID string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seen[execID] = at
+	pruneExecutions(s.seen, at.Add(-s.ttl))
+
+	data, err := json.Marshal(s.seen)
+	if err != nil {
+		return fmt.Errorf("failed to marshal executions: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write executions file: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to replace executions file: %w", err)
+	}
+
+	return nil
+}
+
+func pruneExecutions(seen map[string]time.Time, cutoff time.Time) {
+	for id, at := range seen {
+		if at.Before(cutoff) {
+			delete(seen, id)
+		}
+	}
+}
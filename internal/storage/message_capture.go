@@ -0,0 +1,135 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"cryptorg/internal/domain"
+)
+
+// FileMessageCapture пишет сообщения в JSON Lines. Когда файл дорастает до половины
+// maxBytes, он переименовывается в <path>.1 вместо прежнего, и запись начинается заново:
+// на диске остается не больше maxBytes последних сообщений.
+type FileMessageCapture struct {
+	mu       sync.Mutex
+	path     string
+	maxBytes int64
+	file     *os.File
+	size     int64
+}
+
+func NewFileMessageCapture(path string, maxBytes int64) (*FileMessageCapture, error) {
+	if maxBytes <= 0 {
+		return nil, fmt.Errorf("message capture size must be positive, got %d", maxBytes)
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create message capture directory: %w", err)
+		}
+	}
+
+	c := &FileMessageCapture{path: path, maxBytes: maxBytes}
+	if err := c.open(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *FileMessageCapture) Capture(source string, payload []byte) error {
+	message := domain.RawMessage{
+		ReceivedAt: time.Now(),
+		Source:     source,
+		Payload:    payload,
+	}
+	if !json.Valid(payload) {
+		quoted, err := json.Marshal(string(payload))
+		if err != nil {
+			return fmt.Errorf("failed to encode message: %w", err)
+		}
+		message.Payload = quoted
+	}
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+	data = append(data, '\n')
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.size > 0 && c.size+int64(len(data)) > c.maxBytes/2 {
+		if err := c.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := c.file.Write(data)
+	c.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	return nil
+}
+
+// Export держит блокировку, чтобы не отдать строку, которую Capture дописывает в этот момент.
+func (c *FileMessageCapture) Export(w io.Writer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, path := range []string{c.path + ".1", c.path} {
+		file, err := os.Open(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to open message capture: %w", err)
+		}
+		_, err = io.Copy(w, file)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("failed to export message capture: %w", err)
+		}
+	}
+	return nil
+}
+
+func (c *FileMessageCapture) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.file.Close()
+}
+
+func (c *FileMessageCapture) open() error {
+	file, err := os.OpenFile(c.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open message capture: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat message capture: %w", err)
+	}
+	c.file = file
+	c.size = info.Size()
+	return nil
+}
+
+func (c *FileMessageCapture) rotate() error {
+	if err := c.file.Close(); err != nil {
+		return fmt.Errorf("failed to close message capture: %w", err)
+	}
+	if err := os.Rename(c.path, c.path+".1"); err != nil {
+		// Запись продолжается в прежний файл, граница размера восстановится при следующей ротации
+		if openErr := c.open(); openErr != nil {
+			return openErr
+		}
+		return fmt.Errorf("failed to rotate message capture: %w", err)
+	}
+	return c.open()
+}
@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"cryptorg/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+type MemoryPnLRevisionStore struct {
+	mu        sync.Mutex
+	revisions []domain.PnLRevision
+	latest    map[uuid.UUID]int
+}
+
+func NewMemoryPnLRevisionStore() *MemoryPnLRevisionStore {
+	return &MemoryPnLRevisionStore{
+		revisions: make([]domain.PnLRevision, 0),
+		latest:    make(map[uuid.UUID]int),
+	}
+}
+
+func (s *MemoryPnLRevisionStore) Append(revision *domain.PnLRevision) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.latest[revision.TradeID]++
+	revision.Revision = s.latest[revision.TradeID]
+	s.revisions = append(s.revisions, *revision)
+	return nil
+}
+
+func (s *MemoryPnLRevisionStore) ReadAll() ([]domain.PnLRevision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]domain.PnLRevision, len(s.revisions))
+	copy(result, s.revisions)
+	return result, nil
+}
+
+// FilePnLRevisionStore дописывает ревизии в JSON Lines; записанные строки не меняются.
+type FilePnLRevisionStore struct {
+	mu     sync.Mutex
+	path   string
+	file   *os.File
+	latest map[uuid.UUID]int
+}
+
+func NewFilePnLRevisionStore(path string) (*FilePnLRevisionStore, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create pnl revisions directory: %w", err)
+		}
+	}
+
+	s := &FilePnLRevisionStore{
+		path:   path,
+		latest: make(map[uuid.UUID]int),
+	}
+
+	revisions, err := s.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, revision := range revisions {
+		if revision.Revision > s.latest[revision.TradeID] {
+			s.latest[revision.TradeID] = revision.Revision
+		}
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pnl revisions: %w", err)
+	}
+	s.file = file
+
+	return s, nil
+}
+
+func (s *FilePnLRevisionStore) Append(revision *domain.PnLRevision) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	revision.Revision = s.latest[revision.TradeID] + 1
+
+	data, err := json.Marshal(revision)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pnl revision: %w", err)
+	}
+
+	if _, err := s.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write pnl revision: %w", err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync pnl revisions: %w", err)
+	}
+
+	s.latest[revision.TradeID] = revision.Revision
+	return nil
+}
+
+func (s *FilePnLRevisionStore) ReadAll() ([]domain.PnLRevision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return []domain.PnLRevision{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pnl revisions: %w", err)
+	}
+	defer file.Close()
+
+	revisions := make([]domain.PnLRevision, 0)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var revision domain.PnLRevision
+		if err := json.Unmarshal(scanner.Bytes(), &revision); err != nil {
+			return nil, fmt.Errorf("corrupted pnl revision at line %d: %w", line, err)
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read pnl revisions: %w", err)
+	}
+	return revisions, nil
+}
+
+func (s *FilePnLRevisionStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.file.Close()
+}
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"cryptorg/internal/domain"
+)
+
+type MemoryPrecisionStore struct {
+	mu        sync.Mutex
+	overrides map[string]domain.PrecisionOverride
+}
+
+func NewMemoryPrecisionStore() *MemoryPrecisionStore {
+	return &MemoryPrecisionStore{
+		overrides: make(map[string]domain.PrecisionOverride),
+	}
+}
+
+func (s *MemoryPrecisionStore) Load() (map[string]domain.PrecisionOverride, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make(map[string]domain.PrecisionOverride, len(s.overrides))
+	for symbol, override := range s.overrides {
+		result[symbol] = override
+	}
+	return result, nil
+}
+
+func (s *MemoryPrecisionStore) Save(overrides map[string]domain.PrecisionOverride) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.overrides = make(map[string]domain.PrecisionOverride, len(overrides))
+	for symbol, override := range overrides {
+		s.overrides[symbol] = override
+	}
+	return nil
+}
+
+// FilePrecisionStore хранит переопределения одним JSON файлом; запись атомарна через rename.
+type FilePrecisionStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFilePrecisionStore(path string) (*FilePrecisionStore, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create precision store directory: %w", err)
+		}
+	}
+	return &FilePrecisionStore{path: path}, nil
+}
+
+func (s *FilePrecisionStore) Load() (map[string]domain.PrecisionOverride, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	overrides := make(map[string]domain.PrecisionOverride)
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return overrides, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read precision overrides: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to decode precision overrides: %w", err)
+	}
+	return overrides, nil
+}
+
+func (s *FilePrecisionStore) Save(overrides map[string]domain.PrecisionOverride) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.MarshalIndent(overrides, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode precision overrides: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write precision overrides: %w", err)
+	}
+	return os.Rename(tmp, s.path)
+}
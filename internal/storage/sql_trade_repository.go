@@ -0,0 +1,240 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"cryptorg/internal/domain"
+
+	"github.com/google/uuid"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
+
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// migrationLockKey - ключ advisory-блокировки, под которой реплики PostgreSQL по очереди
+// применяют миграции при одновременном старте.
+const migrationLockKey = 7_346_152_001
+
+const pingTimeout = 5 * time.Second
+
+// tradeMigrations - схема хранилища сделок. Примененные версии записываются в
+// schema_migrations, новые изменения схемы только дописываются в конец.
+var tradeMigrations = []string{
+	`CREATE TABLE trades (
+		id         TEXT PRIMARY KEY,
+		status     TEXT NOT NULL,
+		snapshot   TEXT NOT NULL,
+		updated_at TIMESTAMP NOT NULL
+	)`,
+	`CREATE INDEX trades_status_idx ON trades (status)`,
+}
+
+// OpenTradeDatabase подключается к PostgreSQL или SQLite и применяет миграции схемы сделок.
+// Драйвер SQLite работает через одно соединение: запись в файл все равно идет по очереди,
+// а так параллельные писатели ждут друг друга в пуле, а не получают SQLITE_BUSY.
+func OpenTradeDatabase(driver, dsn string) (*sql.DB, error) {
+	var driverName string
+	switch driver {
+	case DriverPostgres:
+		driverName = "pgx"
+	case DriverSQLite:
+		driverName = "sqlite"
+	default:
+		return nil, fmt.Errorf("unsupported trades database driver %q", driver)
+	}
+
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open trades database: %w", err)
+	}
+	if driver == DriverSQLite {
+		db.SetMaxOpenConns(1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to trades database: %w", err)
+	}
+	if err := migrateTrades(db, driver); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// migrateTrades применяет недостающие миграции одной транзакцией: при ошибке схема
+// остается в прежней версии.
+func migrateTrades(db *sql.DB, driver string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start migration: %w", err)
+	}
+	defer tx.Rollback()
+
+	if driver == DriverPostgres {
+		if _, err := tx.Exec(`SELECT pg_advisory_xact_lock($1)`, migrationLockKey); err != nil {
+			return fmt.Errorf("failed to lock migrations: %w", err)
+		}
+	}
+	if _, err := tx.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at TIMESTAMP NOT NULL
+	)`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	var applied int
+	if err := tx.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&applied); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	for version := applied + 1; version <= len(tradeMigrations); version++ {
+		if _, err := tx.Exec(tradeMigrations[version-1]); err != nil {
+			return fmt.Errorf("failed to apply migration %d: %w", version, err)
+		}
+		if _, err := tx.Exec(rebind(driver, `INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`), version, time.Now().UTC()); err != nil {
+			return fmt.Errorf("failed to record migration %d: %w", version, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migrations: %w", err)
+	}
+	return nil
+}
+
+// rebind заменяет плейсхолдеры ? на $1, $2... для PostgreSQL.
+func rebind(driver, query string) string {
+	if driver != DriverPostgres {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// SQLTradeRepository хранит снимок каждой сделки отдельной строкой. Сохранение - upsert
+// одной строки, поэтому параллельные записи разных сделок не затирают друг друга, а
+// завершенные сделки остаются в таблице со своим итоговым статусом.
+type SQLTradeRepository struct {
+	db     *sql.DB
+	driver string
+}
+
+func NewSQLTradeRepository(db *sql.DB, driver string) *SQLTradeRepository {
+	return &SQLTradeRepository{
+		db:     db,
+		driver: driver,
+	}
+}
+
+func (r *SQLTradeRepository) Save(trade *domain.Trade) error {
+	data, err := json.Marshal(trade)
+	if err != nil {
+		return fmt.Errorf("failed to marshal trade: %w", err)
+	}
+
+	query := rebind(r.driver, `INSERT INTO trades (id, status, snapshot, updated_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET status = excluded.status, snapshot = excluded.snapshot, updated_at = excluded.updated_at`)
+	if _, err := r.db.Exec(query, trade.ID.String(), string(trade.Status), string(data), time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to save trade %s: %w", trade.ID, err)
+	}
+	return nil
+}
+
+func (r *SQLTradeRepository) LoadActive() ([]*domain.Trade, error) {
+	placeholders := make([]string, len(domain.OpenTradeStatuses))
+	args := make([]interface{}, len(domain.OpenTradeStatuses))
+	for i, status := range domain.OpenTradeStatuses {
+		placeholders[i] = "?"
+		args[i] = string(status)
+	}
+
+	query := rebind(r.driver, `SELECT id, snapshot FROM trades WHERE status IN (`+strings.Join(placeholders, ", ")+`)`)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load trades: %w", err)
+	}
+	defer rows.Close()
+
+	trades := make([]*domain.Trade, 0)
+	for rows.Next() {
+		var id, snapshot string
+		if err := rows.Scan(&id, &snapshot); err != nil {
+			return nil, fmt.Errorf("failed to read trade row: %w", err)
+		}
+		var trade domain.Trade
+		if err := json.Unmarshal([]byte(snapshot), &trade); err != nil {
+			return nil, fmt.Errorf("failed to decode trade %s: %w", id, err)
+		}
+		trades = append(trades, &trade)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load trades: %w", err)
+	}
+
+	return trades, nil
+}
+
+func (r *SQLTradeRepository) Load(id uuid.UUID) (*domain.Trade, error) {
+	var snapshot string
+	err := r.db.QueryRow(rebind(r.driver, `SELECT snapshot FROM trades WHERE id = ?`), id.String()).Scan(&snapshot)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load trade %s: %w", id, err)
+	}
+
+	var trade domain.Trade
+	if err := json.Unmarshal([]byte(snapshot), &trade); err != nil {
+		return nil, fmt.Errorf("failed to decode trade %s: %w", id, err)
+	}
+	return &trade, nil
+}
+
+// Reset удаляет все сохраненные сделки; нужен восстановлению из копии, которое собирает
+// таблицу заново из журнала.
+func (r *SQLTradeRepository) Reset() error {
+	if _, err := r.db.Exec(`DELETE FROM trades`); err != nil {
+		return fmt.Errorf("failed to clear trades: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLTradeRepository) Driver() string {
+	return r.driver
+}
+
+func (r *SQLTradeRepository) Ping() error {
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+
+	if err := r.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("trades database is unreachable: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package storage
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"cryptorg/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDatabases открывает SQLite во временном каталоге и, если задан TEST_POSTGRES_DSN,
+// PostgreSQL. Таблица trades в PostgreSQL очищается перед тестом.
+func testDatabases(t *testing.T) map[string]*sql.DB {
+	t.Helper()
+
+	databases := make(map[string]*sql.DB)
+	db, err := OpenTradeDatabase(DriverSQLite, filepath.Join(t.TempDir(), "trades.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	databases[DriverSQLite] = db
+
+	if dsn := os.Getenv("TEST_POSTGRES_DSN"); dsn != "" {
+		db, err := OpenTradeDatabase(DriverPostgres, dsn)
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		_, err = db.Exec(`DELETE FROM trades`)
+		require.NoError(t, err)
+		databases[DriverPostgres] = db
+	}
+	return databases
+}
+
+func testTrade(status domain.TradeStatus) *domain.Trade {
+	return &domain.Trade{
+		ID:                 uuid.New(),
+		Symbol:             "BTCUSDT",
+		Status:             status,
+		AveragePrice:       "100.00000000",
+		CurrentPositionQty: "1.00000000",
+		CreatedAt:          time.Now().UTC().Truncate(time.Millisecond),
+		UpdatedAt:          time.Now().UTC().Truncate(time.Millisecond),
+	}
+}
+
+func TestSQLTradeRepositorySavesAndLoadsOpenTrades(t *testing.T) {
+	for driver, db := range testDatabases(t) {
+		t.Run(driver, func(t *testing.T) {
+			repository := NewSQLTradeRepository(db, driver)
+			assert.Equal(t, driver, repository.Driver())
+			require.NoError(t, repository.Ping())
+
+			active := testTrade(domain.TradeStatusActive)
+			paused := testTrade(domain.TradeStatusPaused)
+			completed := testTrade(domain.TradeStatusCompleted)
+			for _, trade := range []*domain.Trade{active, paused, completed} {
+				require.NoError(t, repository.Save(trade))
+			}
+
+			// Повторное сохранение заменяет снимок, завершение убирает сделку из открытых
+			active.AveragePrice = "99.00000000"
+			require.NoError(t, repository.Save(active))
+			paused.Status = domain.TradeStatusCompleted
+			require.NoError(t, repository.Save(paused))
+
+			loaded, err := repository.LoadActive()
+			require.NoError(t, err)
+			require.Len(t, loaded, 1)
+			assert.Equal(t, active.ID, loaded[0].ID)
+			assert.Equal(t, "99.00000000", loaded[0].AveragePrice)
+
+			// Завершенная сделка по-прежнему читается по ID
+			stored, err := repository.Load(paused.ID)
+			require.NoError(t, err)
+			require.NotNil(t, stored)
+			assert.Equal(t, domain.TradeStatusCompleted, stored.Status)
+			missing, err := repository.Load(uuid.New())
+			require.NoError(t, err)
+			assert.Nil(t, missing)
+
+			require.NoError(t, repository.Reset())
+			loaded, err = repository.LoadActive()
+			require.NoError(t, err)
+			assert.Empty(t, loaded)
+		})
+	}
+}
+
+func TestSQLTradeRepositoryConcurrentWriters(t *testing.T) {
+	const (
+		writers = 8
+		saves   = 25
+	)
+
+	for driver, db := range testDatabases(t) {
+		t.Run(driver, func(t *testing.T) {
+			repository := NewSQLTradeRepository(db, driver)
+			shared := testTrade(domain.TradeStatusActive)
+
+			var wg sync.WaitGroup
+			for w := 0; w < writers; w++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					own := testTrade(domain.TradeStatusActive)
+					for i := 0; i < saves; i++ {
+						copied := *shared
+						assert.NoError(t, repository.Save(&copied))
+						assert.NoError(t, repository.Save(own))
+					}
+				}()
+			}
+			wg.Wait()
+
+			// Каждая сделка - своя строка: ни одна запись не потерялась
+			loaded, err := repository.LoadActive()
+			require.NoError(t, err)
+			assert.Len(t, loaded, writers+1)
+		})
+	}
+}
+
+func TestOpenTradeDatabaseKeepsDataAcrossMigrations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trades.db")
+	db, err := OpenTradeDatabase(DriverSQLite, path)
+	require.NoError(t, err)
+	trade := testTrade(domain.TradeStatusActive)
+	require.NoError(t, NewSQLTradeRepository(db, DriverSQLite).Save(trade))
+	require.NoError(t, db.Close())
+
+	// Повторное открытие не применяет миграции заново
+	db, err = OpenTradeDatabase(DriverSQLite, path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	var version int
+	require.NoError(t, db.QueryRow(`SELECT MAX(version) FROM schema_migrations`).Scan(&version))
+	assert.Equal(t, len(tradeMigrations), version)
+
+	loaded, err := NewSQLTradeRepository(db, DriverSQLite).LoadActive()
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+	assert.Equal(t, trade.ID, loaded[0].ID)
+}
+
+func TestOpenTradeDatabaseRejectsUnknownDriver(t *testing.T) {
+	_, err := OpenTradeDatabase("mysql", "dsn")
+	assert.Error(t, err)
+}
+
+func TestRebind(t *testing.T) {
+	query := `INSERT INTO trades (id, status) VALUES (?, ?)`
+	assert.Equal(t, query, rebind(DriverSQLite, query))
+	assert.Equal(t, `INSERT INTO trades (id, status) VALUES ($1, $2)`, rebind(DriverPostgres, query))
+}
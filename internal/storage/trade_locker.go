@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// MemoryTradeLocker - блокировки сделок внутри одного процесса.
+type MemoryTradeLocker struct {
+	mu    sync.Mutex
+	locks map[uuid.UUID]chan struct{}
+}
+
+func NewMemoryTradeLocker() *MemoryTradeLocker {
+	return &MemoryTradeLocker{
+		locks: make(map[uuid.UUID]chan struct{}),
+	}
+}
+
+func (l *MemoryTradeLocker) Lock(ctx context.Context, tradeID uuid.UUID) (func(), error) {
+	l.mu.Lock()
+	lock, exists := l.locks[tradeID]
+	if !exists {
+		lock = make(chan struct{}, 1)
+		l.locks[tradeID] = lock
+	}
+	l.mu.Unlock()
+
+	select {
+	case lock <- struct{}{}:
+		return func() { <-lock }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// PostgresTradeLocker блокирует сделку на общей базе PostgreSQL advisory-блокировкой
+// транзакции: пока транзакция открыта, Lock той же сделки на любой реплике ждет. Внутри
+// процесса сначала берется MemoryTradeLocker, чтобы ждущие горутины не занимали соединения.
+type PostgresTradeLocker struct {
+	db    *sql.DB
+	local *MemoryTradeLocker
+}
+
+func NewPostgresTradeLocker(db *sql.DB) *PostgresTradeLocker {
+	return &PostgresTradeLocker{
+		db:    db,
+		local: NewMemoryTradeLocker(),
+	}
+}
+
+func (l *PostgresTradeLocker) Lock(ctx context.Context, tradeID uuid.UUID) (func(), error) {
+	release, err := l.local.Lock(ctx, tradeID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Транзакция живет до освобождения, а не до отмены контекста вызова: иначе блокировка
+	// снялась бы посреди обработки сделки
+	tx, err := l.db.BeginTx(context.WithoutCancel(ctx), nil)
+	if err != nil {
+		release()
+		return nil, fmt.Errorf("failed to start lock transaction: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, advisoryKey(tradeID)); err != nil {
+		tx.Rollback()
+		release()
+		return nil, fmt.Errorf("failed to lock trade %s: %w", tradeID, err)
+	}
+
+	return func() {
+		if err := tx.Rollback(); err != nil {
+			log.Printf("Failed to release lock of trade %s: %v", tradeID, err)
+		}
+		release()
+	}, nil
+}
+
+// advisoryKey сводит UUID сделки к ключу advisory-блокировки. Совпадение ключей двух сделок
+// только сериализует их обработку.
+func advisoryKey(tradeID uuid.UUID) int64 {
+	return int64(binary.BigEndian.Uint64(tradeID[:8]))
+}
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Две реплики - два отдельных пула соединений к одной базе. Тест идет, только если задан
+// TEST_POSTGRES_DSN.
+func TestPostgresTradeLockerAcrossReplicas(t *testing.T) {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+
+	replicas := make([]*PostgresTradeLocker, 2)
+	for i := range replicas {
+		db, err := OpenTradeDatabase(DriverPostgres, dsn)
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		replicas[i] = NewPostgresTradeLocker(db)
+	}
+
+	tradeID := uuid.New()
+	unlock, err := replicas[0].Lock(context.Background(), tradeID)
+	require.NoError(t, err)
+
+	// Пока первая реплика держит сделку, вторая ждет до отмены контекста
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, err = replicas[1].Lock(ctx, tradeID)
+	assert.Error(t, err)
+
+	// Другая сделка не блокируется
+	other, err := replicas[1].Lock(context.Background(), uuid.New())
+	require.NoError(t, err)
+	other()
+
+	acquired := make(chan func())
+	go func() {
+		unlock, err := replicas[1].Lock(context.Background(), tradeID)
+		assert.NoError(t, err)
+		acquired <- unlock
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second replica locked the trade while the first one holds it")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	unlock()
+	select {
+	case unlock := <-acquired:
+		unlock()
+	case <-time.After(5 * time.Second):
+		t.Fatal("second replica did not get the lock after release")
+	}
+}
+
+func TestMemoryTradeLockerWaitsForRelease(t *testing.T) {
+	locker := NewMemoryTradeLocker()
+	tradeID := uuid.New()
+
+	unlock, err := locker.Lock(context.Background(), tradeID)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = locker.Lock(ctx, tradeID)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	unlock()
+	unlock, err = locker.Lock(context.Background(), tradeID)
+	require.NoError(t, err)
+	unlock()
+}
@@ -0,0 +1,172 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"cryptorg/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// MemoryTradeRepository хранит сделки только до перезапуска.
+type MemoryTradeRepository struct {
+	mu     sync.Mutex
+	trades map[uuid.UUID][]byte
+}
+
+func NewMemoryTradeRepository() *MemoryTradeRepository {
+	return &MemoryTradeRepository{
+		trades: make(map[uuid.UUID][]byte),
+	}
+}
+
+func (r *MemoryTradeRepository) Save(trade *domain.Trade) error {
+	data, err := json.Marshal(trade)
+	if err != nil {
+		return fmt.Errorf("failed to marshal trade: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.trades[trade.ID] = data
+	return nil
+}
+
+func (r *MemoryTradeRepository) LoadActive() ([]*domain.Trade, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return decodeActiveTrades(r.trades)
+}
+
+func (r *MemoryTradeRepository) Driver() string {
+	return "memory"
+}
+
+func (r *MemoryTradeRepository) Ping() error {
+	return nil
+}
+
+// FileTradeRepository хранит снимки сделок одним JSON файлом. Файл переписывается целиком
+// через временный файл и rename, поэтому при сбое остается предыдущая целая версия.
+// Завершенные сделки из файла удаляются - их история остается в журнале событий.
+type FileTradeRepository struct {
+	mu     sync.Mutex
+	path   string
+	trades map[uuid.UUID][]byte
+}
+
+func NewFileTradeRepository(path string) (*FileTradeRepository, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create trades directory: %w", err)
+		}
+	}
+
+	r := &FileTradeRepository{
+		path:   path,
+		trades: make(map[uuid.UUID][]byte),
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trades file: %w", err)
+	}
+
+	var stored map[uuid.UUID]json.RawMessage
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("corrupted trades file: %w", err)
+	}
+	for id, trade := range stored {
+		r.trades[id] = trade
+	}
+
+	return r, nil
+}
+
+func (r *FileTradeRepository) Save(trade *domain.Trade) error {
+	data, err := json.Marshal(trade)
+	if err != nil {
+		return fmt.Errorf("failed to marshal trade: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if trade.Status.IsOpen() {
+		r.trades[trade.ID] = data
+	} else if _, ok := r.trades[trade.ID]; ok {
+		delete(r.trades, trade.ID)
+	} else {
+		return nil
+	}
+
+	return r.flush()
+}
+
+func (r *FileTradeRepository) Driver() string {
+	return "file"
+}
+
+// Ping создает и удаляет пробный файл рядом с файлом сделок: так видны и полный диск,
+// и потерянные права на каталог.
+func (r *FileTradeRepository) Ping() error {
+	probe, err := os.CreateTemp(filepath.Dir(r.path), ".ping-*")
+	if err != nil {
+		return fmt.Errorf("trades directory is not writable: %w", err)
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
+
+func (r *FileTradeRepository) LoadActive() ([]*domain.Trade, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return decodeActiveTrades(r.trades)
+}
+
+func (r *FileTradeRepository) flush() error {
+	stored := make(map[uuid.UUID]json.RawMessage, len(r.trades))
+	for id, trade := range r.trades {
+		stored[id] = trade
+	}
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("failed to marshal trades: %w", err)
+	}
+
+	tmp := r.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write trades file: %w", err)
+	}
+	if err := os.Rename(tmp, r.path); err != nil {
+		return fmt.Errorf("failed to replace trades file: %w", err)
+	}
+
+	return nil
+}
+
+func decodeActiveTrades(stored map[uuid.UUID][]byte) ([]*domain.Trade, error) {
+	trades := make([]*domain.Trade, 0, len(stored))
+	for id, data := range stored {
+		var trade domain.Trade
+		if err := json.Unmarshal(data, &trade); err != nil {
+			return nil, fmt.Errorf("failed to decode trade %s: %w", id, err)
+		}
+		if trade.Status.IsOpen() {
+			trades = append(trades, &trade)
+		}
+	}
+
+	return trades, nil
+}
@@ -0,0 +1,101 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "cryptorg"
+
+// spanKey — ключ, под которым роутер кладёт span запроса в fasthttp.RequestCtx.
+type spanKey struct{}
+
+type Options struct {
+	ServiceName string
+	Version     string
+	Endpoint    string
+	Insecure    bool
+	SampleRatio float64
+}
+
+// Setup регистрирует глобальный TracerProvider с OTLP/HTTP экспортером.
+// Возвращает функцию, которая сбрасывает буфер и останавливает экспортер.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	exporterOpts := []otlptracehttp.Option{}
+	if opts.Endpoint != "" {
+		exporterOpts = append(exporterOpts, otlptracehttp.WithEndpoint(opts.Endpoint))
+	}
+	if opts.Insecure {
+		exporterOpts = append(exporterOpts, otlptracehttp.WithInsecure())
+	}
+
+	exporter, err := otlptracehttp.New(ctx, exporterOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	res := resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(opts.ServiceName),
+		semconv.ServiceVersion(opts.Version),
+	)
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return provider.Shutdown, nil
+}
+
+// Start открывает дочерний span. Если ctx — это fasthttp.RequestCtx, родителем
+// становится span HTTP запроса, привязанный через Bind.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		if parent, ok := ctx.Value(spanKey{}).(trace.Span); ok {
+			ctx = trace.ContextWithSpan(ctx, parent)
+		}
+	}
+
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// Bind сохраняет span в хранилище значений запроса, не завязывая пакет на fasthttp.
+func Bind(holder interface{ SetUserValue(key, value interface{}) }, span trace.Span) {
+	holder.SetUserValue(spanKey{}, span)
+}
+
+// End помечает span ошибкой, если она есть, и закрывает его.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func TradeID(id string) attribute.KeyValue {
+	return attribute.String("trade.id", id)
+}
+
+func OrderID(id string) attribute.KeyValue {
+	return attribute.String("order.id", id)
+}
+
+func Symbol(symbol string) attribute.KeyValue {
+	return attribute.String("trade.symbol", symbol)
+}
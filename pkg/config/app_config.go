@@ -1,6 +1,10 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
 	"strings"
 
 	"github.com/joho/godotenv"
@@ -19,6 +23,7 @@ type BaseConfig struct {
 	ServiceID   string `envconfig:"SERVICE_ID" default:"cryptorg-bot"`
 	LogLevel    string `envconfig:"LOG_LEVEL" default:"info"`
 	LogFormat   string `envconfig:"LOG_FORMAT" default:"json"`
+	Language    string `envconfig:"LANGUAGE" default:"en"` // Язык уведомлений и ответов API без Accept-Language: en, ru
 }
 
 func (c *BaseConfig) IsLocal() bool {
@@ -36,35 +41,251 @@ func (c *BaseConfig) IsDevelopment() bool {
 }
 
 type ServerConfig struct {
-	Port         string `envconfig:"SERVER_PORT" default:"8080"`
-	ReadTimeout  int    `envconfig:"SERVER_READ_TIMEOUT" default:"30"`
-	WriteTimeout int    `envconfig:"SERVER_WRITE_TIMEOUT" default:"30"`
-	IdleTimeout  int    `envconfig:"SERVER_IDLE_TIMEOUT" default:"60"`
+	Port               string            `envconfig:"SERVER_PORT" default:"8080"`
+	ReadTimeout        int               `envconfig:"SERVER_READ_TIMEOUT" default:"30"`
+	WriteTimeout       int               `envconfig:"SERVER_WRITE_TIMEOUT" default:"30"`
+	IdleTimeout        int               `envconfig:"SERVER_IDLE_TIMEOUT" default:"60"`
+	AccessLog          bool              `envconfig:"HTTP_ACCESS_LOG" default:"true"`       // JSON строка на каждый запрос; статистика /api/admin/http-stats ведется всегда
+	PublicStats        bool              `envconfig:"PUBLIC_STATS_ENABLED" default:"false"` // Публичный GET /public/stats без сумм и символов
+	NumberFormat       string            `envconfig:"JSON_NUMBER_FORMAT" default:"string"`  // string или number; клиент может выбрать через Accept: application/json; profile=number
+	OrderWebhookSecret string            `envconfig:"ORDER_WEBHOOK_SECRET"`                 // HMAC-SHA256 подпись тела /api/webhook/order-update в X-Webhook-Signature; пусто - без подписи
+	APIKeys            map[string]string `envconfig:"API_KEYS"`                             // Ключи доступа к /api с ролью read, trade или admin: token1:admin,token2:read; пусто - без проверки
+}
+
+type WorkerConfig struct {
+	SchedulerInterval     int  `envconfig:"SCHEDULER_INTERVAL" default:"60"`
+	FillWorkers           int  `envconfig:"FILL_WORKERS" default:"4"`                // Воркеры обработки исполнений из вебхука
+	FillQueueSize         int  `envconfig:"FILL_QUEUE_SIZE" default:"256"`           // Очередь на воркер; при переполнении вебхук получает 503
+	ConsistencyAutoRepair bool `envconfig:"CONSISTENCY_AUTO_REPAIR" default:"false"` // Исправлять простые расхождения сделок с биржей
+	ReconcileOnStartup    bool `envconfig:"RECONCILE_ON_STARTUP" default:"true"`     // Сверка с исправлениями до запуска планировщика
+	OrderPollInterval     int  `envconfig:"ORDER_POLL_INTERVAL" default:"30"`        // Секунды между опросами статусов ордеров; 0 - только вебхук
+	OrderPollPerSymbol    int  `envconfig:"ORDER_POLL_PER_SYMBOL" default:"5"`       // Запросов статуса на символ за один опрос
+}
+
+type ExchangeConfig struct {
+	Name             string             `envconfig:"EXCHANGE" default:"bybit"`
+	Extra            []string           `envconfig:"EXCHANGES"` // Дополнительные биржи, которые можно выбрать у бота: okx
+	OrderCacheTTL    int                `envconfig:"ORDER_CACHE_TTL" default:"30"`
+	QuoteBudgets     map[string]float64 `envconfig:"QUOTE_BUDGETS"`                      // Бюджет по котируемым валютам: USDT:1000,USDC:500
+	DailyLossLimits  map[string]float64 `envconfig:"DAILY_LOSS_LIMITS"`                  // Убыток за 24 часа, после которого новые циклы останавливаются: USDT:100
+	StopLossCooldown int                `envconfig:"STOP_LOSS_COOLDOWN" default:"0"`     // Секунды без новых сделок по символу после выхода по стопу; 0 - выключено
+	RawPayloads      bool               `envconfig:"ORDER_RAW_PAYLOADS" default:"false"` // Сохранять исходные ответы биржи по ордерам
+	SymbolAllowlist  []string           `envconfig:"SYMBOL_ALLOWLIST"`                   // Шаблоны разрешенных символов: BTC*,ETHUSDT; пусто - все
+	SymbolDenylist   []string           `envconfig:"SYMBOL_DENYLIST"`                    // Шаблоны запрещенных символов, важнее разрешений: USDC*,*DAI*
+	ApprovalLimits   map[string]float64 `envconfig:"APPROVAL_THRESHOLDS"`                // Капитал сделки, выше которого нужно подтверждение оператора: USDT:5000
+}
+
+// HasAdminKey - среди API_KEYS есть ключ с ролью admin. Без него подтверждение сделок
+// через API выключено.
+func (c ServerConfig) HasAdminKey() bool {
+	for _, role := range c.APIKeys {
+		if role == "admin" {
+			return true
+		}
+	}
+	return false
+}
+
+// Enabled возвращает биржу по умолчанию и дополнительные биржи без повторов.
+func (c ExchangeConfig) Enabled() []string {
+	names := []string{c.Name}
+	for _, name := range c.Extra {
+		if name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+type OKXConfig struct {
+	APIKey     string `envconfig:"OKX_API_KEY"`
+	SecretKey  string `envconfig:"OKX_API_SECRET"`
+	Passphrase string `envconfig:"OKX_PASSPHRASE"`
+	Demo       bool   `envconfig:"OKX_DEMO" default:"false"`
+}
+
+type StorageConfig struct {
+	JournalPath            string `envconfig:"JOURNAL_PATH" default:""`
+	TradesPath             string `envconfig:"TRADES_PATH" default:""`                                  // Снимки открытых сделок для восстановления после перезапуска
+	TradesDBDriver         string `envconfig:"TRADES_DB_DRIVER" default:"" yaml:"trades_db_driver"`     // postgres или sqlite; задан - сделки хранятся в базе вместо TRADES_PATH
+	TradesDBDSN            string `envconfig:"TRADES_DB_DSN" default:"" yaml:"trades_db_dsn"`           // Строка подключения: postgres://... или путь к файлу SQLite
+	ExecutionsPath         string `envconfig:"EXECUTIONS_PATH" default:""`                              // ID обработанных исполнений для отсева повторной доставки
+	BotsPath               string `envconfig:"BOTS_PATH" default:""`                                    // Боты, открывающие сделки по шаблону
+	PnLRevisionsPath       string `envconfig:"PNL_REVISIONS_PATH" default:"" yaml:"pnl_revisions_path"` // Ревизии пересчета PnL закрытых сделок
+	PrecisionPath          string `envconfig:"PRECISION_OVERRIDES_PATH" default:""`
+	CooldownsPath          string `envconfig:"COOLDOWNS_PATH" default:""`                    // Паузы символов после стопа
+	MessageCapturePath     string `envconfig:"MESSAGE_CAPTURE_PATH" default:""`              // Сырые сообщения биржи для разбора пропущенных исполнений; пусто - не сохраняются
+	MessageCaptureMaxBytes int64  `envconfig:"MESSAGE_CAPTURE_MAX_BYTES" default:"10485760"` // Предел размера сохраненных сообщений на диске
+	BackupDir              string `envconfig:"BACKUP_DIR" default:"data/backups"`
+}
+
+type MetricsConfig struct {
+	Backend    string `envconfig:"METRICS_BACKEND" default:"none"`
+	Prefix     string `envconfig:"METRICS_PREFIX" default:"cryptorg"`
+	StatsDAddr string `envconfig:"STATSD_ADDR" default:"127.0.0.1:8125" yaml:"statsd_addr"`
+}
+
+type TracingConfig struct {
+	Enabled     bool    `envconfig:"TRACING_ENABLED" default:"false"`
+	Endpoint    string  `envconfig:"TRACING_OTLP_ENDPOINT" default:"localhost:4318"`
+	Insecure    bool    `envconfig:"TRACING_OTLP_INSECURE" default:"true"`
+	SampleRatio float64 `envconfig:"TRACING_SAMPLE_RATIO" default:"1"`
+}
+
+// TradeDefaultsConfig - значения TradeConfig по умолчанию для POST /api/trades.
+// Файл (JSON в формате TradeConfig) читается первым, заданные переменные окружения перекрывают его.
+type TradeDefaultsConfig struct {
+	File              string  `envconfig:"TRADE_DEFAULTS_FILE"`
+	EntryVolume       string  `envconfig:"TRADE_DEFAULT_ENTRY_VOLUME"`
+	DCAVolume         string  `envconfig:"TRADE_DEFAULT_DCA_VOLUME"`
+	DCACount          int     `envconfig:"TRADE_DEFAULT_DCA_COUNT"`
+	DCAStepPercent    float64 `envconfig:"TRADE_DEFAULT_DCA_STEP_PERCENT"`
+	TakeProfitPercent float64 `envconfig:"TRADE_DEFAULT_TAKE_PROFIT_PERCENT"`
+	Martingale        float64 `envconfig:"TRADE_DEFAULT_MARTINGALE"`
+	StopLossPercent   float64 `envconfig:"TRADE_DEFAULT_STOP_LOSS_PERCENT"`
+}
+
+type NotifyConfig struct {
+	TelegramToken  string `envconfig:"TELEGRAM_BOT_TOKEN"`
+	TelegramChatID string `envconfig:"TELEGRAM_CHAT_ID"`
+	WebhookSecret  string `envconfig:"TELEGRAM_WEBHOOK_SECRET"`        // X-Telegram-Bot-Api-Secret-Token вебхука кнопок подтверждения
+	RetryBase      int    `envconfig:"NOTIFY_RETRY_BASE" default:"5"`  // Секунды до первой повторной попытки
+	RetryMax       int    `envconfig:"NOTIFY_RETRY_MAX" default:"300"` // Потолок задержки между попытками
+	MaxAge         int    `envconfig:"NOTIFY_MAX_AGE" default:"3600"`  // Секунды, после которых уведомление считается недоставленным
+}
+
+type SignalConfig struct {
+	Cooldown        int            `envconfig:"SIGNAL_COOLDOWN" default:"60"`      // Секунды между входами по одному символу
+	SymbolCooldowns map[string]int `envconfig:"SIGNAL_SYMBOL_COOLDOWNS"`           // Переопределения по символам: BTCUSDT:300,SOLUSDT:120
+	DedupWindow     int            `envconfig:"SIGNAL_DEDUP_WINDOW" default:"300"` // Секунды, в течение которых одинаковый payload считается дублем
+}
+
+type ReportConfig struct {
+	Timezone     string `envconfig:"REPORT_TIMEZONE" default:"UTC"`        // IANA имя, например Europe/Moscow
+	DailyEnabled bool   `envconfig:"REPORT_DAILY_ENABLED" default:"false"` // Отправлять ежедневный отчет в уведомления
+	DeliveryHour int    `envconfig:"REPORT_DELIVERY_HOUR" default:"9"`     // Час отправки в часовом поясе отчетов
+}
+
+// ExportConfig - выгрузка закрытых сделок в Google таблицу через сервисный аккаунт.
+type ExportConfig struct {
+	SheetsCredentialsFile string `envconfig:"GOOGLE_SHEETS_CREDENTIALS_FILE"`           // JSON ключ сервисного аккаунта
+	SheetsSpreadsheetID   string `envconfig:"GOOGLE_SHEETS_SPREADSHEET_ID"`             // Пусто - выгрузка выключена
+	SheetsRange           string `envconfig:"GOOGLE_SHEETS_RANGE" default:"Trades!A:K"` // Лист и столбцы, после которых дописываются строки
+	QueueSize             int    `envconfig:"EXPORT_QUEUE_SIZE" default:"256"`          // Сделки сверх очереди не выгружаются
+}
+
+type FeatureConfig struct {
+	File      string          `envconfig:"FEATURE_FLAGS_FILE"` // JSON с секцией default и секциями окружений
+	Overrides map[string]bool `envconfig:"FEATURE_FLAGS"`      // Переопределения: tp_amend:true,ws_fills:false
 }
 
 type BybitConfig struct {
-	APIKey    string `envconfig:"BYBIT_API_KEY" required:"true"`
-	SecretKey string `envconfig:"BYBIT_API_SECRET" required:"true"`
+	APIKey    string `envconfig:"BYBIT_API_KEY"` // Обязателен, если Bybit среди EXCHANGE/EXCHANGES
+	SecretKey string `envconfig:"BYBIT_API_SECRET"`
 	Testnet   bool   `envconfig:"BYBIT_TESTNET" default:"false"`
 	Symbol    string `envconfig:"SYMBOL" default:"SOLUSDT"`
+
+	FixturesMode string `envconfig:"BYBIT_FIXTURES_MODE"`                         // record или replay, пусто - обычная работа
+	FixturesDir  string `envconfig:"BYBIT_FIXTURES_DIR" default:"testdata/bybit"` // Каталог фикстур ответов
 }
 
 type Config struct {
-	Base   BaseConfig   `envconfig:""`
-	Server ServerConfig `envconfig:""`
-	Bybit  BybitConfig  `envconfig:""`
+	Base     BaseConfig          `envconfig:""`
+	Server   ServerConfig        `envconfig:""`
+	Exchange ExchangeConfig      `envconfig:""`
+	Bybit    BybitConfig         `envconfig:""`
+	OKX      OKXConfig           `envconfig:""`
+	Worker   WorkerConfig        `envconfig:""`
+	Storage  StorageConfig       `envconfig:""`
+	Metrics  MetricsConfig       `envconfig:""`
+	Tracing  TracingConfig       `envconfig:""`
+	Trade    TradeDefaultsConfig `envconfig:""`
+	Notify   NotifyConfig        `envconfig:""`
+	Signal   SignalConfig        `envconfig:""`
+	Report   ReportConfig        `envconfig:""`
+	Feature  FeatureConfig       `envconfig:""`
+	Export   ExportConfig        `envconfig:""`
 }
 
+// Load читает конфигурацию: значения по умолчанию, затем файл CONFIG_FILE (YAML),
+// затем .env и переменные окружения.
 func Load() (*Config, error) {
 	_ = godotenv.Load()
 
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := applyFile(path); err != nil {
+			return nil, err
+		}
+	}
+
 	cfg, err := LoadFromENV[Config]()
 	if err != nil {
 		return nil, err
 	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
 	return &cfg, nil
 }
 
+// Validate проверяет значения, которые envconfig принимает по типу, но бот не поддерживает.
+func (c *Config) Validate() error {
+	var errs []error
+	for _, name := range c.Exchange.Enabled() {
+		switch name {
+		case "bybit":
+			if c.Bybit.APIKey == "" || c.Bybit.SecretKey == "" {
+				errs = append(errs, fmt.Errorf("BYBIT_API_KEY and BYBIT_API_SECRET are required for bybit"))
+			}
+		case "okx":
+			if c.OKX.APIKey == "" || c.OKX.SecretKey == "" || c.OKX.Passphrase == "" {
+				errs = append(errs, fmt.Errorf("OKX_API_KEY, OKX_API_SECRET and OKX_PASSPHRASE are required for okx"))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("EXCHANGE and EXCHANGES must be bybit or okx, got %q", name))
+		}
+	}
+	if mode := c.Bybit.FixturesMode; mode != "" && mode != "record" && mode != "replay" {
+		errs = append(errs, fmt.Errorf("BYBIT_FIXTURES_MODE must be record or replay, got %q", mode))
+	}
+	if c.Worker.SchedulerInterval <= 0 {
+		errs = append(errs, fmt.Errorf("SCHEDULER_INTERVAL must be positive"))
+	}
+	switch c.Storage.TradesDBDriver {
+	case "":
+	case "postgres", "sqlite":
+		if c.Storage.TradesDBDSN == "" {
+			errs = append(errs, fmt.Errorf("TRADES_DB_DSN is required with TRADES_DB_DRIVER"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("TRADES_DB_DRIVER must be postgres or sqlite, got %q", c.Storage.TradesDBDriver))
+	}
+	if c.Storage.MessageCapturePath != "" && c.Storage.MessageCaptureMaxBytes <= 0 {
+		errs = append(errs, fmt.Errorf("MESSAGE_CAPTURE_MAX_BYTES must be positive"))
+	}
+	if c.Exchange.StopLossCooldown < 0 {
+		errs = append(errs, fmt.Errorf("STOP_LOSS_COOLDOWN must not be negative"))
+	}
+	if c.Worker.OrderPollInterval < 0 {
+		errs = append(errs, fmt.Errorf("ORDER_POLL_INTERVAL must not be negative"))
+	}
+	if c.Report.DeliveryHour < 0 || c.Report.DeliveryHour > 23 {
+		errs = append(errs, fmt.Errorf("REPORT_DELIVERY_HOUR must be between 0 and 23"))
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("TRACING_SAMPLE_RATIO must be between 0 and 1"))
+	}
+	for _, role := range c.Server.APIKeys {
+		if role != "read" && role != "trade" && role != "admin" {
+			errs = append(errs, fmt.Errorf("API_KEYS roles must be read, trade or admin, got %q", role))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid config: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
 func (c *Config) GetLoggerConfig() map[string]interface{} {
 	return map[string]interface{}{
 		"level":  c.Base.LogLevel,
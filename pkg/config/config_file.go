@@ -0,0 +1,194 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Файл CONFIG_FILE задает те же параметры, что и переменные окружения, по секциям Config:
+//
+//	exchange:
+//	  quote_budgets: {USDT: 1000, USDC: 500}
+//	bybit:
+//	  api_key: ...
+//
+// Ключ - имя поля в snake_case или тег yaml. Переменная окружения важнее файла,
+// файл важнее значения по умолчанию.
+
+// configField - параметр конфигурации: ключ в файле и переменная окружения.
+type configField struct {
+	section string
+	key     string
+	env     string
+	secret  bool
+}
+
+// configFields перечисляет параметры Config в порядке объявления.
+func configFields() []configField {
+	fields := make([]configField, 0)
+	root := reflect.TypeOf(Config{})
+	for i := 0; i < root.NumField(); i++ {
+		section := root.Field(i)
+		for j := 0; j < section.Type.NumField(); j++ {
+			field := section.Type.Field(j)
+			fields = append(fields, configField{
+				section: fileKey(section),
+				key:     fileKey(field),
+				env:     field.Tag.Get("envconfig"),
+				secret:  isSecret(field.Name),
+			})
+		}
+	}
+	return fields
+}
+
+func fileKey(field reflect.StructField) string {
+	if key := field.Tag.Get("yaml"); key != "" {
+		return key
+	}
+	return snakeCase(field.Name)
+}
+
+// snakeCase: APIKey -> api_key, SheetsSpreadsheetID -> sheets_spreadsheet_id.
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prevLower := unicode.IsLower(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+func isSecret(name string) bool {
+	for _, marker := range []string{"Key", "Secret", "Token", "Passphrase", "DSN"} {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyFile выставляет значения из файла в переменные окружения, которые еще не заданы,
+// чтобы разбор, значения по умолчанию и обязательность остались за envconfig.
+func applyFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var file map[string]map[string]interface{}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	known := make(map[string]configField)
+	sections := make(map[string]bool)
+	for _, field := range configFields() {
+		known[field.section+"."+field.key] = field
+		sections[field.section] = true
+	}
+
+	var errs []error
+	for section, values := range file {
+		if !sections[section] {
+			errs = append(errs, fmt.Errorf("unknown section %q", section))
+			continue
+		}
+		for key, value := range values {
+			field, ok := known[section+"."+key]
+			if !ok {
+				errs = append(errs, fmt.Errorf("unknown key %s.%s", section, key))
+				continue
+			}
+			if _, set := os.LookupEnv(field.env); set || value == nil {
+				continue
+			}
+			encoded, err := envValue(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s.%s: %w", section, key, err))
+				continue
+			}
+			os.Setenv(field.env, encoded)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid config file %s: %w", path, errors.Join(errs...))
+	}
+	return nil
+}
+
+// envValue кодирует значение из файла в формат envconfig: списки через запятую,
+// словари как key:value через запятую.
+func envValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			encoded, err := envValue(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, encoded)
+		}
+		return strings.Join(items, ","), nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		pairs := make([]string, 0, len(v))
+		for _, key := range keys {
+			encoded, err := envValue(v[key])
+			if err != nil {
+				return "", err
+			}
+			pairs = append(pairs, key+":"+encoded)
+		}
+		return strings.Join(pairs, ","), nil
+	case string, bool, int, float64:
+		return fmt.Sprint(v), nil
+	}
+	return "", fmt.Errorf("unsupported value %v", value)
+}
+
+// Effective возвращает итоговую конфигурацию в формате файла; секреты скрыты.
+func (c *Config) Effective() ([]byte, error) {
+	result := make(map[string]map[string]interface{})
+	root := reflect.ValueOf(*c)
+	fields := configFields()
+
+	n := 0
+	for i := 0; i < root.NumField(); i++ {
+		section := root.Field(i)
+		for j := 0; j < section.NumField(); j++ {
+			field := fields[n]
+			n++
+
+			value := section.Field(j).Interface()
+			if field.secret && !section.Field(j).IsZero() {
+				value = "***"
+			}
+			if result[field.section] == nil {
+				result[field.section] = make(map[string]interface{})
+			}
+			result[field.section][field.key] = value
+		}
+	}
+	return yaml.Marshal(result)
+}
@@ -8,11 +8,12 @@ import (
 type ErrorType string
 
 const (
-	ErrorTypeValidation ErrorType = "validation"
-	ErrorTypeDomain     ErrorType = "domain"
-	ErrorTypeExternal   ErrorType = "external"
-	ErrorTypeInternal   ErrorType = "internal"
-	ErrorTypeNotFound   ErrorType = "not_found"
+	ErrorTypeValidation  ErrorType = "validation"
+	ErrorTypeDomain      ErrorType = "domain"
+	ErrorTypeExternal    ErrorType = "external"
+	ErrorTypeInternal    ErrorType = "internal"
+	ErrorTypeNotFound    ErrorType = "not_found"
+	ErrorTypeRateLimited ErrorType = "rate_limited"
 )
 
 type AppError struct {
@@ -76,6 +77,17 @@ func NotFoundError(resource, identifier string) *AppError {
 	}
 }
 
+func RateLimitedError(service, message string) *AppError {
+	return &AppError{
+		Type:    ErrorTypeRateLimited,
+		Code:    "RATE_LIMITED",
+		Message: fmt.Sprintf("external service '%s' rate limit: %s", service, message),
+		Details: map[string]interface{}{
+			"service": service,
+		},
+	}
+}
+
 func (e *AppError) GetHTTPStatus() int {
 	switch e.Type {
 	case ErrorTypeValidation:
@@ -88,6 +100,8 @@ func (e *AppError) GetHTTPStatus() int {
 		return http.StatusInternalServerError
 	case ErrorTypeNotFound:
 		return http.StatusNotFound
+	case ErrorTypeRateLimited:
+		return http.StatusTooManyRequests
 	default:
 		return http.StatusInternalServerError
 	}
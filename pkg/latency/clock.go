@@ -0,0 +1,49 @@
+package latency
+
+import (
+	"sync"
+	"time"
+)
+
+// clockSmoothing - доля нового замера в оценке смещения; сглаживает разброс сети.
+const clockSmoothing = 0.2
+
+// ClockOffset оценивает, насколько часы биржи опережают локальные. Замер - время
+// сервера из ответа против середины запроса, так половина задержки не попадает в смещение.
+type ClockOffset struct {
+	mu       sync.Mutex
+	offset   time.Duration
+	observed bool
+}
+
+func NewClockOffset() *ClockOffset {
+	return &ClockOffset{}
+}
+
+// Observe учитывает время сервера из ответа на запрос, отправленный в sent и полученный в received.
+func (c *ClockOffset) Observe(server, sent, received time.Time) {
+	sample := server.Sub(sent.Add(received.Sub(sent) / 2))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.observed {
+		c.offset = sample
+		c.observed = true
+		return
+	}
+	c.offset += time.Duration(float64(sample-c.offset) * clockSmoothing)
+}
+
+// Offset - текущая оценка; до первого замера 0.
+func (c *ClockOffset) Offset() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.offset
+}
+
+// ToLocal переводит время биржи в локальные часы.
+func (c *ClockOffset) ToLocal(server time.Time) time.Time {
+	return server.Add(-c.Offset())
+}
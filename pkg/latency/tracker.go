@@ -0,0 +1,74 @@
+package latency
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const DefaultWindow = 1024
+
+type Tracker struct {
+	mu      sync.Mutex
+	samples []time.Duration
+	next    int
+	full    bool
+}
+
+func NewTracker(window int) *Tracker {
+	if window <= 0 {
+		window = DefaultWindow
+	}
+	return &Tracker{
+		samples: make([]time.Duration, window),
+	}
+}
+
+func (t *Tracker) Record(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.samples[t.next] = d
+	t.next++
+	if t.next == len(t.samples) {
+		t.next = 0
+		t.full = true
+	}
+}
+
+func (t *Tracker) Count() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.full {
+		return len(t.samples)
+	}
+	return t.next
+}
+
+// Percentile возвращает p-й перцентиль (0..100) по скользящему окну.
+func (t *Tracker) Percentile(p float64) time.Duration {
+	t.mu.Lock()
+	count := t.next
+	if t.full {
+		count = len(t.samples)
+	}
+	sorted := make([]time.Duration, count)
+	copy(sorted, t.samples[:count])
+	t.mu.Unlock()
+
+	if count == 0 {
+		return 0
+	}
+
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	idx := int(float64(count-1) * p / 100)
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= count {
+		idx = count - 1
+	}
+	return sorted[idx]
+}
@@ -0,0 +1,66 @@
+package pricing
+
+import "github.com/shopspring/decimal"
+
+// LadderParams - параметры лестницы DCA. Первый уровень имеет объем Volume, каждый
+// следующий - объем предыдущего, умноженный на Martingale (0 - без мартингейла).
+// При LegacyMartingale множитель применяется уже к первому уровню.
+type LadderParams struct {
+	Count            int
+	StepPercent      float64
+	DynamicStep      bool // Шаг i-го уровня равен StepPercent * i
+	Volume           decimal.Decimal
+	Martingale       decimal.Decimal
+	LegacyMartingale bool
+	MinVolume        decimal.Decimal // Уровни меньше поднимаются до него, не влияя на мартингейл
+	Short            bool
+}
+
+// Rung - уровень лестницы с отклонением от цены входа в процентах.
+type Rung struct {
+	Index            int
+	Price            decimal.Decimal
+	Volume           decimal.Decimal
+	DeviationPercent decimal.Decimal
+	Bumped           bool
+}
+
+// Ladder строит уровни DCA от цены входа. Для неположительной цены уровней нет.
+func Ladder(entry decimal.Decimal, params LadderParams) []Rung {
+	rungs := make([]Rung, 0, params.Count)
+	if !entry.IsPositive() {
+		return rungs
+	}
+
+	price := entry
+	volume := params.Volume
+	deviationSign := decimal.NewFromFloat(sign(params.Short) * 100)
+
+	for i := 0; i < params.Count; i++ {
+		step := params.StepPercent
+		if params.DynamicStep {
+			step *= float64(i + 1)
+		}
+		price = DCAPrice(price, step, params.Short)
+
+		if params.Martingale.IsPositive() && (i > 0 || params.LegacyMartingale) {
+			volume = volume.Mul(params.Martingale)
+		}
+
+		level := volume
+		bumped := level.LessThan(params.MinVolume)
+		if bumped {
+			level = params.MinVolume
+		}
+
+		rungs = append(rungs, Rung{
+			Index:            i + 1,
+			Price:            price,
+			Volume:           level,
+			DeviationPercent: entry.Sub(price).Div(entry).Mul(deviationSign),
+			Bumped:           bumped,
+		})
+	}
+
+	return rungs
+}
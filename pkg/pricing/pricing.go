@@ -0,0 +1,95 @@
+// Package pricing - расчеты цен и объемов сетки в десятичной арифметике: TP, шаг DCA,
+// лестница уровней с мартингейлом, средняя цена и безубыток с комиссиями.
+// Для лонга уровни DCA ниже входа, а TP выше; для шорта наоборот.
+package pricing
+
+import (
+	"errors"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	one     = decimal.NewFromInt(1)
+	hundred = decimal.NewFromInt(100)
+)
+
+// Fees - комиссии входа и выхода в долях: 0.001 = 0.1%.
+type Fees struct {
+	Entry decimal.Decimal
+	Exit  decimal.Decimal
+}
+
+// FeesFromPercent переводит комиссии из процентов в доли.
+func FeesFromPercent(entryPercent, exitPercent float64) Fees {
+	return Fees{
+		Entry: decimal.NewFromFloat(entryPercent).Div(hundred),
+		Exit:  decimal.NewFromFloat(exitPercent).Div(hundred),
+	}
+}
+
+// Factor возвращает множитель 1 + percent/100.
+func Factor(percent float64) decimal.Decimal {
+	return one.Add(decimal.NewFromFloat(percent).Div(hundred))
+}
+
+func sign(short bool) float64 {
+	if short {
+		return -1
+	}
+	return 1
+}
+
+// TakeProfit - цена TP от средней цены: выше на percent для лонга, ниже для шорта.
+// С комиссиями цена отодвигается так, чтобы percent остался чистой прибылью.
+func TakeProfit(average decimal.Decimal, percent float64, short bool, fees *Fees) decimal.Decimal {
+	price := average.Mul(Factor(sign(short) * percent))
+	if fees != nil {
+		price = withFees(price, short, *fees)
+	}
+	return price
+}
+
+// BreakEven - цена выхода, при которой комиссии входа и выхода покрыты и PnL равен нулю.
+func BreakEven(average decimal.Decimal, short bool, fees Fees) decimal.Decimal {
+	return withFees(average, short, fees)
+}
+
+func withFees(price decimal.Decimal, short bool, fees Fees) decimal.Decimal {
+	if short {
+		return price.Mul(one.Sub(fees.Entry)).Div(one.Add(fees.Exit))
+	}
+	return price.Mul(one.Add(fees.Entry)).Div(one.Sub(fees.Exit))
+}
+
+// DCAPrice - цена следующего уровня: ниже на stepPercent для лонга, выше для шорта.
+func DCAPrice(price decimal.Decimal, stepPercent float64, short bool) decimal.Decimal {
+	return price.Mul(Factor(-sign(short) * stepPercent))
+}
+
+// QuantityFromQuote переводит сумму в котируемой валюте в количество базовой монеты.
+func QuantityFromQuote(amount, price decimal.Decimal) (decimal.Decimal, error) {
+	if !price.IsPositive() {
+		return decimal.Zero, errors.New("price must be positive")
+	}
+	return amount.Div(price), nil
+}
+
+// Fill - исполнение по цене Price в количестве Quantity.
+type Fill struct {
+	Price    decimal.Decimal
+	Quantity decimal.Decimal
+}
+
+// AveragePrice возвращает среднюю цену исполнений, взвешенную по количеству, и общее количество.
+func AveragePrice(fills []Fill) (average, quantity decimal.Decimal, err error) {
+	cost := decimal.Zero
+	for _, fill := range fills {
+		quantity = quantity.Add(fill.Quantity)
+		cost = cost.Add(fill.Price.Mul(fill.Quantity))
+	}
+	if quantity.IsZero() {
+		return decimal.Zero, decimal.Zero, errors.New("total volume is zero")
+	}
+	return cost.Div(quantity), quantity, nil
+}
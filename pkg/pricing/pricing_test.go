@@ -0,0 +1,258 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func d(value string) decimal.Decimal {
+	return decimal.RequireFromString(value)
+}
+
+func assertDecimal(t *testing.T, want string, got decimal.Decimal, msgAndArgs ...interface{}) {
+	t.Helper()
+	assert.True(t, d(want).Equal(got), append([]interface{}{"want %s, got %s", want, got.String()}, msgAndArgs...)...)
+}
+
+func TestFeesFromPercent(t *testing.T) {
+	fees := FeesFromPercent(0.1, 0.2)
+	assertDecimal(t, "0.001", fees.Entry)
+	assertDecimal(t, "0.002", fees.Exit)
+}
+
+func TestFactorIsExact(t *testing.T) {
+	// 0.1 и 0.7 не представимы во float64, но множитель получается точным
+	assertDecimal(t, "1.001", Factor(0.1))
+	assertDecimal(t, "0.993", Factor(-0.7))
+	assertDecimal(t, "1", Factor(0))
+}
+
+func TestTakeProfit(t *testing.T) {
+	fees := FeesFromPercent(0.1, 0.1)
+
+	tests := []struct {
+		name  string
+		short bool
+		fees  *Fees
+		want  string
+	}{
+		{name: "long", want: "101"},
+		{name: "short", short: true, want: "99"},
+		{name: "long with fees", fees: &fees, want: "101.2022022"},
+		{name: "short with fees", short: true, fees: &fees, want: "98.8021978"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertDecimal(t, tt.want, TakeProfit(d("100"), 1, tt.short, tt.fees).Round(8))
+		})
+	}
+}
+
+func TestTakeProfitWithFeesKeepsNetPercent(t *testing.T) {
+	fees := FeesFromPercent(0.1, 0.1)
+	quantity := d("2")
+	cost := quantity.Mul(d("100")).Mul(one.Add(fees.Entry))
+
+	price := TakeProfit(d("100"), 1, false, &fees)
+	proceeds := quantity.Mul(price).Mul(one.Sub(fees.Exit))
+
+	// Чистая прибыль - ровно 1% от вложенного вместе с комиссией входа
+	assertDecimal(t, cost.Div(hundred).String(), proceeds.Sub(cost).Round(8))
+}
+
+func TestBreakEven(t *testing.T) {
+	fees := FeesFromPercent(0.1, 0.1)
+
+	tests := []struct {
+		name  string
+		short bool
+		fees  Fees
+		want  string
+	}{
+		{name: "long", fees: fees, want: "100.2002002"},
+		{name: "short", short: true, fees: fees, want: "99.8001998"},
+		{name: "without fees", want: "100"},
+		{name: "maker and taker", fees: FeesFromPercent(0.02, 0.055), want: "100.07504127"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertDecimal(t, tt.want, BreakEven(d("100"), tt.short, tt.fees).Round(8))
+		})
+	}
+}
+
+func TestBreakEvenZeroesPnL(t *testing.T) {
+	fees := FeesFromPercent(0.1, 0.1)
+	quantity := d("0.0123")
+
+	long := BreakEven(d("63000"), false, fees)
+	cost := quantity.Mul(d("63000")).Mul(one.Add(fees.Entry))
+	proceeds := quantity.Mul(long).Mul(one.Sub(fees.Exit))
+	assert.True(t, proceeds.Sub(cost).Abs().LessThan(d("0.00000001")), "long pnl %s", proceeds.Sub(cost))
+
+	// Шорт: продали по средней, откупаем по безубытку
+	short := BreakEven(d("63000"), true, fees)
+	sold := quantity.Mul(d("63000")).Mul(one.Sub(fees.Entry))
+	bought := quantity.Mul(short).Mul(one.Add(fees.Exit))
+	assert.True(t, sold.Sub(bought).Abs().LessThan(d("0.00000001")), "short pnl %s", sold.Sub(bought))
+}
+
+func TestDCAPrice(t *testing.T) {
+	assertDecimal(t, "98", DCAPrice(d("100"), 2, false))
+	assertDecimal(t, "102", DCAPrice(d("100"), 2, true))
+	// Дешевые монеты: точность не теряется на малых ценах
+	assertDecimal(t, "0.00012332655", DCAPrice(d("0.00012345"), 0.1, false))
+	assertDecimal(t, "100", DCAPrice(d("100"), 0, false))
+}
+
+func TestQuantityFromQuote(t *testing.T) {
+	quantity, err := QuantityFromQuote(d("100"), d("63000"))
+	require.NoError(t, err)
+	assertDecimal(t, "0.00158730", quantity.Truncate(8))
+
+	quantity, err = QuantityFromQuote(d("100"), d("3"))
+	require.NoError(t, err)
+	assertDecimal(t, "33.33333333", quantity.Truncate(8))
+
+	_, err = QuantityFromQuote(d("100"), decimal.Zero)
+	assert.Error(t, err)
+	_, err = QuantityFromQuote(d("100"), d("-1"))
+	assert.Error(t, err)
+}
+
+func TestAveragePrice(t *testing.T) {
+	average, quantity, err := AveragePrice([]Fill{
+		{Price: d("100"), Quantity: d("1")},
+		{Price: d("98"), Quantity: d("1.5")},
+	})
+	require.NoError(t, err)
+	assertDecimal(t, "98.8", average)
+	assertDecimal(t, "2.5", quantity)
+
+	average, quantity, err = AveragePrice([]Fill{{Price: d("0.1"), Quantity: d("3")}})
+	require.NoError(t, err)
+	assertDecimal(t, "0.1", average)
+	assertDecimal(t, "3", quantity)
+
+	_, _, err = AveragePrice(nil)
+	assert.Error(t, err)
+	_, _, err = AveragePrice([]Fill{{Price: d("100"), Quantity: decimal.Zero}})
+	assert.Error(t, err)
+}
+
+type rung struct {
+	price     string
+	volume    string
+	deviation string
+	bumped    bool
+}
+
+func TestLadder(t *testing.T) {
+	base := LadderParams{Count: 3, StepPercent: 2, Volume: d("100"), Martingale: d("1.5")}
+
+	tests := []struct {
+		name   string
+		mutate func(params *LadderParams)
+		want   []rung
+	}{
+		{
+			name: "martingale from second level",
+			want: []rung{
+				{price: "98", volume: "100", deviation: "2"},
+				{price: "96.04", volume: "150", deviation: "3.96"},
+				{price: "94.1192", volume: "225", deviation: "5.8808"},
+			},
+		},
+		{
+			name:   "legacy martingale",
+			mutate: func(params *LadderParams) { params.LegacyMartingale = true },
+			want: []rung{
+				{price: "98", volume: "150", deviation: "2"},
+				{price: "96.04", volume: "225", deviation: "3.96"},
+				{price: "94.1192", volume: "337.5", deviation: "5.8808"},
+			},
+		},
+		{
+			name:   "short",
+			mutate: func(params *LadderParams) { params.Short = true },
+			want: []rung{
+				{price: "102", volume: "100", deviation: "2"},
+				{price: "104.04", volume: "150", deviation: "4.04"},
+				{price: "106.1208", volume: "225", deviation: "6.1208"},
+			},
+		},
+		{
+			name:   "dynamic step",
+			mutate: func(params *LadderParams) { params.DynamicStep = true },
+			want: []rung{
+				{price: "98", volume: "100", deviation: "2"},
+				{price: "94.08", volume: "150", deviation: "5.92"},
+				{price: "88.4352", volume: "225", deviation: "11.5648"},
+			},
+		},
+		{
+			name: "fractional martingale stays exact",
+			mutate: func(params *LadderParams) {
+				params.Martingale = d("1.1")
+				params.Count = 4
+			},
+			want: []rung{
+				{price: "98", volume: "100", deviation: "2"},
+				{price: "96.04", volume: "110", deviation: "3.96"},
+				{price: "94.1192", volume: "121", deviation: "5.8808"},
+				{price: "92.236816", volume: "133.1", deviation: "7.763184"},
+			},
+		},
+		{
+			name: "min volume bump does not compound",
+			mutate: func(params *LadderParams) {
+				params.Volume = d("4")
+				params.MinVolume = d("5")
+			},
+			want: []rung{
+				{price: "98", volume: "5", deviation: "2", bumped: true},
+				{price: "96.04", volume: "6", deviation: "3.96"},
+				{price: "94.1192", volume: "9", deviation: "5.8808"},
+			},
+		},
+		{
+			name:   "single level",
+			mutate: func(params *LadderParams) { params.Count = 1 },
+			want:   []rung{{price: "98", volume: "100", deviation: "2"}},
+		},
+		{
+			name:   "no levels",
+			mutate: func(params *LadderParams) { params.Count = 0 },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := base
+			if tt.mutate != nil {
+				tt.mutate(&params)
+			}
+
+			rungs := Ladder(d("100"), params)
+			require.Len(t, rungs, len(tt.want))
+			for i, want := range tt.want {
+				assert.Equal(t, i+1, rungs[i].Index)
+				assertDecimal(t, want.price, rungs[i].Price, "level %d price", i+1)
+				assertDecimal(t, want.volume, rungs[i].Volume, "level %d volume", i+1)
+				assertDecimal(t, want.deviation, rungs[i].DeviationPercent, "level %d deviation", i+1)
+				assert.Equal(t, want.bumped, rungs[i].Bumped, "level %d bumped", i+1)
+			}
+		})
+	}
+}
+
+func TestLadderWithoutEntryPrice(t *testing.T) {
+	params := LadderParams{Count: 3, StepPercent: 2, Volume: d("100")}
+	assert.Empty(t, Ladder(decimal.Zero, params))
+	assert.Empty(t, Ladder(d("-1"), params))
+}
@@ -0,0 +1,61 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Bucket - token bucket: до burst запросов подряд, дальше не чаще rate в секунду.
+// В отличие от Tracker не ждет заголовков биржи и сглаживает пачки запросов заранее.
+type Bucket struct {
+	mu       sync.Mutex
+	rate     float64
+	burst    float64
+	tokens   float64
+	refilled time.Time
+}
+
+func NewBucket(rate float64, burst int) *Bucket {
+	return &Bucket{
+		rate:     rate,
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		refilled: time.Now(),
+	}
+}
+
+// Wait забирает токен, дожидаясь его появления или отмены контекста.
+func (b *Bucket) Wait(ctx context.Context) error {
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens += now.Sub(b.refilled).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.refilled = now
+
+	// Токен резервируется сразу: следующие вызовы встанут в очередь за этим
+	b.tokens--
+	var delay time.Duration
+	if b.tokens < 0 {
+		delay = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	b.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// Неиспользованный токен возвращается
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return ctx.Err()
+	}
+}
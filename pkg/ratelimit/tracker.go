@@ -0,0 +1,160 @@
+package ratelimit
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultWindow = time.Minute
+	// Остаток квоты, который не расходуется: при нем запрос ждет сброса окна биржи
+	DefaultReserve = 1
+	// Дольше не ждем даже при далеком сбросе: время биржи может расходиться с локальным
+	MaxWait = 5 * time.Second
+)
+
+// Usage - состояние лимита эндпоинта по последнему ответу биржи и использование
+// за скользящее окно трекера.
+type Usage struct {
+	Endpoint    string     `json:"endpoint"`
+	Limit       int        `json:"limit"` // Разрешено запросов в окне биржи; 0 - биржа не сообщила
+	Remaining   int        `json:"remaining"`
+	ResetAt     *time.Time `json:"reset_at,omitempty"`
+	UsedPercent float64    `json:"used_percent"`
+	Requests    int        `json:"requests"` // Запросов за скользящее окно трекера
+	Waits       int        `json:"waits"`    // Запросов, задержанных до сброса окна биржи
+	Rejected    int        `json:"rejected"` // Отказов биржи из-за превышения лимита
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+type endpointState struct {
+	usage    Usage
+	requests []time.Time
+}
+
+// Tracker ведет квоты эндпоинтов по заголовкам ответов и задерживает запросы,
+// когда остаток квоты дошел до резерва: лимит подстраивается под фактическую
+// квоту биржи, а не под заранее заданную частоту.
+type Tracker struct {
+	mu        sync.Mutex
+	window    time.Duration
+	reserve   int
+	endpoints map[string]*endpointState
+}
+
+func NewTracker(window time.Duration, reserve int) *Tracker {
+	if window <= 0 {
+		window = DefaultWindow
+	}
+	return &Tracker{
+		window:    window,
+		reserve:   reserve,
+		endpoints: make(map[string]*endpointState),
+	}
+}
+
+func (t *Tracker) state(endpoint string) *endpointState {
+	state, exists := t.endpoints[endpoint]
+	if !exists {
+		state = &endpointState{usage: Usage{Endpoint: endpoint}}
+		t.endpoints[endpoint] = state
+	}
+	return state
+}
+
+// Wait учитывает запрос и, если квота окна исчерпана до резерва, ждет ее сброса.
+// Остаток уменьшается сразу, чтобы параллельные запросы не израсходовали квоту
+// до прихода заголовков.
+func (t *Tracker) Wait(ctx context.Context, endpoint string) error {
+	t.mu.Lock()
+	state := t.state(endpoint)
+	now := time.Now()
+
+	usage := &state.usage
+	if usage.ResetAt != nil && !now.Before(*usage.ResetAt) {
+		usage.Remaining = usage.Limit
+		usage.ResetAt = nil
+	}
+
+	var delay time.Duration
+	if usage.Limit > 0 && usage.ResetAt != nil && usage.Remaining <= t.reserve {
+		delay = usage.ResetAt.Sub(now)
+		if delay > MaxWait {
+			delay = MaxWait
+		}
+		usage.Waits++
+	}
+	if usage.Remaining > 0 {
+		usage.Remaining--
+	}
+	state.requests = append(t.prune(state.requests, now), now)
+	t.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Observe запоминает квоту эндпоинта из ответа биржи.
+func (t *Tracker) Observe(endpoint string, limit, remaining int, resetAt time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	usage := &t.state(endpoint).usage
+	usage.Limit = limit
+	usage.Remaining = remaining
+	usage.ResetAt = &resetAt
+	usage.UpdatedAt = time.Now()
+}
+
+// Reject отмечает отказ биржи по лимиту: до сброса окна запросы будут ждать.
+func (t *Tracker) Reject(endpoint string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	usage := &t.state(endpoint).usage
+	usage.Rejected++
+	usage.Remaining = 0
+	usage.UpdatedAt = time.Now()
+}
+
+// Snapshot возвращает использование всех эндпоинтов, отсортированное по пути.
+func (t *Tracker) Snapshot() []Usage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	result := make([]Usage, 0, len(t.endpoints))
+	for _, state := range t.endpoints {
+		state.requests = t.prune(state.requests, now)
+
+		usage := state.usage
+		if usage.ResetAt != nil && !now.Before(*usage.ResetAt) {
+			usage.Remaining = usage.Limit
+			usage.ResetAt = nil
+		}
+		usage.Requests = len(state.requests)
+		if usage.Limit > 0 {
+			usage.UsedPercent = float64(usage.Limit-usage.Remaining) / float64(usage.Limit) * 100
+		}
+		result = append(result, usage)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Endpoint < result[j].Endpoint })
+	return result
+}
+
+func (t *Tracker) prune(requests []time.Time, now time.Time) []time.Time {
+	cutoff := now.Add(-t.window)
+	i := sort.Search(len(requests), func(i int) bool { return requests[i].After(cutoff) })
+	return requests[i:]
+}
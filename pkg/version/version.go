@@ -0,0 +1,29 @@
+package version
+
+import "fmt"
+
+// Заполняются при сборке:
+//
+//	go build -ldflags "-X cryptorg/pkg/version.Version=v1.2.0 -X cryptorg/pkg/version.Commit=$(git rev-parse --short HEAD) -X cryptorg/pkg/version.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildDate = "unknown"
+)
+
+// Short - краткая метка сборки для логов и уведомлений: v1.2.0@abc1234.
+func Short() string {
+	return Version + "@" + Commit
+}
+
+func String() string {
+	return fmt.Sprintf("%s (commit %s, built %s)", Version, Commit, BuildDate)
+}
+
+func Info() map[string]string {
+	return map[string]string{
+		"version":    Version,
+		"commit":     Commit,
+		"build_date": BuildDate,
+	}
+}